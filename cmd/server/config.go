@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apptz"
+)
+
+// checkEnv verifies the env the server can't run without, based on the
+// selected JWT_ALG, PAYMENT_PROVIDER and STORAGE_DRIVER:
+//   - always DATABASE_URL
+//   - HS256 (the default): JWT_SECRET of at least auth.MinSecretLen bytes
+//   - PAYMENT_PROVIDER=stripe (the default): STRIPE_SECRET, STRIPE_WEBHOOK_SECRET, PUBLIC_BASE_URL
+//   - PAYMENT_PROVIDER=mock in dev: DEV_PAYMENT_SECRET
+//   - STORAGE_DRIVER=supabase (the only driver): SUPABASE_URL, SUPABASE_SERVICE_KEY, SUPABASE_BUCKET
+//   - APP_TZ, when set, must be a known IANA timezone
+//
+// Everything missing is reported in one error so a deploy is fixed in one go;
+// unknown provider/driver values and a short secret are errors too. Legal but
+// weak settings (mock payments outside dev) come back as warnings.
+func checkEnv() (warnings []string, err error) {
+	var missing, invalid []string
+	require := func(keys ...string) {
+		for _, k := range keys {
+			if strings.TrimSpace(os.Getenv(k)) == "" {
+				missing = append(missing, k)
+			}
+		}
+	}
+
+	require("DATABASE_URL")
+
+	switch alg := strings.ToUpper(strings.TrimSpace(os.Getenv("JWT_ALG"))); alg {
+	case "", "HS256":
+		require("JWT_SECRET")
+		if n := len(os.Getenv("JWT_SECRET")); n > 0 && n < auth.MinSecretLen {
+			invalid = append(invalid, fmt.Sprintf("JWT_SECRET is only %d bytes (want at least %d)", n, auth.MinSecretLen))
+		}
+	case "RS256":
+		// Key files are checked by auth.ValidateKeyConfig
+	}
+
+	appEnv := strings.TrimSpace(os.Getenv("APP_ENV"))
+	switch p := strings.TrimSpace(os.Getenv("PAYMENT_PROVIDER")); p {
+	case "", "stripe":
+		require("STRIPE_SECRET", "STRIPE_WEBHOOK_SECRET", "PUBLIC_BASE_URL")
+	case "mock":
+		if appEnv == "dev" {
+			require("DEV_PAYMENT_SECRET")
+		} else {
+			warnings = append(warnings, "PAYMENT_PROVIDER=mock outside APP_ENV=dev: payments can never complete")
+		}
+	default:
+		invalid = append(invalid, fmt.Sprintf("PAYMENT_PROVIDER=%q (want stripe or mock)", p))
+	}
+
+	switch d := strings.TrimSpace(os.Getenv("STORAGE_DRIVER")); d {
+	case "", "supabase":
+		require("SUPABASE_URL", "SUPABASE_SERVICE_KEY", "SUPABASE_BUCKET")
+	default:
+		invalid = append(invalid, fmt.Sprintf("STORAGE_DRIVER=%q (want supabase)", d))
+	}
+
+	if _, err := apptz.Load(); err != nil {
+		invalid = append(invalid, err.Error())
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(missing, ", "))
+	}
+	problems = append(problems, invalid...)
+	if len(problems) > 0 {
+		return warnings, fmt.Errorf("invalid environment: %s", strings.Join(problems, "; "))
+	}
+	return warnings, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/middleware/cors"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/internal/payments"
+	"github.com/aldoetobex/legal-mp-backend/pkg/origins"
+)
+
+// Developer-friendly defaults when the CORS_* env is unset
+// (FRONTEND_ORIGIN falls back to origins.Default).
+const (
+	defaultCORSMethods = "GET,POST,PUT,PATCH,DELETE,OPTIONS"
+	defaultCORSHeaders = "Authorization,Content-Type,If-None-Match," + auth.RequestIDHeader + "," + payments.IdempotencyKeyHeader
+)
+
+// corsConfigFromEnv builds the CORS config from:
+//   - FRONTEND_ORIGIN: comma-separated origins; "*" allows any origin and
+//     "https://*.example.com" allows its subdomains
+//   - CORS_ALLOW_METHODS / CORS_ALLOW_HEADERS: comma-separated overrides
+//   - CORS_ALLOW_CREDENTIALS: default true
+//
+// Browsers refuse credentials with a "*" origin (and Fiber panics on it), so
+// that combination drops credentials and is reported in warnings.
+// Malformed origins are an error so a typo fails at startup, not per request.
+func corsConfigFromEnv() (cfg cors.Config, warnings []string, err error) {
+	allowed := origins.FromEnv()
+	for _, o := range allowed {
+		if o == "*" {
+			continue
+		}
+		u, perr := url.Parse(o)
+		if perr != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return cfg, nil, fmt.Errorf("invalid FRONTEND_ORIGIN entry %q (want scheme://host[:port])", o)
+		}
+	}
+
+	creds := true
+	if v := os.Getenv("CORS_ALLOW_CREDENTIALS"); v != "" {
+		if creds, err = strconv.ParseBool(v); err != nil {
+			return cfg, nil, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS %q", v)
+		}
+	}
+
+	allowOrigins := strings.Join(allowed, ",")
+	for _, o := range allowed {
+		if o == "*" {
+			allowOrigins = "*" // any other entries are redundant
+			if creds {
+				warnings = append(warnings, `FRONTEND_ORIGIN "*" cannot be combined with credentials; cookies/Authorization from browsers will not be allowed cross-origin (set explicit origins instead)`)
+				creds = false
+			}
+			break
+		}
+	}
+
+	return cors.Config{
+		AllowOrigins:     allowOrigins,
+		AllowMethods:     strings.Join(csvEnv("CORS_ALLOW_METHODS", defaultCORSMethods), ","),
+		AllowHeaders:     strings.Join(csvEnv("CORS_ALLOW_HEADERS", defaultCORSHeaders), ","),
+		ExposeHeaders:    auth.RequestIDHeader + ",ETag",
+		AllowCredentials: creds,
+		MaxAge:           600,
+	}, warnings, nil
+}
+
+// csvEnv splits a comma-separated env var (or def when unset), dropping blanks.
+func csvEnv(key, def string) []string {
+	raw := os.Getenv(key)
+	if strings.TrimSpace(raw) == "" {
+		raw = def
+	}
+	var out []string
+	for _, p := range strings.Split(raw, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
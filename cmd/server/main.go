@@ -13,23 +13,37 @@ package main
 
 import (
 	"log"
+	"log/slog"
+	"net"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/joho/godotenv"
 
+	"github.com/aldoetobex/legal-mp-backend/pkg/apptz"
 	"github.com/aldoetobex/legal-mp-backend/pkg/database"
+	"github.com/aldoetobex/legal-mp-backend/pkg/mailer"
+	"github.com/aldoetobex/legal-mp-backend/pkg/metrics"
 	"github.com/aldoetobex/legal-mp-backend/pkg/models"
 
 	// Swagger docs (adjust module path if needed)
 	_ "github.com/aldoetobex/legal-mp-backend/docs"
 
+	"github.com/aldoetobex/legal-mp-backend/internal/account"
+	"github.com/aldoetobex/legal-mp-backend/internal/admin"
 	"github.com/aldoetobex/legal-mp-backend/internal/auth"
 	"github.com/aldoetobex/legal-mp-backend/internal/cases"
+	"github.com/aldoetobex/legal-mp-backend/internal/health"
+	"github.com/aldoetobex/legal-mp-backend/internal/messages"
+	"github.com/aldoetobex/legal-mp-backend/internal/notifications"
 	"github.com/aldoetobex/legal-mp-backend/internal/payments"
 	"github.com/aldoetobex/legal-mp-backend/internal/quotes"
 	"github.com/aldoetobex/legal-mp-backend/internal/storage"
+	"github.com/aldoetobex/legal-mp-backend/internal/webhooks"
 	fiberSwagger "github.com/gofiber/swagger"
 )
 
@@ -37,6 +51,28 @@ func main() {
 	// Load .env (no-op if file missing)
 	_ = godotenv.Load()
 
+	// Structured JSON logs (the standard log package is routed through it too)
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	// Fail fast on missing config (secrets, DB, payment/storage credentials)
+	envWarnings, err := checkEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, w := range envWarnings {
+		log.Println("warning: env:", w)
+	}
+	// Local dates (filters, deadlines, references) are read in this zone
+	log.Println("app timezone:", apptz.Location())
+
+	// Fail fast on a broken JWT key setup (JWT_ALG / key files)
+	if err := auth.ValidateKeyConfig(); err != nil {
+		log.Fatal("jwt keys:", err)
+	}
+	if err := auth.ValidateTokenTTLs(); err != nil {
+		log.Fatal("jwt ttl:", err)
+	}
+
 	// Initialize DB and run migrations (idempotent)
 	db := database.Init()
 	if err := db.AutoMigrate(
@@ -44,90 +80,171 @@ func main() {
 		&models.Case{},
 		&models.CaseFile{},
 		&models.Quote{},
+		&models.QuoteRevision{},
 		&models.Payment{},
 		&models.CaseHistory{},
+		&models.Notification{},
+		&models.SavedCase{},
+		&models.CaseCollaborator{},
+		&models.Message{},
+		&models.WebhookEndpoint{},
+		&models.WebhookDelivery{},
 	); err != nil {
 		log.Fatal("migration failed:", err)
 	}
+	// Case-insensitive email uniqueness (fails if legacy mixed-case duplicates exist)
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS ux_users_email_lower ON users (lower(email))`).Error; err != nil {
+		log.Println("warning: could not create ux_users_email_lower:", err)
+	}
+	// Cases migrated before updated_at existed start from their creation time
+	if err := db.Exec(`UPDATE cases SET updated_at = created_at WHERE updated_at IS NULL`).Error; err != nil {
+		log.Println("warning: could not backfill cases.updated_at:", err)
+	}
+	// Accounts deleted before deleted_at existed were only anonymized
+	if err := db.Exec(`UPDATE users SET deleted_at = now() WHERE deleted_at IS NULL AND email LIKE 'deleted-%@deleted.invalid'`).Error; err != nil {
+		log.Println("warning: could not backfill users.deleted_at:", err)
+	}
+	// At most one ACCEPTED quote per case (fails if legacy data already has two)
+	if err := quotes.EnsureSingleAcceptedQuote(db); err != nil {
+		log.Println("warning: could not create ux_quotes_one_accepted:", err)
+	}
+	// Sequence behind human-friendly case references (LMP-2024-000123)
+	if err := cases.EnsureReferenceSequence(db); err != nil {
+		log.Fatal("case reference sequence:", err)
+	}
 
-	// Create Fiber app with a centralized error handler
-	app := fiber.New(fiber.Config{
-		ErrorHandler: auth.ErrorHandler,
-	})
+	// Create Fiber app with a centralized error handler and upload-sized body limit
+	app := fiber.New(appConfig())
 
-	// CORS: allow one or more frontend origins (comma-separated)
-	// Example: http://localhost:3000,https://your-frontend.vercel.app
-	allowed := os.Getenv("FRONTEND_ORIGIN")
-	if allowed == "" {
-		// Developer-friendly default
-		allowed = "http://localhost:3000,https://legal-mp-frontend.vercel.app"
-	}
-	app.Use(cors.New(cors.Config{
-		AllowOrigins:     allowed,
-		AllowMethods:     "GET,POST,PUT,PATCH,DELETE,OPTIONS",
-		AllowHeaders:     "Authorization,Content-Type",
-		AllowCredentials: true,
-		MaxAge:           600,
-	}))
+	// Request id first, then metrics, then one structured access-log line per request
+	// (AccessLog renders errors, so metrics sees the final status)
+	mtr := metrics.New()
+	app.Use(auth.RequestID(), mtr.Middleware(), auth.AccessLog())
+
+	// Prometheus scrape endpoint (unauthenticated; restrict at the network edge)
+	app.Get("/metrics", mtr.Handler())
+
+	// CORS: origins, methods and headers come from env (see cors.go)
+	corsCfg, warnings, err := corsConfigFromEnv()
+	if err != nil {
+		log.Fatal("cors:", err)
+	}
+	for _, w := range warnings {
+		log.Println("warning: cors:", w)
+	}
+	app.Use(cors.New(corsCfg))
 
 	// Respond to preflight quickly (useful behind strict proxies)
 	app.Options("/*", func(c *fiber.Ctx) error {
 		return c.SendStatus(fiber.StatusNoContent)
 	})
 
-	// Basic health check
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{"status": "ok"})
-	})
-
 	// Swagger UI
 	app.Get("/swagger/*", fiberSwagger.HandlerDefault)
 
 	// API group
 	api := app.Group("/api")
 
+	/* ============================ Mailer ============================ */
+	// SMTP when SMTP_HOST is set; otherwise emails are silently dropped
+	mail := mailer.NewFromEnv()
+
+	/* =========================== Webhooks =========================== */
+	// Outbound case events for integrators (async, HMAC-signed, retried)
+	hooks := webhooks.NewDispatcher(db)
+
 	/* ============================ Auth ============================ */
-	authH := auth.NewHandler(db)
-	api.Post("/signup", authH.Signup)
-	api.Post("/login", authH.Login)
-	api.Get("/me", auth.RequireAuth(), authH.Me)
+	authH := auth.NewHandler(db, mail)
+	// Per-IP + per-email throttling (AUTH_RATE_*); separate budgets per route
+	rl := auth.RateLimitConfigFromEnv()
+	api.Post("/signup", append(auth.RateLimit(rl), authH.Signup)...)
+	api.Post("/login", append(auth.RateLimit(rl), authH.Login)...)
+	api.Post("/auth/verify-email", append(auth.RateLimit(rl), authH.VerifyEmail)...)
+	// Resend sends mail, so it is throttled per IP too
+	resend := append([]fiber.Handler{auth.RequireAuth(db)}, auth.RateLimit(rl)...)
+	api.Post("/auth/verify-email/resend", append(resend, authH.ResendVerification)...)
+	api.Get("/me", auth.RequireAuth(db), authH.Me)
+	api.Patch("/me", auth.RequireAuth(db), authH.UpdateMe)
 
 	/* ============================ Storage ============================ */
 	// Uses SUPABASE_URL / SUPABASE_SECRET_KEY / SUPABASE_BUCKET
 	sb := storage.NewSupabase()
 
+	/* ============================ Health ============================ */
+	// /health/live never touches dependencies; /health/ready pings the DB
+	// (and storage when HEALTH_CHECK_STORAGE=true) and returns 503 on failure
+	healthH := health.NewHandler(db, sb)
+	app.Get("/health", healthH.Live) // kept for existing probes
+	app.Get("/health/live", healthH.Live)
+	app.Get("/health/ready", healthH.Ready)
+
+	/* ============================ Account ============================ */
+	// Account deletion cascades into cases/files, so it needs storage
+	accountH := account.NewHandler(db, sb)
+	api.Delete("/me", auth.RequireAuth(db), accountH.DeleteMe)
+
 	/* ============================ Cases ============================ */
 	caseH := cases.NewHandler(db, sb)
 
 	// Client endpoints
-	api.Post("/cases", auth.RequireAuth(), auth.RequireRole("client"), caseH.Create)
-	api.Get("/cases/mine", auth.RequireAuth(), auth.RequireRole("client"), caseH.ListMine)
-	api.Get("/cases/:id", auth.RequireAuth(), caseH.GetDetail)
-	api.Post("/cases/:id/files", auth.RequireAuth(), auth.RequireRole("client"), caseH.UploadFile)
-	api.Get("/cases/:id/history", auth.RequireAuth(), caseH.ListHistory)
-	api.Post("/cases/:id/cancel", auth.RequireAuth(), auth.RequireRole("client"), caseH.Cancel)
-	api.Post("/cases/:id/close", auth.RequireAuth(), auth.RequireRole("client"), caseH.Close)
+	api.Post("/cases", auth.RequireAuth(db), auth.RequireRole("client"), caseH.Create)
+	api.Get("/cases/mine", auth.RequireAuth(db), auth.RequireRole("client"), caseH.ListMine)
+	api.Get("/cases/:id", auth.RequireAuth(db), caseH.GetDetail)
+	api.Get("/cases/:id/files", auth.RequireAuth(db), caseH.ListFiles)
+	api.Post("/cases/:id/files", auth.RequireAuth(db), auth.RequireAnyRole("client", "lawyer"), caseH.UploadFile)
+	api.Post("/cases/:id/files/delete", auth.RequireAuth(db), auth.RequireRole("client"), caseH.DeleteFiles)
+	api.Get("/cases/:id/history", auth.RequireAuth(db), caseH.ListHistory)
+	api.Get("/cases/:id/collaborators", auth.RequireAuth(db), auth.RequireRole("lawyer"), caseH.ListCollaborators)
+	api.Post("/cases/:id/collaborators", auth.RequireAuth(db), auth.RequireRole("lawyer"), caseH.GrantCollaborator)
+	api.Delete("/cases/:id/collaborators/:userID", auth.RequireAuth(db), auth.RequireRole("lawyer"), caseH.RevokeCollaborator)
+	api.Post("/cases/:id/cancel", auth.RequireAuth(db), auth.RequireRole("client"), caseH.Cancel)
+	api.Post("/cases/:id/close", auth.RequireAuth(db), auth.RequireRole("client"), caseH.Close)
+	api.Post("/cases/:id/reopen", auth.RequireAuth(db), auth.RequireRole("client"), caseH.Reopen)
+	api.Post("/cases/:id/pause", auth.RequireAuth(db), auth.RequireRole("client"), caseH.Pause)
+	api.Post("/cases/:id/resume", auth.RequireAuth(db), auth.RequireRole("client"), caseH.Resume)
+	api.Delete("/cases/:id", auth.RequireAuth(db), auth.RequireRole("client"), caseH.Delete)
+	api.Get("/clients/me/stats", auth.RequireAuth(db), auth.RequireRole("client"), caseH.Stats)
+	// Either role: own cases (client) or quoted cases (lawyer)
+	api.Get("/me/cases", auth.RequireAuth(db), auth.RequireAnyRole("client", "lawyer"), caseH.MyCases)
 
 	// Lawyer endpoints
-	api.Get("/marketplace", auth.RequireAuth(), auth.RequireRole("lawyer"), caseH.Marketplace)
-	api.Get("/files/:fileID/signed-url", auth.RequireAuth(), caseH.SignedDownloadURL)
-	api.Delete("/files/:fileID", auth.RequireAuth(), auth.RequireRole("client"), caseH.DeleteFile)
+	api.Get("/marketplace", auth.RequireAuth(db), auth.RequireRole("lawyer"), caseH.Marketplace)
+	api.Get("/marketplace/categories", auth.RequireAuth(db), auth.RequireRole("lawyer"), caseH.MarketCategories)
+	api.Get("/marketplace/stats", auth.RequireAuth(db), auth.RequireRole("lawyer"), caseH.MarketStats)
+	api.Get("/marketplace/saved", auth.RequireAuth(db), auth.RequireRole("lawyer"), caseH.ListSaved)
+	api.Post("/marketplace/:id/save", auth.RequireAuth(db), auth.RequireRole("lawyer"), caseH.SaveCase)
+	api.Delete("/marketplace/:id/save", auth.RequireAuth(db), auth.RequireRole("lawyer"), caseH.UnsaveCase)
+	api.Get("/files/:fileID/signed-url", auth.RequireAuth(db), caseH.SignedDownloadURL)
+	api.Put("/files/:fileID", auth.RequireAuth(db), auth.RequireRole("client"), caseH.ReplaceFile)
+	api.Delete("/files/:fileID", auth.RequireAuth(db), auth.RequireRole("client"), caseH.DeleteFile)
 
 	/* ============================ Quotes ============================ */
-	quoteH := quotes.NewHandler(db)
+	quoteH := quotes.NewHandler(db, mail, mtr, hooks)
 
 	// Lawyer: create/update quote & list mine
-	api.Post("/quotes", auth.RequireAuth(), auth.RequireRole("lawyer"), quoteH.Upsert)
-	api.Get("/quotes/mine", auth.RequireAuth(), auth.RequireRole("lawyer"), quoteH.ListMine)
+	api.Post("/quotes", auth.RequireAuth(db), auth.RequireRole("lawyer"), quoteH.Upsert)
+	api.Get("/quotes/mine", auth.RequireAuth(db), auth.RequireRole("lawyer"), quoteH.ListMine)
+	api.Get("/lawyers/me/stats", auth.RequireAuth(db), auth.RequireRole("lawyer"), quoteH.Stats)
+	// Owning lawyer or case owner: single quote (registered after /quotes/mine)
+	api.Get("/quotes/:id", auth.RequireAuth(db), quoteH.GetByID)
+	api.Get("/quotes/:id/revisions", auth.RequireAuth(db), quoteH.ListRevisions)
 
 	// Client: list all quotes for own case
-	api.Get("/cases/:id/quotes", auth.RequireAuth(), quoteH.ListByCaseForOwner)
+	api.Get("/cases/:id/quotes", auth.RequireAuth(db), quoteH.ListByCaseForOwner)
+	// Owner, accepted lawyer or admin: the engagement's accepted quote
+	api.Get("/cases/:id/quotes/accepted", auth.RequireAuth(db), quoteH.GetAccepted)
+	// Client owner: aggregate comparison of live quotes
+	api.Get("/cases/:id/quotes/summary", auth.RequireAuth(db), auth.RequireRole("client"), quoteH.SummaryForOwner)
+	api.Post("/cases/:id/quotes/:quoteID/reject", auth.RequireAuth(db), auth.RequireRole("client"), quoteH.RejectByOwner)
 
 	/* ============================ Payments ============================ */
-	payH := payments.NewHandler(db)
+	payH := payments.NewHandler(db, mail, mtr, hooks)
 
 	// Client: start checkout for a selected quote
-	api.Post("/checkout/:quoteID", auth.RequireAuth(), auth.RequireRole("client"), payH.CreateCheckout)
+	api.Post("/checkout/:quoteID", auth.RequireAuth(db), auth.RequireRole("client"), payH.CreateCheckout)
+	// Client: payment summary for the checkout page (owner only)
+	api.Get("/payments/:id", auth.RequireAuth(db), auth.RequireRole("client"), payH.GetPayment)
+	api.Get("/cases/:id/payment", auth.RequireAuth(db), auth.RequireRole("client"), payH.GetCasePayment)
 
 	// Stripe webhook (server → server). No auth; verify via Stripe signature.
 	api.Post("/payments/stripe/webhook", payH.StripeWebhook)
@@ -137,11 +254,109 @@ func main() {
 		api.Post("/payments/mock/complete", payH.MockComplete)
 	}
 
+	/* =========================== Messages =========================== */
+	// Client ↔ accepted lawyer, engaged/closed cases only
+	msgH := messages.NewHandler(db)
+	api.Post("/cases/:id/messages", auth.RequireAuth(db), msgH.Send)
+	api.Get("/cases/:id/messages", auth.RequireAuth(db), msgH.List)
+
+	/* ========================= Notifications ========================= */
+	notifH := notifications.NewHandler(db)
+	api.Get("/notifications", auth.RequireAuth(db), notifH.List)
+	api.Get("/notifications/count", auth.RequireAuth(db), notifH.Count)
+	api.Post("/notifications/read-all", auth.RequireAuth(db), notifH.MarkAllRead)
+	api.Post("/notifications/:id/read", auth.RequireAuth(db), notifH.MarkRead)
+
+	/* ============================ Admin ============================ */
+	// Support staff only; every request is audit-logged
+	adminH := admin.NewHandler(db)
+	adm := api.Group("/admin", auth.RequireAuth(db), auth.RequireRole("admin"), auth.AuditAdmin())
+	adm.Get("/cases", adminH.ListCases)
+	adm.Post("/cases/:id/reassign", adminH.Reassign)
+	adm.Get("/users", adminH.ListUsers)
+	hookH := webhooks.NewHandler(db)
+	adm.Post("/webhooks", hookH.Create)
+	adm.Get("/webhooks", hookH.List)
+
+	/* ========================== Background ========================== */
+	// Hourly sweep: expired PROPOSED quotes → REJECTED
+	go func() {
+		for range time.Tick(time.Hour) {
+			if n, err := quotes.ExpireStale(db, time.Now()); err != nil {
+				log.Println("quote expiry sweep failed:", err)
+			} else if n > 0 {
+				log.Printf("quote expiry sweep: %d quotes expired", n)
+			}
+		}
+	}()
+	// Stale INITIATED payments → FAILED (unless the Stripe session is still open)
+	go func() {
+		for range time.Tick(payments.SweepInterval()) {
+			if n, err := payments.FailStale(db, time.Now(), payments.StaleAfter()); err != nil {
+				log.Println("payment sweep failed:", err)
+			} else if n > 0 {
+				log.Printf("payment sweep: %d stale payments failed", n)
+			}
+		}
+	}()
+
 	/* ============================ Server ============================ */
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "3000"
 	}
+	ln, err := net.Listen(app.Config().Network, ":"+port)
+	if err != nil {
+		log.Fatal("listen:", err)
+	}
 	log.Println("Server running on :" + port)
-	log.Fatal(app.Listen(":" + port))
+
+	// SIGINT/SIGTERM: stop accepting, drain in-flight requests, then close the DB
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	if err := serve(app, ln, shutdownTimeout(), stop); err != nil {
+		log.Println("shutdown:", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+	log.Println("Server stopped")
+}
+
+// serve runs app on ln until a signal arrives on stop, then stops accepting
+// new connections and waits up to drain for in-flight requests (uploads,
+// webhooks mid-transaction) to finish.
+func serve(app *fiber.App, ln net.Listener, drain time.Duration, stop <-chan os.Signal) error {
+	errc := make(chan error, 1)
+	go func() { errc <- app.Listener(ln) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case sig := <-stop:
+		log.Printf("received %s, draining for up to %s", sig, drain)
+	}
+	if err := app.ShutdownWithTimeout(drain); err != nil {
+		return err
+	}
+	return <-errc
+}
+
+// appConfig is the Fiber config: the centralized JSON error handler and a
+// body limit sized for a full multipart upload. Bodies over the limit are
+// refused before any handler runs, as a 413 PAYLOAD_TOO_LARGE ErrorResponse.
+func appConfig() fiber.Config {
+	return fiber.Config{
+		ErrorHandler: auth.ErrorHandler,
+		BodyLimit:    cases.MaxRequestBodyBytes(),
+	}
+}
+
+// shutdownTimeout is how long in-flight requests get to finish on shutdown.
+// Env: SHUTDOWN_TIMEOUT (Go duration, default 30s).
+func shutdownTimeout() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("SHUTDOWN_TIMEOUT")); err == nil && d > 0 {
+		return d
+	}
+	return 30 * time.Second
 }
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+// After a signal the server finishes the in-flight request but refuses new connections.
+func Test_Serve_DrainsInFlightThenStops(t *testing.T) {
+	started := make(chan struct{})
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		close(started)
+		time.Sleep(300 * time.Millisecond)
+		return c.SendString("done")
+	})
+
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://" + ln.Addr().String() + "/slow"
+
+	stop := make(chan os.Signal, 1)
+	served := make(chan error, 1)
+	go func() { served <- serve(app, ln, 5*time.Second, stop) }()
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}, Timeout: 5 * time.Second}
+	inFlight := make(chan int, 1)
+	go func() {
+		resp, err := client.Get(url)
+		if err != nil {
+			inFlight <- 0
+			return
+		}
+		resp.Body.Close()
+		inFlight <- resp.StatusCode
+	}()
+
+	<-started
+	stop <- syscall.SIGTERM
+
+	if code := <-inFlight; code != 200 {
+		t.Fatalf("in-flight request: want 200, got %d", code)
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	if _, err := client.Get(url); err == nil {
+		t.Fatal("new connections should be refused after shutdown")
+	}
+}
+
+// corsApp serves one route behind the env-driven CORS config.
+func corsApp(t *testing.T) (*fiber.App, []string) {
+	t.Helper()
+	cfg, warnings, err := corsConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := fiber.New()
+	app.Use(cors.New(cfg))
+	app.Get("/x", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	return app, warnings
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin sent for origin.
+func allowOrigin(t *testing.T, app *fiber.App, origin string) string {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/x", nil)
+	req.Header.Set("Origin", origin)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Header.Get("Access-Control-Allow-Origin")
+}
+
+// Listed origins (and subdomain wildcards) are echoed; others get no CORS header.
+func Test_CORS_AllowedVsDisallowedOrigin(t *testing.T) {
+	t.Setenv("FRONTEND_ORIGIN", "https://app.example.com, https://*.preview.example.com")
+	app, warnings := corsApp(t)
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+
+	for _, o := range []string{"https://app.example.com", "https://pr-7.preview.example.com"} {
+		if got := allowOrigin(t, app, o); got != o {
+			t.Fatalf("%s: want echoed origin, got %q", o, got)
+		}
+	}
+	if got := allowOrigin(t, app, "https://evil.example.net"); got != "" {
+		t.Fatalf("disallowed origin should get no header, got %q", got)
+	}
+}
+
+// "*" with credentials is downgraded with a warning; malformed origins fail.
+func Test_CORS_WildcardWithCredentials_Warns(t *testing.T) {
+	t.Setenv("FRONTEND_ORIGIN", "*")
+	app, warnings := corsApp(t)
+	if len(warnings) != 1 {
+		t.Fatalf("want 1 warning, got %v", warnings)
+	}
+	if got := allowOrigin(t, app, "https://anything.test"); got != "*" {
+		t.Fatalf("want *, got %q", got)
+	}
+
+	t.Setenv("FRONTEND_ORIGIN", "app.example.com")
+	if _, _, err := corsConfigFromEnv(); err == nil {
+		t.Fatal("origin without scheme should be rejected")
+	}
+}
+
+// completeEnv sets a minimal valid production-like environment.
+func completeEnv(t *testing.T) {
+	t.Helper()
+	for k, v := range map[string]string{
+		"DATABASE_URL":          "postgres://u:p@localhost/db",
+		"JWT_ALG":               "",
+		"JWT_SECRET":            strings.Repeat("s", auth.MinSecretLen),
+		"APP_ENV":               "prod",
+		"PAYMENT_PROVIDER":      "stripe",
+		"STRIPE_SECRET":         "sk_test_x",
+		"STRIPE_WEBHOOK_SECRET": "whsec_x",
+		"PUBLIC_BASE_URL":       "https://app.example.com",
+		"STORAGE_DRIVER":        "",
+		"SUPABASE_URL":          "https://p.supabase.co",
+		"SUPABASE_SERVICE_KEY":  "key",
+		"SUPABASE_BUCKET":       "case-files",
+	} {
+		t.Setenv(k, v)
+	}
+}
+
+// A complete env passes; a missing JWT secret (and anything else missing)
+// is named in one error.
+func Test_CheckEnv_MissingSecret(t *testing.T) {
+	completeEnv(t)
+	if warnings, err := checkEnv(); err != nil || len(warnings) != 0 {
+		t.Fatalf("complete env: want no error/warnings, got %v %v", err, warnings)
+	}
+
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("SUPABASE_BUCKET", " ")
+	_, err := checkEnv()
+	if err == nil {
+		t.Fatal("missing JWT_SECRET should fail")
+	}
+	for _, want := range []string{"JWT_SECRET", "SUPABASE_BUCKET"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error should name %s: %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "STRIPE_SECRET") {
+		t.Fatalf("present vars must not be reported: %v", err)
+	}
+}
+
+// Requirements follow the selected provider; a short secret fails, mock
+// payments outside dev only warn.
+func Test_CheckEnv_ProviderSpecific(t *testing.T) {
+	completeEnv(t)
+	t.Setenv("STRIPE_SECRET", "")
+	t.Setenv("PAYMENT_PROVIDER", "mock")
+	t.Setenv("APP_ENV", "dev")
+	t.Setenv("DEV_PAYMENT_SECRET", "")
+	if _, err := checkEnv(); err == nil || !strings.Contains(err.Error(), "DEV_PAYMENT_SECRET") {
+		t.Fatalf("mock in dev needs DEV_PAYMENT_SECRET, got %v", err)
+	}
+
+	t.Setenv("DEV_PAYMENT_SECRET", "dev")
+	t.Setenv("JWT_SECRET", "short")
+	if _, err := checkEnv(); err == nil || !strings.Contains(err.Error(), "JWT_SECRET") {
+		t.Fatalf("short secret should fail, got %v", err)
+	}
+
+	t.Setenv("JWT_SECRET", strings.Repeat("s", auth.MinSecretLen))
+	t.Setenv("APP_ENV", "production")
+	warnings, err := checkEnv()
+	if err != nil || len(warnings) != 1 {
+		t.Fatalf("mock outside dev should warn only, got %v %v", err, warnings)
+	}
+	t.Setenv("APP_ENV", "dev")
+
+	t.Setenv("PAYMENT_PROVIDER", "paypal")
+	if _, err := checkEnv(); err == nil || !strings.Contains(err.Error(), "PAYMENT_PROVIDER") {
+		t.Fatalf("unknown provider should fail, got %v", err)
+	}
+}
+
+// A typo'd APP_TZ stops startup instead of silently using the default.
+func Test_CheckEnv_InvalidAppTZ(t *testing.T) {
+	completeEnv(t)
+	t.Setenv("APP_TZ", "Asia/Singapore")
+	if _, err := checkEnv(); err != nil {
+		t.Fatalf("valid APP_TZ: %v", err)
+	}
+	t.Setenv("APP_TZ", "Asia/Singapur")
+	if _, err := checkEnv(); err == nil || !strings.Contains(err.Error(), "APP_TZ") {
+		t.Fatalf("invalid APP_TZ should fail, got %v", err)
+	}
+}
+
+// A body over the limit is refused before any handler with the standard 413
+// JSON body; the default limit fits a full upload (10 files × 10 MB).
+func Test_AppConfig_OversizedBodyIs413JSON(t *testing.T) {
+	if got := appConfig().BodyLimit; got <= 100*1024*1024 {
+		t.Fatalf("default body limit %d can't hold a full upload", got)
+	}
+
+	t.Setenv("UPLOAD_BODY_LIMIT_MB", "1")
+	cfg := appConfig()
+	cfg.DisableStartupMessage = true
+	app := fiber.New(cfg)
+	reached := false
+	app.Post("/api/cases/:id/files", func(c *fiber.Ctx) error {
+		reached = true
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	// A real listener: app.Test reports the refused body as a transport error
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = app.Listener(ln) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	resp, err := http.Post("http://"+ln.Addr().String()+"/api/cases/x/files",
+		"application/octet-stream", bytes.NewReader(make([]byte, 2*1024*1024)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != fiber.StatusRequestEntityTooLarge || reached {
+		t.Fatalf("want 413 before the handler, got %d (reached=%v)", resp.StatusCode, reached)
+	}
+	var body models.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("413 body is not JSON: %v", err)
+	}
+	if body.Code != "PAYLOAD_TOO_LARGE" || !body.Error || body.Message == "" {
+		t.Fatalf("unexpected error body: %+v", body)
+	}
+}
@@ -18,75 +18,21 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/cases": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Client creates a new case",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "cases"
-                ],
-                "summary": "Create case",
-                "parameters": [
-                    {
-                        "description": "Case payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/cases.CreateCaseRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "id",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.ValidationErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/cases/mine": {
+        "/admin/cases": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Client lists their own cases (paginated)",
+                "description": "Admin browses every case, including soft-deleted ones, with optional filters (paginated)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "cases"
+                    "admin"
                 ],
-                "summary": "List my cases",
+                "summary": "List all cases (admin)",
                 "parameters": [
                     {
                         "type": "integer",
@@ -99,86 +45,53 @@ const docTemplate = `{
                         "description": "pageSize",
                         "name": "pageSize",
                         "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/cases.PageCases"
-                        }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/cases/{id}/cancel": {
-            "post": {
-                "security": [
                     {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Client cancels their own case (only if still open)",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "cases"
-                ],
-                "summary": "Cancel case",
-                "parameters": [
+                        "type": "string",
+                        "description": "open|paused|engaged|closed|cancelled",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "case id (uuid)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "category",
+                        "name": "category",
+                        "in": "query"
                     },
                     {
-                        "description": "Optional comment",
-                        "name": "payload",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/cases.ActionRequest"
-                        }
+                        "type": "string",
+                        "description": "client id (uuid)",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "accepted lawyer id (uuid)",
+                        "name": "lawyer_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "status",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/pagination.Page-admin_AdminCaseItem"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -186,21 +99,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/cases/{id}/close": {
+        "/admin/cases/{id}/reassign": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Client closes their own case (only if engaged)",
+                "description": "Support switches an engaged case to another quote on it (e.g. the accepted lawyer went silent): the current accepted quote is rejected, the chosen one accepted, and a \"reassigned\" history entry logged. Quotes rejected when the case was engaged may be chosen. Payments are not touched (refunds are handled separately).",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "cases"
+                    "admin"
                 ],
-                "summary": "Close case",
+                "summary": "Reassign an engaged case to another lawyer (admin)",
                 "parameters": [
                     {
                         "type": "string",
@@ -210,22 +126,26 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Optional comment",
+                        "description": "new quote and reason",
                         "name": "payload",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/cases.ActionRequest"
+                            "$ref": "#/definitions/admin.ReassignRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "status",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/admin.ReassignResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -247,7 +167,7 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Conflict",
+                        "description": "CASE_NOT_ENGAGED, QUOTE_ALREADY_ACCEPTED",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -255,50 +175,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/cases/{id}/files": {
-            "post": {
+        "/admin/users": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Client (owner) uploads up to 10 files. Only allowed when case is open/engaged.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Admin browses users with optional role/email filters (paginated). Never returns password hashes.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "admin"
                 ],
-                "summary": "Upload multiple case files (PDF/PNG)",
+                "summary": "List all users (admin)",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "pageSize",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "case id (uuid)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "client|lawyer|admin",
+                        "name": "role",
+                        "in": "query"
                     },
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "file"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "PDF/PNG (max 10; max 10MB each)",
-                        "name": "files",
-                        "in": "formData",
-                        "required": true
+                        "type": "string",
+                        "description": "email contains (case-insensitive)",
+                        "name": "email",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "results: [{id,key,name,size,error?}]",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/pagination.Page-admin_AdminUserItem"
                         }
                     },
                     "400": {
@@ -307,20 +229,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -328,37 +244,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/cases/{id}/history": {
+        "/admin/webhooks": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "List case status changes (owner \u0026 accepted lawyer only)",
+                "description": "Admin only. Secrets are never listed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "cases"
-                ],
-                "summary": "Case history",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "case id (uuid)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List webhook endpoints",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/cases.CaseHistoryDTO"
+                                "$ref": "#/definitions/webhooks.EndpointItem"
                             }
                         }
                     },
@@ -373,63 +280,48 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
                     }
                 }
-            }
-        },
-        "/cases/{id}/quotes": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Client owner sees all quotes for their case (paginated)",
+                "description": "Admin only. Returns the signing secret once.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "quotes"
+                    "admin"
                 ],
-                "summary": "Quotes by case (owner)",
+                "summary": "Register a webhook endpoint",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "case id (uuid)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "pageSize",
-                        "name": "pageSize",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/quotes.PageMyQuotes"
-                        }
+                        "description": "endpoint",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.CreateEndpointRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.CreateEndpointResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -443,77 +335,37 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/checkout/{quoteID}": {
+        "/auth/verify-email": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+                "description": "Confirms the account's email with the token from the verification email. Tokens are single-use and expire (EMAIL_VERIFY_TTL, default 48h).",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Create a Stripe Checkout Session using amount from DB",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "payments"
+                    "auth"
                 ],
-                "summary": "Create checkout (Stripe)",
+                "summary": "Verify email",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "quote id (uuid)",
-                        "name": "quoteID",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                        "description": "Token from the email link",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/payments.CheckoutResponse"
+                            "$ref": "#/definitions/auth.VerifyEmailRequest"
                         }
                     }
-                }
-            }
-        },
-        "/files/{fileID}": {
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Only the client owner can delete files, and only while the case is open/engaged.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "files"
-                ],
-                "summary": "Delete a case file",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "file id (uuid)",
-                        "name": "fileID",
-                        "in": "path",
-                        "required": true
-                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "status: ok",
+                        "description": "status: verified",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -521,20 +373,14 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "INVALID_VERIFICATION_TOKEN (unknown, used or expired)",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "429": {
+                        "description": "too many attempts",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -542,52 +388,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/files/{fileID}/signed-url": {
-            "get": {
+        "/auth/verify-email/resend": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Client owner or the accepted lawyer obtains a short-lived signed URL",
+                "description": "Emails a new verification link to the current user; earlier links stop working",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "files"
-                ],
-                "summary": "Get signed URL for a case file",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "file id (uuid)",
-                        "name": "fileID",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Resend verification email",
                 "responses": {
                     "200": {
-                        "description": "url, expires_in, now",
+                        "description": "status: sent",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "EMAIL_ALREADY_VERIFIED",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "429": {
+                        "description": "too many attempts",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -595,9 +434,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
+        "/cases": {
             "post": {
-                "description": "Authenticate and receive a JWT",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Client creates a new case",
                 "consumes": [
                     "application/json"
                 ],
@@ -605,25 +449,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "cases"
                 ],
-                "summary": "Login",
+                "summary": "Create case",
                 "parameters": [
                     {
-                        "description": "Login payload",
+                        "description": "Case payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.LoginRequest"
+                            "$ref": "#/definitions/cases.CreateCaseRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "id, reference",
                         "schema": {
-                            "$ref": "#/definitions/auth.AuthResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -637,25 +484,31 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "DUPLICATE_CASE",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/marketplace": {
+        "/cases/mine": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Lawyer browses OPEN cases (server-side filters \u0026 pagination; no client identity)",
+                "description": "Client lists their own cases (paginated)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "marketplace"
+                    "cases"
                 ],
-                "summary": "Marketplace (anonymized)",
+                "summary": "List my cases",
                 "parameters": [
                     {
                         "type": "integer",
@@ -671,14 +524,14 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "category",
-                        "name": "category",
+                        "description": "created_desc (default) | updated_desc (recently active)",
+                        "name": "sort",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "YYYY-MM-DD (Asia/Singapore)",
-                        "name": "created_since",
+                        "description": "open | paused | engaged | closed | cancelled (default: all)",
+                        "name": "status",
                         "in": "query"
                     }
                 ],
@@ -686,7 +539,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/cases.PageMarketCases"
+                            "$ref": "#/definitions/pagination.Page-cases_CaseListItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
@@ -698,26 +557,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/me": {
-            "get": {
+        "/cases/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Return full profile of the authenticated user",
-                "produces": [
+                "description": "Client soft-deletes their own case (not once engaged/closed, nor while a checkout on one of its quotes is in progress). It disappears from listings; files, quotes and history are kept for audit.",
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "cases"
+                ],
+                "summary": "Delete case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional comment",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/cases.ActionRequest"
+                        }
+                    }
                 ],
-                "summary": "Get current user profile",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "status",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
@@ -725,109 +604,137 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "CASE_NOT_DELETABLE, CASE_IN_CHECKOUT",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/payments/mock/complete": {
+        "/cases/{id}/cancel": {
             "post": {
-                "description": "Dev-only: finalize payment and mark a single quote as accepted",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
-                "produces": [
+                "description": "Client cancels their own case (only if still open or paused). Repeating it on a case the client already cancelled is a no-op 200.",
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "payments"
+                    "cases"
                 ],
-                "summary": "Complete payment (mock)",
+                "summary": "Cancel case",
                 "parameters": [
                     {
-                        "description": "Payment ID",
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional comment",
                         "name": "payload",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/payments.MockCompleteRequest"
+                            "$ref": "#/definitions/cases.ActionRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "ok",
+                        "description": "status",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
-                    }
-                }
-            }
-        },
-        "/payments/stripe/webhook": {
-            "post": {
-                "description": "Verify signature and finalize payment (checkout.session.completed)",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "payments"
-                ],
-                "summary": "Stripe webhook endpoint",
-                "responses": {
-                    "200": {
-                        "description": "ok",
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/quotes": {
+        "/cases/{id}/close": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Lawyer creates or updates a quote while the case is still OPEN",
+                "description": "Client closes their own case (only if engaged). Repeating it on a case the client already closed is a no-op 200.",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "quotes"
+                    "cases"
                 ],
-                "summary": "Submit or update a quote (1 active per case per lawyer)",
+                "summary": "Close case",
                 "parameters": [
                     {
-                        "description": "Quote upsert payload",
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional comment",
                         "name": "payload",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/quotes.UpsertQuoteRequest"
+                            "$ref": "#/definitions/cases.ActionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "id, status, amount_cents, days, note",
+                    "200": {
+                        "description": "status",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
@@ -836,20 +743,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "immutable or case not open",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -857,46 +764,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/quotes/mine": {
+        "/cases/{id}/collaborators": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Lawyer lists their quotes (filter by status, with pagination). Includes case title.",
+                "description": "Accepted lawyer lists who they granted read access to (case detail, file list and signed URLs)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "quotes"
+                    "cases"
                 ],
-                "summary": "List my quotes",
+                "summary": "Case collaborators",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "pageSize",
-                        "name": "pageSize",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "proposed|accepted|rejected",
-                        "name": "status",
-                        "in": "query"
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/quotes.PageMyQuotes"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/cases.CollaboratorItem"
+                            }
                         }
                     },
                     "400": {
@@ -911,18 +810,21 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/signup": {
+            },
             "post": {
-                "description": "Register a new user (client or lawyer)",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accepted lawyer gives another lawyer account (e.g. a paralegal) read access to this ENGAGED case: detail, file list and signed download URLs. Scoped to this case only; granting twice is a no-op.",
                 "consumes": [
                     "application/json"
                 ],
@@ -930,17 +832,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "cases"
                 ],
-                "summary": "Sign up",
+                "summary": "Grant case access",
                 "parameters": [
                     {
-                        "description": "Signup payload",
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Collaborator's account email",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.SignupRequest"
+                            "$ref": "#/definitions/cases.GrantCollaboratorRequest"
                         }
                     }
                 ],
@@ -948,420 +857,4065 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/auth.AuthResponse"
+                            "$ref": "#/definitions/cases.CollaboratorItem"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "no lawyer account with that email, or your own",
                         "schema": {
-                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "email already exists",
+                        "description": "CASE_NOT_ENGAGED",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.AuthResponse": {
-            "type": "object",
-            "properties": {
-                "role": {
-                    "type": "string"
-                },
-                "token": {
-                    "type": "string"
-                }
-            }
         },
-        "auth.LoginRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
-                    "type": "string",
-                    "maxLength": 60
-                },
-                "password": {
-                    "type": "string"
+        "/cases/{id}/collaborators/{userID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accepted lawyer removes a collaborator's access to this case (idempotent; works in any case status)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cases"
+                ],
+                "summary": "Revoke case access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "collaborator user id (uuid)",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "status: revoked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "auth.SignupRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password",
-                "role"
-            ],
-            "properties": {
-                "bar_number": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string",
-                    "maxLength": 120
-                },
-                "jurisdiction": {
-                    "description": "Optional for lawyers",
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string",
-                    "maxLength": 80,
-                    "minLength": 2
-                },
-                "password": {
-                    "type": "string",
-                    "maxLength": 72,
-                    "minLength": 6
+        "/cases/{id}/files": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Paginated file metadata without quotes/history. Same access as the case detail: owner client, admins, or the accepted lawyer (and their collaborators) once engaged/closed. Filenames are masked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "List files of a case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "pageSize (default 10)",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-cases_CaseFileItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Client (owner) uploads up to 10 files while the case is open/paused/engaged; the accepted lawyer may upload on an engaged case. Files that would push the case past its storage quota (CASE_STORAGE_QUOTA_MB, default 100) are rejected individually with remaining_bytes.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Upload multiple case files (PDF/PNG)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "file"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "PDF/PNG (max 10; max 10MB each)",
+                        "name": "files",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "optional label (max 200 chars); repeat once per file, in order, or send one for all",
+                        "name": "description",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "results: [{id,key,name,size,description?,error?,remaining_bytes?}]",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "MULTIPART_REQUIRED | MULTIPART_INVALID | NO_FILES_PROVIDED | NO_FILES_STORED (every file rejected; body also has results)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "a single file that isn't PDF/PNG",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cases/{id}/files/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Client owner deletes up to 50 files of one case at once, only while the case is open/cancelled. Per-ID results; IDs that are not files of this case fail individually.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Delete several case files",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "file ids",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cases.BulkDeleteFilesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "results: [{id,deleted?,error?}]",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cases/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List case status changes (owner, accepted lawyer, or admin), optionally within a date window and as a CSV download",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "cases"
+                ],
+                "summary": "Case history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "YYYY-MM-DD (app timezone, APP_TZ)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "YYYY-MM-DD, inclusive (app timezone, APP_TZ)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) | csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/cases.CaseHistoryDTO"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "not modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cases/{id}/messages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owning client or accepted lawyer reads the conversation, oldest first (paginated)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "List case messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "pageSize (default 10)",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-messages_MessageItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owning client or accepted lawyer posts a message on an engaged/closed case (not redacted)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Send a case message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "message",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/messages.SendMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/messages.MessageItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cases/{id}/pause": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Client hides their own OPEN case from the marketplace (e.g. while gathering documents). Quotes and files are kept; lawyers can't quote until it is resumed. Not while a checkout on one of its quotes is in progress.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cases"
+                ],
+                "summary": "Pause case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional comment",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/cases.ActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "CASE_NOT_PAUSABLE, CASE_IN_CHECKOUT",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cases/{id}/payment": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owning client only: status, amount, provider and (once paid via Stripe) the receipt URL of the case's most recent payment. Poll it from the success page until status is \"paid\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Latest payment on a case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payments.CasePaymentStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "no such case, or no payment yet",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cases/{id}/quotes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Client owner sees all quotes for their case (filter by status, with pagination); notes and pitches are redacted except the accepted quote's once engaged/closed. Admins get every case (deleted ones too) with notes and pitches unredacted for moderation; each admin read is audit-logged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quotes"
+                ],
+                "summary": "Quotes by case (owner or admin)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "pageSize",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "proposed|accepted|rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-quotes_MyQuoteItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cases/{id}/quotes/accepted": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lightweight engagement summary. Same access as the case detail: owner client, accepted lawyer and their collaborators see the full note (other lawyers get 404, or 403 with CASE_LAWYER_DENY_STATUS=403); admins see it redacted (partially with PII_STAFF_MASKING=partial). 404 unless the case is engaged/closed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quotes"
+                ],
+                "summary": "Accepted quote of a case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/quotes.QuoteDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cases/{id}/quotes/summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Client owner gets count, min/max days and min/max/median amount per currency over the case's live quotes (aggregates only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quotes"
+                ],
+                "summary": "Quote comparison summary (owner)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/quotes.QuoteSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cases/{id}/quotes/{quoteID}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Case owner rejects a still-proposed quote while the case is OPEN. Accepted quotes cannot be rejected, nor a quote whose checkout is in progress.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quotes"
+                ],
+                "summary": "Reject a quote",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "quote id (uuid)",
+                        "name": "quoteID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional comment",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/quotes.RejectQuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "CASE_NOT_OPEN, QUOTE_NOT_PROPOSED, QUOTE_IN_CHECKOUT",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cases/{id}/reopen": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Client reopens their own cancelled case (never engaged, within the grace window). Files and quotes are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cases"
+                ],
+                "summary": "Reopen case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional comment",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/cases.ActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cases/{id}/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Client puts their own PAUSED case back on the marketplace",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cases"
+                ],
+                "summary": "Resume case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional comment",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/cases.ActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/checkout/{quoteID}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a Stripe Checkout Session using amount from DB. Stripe returns the client to return_origin (one of FRONTEND_ORIGIN) or PUBLIC_BASE_URL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Create checkout (Stripe)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "quote id (uuid)",
+                        "name": "quoteID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "repeat returns the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "optional return origin",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/payments.CheckoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/payments.CheckoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "RETURN_ORIGIN_NOT_ALLOWED",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "IDEMPOTENCY_KEY_REUSED",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/clients/me/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Client's case counts by status, quotes received, spend per currency and distinct lawyers engaged (aggregates only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cases"
+                ],
+                "summary": "My dashboard stats (client)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cases.ClientStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{fileID}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Client (owner) uploads a new version of a file while the case is open/paused/engaged. The file keeps its id (links stay valid); name, type, size and storage key change and the old object is removed. Same checks as uploads, quota counted without the old version.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Replace a case file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file id (uuid)",
+                        "name": "fileID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "PDF/PNG (max 10MB)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "id, key, name, size",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "file isn't PDF/PNG",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "storage upload failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Only the client owner can delete files, and only while the case is open/engaged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Delete a case file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file id (uuid)",
+                        "name": "fileID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "status: ok",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{fileID}/signed-url": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Client owner, the accepted lawyer or a collaborator they granted access obtains a short-lived signed URL. With format=qr the same URL comes back as a PNG QR code (to open the file on a phone).",
+                "produces": [
+                    "application/json",
+                    "image/png"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Get signed URL for a case file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file id (uuid)",
+                        "name": "fileID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) | qr",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "url, expires_in, now",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lawyers/me/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lawyer's quote counts by status, engaged cases, earnings per currency and win rate (aggregates only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quotes"
+                ],
+                "summary": "My dashboard stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/quotes.LawyerStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Authenticate and receive a JWT. Repeated failures lock the account for a while.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "Login payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "account temporarily locked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "too many attempts",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/marketplace": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lawyer browses OPEN cases (server-side filters \u0026 pagination; no client identity). With MARKETPLACE_CLIENT_CASE_COUNT on, items also carry client_open_case_count.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "marketplace"
+                ],
+                "summary": "Marketplace (anonymized)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "pageSize",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "YYYY-MM-DD (app timezone, APP_TZ)",
+                        "name": "created_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "YYYY-MM-DD, inclusive (app timezone, APP_TZ)",
+                        "name": "created_until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true hides cases I have already quoted",
+                        "name": "exclude_quoted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "newest (default) | urgency (high first, then nearest deadline) | deadline (nearest first)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-cases_MarketCaseItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/marketplace/categories": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Distinct categories among OPEN cases with a count each, most cases first (for building the category filter)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "marketplace"
+                ],
+                "summary": "Marketplace categories",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/cases.MarketCategory"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/marketplace/saved": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lawyer lists their saved cases that are still OPEN, most recently saved first, in the anonymized marketplace shape. Cases that were engaged, paused, closed or deleted drop out (and come back if reopened).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "marketplace"
+                ],
+                "summary": "My saved cases",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "pageSize",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-cases_MarketCaseItem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/marketplace/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Per category: OPEN cases now and how many of them were posted in the last 7 days (today and the 6 days before, app timezone APP_TZ). Most open cases first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "marketplace"
+                ],
+                "summary": "Marketplace demand by category",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cases.MarketStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/marketplace/{id}/save": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lawyer bookmarks an OPEN case to come back to (idempotent)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "marketplace"
+                ],
+                "summary": "Save a marketplace case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "status: saved",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "CASE_NOT_OPEN",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lawyer removes a bookmark (idempotent; also works once the case has left the marketplace)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "marketplace"
+                ],
+                "summary": "Unsave a marketplace case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "case id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "status: unsaved",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return full profile of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get current user profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Clients without engaged/closed cases, paid payments or a checkout in progress: their cases, files (storage too, best-effort), quotes on them and related rows are deleted. Lawyers without an engaged case or a checkout in progress on one of their quotes: open proposals are withdrawn and quote notes/pitches cleared (accepted quotes stay on the client's record). Either way the user row is anonymized (email, name, contact details, password) so it can't sign in again, tokens already issued stop working, and the deletion is logged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Delete my account",
+                "responses": {
+                    "200": {
+                        "description": "status: deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin accounts",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "ACCOUNT_HAS_ENGAGEMENTS",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update name and contact details (phone, preferred contact); lawyers may also update jurisdiction and bar number. Role and email cannot be changed here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Update current user profile",
+                "parameters": [
+                    {
+                        "description": "Fields to change",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auth.UserProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "lawyer-only field",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/cases": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Clients get their own cases (as /cases/mine); lawyers get the cases they have quoted on, with their quote's id and status. Same pagination and sort for both.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cases"
+                ],
+                "summary": "My cases (either role)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "pageSize",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_desc (default) | updated_desc (recently active)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-cases_MyCaseItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Authenticated user lists their in-app notifications, newest first (paginated)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List my notifications",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "pageSize",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only unread when true",
+                        "name": "unread",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-notifications_NotificationItem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/count": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Total and unread notification counts for the caller (for the bell badge)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Count my notifications",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/notifications.NotificationCount"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/read-all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks every unread notification of the caller as read; returns how many changed (0 on repeats)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark all notifications as read",
+                "responses": {
+                    "200": {
+                        "description": "updated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer",
+                                "format": "int64"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/{id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks one of the caller's notifications as read (idempotent)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark notification as read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "notification id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/notifications.NotificationItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/mock/complete": {
+            "post": {
+                "description": "Dev-only: finalize payment and mark a single quote as accepted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Complete payment (mock)",
+                "parameters": [
+                    {
+                        "description": "Payment ID",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payments.MockCompleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/stripe/webhook": {
+            "post": {
+                "description": "Verify signature and finalize payment (checkout.session.completed)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Stripe webhook endpoint",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owning client only: amount, case title, quote note, status and (once paid via Stripe) the receipt URL",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Get a payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "payment id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payments.PaymentSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/quotes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lawyer creates or updates a quote while the case is still OPEN",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quotes"
+                ],
+                "summary": "Submit or update a quote (1 active per case per lawyer)",
+                "parameters": [
+                    {
+                        "description": "Quote upsert payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quotes.UpsertQuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "id, status, amount_cents, currency, days, note, pitch, expires_at",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "EMAIL_NOT_VERIFIED (when REQUIRE_LAWYER_EMAIL_VERIFICATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "immutable, CASE_NOT_OPEN, CASE_EXPIRED, or QUOTE_IN_CHECKOUT (checkout started)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/quotes/mine": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lawyer lists their quotes (filter by status, with pagination). Includes case title.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quotes"
+                ],
+                "summary": "List my quotes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "pageSize",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "proposed|accepted|rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-quotes_MyQuoteItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/quotes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owning lawyer sees their quote as-is; the owning client of the case sees it with the same note redaction as the case quote list. Once the case is soft-deleted only the lawyer and admins can read it (404 for others).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quotes"
+                ],
+                "summary": "Get a quote",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "quote id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/quotes.QuoteDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/quotes/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Every version of a quote (amount, currency, days, note), oldest first. Owning lawyer sees notes as written; the case owner sees them with the same redaction as the quote.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quotes"
+                ],
+                "summary": "Quote revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "quote id (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/quotes.QuoteRevisionItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/signup": {
+            "post": {
+                "description": "Register a new user (client or lawyer)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Sign up",
+                "parameters": [
+                    {
+                        "description": "Signup payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.SignupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/auth.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "email already registered",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "too many attempts",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "admin.AdminCaseItem": {
+            "type": "object",
+            "properties": {
+                "accepted_lawyer_id": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "set when the owner soft-deleted it",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "nil on legacy cases",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.CaseStatus"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.AdminUserItem": {
+            "type": "object",
+            "properties": {
+                "bar_number": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "jurisdiction": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/models.Role"
+                }
+            }
+        },
+        "admin.ReassignRequest": {
+            "type": "object",
+            "required": [
+                "quote_id",
+                "reason"
+            ],
+            "properties": {
+                "quote_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "minLength": 3
+                }
+            }
+        },
+        "admin.ReassignResponse": {
+            "type": "object",
+            "properties": {
+                "accepted_lawyer_id": {
+                    "type": "string"
+                },
+                "accepted_quote_id": {
+                    "type": "string"
+                },
+                "case_id": {
+                    "type": "string"
+                },
+                "previous_quote_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 60
+                },
+                "password": {
+                    "type": "string"
+                },
+                "remember": {
+                    "description": "longer session (JWT_REMEMBER_TTL)",
+                    "type": "boolean"
+                }
+            }
+        },
+        "auth.SignupRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password",
+                "role"
+            ],
+            "properties": {
+                "bar_number": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "jurisdiction": {
+                    "description": "Optional for lawyers",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 80,
+                    "minLength": 2
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 6
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "client",
+                        "lawyer"
+                    ]
+                }
+            }
+        },
+        "auth.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "bar_number": {
+                    "type": "string"
+                },
+                "contact_phone": {
+                    "type": "string"
+                },
+                "jurisdiction": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 80,
+                    "minLength": 2
+                },
+                "preferred_contact": {
+                    "type": "string",
+                    "enum": [
+                        "email",
+                        "phone"
+                    ]
+                }
+            }
+        },
+        "auth.UserProfileResponse": {
+            "type": "object",
+            "properties": {
+                "bar_number": {
+                    "type": "string"
+                },
+                "contact_phone": {
+                    "description": "Shared with the counterpart of an engaged/closed case only",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "description": "Lawyers may need a verified email to quote (see /auth/verify-email)",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "jurisdiction": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "preferred_contact": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/models.Role"
+                }
+            }
+        },
+        "auth.VerifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "cases.ActionRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "description": "Optional comment shown in history",
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "cases.BulkDeleteFilesRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "cases.CaseFileItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mime": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cases.CaseHistoryDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "description": "uuid.Nil for system actions",
+                    "type": "string"
+                },
+                "actor_type": {
+                    "$ref": "#/definitions/models.ActorType"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "new_status": {
+                    "$ref": "#/definitions/models.CaseStatus"
+                },
+                "old_status": {
+                    "$ref": "#/definitions/models.CaseStatus"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "cases.CaseListItem": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "files": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "quotes": {
+                    "type": "integer"
+                },
+                "reference": {
+                    "description": "nil on legacy cases",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "cases.ClientStats": {
+            "type": "object",
+            "properties": {
+                "cancelled": {
+                    "type": "integer"
+                },
+                "closed": {
+                    "type": "integer"
+                },
+                "engaged": {
+                    "type": "integer"
+                },
+                "lawyers_engaged": {
+                    "description": "Distinct lawyers ever engaged (also on cases cancelled or closed since)",
+                    "type": "integer"
+                },
+                "open": {
+                    "type": "integer"
+                },
+                "paused": {
+                    "type": "integer"
+                },
+                "quotes_received": {
+                    "description": "every quote on my cases, whatever its status",
+                    "type": "integer"
+                },
+                "spent_cents": {
+                    "description": "Paid payments, per currency (ISO-4217 → cents)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                }
+            }
+        },
+        "cases.CollaboratorItem": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "granted_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "cases.CreateCaseRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "title"
+            ],
+            "properties": {
+                "allow_duplicate": {
+                    "description": "Skip the duplicate check (the client really means a second, identical case)",
+                    "type": "boolean"
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 40
+                },
+                "deadline": {
+                    "description": "Optional: a legal deadline (YYYY-MM-DD, today or later in the app TZ)\nand how urgent the matter is",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 120,
+                    "minLength": 3
+                },
+                "urgency": {
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "normal",
+                        "high"
+                    ]
+                }
+            }
+        },
+        "cases.GrantCollaboratorRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "cases.MarketCaseItem": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "client_open_case_count": {
+                    "description": "How many OPEN cases the poster has right now, this one included; a\ncount only, never who they are. Omitted unless MARKETPLACE_CLIENT_CASE_COUNT is on.",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deadline": {
+                    "description": "Client-set priority hints; omitted when not given",
+                    "type": "string"
+                },
+                "has_my_quote": {
+                    "description": "FE can use this to disable \"submit quote\"",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lowest_amount_cents": {
+                    "description": "nil when no live quotes",
+                    "type": "integer"
+                },
+                "preview": {
+                    "type": "string"
+                },
+                "quote_count": {
+                    "description": "Competitive context: aggregates over live (proposed) quotes only",
+                    "type": "integer"
+                },
+                "reference": {
+                    "description": "nil on legacy cases",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "urgency": {
+                    "$ref": "#/definitions/models.Urgency"
+                }
+            }
+        },
+        "cases.MarketCategory": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cases.MarketCategoryStats": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "new_cases": {
+                    "description": "of those, posted since MarketStats.Since",
+                    "type": "integer"
+                },
+                "open": {
+                    "description": "OPEN cases right now",
+                    "type": "integer"
+                }
+            }
+        },
+        "cases.MarketStats": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cases.MarketCategoryStats"
+                    }
+                },
+                "since": {
+                    "description": "start of the window: midnight app TZ, 6 days before today",
+                    "type": "string"
+                }
+            }
+        },
+        "cases.MyCaseItem": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "files": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "my_quote_id": {
+                    "description": "Lawyer only",
+                    "type": "string"
+                },
+                "my_quote_status": {
+                    "type": "string"
+                },
+                "quotes": {
+                    "description": "Client only",
+                    "type": "integer"
+                },
+                "reference": {
+                    "description": "nil on legacy cases",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "messages.MessageItem": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "case_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "sender_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "messages.SendMessageRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "models.ActorType": {
+            "type": "string",
+            "enum": [
+                "user",
+                "lawyer",
+                "system"
+            ],
+            "x-enum-comments": {
+                "ActorLawyer": "a lawyer acting on the case",
+                "ActorSystem": "webhooks and background sweeps (ActorID is uuid.Nil)",
+                "ActorUser": "a client or admin acting through the API"
+            },
+            "x-enum-descriptions": [
+                "a client or admin acting through the API",
+                "a lawyer acting on the case",
+                "webhooks and background sweeps (ActorID is uuid.Nil)"
+            ],
+            "x-enum-varnames": [
+                "ActorUser",
+                "ActorLawyer",
+                "ActorSystem"
+            ]
+        },
+        "models.CaseStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "engaged",
+                "closed",
+                "cancelled",
+                "paused",
+                "expired"
+            ],
+            "x-enum-comments": {
+                "CaseExpired": "reserved: no flow sets it yet, but quoting already refuses it",
+                "CasePaused": "owner hid it from the marketplace; quotes are kept"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "",
+                "owner hid it from the marketplace; quotes are kept",
+                "reserved: no flow sets it yet, but quoting already refuses it"
+            ],
+            "x-enum-varnames": [
+                "CaseOpen",
+                "CaseEngaged",
+                "CaseClosed",
+                "CaseCancelled",
+                "CasePaused",
+                "CaseExpired"
+            ]
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "machine-friendly error code",
+                    "type": "string",
+                    "example": "FORBIDDEN"
+                },
+                "error": {
+                    "description": "always true for error cases",
+                    "type": "boolean",
+                    "example": true
+                },
+                "message": {
+                    "description": "human-readable error message",
+                    "type": "string",
+                    "example": "Forbidden"
+                },
+                "request_id": {
+                    "description": "Correlation id (also in the X-Request-ID header); quote it when reporting issues",
+                    "type": "string",
+                    "example": "3f2b8c1e-7d4a-4e0b-9a51-2c6d8e9f0a1b"
+                }
+            }
+        },
+        "models.NotificationType": {
+            "type": "string",
+            "enum": [
+                "quote_received"
+            ],
+            "x-enum-varnames": [
+                "NotifyQuoteReceived"
+            ]
+        },
+        "models.PayStatus": {
+            "type": "string",
+            "enum": [
+                "initiated",
+                "paid",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "PayInitiated",
+                "PayPaid",
+                "PayFailed"
+            ]
+        },
+        "models.Role": {
+            "type": "string",
+            "enum": [
+                "client",
+                "lawyer",
+                "admin"
+            ],
+            "x-enum-comments": {
+                "RoleAdmin": "support staff; read access across cases"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "support staff; read access across cases"
+            ],
+            "x-enum-varnames": [
+                "RoleClient",
+                "RoleLawyer",
+                "RoleAdmin"
+            ]
+        },
+        "models.Urgency": {
+            "type": "string",
+            "enum": [
+                "low",
+                "normal",
+                "high"
+            ],
+            "x-enum-varnames": [
+                "UrgencyLow",
+                "UrgencyNormal",
+                "UrgencyHigh"
+            ]
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "barNumber": {
+                    "type": "string"
+                },
+                "contactPhone": {
+                    "description": "Optional contact details, shown only to the counterpart of an\nengaged/closed case",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "Set when the account is deleted (see account.DeleteMe); the row stays,\nanonymized, and its tokens stop working. Not a GORM soft delete.",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "emailVerifiedAt": {
+                    "description": "Email verification (see auth.VerifyEmail); nil = not verified yet.\nOnly the SHA-256 of the emailed token is stored.",
+                    "type": "string"
+                },
+                "emailVerifyExpiresAt": {
+                    "type": "string"
+                },
+                "emailVerifyTokenHash": {
+                    "type": "string"
+                },
+                "failedAttempts": {
+                    "description": "Login lockout state (see auth.Login)",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "jurisdiction": {
+                    "type": "string"
+                },
+                "lockedUntil": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "passwordHash": {
+                    "type": "string"
+                },
+                "preferredContact": {
+                    "description": "email|phone; empty = no preference",
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/models.Role"
+                }
+            }
+        },
+        "models.ValidationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "field name -\u003e list of error messages",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Validation failed"
+                }
+            }
+        },
+        "notifications.NotificationCount": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "integer"
+                },
+                "unread": {
+                    "type": "integer"
+                }
+            }
+        },
+        "notifications.NotificationItem": {
+            "type": "object",
+            "properties": {
+                "case_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.NotificationType"
+                }
+            }
+        },
+        "pagination.Links": {
+            "type": "object",
+            "properties": {
+                "next": {
+                    "type": "string"
+                },
+                "prev": {
+                    "type": "string"
+                }
+            }
+        },
+        "pagination.Page-admin_AdminCaseItem": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "always [] when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.AdminCaseItem"
+                    }
                 },
-                "role": {
-                    "type": "string",
-                    "enum": [
-                        "client",
-                        "lawyer"
-                    ]
+                "links": {
+                    "$ref": "#/definitions/pagination.Links"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "pages": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "cases.ActionRequest": {
+        "pagination.Page-admin_AdminUserItem": {
             "type": "object",
             "properties": {
-                "comment": {
-                    "description": "Optional comment shown in history",
-                    "type": "string",
-                    "maxLength": 500
+                "items": {
+                    "description": "always [] when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.AdminUserItem"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/pagination.Links"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "pages": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "cases.CaseHistoryDTO": {
+        "pagination.Page-cases_CaseFileItem": {
             "type": "object",
             "properties": {
-                "action": {
-                    "type": "string"
+                "items": {
+                    "description": "always [] when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cases.CaseFileItem"
+                    }
                 },
-                "actor_id": {
-                    "type": "string"
+                "links": {
+                    "$ref": "#/definitions/pagination.Links"
                 },
-                "created_at": {
-                    "type": "string"
+                "page": {
+                    "type": "integer"
                 },
-                "id": {
-                    "type": "string"
+                "pageSize": {
+                    "type": "integer"
                 },
-                "new_status": {
-                    "$ref": "#/definitions/models.CaseStatus"
+                "pages": {
+                    "type": "integer"
                 },
-                "old_status": {
-                    "$ref": "#/definitions/models.CaseStatus"
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-cases_CaseListItem": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "always [] when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cases.CaseListItem"
+                    }
                 },
-                "reason": {
-                    "type": "string"
+                "links": {
+                    "$ref": "#/definitions/pagination.Links"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "pages": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "cases.CaseListItem": {
+        "pagination.Page-cases_MarketCaseItem": {
             "type": "object",
             "properties": {
-                "category": {
-                    "type": "string"
+                "items": {
+                    "description": "always [] when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cases.MarketCaseItem"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/pagination.Links"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "pages": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-cases_MyCaseItem": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "always [] when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cases.MyCaseItem"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/pagination.Links"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "pages": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-messages_MessageItem": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "always [] when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/messages.MessageItem"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/pagination.Links"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "pages": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-notifications_NotificationItem": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "always [] when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/notifications.NotificationItem"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/pagination.Links"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "pages": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-quotes_MyQuoteItem": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "always [] when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quotes.MyQuoteItem"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/pagination.Links"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "pages": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "payments.CasePaymentStatus": {
+            "type": "object",
+            "properties": {
+                "amount_cents": {
+                    "type": "integer"
                 },
                 "created_at": {
                     "type": "string"
                 },
+                "currency": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "quotes": {
-                    "type": "integer"
+                "paid_at": {
+                    "type": "string"
                 },
-                "status": {
+                "provider": {
+                    "description": "stripe | mock",
                     "type": "string"
                 },
-                "title": {
+                "receipt_url": {
                     "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.PayStatus"
                 }
             }
         },
-        "cases.CreateCaseRequest": {
+        "payments.CheckoutRequest": {
             "type": "object",
-            "required": [
-                "category",
-                "title"
-            ],
             "properties": {
-                "category": {
+                "return_origin": {
+                    "description": "Frontend to send the client back to after Stripe (must be one of the\nFRONTEND_ORIGIN origins); PUBLIC_BASE_URL when empty",
                     "type": "string",
-                    "maxLength": 40
+                    "example": "https://partners.example.com"
+                }
+            }
+        },
+        "payments.CheckoutResponse": {
+            "type": "object",
+            "properties": {
+                "payment_id": {
+                    "type": "string"
                 },
-                "description": {
-                    "type": "string",
-                    "maxLength": 2000
+                "provider": {
+                    "type": "string"
                 },
-                "title": {
-                    "type": "string",
-                    "maxLength": 120,
-                    "minLength": 3
+                "redirect_url": {
+                    "type": "string"
                 }
             }
         },
-        "cases.MarketCaseItem": {
+        "payments.MockCompleteRequest": {
             "type": "object",
             "properties": {
-                "category": {
+                "payment_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "payments.PaymentSummary": {
+            "type": "object",
+            "properties": {
+                "amount_cents": {
+                    "type": "integer"
+                },
+                "case_id": {
+                    "type": "string"
+                },
+                "case_title": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "has_my_quote": {
-                    "description": "FE can use this to disable \"submit quote\"",
-                    "type": "boolean"
+                "currency": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "preview": {
+                "quote_id": {
                     "type": "string"
                 },
-                "title": {
+                "quote_note": {
+                    "description": "PII-redacted until the quote is accepted",
+                    "type": "string"
+                },
+                "receipt_url": {
+                    "description": "Stripe-hosted receipt once paid (stripe provider only)",
                     "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.PayStatus"
                 }
             }
         },
-        "cases.PageCases": {
+        "quotes.AmountSummary": {
             "type": "object",
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/cases.CaseListItem"
-                    }
-                },
-                "page": {
+                "count": {
                     "type": "integer"
                 },
-                "pageSize": {
+                "currency": {
+                    "type": "string"
+                },
+                "max_cents": {
                     "type": "integer"
                 },
-                "pages": {
+                "median_cents": {
+                    "description": "rounded to the nearest cent",
                     "type": "integer"
                 },
-                "total": {
+                "min_cents": {
                     "type": "integer"
                 }
             }
         },
-        "cases.PageMarketCases": {
+        "quotes.LawyerStats": {
             "type": "object",
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/cases.MarketCaseItem"
+                "accepted": {
+                    "type": "integer"
+                },
+                "earned_cents": {
+                    "description": "Paid payments for accepted quotes, per currency (ISO-4217 → cents)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
                     }
                 },
-                "page": {
+                "engaged_cases": {
                     "type": "integer"
                 },
-                "pageSize": {
+                "proposed": {
                     "type": "integer"
                 },
-                "pages": {
+                "rejected": {
                     "type": "integer"
                 },
-                "total": {
-                    "type": "integer"
+                "win_rate": {
+                    "description": "Accepted / (accepted + rejected); 0 until a quote has been decided",
+                    "type": "number"
                 }
             }
         },
-        "models.CaseStatus": {
-            "type": "string",
-            "enum": [
-                "open",
-                "engaged",
-                "closed",
-                "cancelled"
-            ],
-            "x-enum-varnames": [
-                "CaseOpen",
-                "CaseEngaged",
-                "CaseClosed",
-                "CaseCancelled"
-            ]
-        },
-        "models.ErrorResponse": {
+        "quotes.MyQuoteItem": {
             "type": "object",
             "properties": {
-                "code": {
-                    "description": "machine-friendly error code",
-                    "type": "string",
-                    "example": "FORBIDDEN"
-                },
-                "error": {
-                    "description": "always true for error cases",
-                    "type": "boolean",
-                    "example": true
+                "amount_cents": {
+                    "type": "integer"
                 },
-                "message": {
-                    "description": "human-readable error message",
-                    "type": "string",
-                    "example": "Forbidden"
-                }
-            }
-        },
-        "models.Role": {
-            "type": "string",
-            "enum": [
-                "client",
-                "lawyer"
-            ],
-            "x-enum-varnames": [
-                "RoleClient",
-                "RoleLawyer"
-            ]
-        },
-        "models.User": {
-            "type": "object",
-            "properties": {
-                "barNumber": {
+                "case_category": {
+                    "description": "optional",
                     "type": "string"
                 },
-                "createdAt": {
+                "case_id": {
                     "type": "string"
                 },
-                "email": {
+                "case_status": {
+                    "description": "optional",
                     "type": "string"
                 },
-                "id": {
+                "case_title": {
+                    "description": "from cases.title",
                     "type": "string"
                 },
-                "jurisdiction": {
+                "created_at": {
                     "type": "string"
                 },
-                "name": {
+                "currency": {
                     "type": "string"
                 },
-                "passwordHash": {
-                    "type": "string"
+                "days": {
+                    "type": "integer"
                 },
-                "role": {
-                    "$ref": "#/definitions/models.Role"
-                }
-            }
-        },
-        "models.ValidationErrorResponse": {
-            "type": "object",
-            "properties": {
-                "errors": {
-                    "description": "field name -\u003e list of error messages",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
-                    }
+                "expires_at": {
+                    "type": "string"
                 },
-                "message": {
-                    "type": "string",
-                    "example": "Validation failed"
-                }
-            }
-        },
-        "payments.CheckoutResponse": {
-            "type": "object",
-            "properties": {
-                "payment_id": {
+                "id": {
                     "type": "string"
                 },
-                "provider": {
+                "note": {
                     "type": "string"
                 },
-                "redirect_url": {
+                "pitch": {
                     "type": "string"
-                }
-            }
-        },
-        "payments.MockCompleteRequest": {
-            "type": "object",
-            "properties": {
-                "payment_id": {
+                },
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "quotes.MyQuoteItem": {
+        "quotes.QuoteDetail": {
             "type": "object",
             "properties": {
                 "amount_cents": {
                     "type": "integer"
                 },
-                "case_category": {
-                    "description": "optional",
-                    "type": "string"
-                },
                 "case_id": {
                     "type": "string"
                 },
-                "case_status": {
-                    "description": "optional",
-                    "type": "string"
-                },
-                "case_title": {
-                    "description": "from cases.title",
+                "created_at": {
                     "type": "string"
                 },
-                "created_at": {
+                "currency": {
                     "type": "string"
                 },
                 "days": {
                     "type": "integer"
                 },
+                "expires_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "lawyer_id": {
+                    "type": "string"
+                },
                 "note": {
                     "type": "string"
                 },
+                "pitch": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "quotes.PageMyQuotes": {
+        "quotes.QuoteRevisionItem": {
             "type": "object",
             "properties": {
-                "items": {
+                "amount_cents": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "note": {
+                    "description": "redacted for the client like the quote itself",
+                    "type": "string"
+                }
+            }
+        },
+        "quotes.QuoteSummary": {
+            "type": "object",
+            "properties": {
+                "amounts": {
+                    "description": "Amounts can't be compared across currencies: one entry each",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/quotes.MyQuoteItem"
+                        "$ref": "#/definitions/quotes.AmountSummary"
                     }
                 },
-                "page": {
-                    "type": "integer"
-                },
-                "pageSize": {
+                "count": {
                     "type": "integer"
                 },
-                "pages": {
+                "max_days": {
                     "type": "integer"
                 },
-                "total": {
+                "min_days": {
+                    "description": "nil when there are no live quotes",
                     "type": "integer"
                 }
             }
         },
+        "quotes.RejectQuoteRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
         "quotes.UpsertQuoteRequest": {
             "type": "object",
             "required": [
@@ -1371,7 +4925,7 @@ const docTemplate = `{
             ],
             "properties": {
                 "amount_cents": {
-                    "description": "min S$10, max S$1,000,000",
+                    "description": "max S$1,000,000",
                     "type": "integer",
                     "maximum": 100000000,
                     "minimum": 1
@@ -1379,6 +4933,10 @@ const docTemplate = `{
                 "case_id": {
                     "type": "string"
                 },
+                "currency": {
+                    "description": "ISO-4217; defaults to STRIPE_CURRENCY",
+                    "type": "string"
+                },
                 "days": {
                     "type": "integer",
                     "maximum": 365,
@@ -1387,6 +4945,85 @@ const docTemplate = `{
                 "note": {
                     "type": "string",
                     "maxLength": 500
+                },
+                "pitch": {
+                    "description": "cover letter for the client",
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "valid_until": {
+                    "description": "Optional; defaults to now + QUOTE_VALIDITY. Must be in the future.",
+                    "type": "string"
+                }
+            }
+        },
+        "webhooks.CreateEndpointRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "webhooks.CreateEndpointResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "shown once; used to verify X-Webhook-Signature",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "webhooks.EndpointItem": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         }
@@ -0,0 +1,320 @@
+package account
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/internal/storage"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+/* ============================================================================
+   Helpers
+   ============================================================================ */
+
+// openTestDB connects to TEST_DATABASE_URL, migrates tables, and truncates them
+// after the test finishes.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	_ = godotenv.Load()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_URL is empty")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.User{}, &models.Case{}, &models.CaseFile{}, &models.CaseHistory{},
+		&models.Quote{}, &models.QuoteRevision{}, &models.Payment{}, &models.Message{},
+		&models.Notification{}, &models.SavedCase{}, &models.CaseCollaborator{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	t.Cleanup(func() {
+		sql := `
+TRUNCATE TABLE
+	case_collaborators,
+	saved_cases,
+	notifications,
+	messages,
+	payments,
+	quote_revisions,
+	quotes,
+	case_histories,
+	case_files,
+	cases,
+	users
+RESTART IDENTITY CASCADE`
+		if err := db.Exec(sql).Error; err != nil {
+			t.Logf("truncate failed (ignored): %v", err)
+		}
+	})
+
+	return db
+}
+
+// injectAuth sets Locals so MustUserID reads identity properly.
+func injectAuth(userID uuid.UUID, role string) fiber.Handler {
+	id := userID.String()
+	return func(c *fiber.Ctx) error {
+		c.Locals("userID", id)
+		c.Locals("role", role)
+		return c.Next()
+	}
+}
+
+func newTestApp(h *Handler, userID uuid.UUID, role string) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+	app.Use(injectAuth(userID, role))
+	app.Delete("/api/me", h.DeleteMe)
+	return app
+}
+
+// recordingStorage is a storage backend that remembers request bodies.
+func recordingStorage(t *testing.T) (*storage.Supabase, func() []string) {
+	t.Helper()
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, r.Method+" "+r.URL.Path+" "+string(b))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("SUPABASE_URL", srv.URL)
+	t.Setenv("SUPABASE_BUCKET", "test")
+	return storage.NewSupabase(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), bodies...)
+	}
+}
+
+func mkUser(t *testing.T, db *gorm.DB, role models.Role) models.User {
+	t.Helper()
+	u := models.User{
+		ID: uuid.New(), Role: role, Name: "Jane Doe", PasswordHash: "x",
+		ContactPhone: "+6591234567",
+	}
+	u.Email = string(role) + "_" + u.ID.String()[:8] + "@x.com"
+	if err := db.Create(&u).Error; err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func mkCase(t *testing.T, db *gorm.DB, clientID uuid.UUID, status models.CaseStatus) models.Case {
+	t.Helper()
+	cs := models.Case{
+		ID: uuid.New(), ClientID: clientID, Title: "T", Category: "Family",
+		Description: "call me on +6591234567", Status: status, CreatedAt: time.Now(),
+	}
+	if err := db.Create(&cs).Error; err != nil {
+		t.Fatal(err)
+	}
+	return cs
+}
+
+/* ============================================================================
+   Tests — account deletion
+   ============================================================================ */
+
+// A client with only open cases is deleted: cases and everything on them go
+// (storage objects too), the user row stays but anonymized.
+func Test_DeleteMe_ClientCleanDeletion(t *testing.T) {
+	sb, calls := recordingStorage(t)
+	db := openTestDB(t)
+	client := mkUser(t, db, models.RoleClient)
+	lawyer := mkUser(t, db, models.RoleLawyer)
+
+	cs := mkCase(t, db, client.ID, models.CaseOpen)
+	file := models.CaseFile{CaseID: cs.ID, Key: "case/" + cs.ID.String() + "/a.pdf", Mime: "application/pdf", Size: 1, OriginalName: "a.pdf", CreatedAt: time.Now()}
+	q := models.Quote{CaseID: cs.ID, LawyerID: lawyer.ID, AmountCents: 5000, Days: 3, Status: models.QuoteProposed}
+	for _, row := range []any{&file, &q} {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = db.Create(&models.QuoteRevision{QuoteID: q.ID, AmountCents: 5000, Days: 3, CreatedAt: time.Now()}).Error
+	_ = db.Create(&models.SavedCase{LawyerID: lawyer.ID, CaseID: cs.ID}).Error
+
+	resp, _ := newTestApp(NewHandler(db, sb), client.ID, string(models.RoleClient)).
+		Test(httptest.NewRequest("DELETE", "/api/me", nil))
+	if resp.StatusCode != 200 {
+		t.Fatalf("want 200, got %d", resp.StatusCode)
+	}
+
+	for _, m := range []any{&models.Case{}, &models.CaseFile{}, &models.Quote{}, &models.QuoteRevision{}, &models.SavedCase{}} {
+		var n int64
+		db.Unscoped().Model(m).Count(&n)
+		if n != 0 {
+			t.Fatalf("%T: want all rows gone, got %d", m, n)
+		}
+	}
+	if c := calls(); len(c) != 1 || !strings.Contains(c[0], "/remove") || !strings.Contains(c[0], file.Key) {
+		t.Fatalf("want one bulk delete with the file key, got %v", c)
+	}
+
+	var u models.User
+	db.First(&u, "id = ?", client.ID)
+	if u.Email == client.Email || u.Name == client.Name || u.ContactPhone != "" || u.PasswordHash == client.PasswordHash {
+		t.Fatalf("user row should be anonymized, got %+v", u)
+	}
+}
+
+// An active engagement blocks deletion for both parties with a 409 code,
+// and nothing changes.
+func Test_DeleteMe_BlockedByActiveEngagement(t *testing.T) {
+	db := openTestDB(t)
+	client := mkUser(t, db, models.RoleClient)
+	lawyer := mkUser(t, db, models.RoleLawyer)
+	cs := mkCase(t, db, client.ID, models.CaseEngaged)
+	db.Model(&models.Case{}).Where("id = ?", cs.ID).Update("accepted_lawyer_id", lawyer.ID)
+
+	h := NewHandler(db, nil)
+	for _, who := range []models.User{client, lawyer} {
+		resp, _ := newTestApp(h, who.ID, string(who.Role)).Test(httptest.NewRequest("DELETE", "/api/me", nil))
+		var e models.ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		if resp.StatusCode != 409 || e.Code != apperr.AccountHasEngagements {
+			t.Fatalf("%s: want 409 %s, got %d %+v", who.Role, apperr.AccountHasEngagements, resp.StatusCode, e)
+		}
+
+		var u models.User
+		db.First(&u, "id = ?", who.ID)
+		if u.Email != who.Email {
+			t.Fatalf("%s: account should be untouched, got %+v", who.Role, u)
+		}
+	}
+	var n int64
+	db.Model(&models.Case{}).Where("id = ?", cs.ID).Count(&n)
+	if n != 1 {
+		t.Fatal("engaged case should be kept")
+	}
+}
+
+// A checkout in progress on an open case blocks a client's deletion: the
+// Stripe session could still complete against the deleted case.
+func Test_DeleteMe_ClientBlockedByCheckoutInProgress(t *testing.T) {
+	db := openTestDB(t)
+	client := mkUser(t, db, models.RoleClient)
+	lawyer := mkUser(t, db, models.RoleLawyer)
+	cs := mkCase(t, db, client.ID, models.CaseOpen)
+	q := models.Quote{CaseID: cs.ID, LawyerID: lawyer.ID, AmountCents: 5000, Days: 3, Status: models.QuoteProposed}
+	if err := db.Create(&q).Error; err != nil {
+		t.Fatal(err)
+	}
+	pay := models.Payment{CaseID: cs.ID, QuoteID: q.ID, ClientID: client.ID, AmountCents: 5000, Status: models.PayInitiated}
+	if err := db.Create(&pay).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	resp, _ := newTestApp(NewHandler(db, nil), client.ID, string(models.RoleClient)).
+		Test(httptest.NewRequest("DELETE", "/api/me", nil))
+	var e models.ErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&e)
+	if resp.StatusCode != 409 || e.Code != apperr.AccountHasEngagements {
+		t.Fatalf("want 409 %s, got %d %+v", apperr.AccountHasEngagements, resp.StatusCode, e)
+	}
+	var n int64
+	db.Model(&models.Payment{}).Where("id = ?", pay.ID).Count(&n)
+	if n != 1 {
+		t.Fatal("payment should be kept")
+	}
+}
+
+// A lawyer without engagements is anonymized; their open proposal is
+// withdrawn and its note cleared, the client's case stays.
+func Test_DeleteMe_LawyerWithdrawsProposals(t *testing.T) {
+	db := openTestDB(t)
+	client := mkUser(t, db, models.RoleClient)
+	lawyer := mkUser(t, db, models.RoleLawyer)
+	cs := mkCase(t, db, client.ID, models.CaseOpen)
+	q := models.Quote{CaseID: cs.ID, LawyerID: lawyer.ID, AmountCents: 5000, Days: 3, Note: "email me", Status: models.QuoteProposed}
+	if err := db.Create(&q).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	resp, _ := newTestApp(NewHandler(db, nil), lawyer.ID, string(models.RoleLawyer)).
+		Test(httptest.NewRequest("DELETE", "/api/me", nil))
+	if resp.StatusCode != 200 {
+		t.Fatalf("want 200, got %d", resp.StatusCode)
+	}
+
+	db.First(&q, "id = ?", q.ID)
+	if q.Status != models.QuoteRejected || q.Note != "" {
+		t.Fatalf("proposal should be withdrawn and cleared, got %+v", q)
+	}
+	var n int64
+	db.Model(&models.Case{}).Where("id = ?", cs.ID).Count(&n)
+	if n != 1 {
+		t.Fatal("client's case should be kept")
+	}
+}
+
+// After DELETE /me the token used for it is dead: RequireAuth answers 401
+// on any route, including a second delete.
+func Test_DeleteMe_OldTokenRejected(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-0123456789abcdefghijklmnop")
+	db := openTestDB(t)
+	client := mkUser(t, db, models.RoleClient)
+	token, err := auth.IssueToken(client.ID.String(), string(client.Role), client.Name, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+	app.Get("/api/ping", auth.RequireAuth(db), func(c *fiber.Ctx) error { return c.SendStatus(200) })
+	app.Delete("/api/me", auth.RequireAuth(db), NewHandler(db, nil).DeleteMe)
+	call := func(method, path string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+
+	if code := call("GET", "/api/ping"); code != 200 {
+		t.Fatalf("before deletion: want 200, got %d", code)
+	}
+	if code := call("DELETE", "/api/me"); code != 200 {
+		t.Fatalf("delete: want 200, got %d", code)
+	}
+	if code := call("GET", "/api/ping"); code != 401 {
+		t.Fatalf("old token after deletion: want 401, got %d", code)
+	}
+	if code := call("DELETE", "/api/me"); code != 401 {
+		t.Fatalf("second delete: want 401, got %d", code)
+	}
+
+	var u models.User
+	db.First(&u, "id = ?", client.ID)
+	if u.DeletedAt == nil {
+		t.Fatal("deleted_at should be set")
+	}
+}
@@ -0,0 +1,236 @@
+package account
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/internal/storage"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+/* ============================== Handler ================================== */
+
+type Handler struct {
+	db *gorm.DB
+	sb *storage.Supabase // optional; nil skips storage cleanup (tests)
+}
+
+func NewHandler(db *gorm.DB, sb *storage.Supabase) *Handler {
+	return &Handler{db: db, sb: sb}
+}
+
+// errHasEngagements blocks a deletion that would erase the other party's
+// record of an engagement (or a checkout in progress).
+var errHasEngagements = apperr.Conflict(apperr.AccountHasEngagements,
+	"your account has active or past engagements; it can't be deleted")
+
+/* ============================ Delete Account ============================= */
+
+// @Summary      Delete my account
+// @Description  Clients without engaged/closed cases, paid payments or a checkout in progress: their cases, files (storage too, best-effort), quotes on them and related rows are deleted. Lawyers without an engaged case or a checkout in progress on one of their quotes: open proposals are withdrawn and quote notes/pitches cleared (accepted quotes stay on the client's record). Either way the user row is anonymized (email, name, contact details, password) so it can't sign in again, tokens already issued stop working, and the deletion is logged.
+// @Tags         auth
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  map[string]string  "status: deleted"
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse  "admin accounts"
+// @Failure      409  {object}  models.ErrorResponse  "ACCOUNT_HAS_ENGAGEMENTS"
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /me [delete]
+func (h *Handler) DeleteMe(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(auth.MustUserID(c))
+	if err != nil {
+		return fiber.ErrUnauthorized
+	}
+
+	var (
+		role  models.Role
+		cases []uuid.UUID // deleted cases (clients)
+		keys  []string    // their stored files
+	)
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the user so a parallel checkout/quote can't slip in
+		var u models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id, role").First(&u, "id = ?", userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fiber.ErrUnauthorized
+			}
+			return err
+		}
+		role = u.Role
+
+		switch u.Role {
+		case models.RoleClient:
+			var err error
+			if cases, keys, err = deleteClientData(tx, u.ID); err != nil {
+				return err
+			}
+		case models.RoleLawyer:
+			if err := retireLawyerData(tx, u.ID); err != nil {
+				return err
+			}
+		default:
+			return fiber.NewError(fiber.StatusForbidden, "admin accounts are removed by an operator")
+		}
+
+		// Rows that only ever concern this user
+		if err := tx.Where("user_id = ?", u.ID).Delete(&models.Notification{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("lawyer_id = ?", u.ID).Delete(&models.SavedCase{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", u.ID).Delete(&models.CaseCollaborator{}).Error; err != nil {
+			return err
+		}
+		return anonymize(tx, u.ID)
+	})
+	if err != nil {
+		var fe *fiber.Error
+		if errors.As(err, &fe) {
+			return err
+		}
+		return fiber.ErrInternalServerError
+	}
+	// Its tokens stop working now, not when the auth cache expires
+	auth.ForgetAccount(userID.String())
+
+	// Storage after commit: a failure leaves orphaned objects, not a half-deleted account
+	if h.sb != nil && len(keys) > 0 {
+		if err := h.sb.BulkDelete(keys); err != nil {
+			slog.Warn("account deletion: storage cleanup failed",
+				"user_id", userID, "files", len(keys), "error", err)
+		}
+	}
+
+	slog.Info("account deleted",
+		"request_id", auth.GetRequestID(c),
+		"user_id", userID,
+		"role", role,
+		"cases_deleted", len(cases),
+		"files_deleted", len(keys),
+	)
+	return c.JSON(fiber.Map{"status": "deleted"})
+}
+
+// deleteClientData hard-deletes a client's cases (soft-deleted ones too) and
+// every row hanging off them, returning the case IDs and storage keys. Cases
+// that were engaged, anything paid, or a checkout in progress (its Stripe
+// session could still complete) block the deletion.
+func deleteClientData(tx *gorm.DB, clientID uuid.UUID) ([]uuid.UUID, []string, error) {
+	var blocking int64
+	if err := tx.Unscoped().Model(&models.Case{}).
+		Where("client_id = ? AND status IN ?", clientID, []models.CaseStatus{models.CaseEngaged, models.CaseClosed}).
+		Count(&blocking).Error; err != nil {
+		return nil, nil, err
+	}
+	if blocking == 0 {
+		if err := tx.Model(&models.Payment{}).
+			Where("client_id = ? AND status IN ?", clientID, []models.PayStatus{models.PayPaid, models.PayInitiated}).
+			Count(&blocking).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+	if blocking > 0 {
+		return nil, nil, errHasEngagements
+	}
+
+	var caseIDs []uuid.UUID
+	if err := tx.Unscoped().Model(&models.Case{}).
+		Where("client_id = ?", clientID).Pluck("id", &caseIDs).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(caseIDs) == 0 {
+		return nil, nil, nil
+	}
+
+	var keys []string
+	if err := tx.Model(&models.CaseFile{}).
+		Where("case_id IN ?", caseIDs).Pluck("key", &keys).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Where("quote_id IN (?)", tx.Model(&models.Quote{}).Select("id").Where("case_id IN ?", caseIDs)).
+		Delete(&models.QuoteRevision{}).Error; err != nil {
+		return nil, nil, err
+	}
+	// Children first; cases last
+	for _, m := range []any{
+		&models.Payment{}, &models.Quote{}, &models.CaseFile{}, &models.CaseHistory{},
+		&models.Message{}, &models.Notification{}, &models.SavedCase{}, &models.CaseCollaborator{},
+	} {
+		if err := tx.Where("case_id IN ?", caseIDs).Delete(m).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := tx.Unscoped().Where("id IN ?", caseIDs).Delete(&models.Case{}).Error; err != nil {
+		return nil, nil, err
+	}
+	return caseIDs, keys, nil
+}
+
+// retireLawyerData withdraws a lawyer's open proposals and clears the free
+// text of every quote that wasn't accepted. Accepted quotes stay as the
+// client's record. An engaged case or a checkout in progress blocks it.
+func retireLawyerData(tx *gorm.DB, lawyerID uuid.UUID) error {
+	var blocking int64
+	if err := tx.Model(&models.Case{}).
+		Where("accepted_lawyer_id = ? AND status = ?", lawyerID, models.CaseEngaged).
+		Count(&blocking).Error; err != nil {
+		return err
+	}
+	if blocking == 0 {
+		if err := tx.Model(&models.Payment{}).
+			Joins("JOIN quotes ON quotes.id = payments.quote_id").
+			Where("quotes.lawyer_id = ? AND payments.status = ?", lawyerID, models.PayInitiated).
+			Count(&blocking).Error; err != nil {
+			return err
+		}
+	}
+	if blocking > 0 {
+		return errHasEngagements
+	}
+
+	open := tx.Model(&models.Quote{}).Select("id").
+		Where("lawyer_id = ? AND status <> ?", lawyerID, models.QuoteAccepted)
+	if err := tx.Where("quote_id IN (?)", open).Delete(&models.QuoteRevision{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.Quote{}).
+		Where("lawyer_id = ? AND status = ?", lawyerID, models.QuoteProposed).
+		Update("status", models.QuoteRejected).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Quote{}).
+		Where("lawyer_id = ? AND status <> ?", lawyerID, models.QuoteAccepted).
+		Updates(map[string]any{"note": "", "pitch": ""}).Error
+}
+
+// anonymize strips the user row of everything personal but keeps the ID, so
+// history, messages and accepted quotes still point somewhere. The password
+// hash can never match, so the account can't sign in again, and deleted_at
+// makes RequireAuth reject the tokens it already has.
+func anonymize(tx *gorm.DB, userID uuid.UUID) error {
+	return tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]any{
+		"deleted_at":              time.Now(),
+		"email":                   "deleted-" + userID.String() + "@deleted.invalid",
+		"password_hash":           "!",
+		"name":                    "Deleted user",
+		"jurisdiction":            "",
+		"bar_number":              "",
+		"contact_phone":           "",
+		"preferred_contact":       "",
+		"failed_attempts":         0,
+		"locked_until":            nil,
+		"email_verify_token_hash": "",
+		"email_verify_expires_at": nil,
+	}).Error
+}
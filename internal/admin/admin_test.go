@@ -0,0 +1,334 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/internal/cases"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+/* ============================================================================
+   Helpers
+   ============================================================================ */
+
+// openTestDB connects to TEST_DATABASE_URL, migrates tables, and truncates them
+// after the test finishes.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	_ = godotenv.Load()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_URL is empty")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.User{}, &models.Case{}, &models.CaseFile{},
+		&models.CaseHistory{}, &models.Quote{}, &models.Payment{}, &models.Notification{},
+		&models.CaseCollaborator{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	t.Cleanup(func() {
+		sql := `
+TRUNCATE TABLE
+	case_collaborators,
+	notifications,
+	payments,
+	case_histories,
+	case_files,
+	quotes,
+	cases,
+	users
+RESTART IDENTITY CASCADE`
+		if err := db.Exec(sql).Error; err != nil {
+			t.Logf("truncate failed (ignored): %v", err)
+		}
+	})
+
+	return db
+}
+
+// injectAuth sets Locals so MustUserID/MustRole read identity and role properly.
+func injectAuth(userID uuid.UUID, role string) fiber.Handler {
+	id := userID.String()
+	return func(c *fiber.Ctx) error {
+		c.Locals("userID", id)
+		c.Locals("role", role)
+		return c.Next()
+	}
+}
+
+// newTestApp mirrors the production admin group (role guard + audit log).
+func newTestApp(h *Handler, userID uuid.UUID, role string) *fiber.App {
+	app := fiber.New()
+	app.Use(injectAuth(userID, role))
+	adm := app.Group("/api/admin", auth.RequireRole("admin"), auth.AuditAdmin())
+	adm.Get("/cases", h.ListCases)
+	adm.Post("/cases/:id/reassign", h.Reassign)
+	adm.Get("/users", h.ListUsers)
+	return app
+}
+
+/* ============================================================================
+   Tests — admin access
+   ============================================================================ */
+
+// Non-admin roles are rejected before the handler runs.
+func Test_AdminRoutes_RejectNonAdmin(t *testing.T) {
+	for _, role := range []models.Role{models.RoleClient, models.RoleLawyer} {
+		app := newTestApp(NewHandler(nil), uuid.New(), string(role))
+		for _, path := range []string{"/api/admin/cases", "/api/admin/users"} {
+			resp, _ := app.Test(httptest.NewRequest("GET", path, nil))
+			if resp.StatusCode != 403 {
+				t.Fatalf("%s on %s: want 403, got %d", role, path, resp.StatusCode)
+			}
+		}
+	}
+}
+
+// Admin sees cases across clients and can filter by status.
+func Test_AdminListCases_AllClients_WithStatusFilter(t *testing.T) {
+	db := openTestDB(t)
+
+	adminID := uuid.New()
+	clientA, clientB := uuid.New(), uuid.New()
+	for _, u := range []models.User{
+		{ID: adminID, Email: "a_" + adminID.String()[:8] + "@x.com", Role: models.RoleAdmin},
+		{ID: clientA, Email: "ca_" + clientA.String()[:8] + "@x.com", Role: models.RoleClient},
+		{ID: clientB, Email: "cb_" + clientB.String()[:8] + "@x.com", Role: models.RoleClient},
+	} {
+		if err := db.Create(&u).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, cs := range []models.Case{
+		{ClientID: clientA, Title: "A open", Category: "Cat", Status: models.CaseOpen, CreatedAt: time.Now()},
+		{ClientID: clientB, Title: "B open", Category: "Cat", Status: models.CaseOpen, CreatedAt: time.Now()},
+		{ClientID: clientB, Title: "B closed", Category: "Cat", Status: models.CaseClosed, CreatedAt: time.Now()},
+	} {
+		if err := db.Create(&cs).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := newTestApp(NewHandler(db), adminID, string(models.RoleAdmin))
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/api/admin/cases?status=open", nil))
+	if resp.StatusCode != 200 {
+		t.Fatalf("want 200, got %d", resp.StatusCode)
+	}
+	var out PageAdminCases
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	if out.Total != 2 || len(out.Items) != 2 {
+		t.Fatalf("want 2 open cases across clients, got total=%d items=%d", out.Total, len(out.Items))
+	}
+
+	resp2, _ := app.Test(httptest.NewRequest("GET", "/api/admin/users?role=client", nil))
+	if resp2.StatusCode != 200 {
+		t.Fatalf("users: want 200, got %d", resp2.StatusCode)
+	}
+	var users PageAdminUsers
+	_ = json.NewDecoder(resp2.Body).Decode(&users)
+	if users.Total != 2 {
+		t.Fatalf("want 2 clients, got %d", users.Total)
+	}
+}
+
+/* ============================================================================
+   Tests — reassign lawyer
+   ============================================================================ */
+
+type engagedSeed struct {
+	AdminID, CaseID  uuid.UUID
+	Accepted, Runner models.Quote
+}
+
+// seedEngaged inserts an engaged case with an accepted quote and a second
+// lawyer's quote that was rejected at engagement.
+func seedEngaged(t *testing.T, db *gorm.DB, status models.CaseStatus) engagedSeed {
+	t.Helper()
+	adminID, clientID, lawyerA, lawyerB := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	for _, u := range []models.User{
+		{ID: adminID, Email: "a_" + adminID.String()[:8] + "@x.com", Role: models.RoleAdmin},
+		{ID: clientID, Email: "c_" + clientID.String()[:8] + "@x.com", Role: models.RoleClient},
+		{ID: lawyerA, Email: "la_" + lawyerA.String()[:8] + "@x.com", Role: models.RoleLawyer},
+		{ID: lawyerB, Email: "lb_" + lawyerB.String()[:8] + "@x.com", Role: models.RoleLawyer},
+	} {
+		if err := db.Create(&u).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	cs := models.Case{ID: uuid.New(), ClientID: clientID, Title: "T", Category: "Cat", Status: models.CaseOpen, CreatedAt: time.Now()}
+	if err := db.Create(&cs).Error; err != nil {
+		t.Fatal(err)
+	}
+	s := engagedSeed{AdminID: adminID, CaseID: cs.ID}
+	s.Accepted = models.Quote{CaseID: cs.ID, LawyerID: lawyerA, AmountCents: 1000, Days: 3, Status: models.QuoteAccepted}
+	s.Runner = models.Quote{CaseID: cs.ID, LawyerID: lawyerB, AmountCents: 1200, Days: 5, Status: models.QuoteRejected}
+	for _, q := range []*models.Quote{&s.Accepted, &s.Runner} {
+		if err := db.Create(q).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Model(&models.Case{}).Where("id = ?", cs.ID).Updates(map[string]any{
+		"status": status, "accepted_quote_id": s.Accepted.ID, "accepted_lawyer_id": lawyerA,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func reassign(t *testing.T, app *fiber.App, caseID, quoteID uuid.UUID) int {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/admin/cases/"+caseID.String()+"/reassign",
+		strings.NewReader(`{"quote_id":"`+quoteID.String()+`","reason":"lawyer unresponsive"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+// The case moves to the other lawyer's quote; the old one is rejected and
+// the switch is logged. Payments are left alone.
+func Test_Reassign_EngagedCase(t *testing.T) {
+	db := openTestDB(t)
+	s := seedEngaged(t, db, models.CaseEngaged)
+	app := newTestApp(NewHandler(db), s.AdminID, string(models.RoleAdmin))
+
+	if code := reassign(t, app, s.CaseID, s.Runner.ID); code != 200 {
+		t.Fatalf("want 200, got %d", code)
+	}
+
+	var cs models.Case
+	db.First(&cs, "id = ?", s.CaseID)
+	if cs.Status != models.CaseEngaged || cs.AcceptedQuoteID != s.Runner.ID || cs.AcceptedLawyerID != s.Runner.LawyerID {
+		t.Fatalf("case should point at the new quote/lawyer, got %+v", cs)
+	}
+	var oldQ, newQ models.Quote
+	db.First(&oldQ, "id = ?", s.Accepted.ID)
+	db.First(&newQ, "id = ?", s.Runner.ID)
+	if oldQ.Status != models.QuoteRejected || newQ.Status != models.QuoteAccepted {
+		t.Fatalf("want old rejected / new accepted, got %s / %s", oldQ.Status, newQ.Status)
+	}
+
+	var h models.CaseHistory
+	if err := db.First(&h, "case_id = ? AND action = ?", s.CaseID, "reassigned").Error; err != nil {
+		t.Fatalf("history entry missing: %v", err)
+	}
+	if h.ActorID != s.AdminID || !strings.Contains(h.Reason, "lawyer unresponsive") {
+		t.Fatalf("unexpected history entry: %+v", h)
+	}
+
+	// The now-accepted quote can't be chosen again
+	if code := reassign(t, app, s.CaseID, s.Runner.ID); code != 409 {
+		t.Fatalf("same quote again: want 409, got %d", code)
+	}
+}
+
+// Closed cases are final: nothing changes.
+func Test_Reassign_ClosedCase_Conflict(t *testing.T) {
+	db := openTestDB(t)
+	s := seedEngaged(t, db, models.CaseClosed)
+	app := newTestApp(NewHandler(db), s.AdminID, string(models.RoleAdmin))
+
+	if code := reassign(t, app, s.CaseID, s.Runner.ID); code != 409 {
+		t.Fatalf("want 409, got %d", code)
+	}
+	var cs models.Case
+	db.First(&cs, "id = ?", s.CaseID)
+	if cs.AcceptedQuoteID != s.Accepted.ID {
+		t.Fatalf("closed case must keep its accepted quote")
+	}
+}
+
+// Someone the old lawyer granted access to loses it once the case is
+// reassigned; the new lawyer can grant it again.
+func Test_Reassign_DropsPreviousLawyersCollaborators(t *testing.T) {
+	db := openTestDB(t)
+	s := seedEngaged(t, db, models.CaseEngaged)
+	para := models.User{ID: uuid.New(), Role: models.RoleLawyer}
+	para.Email = "p_" + para.ID.String()[:8] + "@x.com"
+	file := models.CaseFile{CaseID: s.CaseID, Key: "case/" + s.CaseID.String() + "/a.pdf", Mime: "application/pdf", Size: 1, OriginalName: "a.pdf", CreatedAt: time.Now()}
+	for _, row := range []any{&para, &file} {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Create(&models.CaseCollaborator{CaseID: s.CaseID, UserID: para.ID, GrantedBy: s.Accepted.LawyerID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ch := cases.NewHandler(db, nil)
+	paraApp := fiber.New()
+	paraApp.Use(injectAuth(para.ID, string(models.RoleLawyer)))
+	paraApp.Get("/api/cases/:id", ch.GetDetail)
+	paraApp.Get("/api/cases/:id/files", ch.ListFiles)
+	paraApp.Get("/api/files/:fileID/signed-url", ch.SignedDownloadURL)
+	paths := []string{
+		"/api/cases/" + s.CaseID.String(),
+		"/api/cases/" + s.CaseID.String() + "/files",
+		"/api/files/" + file.ID.String() + "/signed-url",
+	}
+	status := func(path string) int {
+		resp, err := paraApp.Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+	for _, p := range paths {
+		if code := status(p); code != 200 {
+			t.Fatalf("before reassign %s: want 200, got %d", p, code)
+		}
+	}
+
+	app := newTestApp(NewHandler(db), s.AdminID, string(models.RoleAdmin))
+	if code := reassign(t, app, s.CaseID, s.Runner.ID); code != 200 {
+		t.Fatalf("reassign: want 200, got %d", code)
+	}
+	for _, p := range paths {
+		if code := status(p); code != 404 {
+			t.Fatalf("after reassign %s: want 404, got %d", p, code)
+		}
+	}
+
+	// The new lawyer doesn't see the old grant and can make their own
+	newLawyer := fiber.New()
+	newLawyer.Use(injectAuth(s.Runner.LawyerID, string(models.RoleLawyer)))
+	newLawyer.Get("/api/cases/:id/collaborators", ch.ListCollaborators)
+	newLawyer.Post("/api/cases/:id/collaborators", ch.GrantCollaborator)
+	resp, _ := newLawyer.Test(httptest.NewRequest("GET", "/api/cases/"+s.CaseID.String()+"/collaborators", nil))
+	var list []cases.CollaboratorItem
+	_ = json.NewDecoder(resp.Body).Decode(&list)
+	if len(list) != 0 {
+		t.Fatalf("want no collaborators for the new lawyer, got %+v", list)
+	}
+	req := httptest.NewRequest("POST", "/api/cases/"+s.CaseID.String()+"/collaborators",
+		strings.NewReader(`{"email":"`+para.Email+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if resp, _ := newLawyer.Test(req); resp.StatusCode != 201 {
+		t.Fatalf("re-grant: want 201, got %d", resp.StatusCode)
+	}
+	if code := status(paths[2]); code != 200 {
+		t.Fatalf("after re-grant: want 200, got %d", code)
+	}
+}
@@ -0,0 +1,302 @@
+package admin
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apitime"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/pagination"
+	"github.com/aldoetobex/legal-mp-backend/pkg/utils"
+	"github.com/aldoetobex/legal-mp-backend/pkg/validation"
+)
+
+/* =============================== DTOs ==================================== */
+
+// AdminCaseItem is the list item shape for the admin case browser.
+type AdminCaseItem struct {
+	ID               uuid.UUID         `json:"id"`
+	Reference        *string           `json:"reference,omitempty"` // nil on legacy cases
+	ClientID         uuid.UUID         `json:"client_id"`
+	Title            string            `json:"title"`
+	Category         string            `json:"category"`
+	Status           models.CaseStatus `json:"status"`
+	AcceptedLawyerID uuid.UUID         `json:"accepted_lawyer_id"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+	DeletedAt        *time.Time        `json:"deleted_at"` // set when the owner soft-deleted it
+}
+
+type PageAdminCases = pagination.Page[AdminCaseItem]
+
+// AdminUserItem is the list item shape for the admin user browser (no secrets).
+type AdminUserItem struct {
+	ID           uuid.UUID   `json:"id"`
+	Email        string      `json:"email"`
+	Role         models.Role `json:"role"`
+	Name         string      `json:"name"`
+	Jurisdiction string      `json:"jurisdiction"`
+	BarNumber    string      `json:"bar_number"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+type PageAdminUsers = pagination.Page[AdminUserItem]
+
+// ReassignRequest names the quote that takes over an engaged case.
+type ReassignRequest struct {
+	QuoteID string `json:"quote_id" validate:"required,uuid"`
+	Reason  string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+type ReassignResponse struct {
+	CaseID           uuid.UUID `json:"case_id"`
+	AcceptedQuoteID  uuid.UUID `json:"accepted_quote_id"`
+	AcceptedLawyerID uuid.UUID `json:"accepted_lawyer_id"`
+	PreviousQuoteID  uuid.UUID `json:"previous_quote_id"`
+}
+
+/* ============================== Handler ================================== */
+
+type Handler struct{ db *gorm.DB }
+
+func NewHandler(db *gorm.DB) *Handler { return &Handler{db: db} }
+
+/* ============================== List Cases =============================== */
+
+// @Summary      List all cases (admin)
+// @Description  Admin browses every case, including soft-deleted ones, with optional filters (paginated)
+// @Tags         admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page       query int    false "page"
+// @Param        pageSize   query int    false "pageSize"
+// @Param        status     query string false "open|paused|engaged|closed|cancelled"
+// @Param        category   query string false "category"
+// @Param        client_id  query string false "client id (uuid)"
+// @Param        lawyer_id  query string false "accepted lawyer id (uuid)"
+// @Success      200  {object}  pagination.Page[admin.AdminCaseItem]
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Router       /admin/cases [get]
+func (h *Handler) ListCases(c *fiber.Ctx) error {
+	page, size := pagination.Parse(c)
+
+	// Unscoped: admins see soft-deleted cases too (deleted_at is set on those)
+	q := h.db.Unscoped().Model(&models.Case{})
+
+	// Optional filters
+	if status := strings.TrimSpace(c.Query("status")); status != "" {
+		switch models.CaseStatus(status) {
+		case models.CaseOpen, models.CasePaused, models.CaseEngaged, models.CaseClosed, models.CaseCancelled:
+			q = q.Where("status = ?", status)
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "invalid status filter")
+		}
+	}
+	if category := strings.TrimSpace(c.Query("category")); category != "" {
+		q = q.Where("category = ?", category)
+	}
+	if v := strings.TrimSpace(c.Query("client_id")); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid client_id")
+		}
+		q = q.Where("client_id = ?", id)
+	}
+	if v := strings.TrimSpace(c.Query("lawyer_id")); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid lawyer_id")
+		}
+		q = q.Where("accepted_lawyer_id = ?", id)
+	}
+
+	// Count first
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	// Load page
+	items := make([]AdminCaseItem, 0, size)
+	if err := q.
+		Select("id, reference, client_id, title, category, status, accepted_lawyer_id, created_at, updated_at, deleted_at").
+		Order("created_at DESC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Scan(&items).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	for i := range items {
+		items[i].CreatedAt = apitime.Normalize(items[i].CreatedAt)
+		items[i].UpdatedAt = apitime.Normalize(items[i].UpdatedAt)
+		items[i].DeletedAt = apitime.NormalizePtr(items[i].DeletedAt)
+	}
+
+	return c.JSON(pagination.New(c, page, size, total, items))
+}
+
+/* ============================== List Users =============================== */
+
+// @Summary      List all users (admin)
+// @Description  Admin browses users with optional role/email filters (paginated). Never returns password hashes.
+// @Tags         admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page      query int    false "page"
+// @Param        pageSize  query int    false "pageSize"
+// @Param        role      query string false "client|lawyer|admin"
+// @Param        email     query string false "email contains (case-insensitive)"
+// @Success      200  {object}  pagination.Page[admin.AdminUserItem]
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Router       /admin/users [get]
+func (h *Handler) ListUsers(c *fiber.Ctx) error {
+	page, size := pagination.Parse(c)
+
+	q := h.db.Model(&models.User{})
+
+	// Optional filters
+	if role := strings.TrimSpace(c.Query("role")); role != "" {
+		switch models.Role(role) {
+		case models.RoleClient, models.RoleLawyer, models.RoleAdmin:
+			q = q.Where("role = ?", role)
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "invalid role filter")
+		}
+	}
+	if email := strings.ToLower(strings.TrimSpace(c.Query("email"))); email != "" {
+		q = q.Where("LOWER(email) LIKE ?", "%"+email+"%")
+	}
+
+	// Count first
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	// Load page (explicit columns: never select password_hash)
+	items := make([]AdminUserItem, 0, size)
+	if err := q.
+		Select("id, email, role, name, jurisdiction, bar_number, created_at").
+		Order("created_at DESC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Scan(&items).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(pagination.New(c, page, size, total, items))
+}
+
+/* ============================= Reassign Case ============================= */
+
+// @Summary      Reassign an engaged case to another lawyer (admin)
+// @Description  Support switches an engaged case to another quote on it (e.g. the accepted lawyer went silent): the current accepted quote is rejected, the chosen one accepted, and a "reassigned" history entry logged. Quotes rejected when the case was engaged may be chosen. Payments are not touched (refunds are handled separately).
+// @Tags         admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string           true  "case id (uuid)"
+// @Param        payload  body  ReassignRequest  true  "new quote and reason"
+// @Success      200  {object}  ReassignResponse
+// @Failure      400  {object}  models.ValidationErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse  "CASE_NOT_ENGAGED, QUOTE_ALREADY_ACCEPTED"
+// @Router       /admin/cases/{id}/reassign [post]
+func (h *Handler) Reassign(c *fiber.Ctx) error {
+	adminID := uuid.MustParse(auth.MustUserID(c))
+	caseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid case id")
+	}
+	var in ReassignRequest
+	if err := c.BodyParser(&in); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid json")
+	}
+	in.Reason = strings.TrimSpace(in.Reason)
+	if errs, _ := validation.Validate(in); errs != nil {
+		return validation.Respond(c, errs)
+	}
+
+	tx := h.db.Begin()
+
+	// Lock the case so a concurrent close/reassign can't interleave
+	var cs models.Case
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&cs, "id = ?", caseID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	if cs.Status != models.CaseEngaged {
+		tx.Rollback()
+		return apperr.Conflict(apperr.CaseNotEngaged, "only engaged cases can be reassigned")
+	}
+
+	var q models.Quote
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&q, "id = ? AND case_id = ?", in.QuoteID, cs.ID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "quote not found on this case")
+		}
+		return fiber.ErrInternalServerError
+	}
+	if q.Status == models.QuoteAccepted || q.ID == cs.AcceptedQuoteID {
+		tx.Rollback()
+		return apperr.Conflict(apperr.QuoteAlreadyAccepted, "quote is already the accepted one")
+	}
+
+	// Old winner out first so the single-accepted index never sees two
+	now := time.Now()
+	if cs.AcceptedQuoteID != uuid.Nil {
+		if err := tx.Model(&models.Quote{}).Where("id = ?", cs.AcceptedQuoteID).
+			Updates(map[string]any{"status": models.QuoteRejected, "updated_at": now}).Error; err != nil {
+			tx.Rollback()
+			return fiber.ErrInternalServerError
+		}
+	}
+	if err := tx.Model(&models.Quote{}).Where("id = ?", q.ID).
+		Updates(map[string]any{"status": models.QuoteAccepted, "updated_at": now}).Error; err != nil {
+		tx.Rollback()
+		return fiber.ErrInternalServerError
+	}
+	if err := tx.Model(&models.Case{}).Where("id = ?", cs.ID).
+		Updates(map[string]any{
+			"accepted_quote_id":  q.ID,
+			"accepted_lawyer_id": q.LawyerID,
+		}).Error; err != nil {
+		tx.Rollback()
+		return fiber.ErrInternalServerError
+	}
+
+	// History (status stays engaged)
+	utils.LogCaseHistory(c.Context(), tx, cs.ID, adminID, models.ActorUser, "reassigned", cs.Status, cs.Status,
+		"quote "+cs.AcceptedQuoteID.String()+" → "+q.ID.String()+": "+in.Reason)
+
+	if err := tx.Commit().Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(ReassignResponse{
+		CaseID:           cs.ID,
+		AcceptedQuoteID:  q.ID,
+		AcceptedLawyerID: q.LawyerID,
+		PreviousQuoteID:  cs.AcceptedQuoteID,
+	})
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+/* ============================================================================
+   Account check behind RequireAuth
+   ============================================================================ */
+
+// defaultAccountCheckTTL is how long a live account is trusted without
+// another lookup when AUTH_ACCOUNT_CHECK_TTL is unset.
+const defaultAccountCheckTTL = 30 * time.Second
+
+// accountCacheMax bounds the cache; past it, expired entries are swept.
+const accountCacheMax = 10000
+
+// activeAccounts remembers accounts recently seen alive (user ID → expiry),
+// so RequireAuth doesn't hit the users table on every request.
+var activeAccounts = struct {
+	sync.Mutex
+	until map[string]time.Time
+}{until: map[string]time.Time{}}
+
+// accountCheckTTL reads AUTH_ACCOUNT_CHECK_TTL (Go duration, default 30s).
+// "0" looks the account up on every request.
+func accountCheckTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("AUTH_ACCOUNT_CHECK_TTL")); err == nil && d >= 0 {
+		return d
+	}
+	return defaultAccountCheckTTL
+}
+
+// accountActive reports whether the token's user still exists and isn't
+// deleted. A live account is cached for accountCheckTTL; deleted or missing
+// ones are never cached.
+func accountActive(db *gorm.DB, sub string) (bool, error) {
+	id, err := uuid.Parse(sub)
+	if err != nil {
+		return false, nil
+	}
+	now := time.Now()
+	activeAccounts.Lock()
+	until, ok := activeAccounts.until[sub]
+	activeAccounts.Unlock()
+	if ok && now.Before(until) {
+		return true, nil
+	}
+
+	var u models.User
+	err = db.Select("id").Where("id = ? AND deleted_at IS NULL", id).Take(&u).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ForgetAccount(sub)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if ttl := accountCheckTTL(); ttl > 0 {
+		activeAccounts.Lock()
+		if len(activeAccounts.until) >= accountCacheMax {
+			for k, t := range activeAccounts.until {
+				if now.After(t) {
+					delete(activeAccounts.until, k)
+				}
+			}
+		}
+		activeAccounts.until[sub] = now.Add(ttl)
+		activeAccounts.Unlock()
+	}
+	return true, nil
+}
+
+// ForgetAccount drops a user from the cache so their tokens are checked
+// against the database on the next request. Call it after deleting an
+// account; other instances notice within AUTH_ACCOUNT_CHECK_TTL.
+func ForgetAccount(userID string) {
+	activeAccounts.Lock()
+	delete(activeAccounts.until, userID)
+	activeAccounts.Unlock()
+}
@@ -0,0 +1,950 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/mailer"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+/* ============================================================================
+   Helpers
+   ============================================================================ */
+
+// testSecret is a JWT_SECRET long enough for MinSecretLen.
+const testSecret = "test-secret-0123456789abcdefghijklmnop"
+
+// openTestDB connects to TEST_DATABASE_URL, migrates users, and truncates them
+// after the test finishes.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	_ = godotenv.Load()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_URL is empty")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	// Same functional index as main.go
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS ux_users_email_lower ON users (lower(email))`).Error; err != nil {
+		t.Fatalf("email index: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := db.Exec(`TRUNCATE TABLE users RESTART IDENTITY CASCADE`).Error; err != nil {
+			t.Logf("truncate failed (ignored): %v", err)
+		}
+	})
+
+	return db
+}
+
+// newTestApp exposes the public auth endpoints with the production error handler.
+func newTestApp(h *Handler) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Post("/api/signup", h.Signup)
+	app.Post("/api/login", h.Login)
+	app.Post("/api/auth/verify-email", h.VerifyEmail)
+	return app
+}
+
+// injectAuth sets Locals so MustUserID/MustRole read identity and role properly.
+func injectAuth(userID uuid.UUID, role string) fiber.Handler {
+	id := userID.String()
+	return func(c *fiber.Ctx) error {
+		c.Locals("userID", id)
+		c.Locals("role", role)
+		return c.Next()
+	}
+}
+
+// newProfileApp exposes PATCH /api/me for the given identity.
+func newProfileApp(h *Handler, userID uuid.UUID, role string) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(injectAuth(userID, role))
+	app.Patch("/api/me", h.UpdateMe)
+	return app
+}
+
+// patchJSON sends a JSON PATCH and returns the response.
+func patchJSON(t *testing.T, app *fiber.App, path, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest("PATCH", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// postJSON sends a JSON POST through the test app.
+func postJSON(t *testing.T, app *fiber.App, path, body string) int {
+	t.Helper()
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+// seedUser inserts a client with the given password (cheap bcrypt cost).
+func seedUser(t *testing.T, db *gorm.DB, email, password string) models.User {
+	t.Helper()
+	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	u := models.User{Email: email, PasswordHash: string(hash), Role: models.RoleClient, Name: "Ann"}
+	if err := db.Create(&u).Error; err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+/* ============================================================================
+   Tests — signup
+   ============================================================================ */
+
+// Signup sends exactly one welcome email to the new user.
+func Test_Signup_SendsWelcomeEmail(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+	db := openTestDB(t)
+
+	rec := &mailer.Recorder{}
+	app := newTestApp(NewHandler(db, rec))
+
+	code := postJSON(t, app, "/api/signup",
+		`{"role":"client","name":"Ann","email":"Ann@Example.com","password":"secret1"}`)
+	if code != 201 {
+		t.Fatalf("signup: want 201, got %d", code)
+	}
+
+	// Welcome and verification mails are sent concurrently
+	welcome := 0
+	for _, m := range rec.WaitFor(2, time.Second) {
+		if m.Subject == mailer.Welcome("", "").Subject {
+			welcome++
+			if m.To != "ann@example.com" {
+				t.Fatalf("welcome email to the wrong address: %+v", m)
+			}
+		}
+	}
+	if welcome != 1 {
+		t.Fatalf("want exactly one welcome email, got %d in %+v", welcome, rec.Messages())
+	}
+}
+
+// The same email in different casing is a 409 on the email field, including
+// against legacy rows stored with mixed case.
+func Test_Signup_EmailUniqueIgnoringCase(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+	db := openTestDB(t)
+	app := newTestApp(NewHandler(db, nil))
+
+	signup := func(email string) *http.Response {
+		req := httptest.NewRequest("POST", "/api/signup", strings.NewReader(
+			`{"role":"client","name":"Ann","email":"`+email+`","password":"secret1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	if resp := signup("ann@example.com"); resp.StatusCode != 201 {
+		t.Fatalf("first signup: want 201, got %d", resp.StatusCode)
+	}
+	resp := signup("ANN@Example.COM")
+	if resp.StatusCode != 409 {
+		t.Fatalf("second signup: want 409, got %d", resp.StatusCode)
+	}
+	var out struct {
+		Errors map[string][]string `json:"errors"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	if len(out.Errors["email"]) != 1 {
+		t.Fatalf("want error on email field, got %+v", out)
+	}
+
+	// Legacy row stored before emails were normalized
+	if err := db.Create(&models.User{Email: "Bob@Example.com", PasswordHash: "x", Role: models.RoleClient}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if resp := signup("bob@example.com"); resp.StatusCode != 409 {
+		t.Fatalf("legacy mixed-case: want 409, got %d", resp.StatusCode)
+	}
+}
+
+// A weak password is rejected with the standard Laravel-style 400 shape.
+func Test_Signup_RejectsWeakPassword(t *testing.T) {
+	app := newTestApp(NewHandler(nil, nil))
+
+	req := httptest.NewRequest("POST", "/api/signup", strings.NewReader(
+		`{"role":"client","name":"Ann","email":"ann@example.com","password":"123456"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 400 {
+		t.Fatalf("want 400, got %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Message string              `json:"message"`
+		Errors  map[string][]string `json:"errors"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	if out.Message != "Validation failed" || len(out.Errors["password"]) != 1 {
+		t.Fatalf("unexpected body: %+v", out)
+	}
+}
+
+/* ============================================================================
+   Tests — email verification
+   ============================================================================ */
+
+// verifyLinkToken pulls the token out of the verification email sent to addr.
+func verifyLinkToken(t *testing.T, rec *mailer.Recorder, addr string) string {
+	t.Helper()
+	re := regexp.MustCompile(`token=([0-9a-f]{64})`)
+	for _, m := range rec.WaitFor(2, time.Second) {
+		if m.To == addr && m.Subject == mailer.VerifyEmail("", "", "", time.Time{}).Subject {
+			if sub := re.FindStringSubmatch(m.Body); sub != nil {
+				return sub[1]
+			}
+		}
+	}
+	t.Fatalf("no verification email with a token for %s in %+v", addr, rec.Messages())
+	return ""
+}
+
+// Signup emails a token that verifies the address once; reusing it, or
+// using one past its expiry, is a 400 INVALID_VERIFICATION_TOKEN.
+func Test_VerifyEmail_TokenTransition(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+	db := openTestDB(t)
+	rec := &mailer.Recorder{}
+	app := newTestApp(NewHandler(db, rec))
+
+	if code := postJSON(t, app, "/api/signup",
+		`{"role":"lawyer","name":"Lee","email":"lee@example.com","password":"secret1"}`); code != 201 {
+		t.Fatalf("signup: want 201, got %d", code)
+	}
+	var u models.User
+	db.First(&u, "email = ?", "lee@example.com")
+	if u.EmailVerifiedAt != nil || u.EmailVerifyTokenHash == "" {
+		t.Fatalf("new user should be unverified with a pending token: %+v", u)
+	}
+
+	token := verifyLinkToken(t, rec, "lee@example.com")
+	if u.EmailVerifyTokenHash == token {
+		t.Fatalf("the raw token must not be stored")
+	}
+	if code := postJSON(t, app, "/api/auth/verify-email", `{"token":"`+token+`"}`); code != 200 {
+		t.Fatalf("verify: want 200, got %d", code)
+	}
+	db.First(&u, "id = ?", u.ID)
+	if u.EmailVerifiedAt == nil || u.EmailVerifyTokenHash != "" || u.EmailVerifyExpiresAt != nil {
+		t.Fatalf("want verified with the token cleared, got %+v", u)
+	}
+
+	verify := func(token string) models.ErrorResponse {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/api/auth/verify-email", strings.NewReader(`{"token":"`+token+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body models.ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		if resp.StatusCode != 400 {
+			t.Fatalf("want 400, got %d %+v", resp.StatusCode, body)
+		}
+		return body
+	}
+
+	// Single use
+	if body := verify(token); body.Code != apperr.InvalidVerificationToken {
+		t.Fatalf("reused token: want %s, got %+v", apperr.InvalidVerificationToken, body)
+	}
+
+	// Expired
+	other := seedUser(t, db, "old@example.com", "secret1")
+	stale := strings.Repeat("ab", 32)
+	past := time.Now().Add(-time.Minute)
+	db.Model(&models.User{}).Where("id = ?", other.ID).Updates(map[string]any{
+		"email_verify_token_hash": hashVerifyToken(stale),
+		"email_verify_expires_at": past,
+	})
+	if body := verify(stale); body.Code != apperr.InvalidVerificationToken {
+		t.Fatalf("expired token: want %s, got %+v", apperr.InvalidVerificationToken, body)
+	}
+	db.First(&other, "id = ?", other.ID)
+	if other.EmailVerifiedAt != nil {
+		t.Fatalf("expired token must not verify the account")
+	}
+}
+
+/* ============================================================================
+   Tests — profile update
+   ============================================================================ */
+
+// A lawyer can fix name, jurisdiction and bar number; the response reflects it.
+func Test_UpdateMe_LawyerUpdatesProfessionalDetails(t *testing.T) {
+	db := openTestDB(t)
+	u := models.User{Email: "law@example.com", PasswordHash: "x", Role: models.RoleLawyer, Name: "Old", Jurisdiction: "MY"}
+	if err := db.Create(&u).Error; err != nil {
+		t.Fatal(err)
+	}
+	app := newProfileApp(NewHandler(db, nil), u.ID, string(models.RoleLawyer))
+
+	resp := patchJSON(t, app, "/api/me", `{"name":"New Name","jurisdiction":"sg","bar_number":"SG/12345"}`)
+	if resp.StatusCode != 200 {
+		t.Fatalf("want 200, got %d", resp.StatusCode)
+	}
+	var out UserProfileResponse
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	if out.Name != "New Name" || out.Jurisdiction != "SG" || out.BarNumber != "SG/12345" || out.Email != u.Email {
+		t.Fatalf("unexpected profile: %+v", out)
+	}
+
+	var got models.User
+	_ = db.First(&got, "id = ?", u.ID).Error
+	if got.Jurisdiction != "SG" || got.BarNumber != "SG/12345" || got.Role != models.RoleLawyer {
+		t.Fatalf("not persisted: %+v", got)
+	}
+
+	// Invalid bar number goes through the shared validator
+	if resp := patchJSON(t, app, "/api/me", `{"bar_number":"#!"}`); resp.StatusCode != 400 {
+		t.Fatalf("bad bar number: want 400, got %d", resp.StatusCode)
+	}
+}
+
+// Clients can't set lawyer-only fields (checked before touching the DB).
+func Test_UpdateMe_ClientCannotSetBarNumber(t *testing.T) {
+	app := newProfileApp(NewHandler(nil, nil), uuid.New(), string(models.RoleClient))
+
+	if resp := patchJSON(t, app, "/api/me", `{"bar_number":"SG/12345"}`); resp.StatusCode != 403 {
+		t.Fatalf("want 403, got %d", resp.StatusCode)
+	}
+	if resp := patchJSON(t, app, "/api/me", `{"jurisdiction":"SG"}`); resp.StatusCode != 403 {
+		t.Fatalf("want 403, got %d", resp.StatusCode)
+	}
+}
+
+// Contact fields go through the shared validator before touching the DB.
+func Test_UpdateMe_ContactValidation(t *testing.T) {
+	app := newProfileApp(NewHandler(nil, nil), uuid.New(), string(models.RoleClient))
+
+	if resp := patchJSON(t, app, "/api/me", `{"contact_phone":"9123 4567"}`); resp.StatusCode != 400 {
+		t.Fatalf("non-E.164 phone: want 400, got %d", resp.StatusCode)
+	}
+	if resp := patchJSON(t, app, "/api/me", `{"preferred_contact":"fax"}`); resp.StatusCode != 400 {
+		t.Fatalf("unknown preferred contact: want 400, got %d", resp.StatusCode)
+	}
+}
+
+/* ============================================================================
+   Tests — lockout
+   ============================================================================ */
+
+// N consecutive failures lock the account (423 even with the right password);
+// once the window passes, the correct password works again.
+func Test_Login_LocksAfterNFailures_AndUnlocksAfterWindow(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+	t.Setenv("AUTH_LOCKOUT_THRESHOLD", "3")
+	t.Setenv("AUTH_LOCKOUT_WINDOW", "15m")
+	db := openTestDB(t)
+	u := seedUser(t, db, "ann@example.com", "secret1")
+	app := newTestApp(NewHandler(db, nil))
+
+	bad := `{"email":"ann@example.com","password":"wrong"}`
+	good := `{"email":"ann@example.com","password":"secret1"}`
+
+	for i := 1; i <= 3; i++ {
+		if code := postJSON(t, app, "/api/login", bad); code != 401 {
+			t.Fatalf("failure %d: want 401, got %d", i, code)
+		}
+	}
+	if code := postJSON(t, app, "/api/login", good); code != 423 {
+		t.Fatalf("locked: want 423, got %d", code)
+	}
+
+	// Simulate the window passing
+	past := time.Now().Add(-time.Minute)
+	if err := db.Model(&models.User{}).Where("id = ?", u.ID).Update("locked_until", &past).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code := postJSON(t, app, "/api/login", good); code != 200 {
+		t.Fatalf("after window: want 200, got %d", code)
+	}
+
+	var got models.User
+	_ = db.First(&got, "id = ?", u.ID).Error
+	if got.FailedAttempts != 0 || got.LockedUntil != nil {
+		t.Fatalf("state not reset: attempts=%d locked_until=%v", got.FailedAttempts, got.LockedUntil)
+	}
+}
+
+// A successful login resets the streak, so failures must be consecutive.
+func Test_Login_SuccessResetsFailureCounter(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+	t.Setenv("AUTH_LOCKOUT_THRESHOLD", "3")
+	db := openTestDB(t)
+	seedUser(t, db, "bob@example.com", "secret1")
+	app := newTestApp(NewHandler(db, nil))
+
+	bad := `{"email":"bob@example.com","password":"wrong"}`
+	good := `{"email":"bob@example.com","password":"secret1"}`
+
+	postJSON(t, app, "/api/login", bad)
+	postJSON(t, app, "/api/login", bad)
+	if code := postJSON(t, app, "/api/login", good); code != 200 {
+		t.Fatalf("want 200, got %d", code)
+	}
+	postJSON(t, app, "/api/login", bad)
+	postJSON(t, app, "/api/login", bad)
+	if code := postJSON(t, app, "/api/login", good); code != 200 {
+		t.Fatalf("streak should have reset: want 200, got %d", code)
+	}
+}
+
+/* ============================================================================
+   Tests — rate limiting
+   ============================================================================ */
+
+// newLimitedApp puts the auth limiters in front of a stub login handler.
+func newLimitedApp(cfg RateLimitConfig) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusUnauthorized) }
+	app.Post("/api/login", append(RateLimit(cfg), ok)...)
+	return app
+}
+
+// The (EmailMax+1)th rapid attempt for one email returns 429; other emails
+// from the same IP still get through until the per-IP budget runs out.
+func Test_RateLimit_NthAttemptReturns429(t *testing.T) {
+	app := newLimitedApp(RateLimitConfig{IPMax: 5, EmailMax: 3, Window: time.Minute})
+	body := `{"email":"Ann@Example.com","password":"wrong"}`
+
+	for i := 1; i <= 3; i++ {
+		if code := postJSON(t, app, "/api/login", body); code != 401 {
+			t.Fatalf("attempt %d: want 401, got %d", i, code)
+		}
+	}
+	// Same email, different casing → same bucket
+	if code := postJSON(t, app, "/api/login", `{"email":" ann@example.com ","password":"x"}`); code != 429 {
+		t.Fatalf("4th attempt for same email: want 429, got %d", code)
+	}
+
+	// Another email from the same IP is still allowed...
+	if code := postJSON(t, app, "/api/login", `{"email":"bob@example.com","password":"x"}`); code != 401 {
+		t.Fatalf("other email: want 401, got %d", code)
+	}
+	// ...until the per-IP budget (5) is spent
+	if code := postJSON(t, app, "/api/login", `{"email":"cat@example.com","password":"x"}`); code != 429 {
+		t.Fatalf("per-IP limit: want 429, got %d", code)
+	}
+}
+
+// A throttled request carries Retry-After and the standard error body with
+// TOO_MANY_REQUESTS; a 429 raised without a limiter still gets a Retry-After.
+func Test_RateLimit_RetryAfterAndErrorBody(t *testing.T) {
+	app := newLimitedApp(RateLimitConfig{IPMax: 5, EmailMax: 1, Window: time.Minute})
+	app.Get("/busy", func(c *fiber.Ctx) error { return fiber.ErrTooManyRequests })
+
+	post := func() *http.Response {
+		req := httptest.NewRequest("POST", "/api/login", strings.NewReader(`{"email":"ann@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	post()
+	resp := post()
+	if resp.StatusCode != 429 {
+		t.Fatalf("want 429, got %d", resp.StatusCode)
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err != nil || secs < 1 || secs > 60 {
+		t.Fatalf("want Retry-After within the window, got %q", resp.Header.Get("Retry-After"))
+	}
+	var body models.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Code != "TOO_MANY_REQUESTS" || !body.Error || body.Message == "" {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/busy", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 429 || resp.Header.Get("Retry-After") != strconv.Itoa(defaultRetryAfter) {
+		t.Fatalf("plain 429: want default Retry-After, got %d %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+}
+
+/* ============================================================================
+   Tests — token claims
+   ============================================================================ */
+
+// A freshly issued token carries the display name, and RequireAuth exposes it.
+func Test_IssueToken_IncludesNameClaim(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+
+	tok, err := IssueToken("user-1", "client", "Ann Lee", TokenTTL(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Claims
+	if _, err := jwt.ParseWithClaims(tok, &claims, func(*jwt.Token) (any, error) {
+		return []byte(testSecret), nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Name != "Ann Lee" || claims.Sub != "user-1" || claims.Role != "client" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	app := fiber.New()
+	app.Get("/whoami", RequireAuth(nil), func(c *fiber.Ctx) error { return c.SendString(UserName(c)) })
+	req := httptest.NewRequest("GET", "/whoami", nil)
+	req.Header.Set("Authorization", "Bearer "+tok)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "Ann Lee" {
+		t.Fatalf("want name in context, got %q", body)
+	}
+}
+
+/* ============================================================================
+   Tests — token lifetime
+   ============================================================================ */
+
+// loginExpiry logs in and returns the exp claim of the issued token.
+func loginExpiry(t *testing.T, app *fiber.App, body string) time.Time {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil || resp.StatusCode != 200 {
+		t.Fatalf("login: want 200, got %v (err=%v)", resp, err)
+	}
+	var out AuthResponse
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+
+	var claims Claims
+	if _, err := jwt.ParseWithClaims(out.Token, &claims, func(*jwt.Token) (any, error) {
+		return []byte(testSecret), nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return claims.ExpiresAt.Time
+}
+
+// "remember" logins get JWT_REMEMBER_TTL; plain logins get JWT_TTL.
+func Test_Login_RememberExtendsExpiry(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+	t.Setenv("JWT_TTL", "1h")
+	t.Setenv("JWT_REMEMBER_TTL", "720h")
+	db := openTestDB(t)
+	seedUser(t, db, "ann@example.com", "secret1")
+	app := newTestApp(NewHandler(db, nil))
+
+	plain := loginExpiry(t, app, `{"email":"ann@example.com","password":"secret1"}`)
+	remember := loginExpiry(t, app, `{"email":"ann@example.com","password":"secret1","remember":true}`)
+
+	if !remember.After(plain) {
+		t.Fatalf("remember expiry %s should be after default %s", remember, plain)
+	}
+	if d := time.Until(plain); d > time.Hour || d < 58*time.Minute {
+		t.Fatalf("default token should live ~1h, got %s", d)
+	}
+	if d := time.Until(remember); d < 719*time.Hour {
+		t.Fatalf("remember token should live ~30d, got %s", d)
+	}
+}
+
+// Broken or inconsistent durations are reported at startup.
+func Test_ValidateTokenTTLs(t *testing.T) {
+	for _, tc := range []struct {
+		ttl, remember string
+		ok            bool
+	}{
+		{"", "", true},
+		{"2h", "", true},
+		{"12h", "240h", true},
+		{"soon", "", false},
+		{"-1h", "", false},
+		{"48h", "24h", false},
+	} {
+		t.Setenv("JWT_TTL", tc.ttl)
+		t.Setenv("JWT_REMEMBER_TTL", tc.remember)
+		if err := ValidateTokenTTLs(); (err == nil) != tc.ok {
+			t.Errorf("JWT_TTL=%q JWT_REMEMBER_TTL=%q: ok=%v, err=%v", tc.ttl, tc.remember, tc.ok, err)
+		}
+	}
+}
+
+/* ============================================================================
+   Tests — RS256 / key rotation
+   ============================================================================ */
+
+// writeRSAKey generates a key pair and writes both PEM files into dir.
+func writeRSAKey(t *testing.T, dir, name string) (privPath, pubPath string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privPath = filepath.Join(dir, name+".pem")
+	pubPath = filepath.Join(dir, name+".pub.pem")
+	priv := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pubDER, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	pub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+	if err := os.WriteFile(privPath, priv, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pubPath, pub, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return privPath, pubPath
+}
+
+// authStatus runs a token through RequireAuth and returns the status code.
+func authStatus(t *testing.T, token string) int {
+	t.Helper()
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/p", RequireAuth(nil), func(c *fiber.Ctx) error { return c.SendStatus(200) })
+	req := httptest.NewRequest("GET", "/p", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+// RS256 tokens carry a kid; after rotation old tokens keep working while the
+// retired public key is listed, and stop once it is removed.
+func Test_RS256_SignVerify_WithRotation(t *testing.T) {
+	dir := t.TempDir()
+	privA, pubA := writeRSAKey(t, dir, "a")
+	privB, _ := writeRSAKey(t, dir, "b")
+
+	t.Setenv("JWT_ALG", "RS256")
+	t.Setenv("JWT_SIGNING_KEY_FILE", privA)
+	t.Setenv("JWT_SIGNING_KID", "a")
+	t.Setenv("JWT_PUBLIC_KEYS", "")
+
+	tokA, err := IssueToken("u1", "client", "Ann", TokenTTL(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed, _, _ := jwt.NewParser().ParseUnverified(tokA, &Claims{}); parsed.Header["kid"] != "a" || parsed.Method.Alg() != "RS256" {
+		t.Fatalf("want RS256 with kid=a, got %v", parsed.Header)
+	}
+	if code := authStatus(t, tokA); code != 200 {
+		t.Fatalf("token A: want 200, got %d", code)
+	}
+
+	// Rotate: sign with B, keep A for verification
+	t.Setenv("JWT_SIGNING_KEY_FILE", privB)
+	t.Setenv("JWT_SIGNING_KID", "b")
+	t.Setenv("JWT_PUBLIC_KEYS", "a="+pubA)
+
+	tokB, err := IssueToken("u1", "client", "Ann", TokenTTL(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := authStatus(t, tokB); code != 200 {
+		t.Fatalf("token B: want 200, got %d", code)
+	}
+	if code := authStatus(t, tokA); code != 200 {
+		t.Fatalf("token A after rotation: want 200, got %d", code)
+	}
+
+	// Retire A
+	t.Setenv("JWT_PUBLIC_KEYS", "")
+	if code := authStatus(t, tokA); code != 401 {
+		t.Fatalf("retired kid: want 401, got %d", code)
+	}
+}
+
+// With RS256 configured, HS256 tokens are refused (no alg confusion).
+func Test_RS256_RejectsHS256Token(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+	t.Setenv("JWT_ALG", "")
+	hs, err := IssueToken("u1", "client", "Ann", TokenTTL(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	priv, _ := writeRSAKey(t, t.TempDir(), "k")
+	t.Setenv("JWT_ALG", "RS256")
+	t.Setenv("JWT_SIGNING_KEY_FILE", priv)
+	t.Setenv("JWT_SIGNING_KID", "k")
+	if code := authStatus(t, hs); code != 401 {
+		t.Fatalf("want 401, got %d", code)
+	}
+}
+
+/* ============================================================================
+   Tests — issuer / audience
+   ============================================================================ */
+
+// Tokens are bound to JWT_ISSUER/JWT_AUDIENCE; a mismatch on either is 401.
+func Test_IssuerAudience_MatchAndMismatch(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+	t.Setenv("JWT_ALG", "")
+	t.Setenv("JWT_ISSUER", "legal-mp-staging")
+	t.Setenv("JWT_AUDIENCE", "legal-mp-api")
+
+	staging, err := IssueToken("u1", "client", "Ann", TokenTTL(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := authStatus(t, staging); code != 200 {
+		t.Fatalf("matching iss/aud: want 200, got %d", code)
+	}
+
+	// Same secret, different environment
+	t.Setenv("JWT_ISSUER", "legal-mp-prod")
+	if code := authStatus(t, staging); code != 401 {
+		t.Fatalf("issuer mismatch: want 401, got %d", code)
+	}
+
+	t.Setenv("JWT_ISSUER", "legal-mp-staging")
+	t.Setenv("JWT_AUDIENCE", "other-api")
+	if code := authStatus(t, staging); code != 401 {
+		t.Fatalf("audience mismatch: want 401, got %d", code)
+	}
+
+	// A token minted without iss/aud is refused once they are required
+	t.Setenv("JWT_ISSUER", "")
+	t.Setenv("JWT_AUDIENCE", "")
+	bare, _ := IssueToken("u1", "client", "Ann", TokenTTL(false))
+	t.Setenv("JWT_ISSUER", "legal-mp-prod")
+	t.Setenv("JWT_AUDIENCE", "legal-mp-api")
+	if code := authStatus(t, bare); code != 401 {
+		t.Fatalf("missing iss/aud: want 401, got %d", code)
+	}
+}
+
+/* ============================================================================
+   Tests — weak secret
+   ============================================================================ */
+
+// With an empty or short JWT_SECRET nothing is signed, and a token forged
+// with that secret is refused.
+func Test_WeakSecret_SigningFailsAuthDenied(t *testing.T) {
+	t.Setenv("JWT_ALG", "")
+	t.Setenv("JWT_ISSUER", "")
+	t.Setenv("JWT_AUDIENCE", "")
+
+	for _, secret := range []string{"", "short-secret"} {
+		t.Setenv("JWT_SECRET", secret)
+		if tok, err := IssueToken("u1", "client", "Ann", TokenTTL(false)); err == nil || tok != "" {
+			t.Fatalf("secret %q: want signing error, got token %q", secret, tok)
+		}
+		if err := ValidateKeyConfig(); err == nil {
+			t.Fatalf("secret %q: startup validation should fail", secret)
+		}
+
+		forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+			Sub: "u1", Role: "admin",
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+		}).SignedString([]byte(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code := authStatus(t, forged); code != 401 {
+			t.Fatalf("secret %q: want 401, got %d", secret, code)
+		}
+	}
+
+	t.Setenv("JWT_SECRET", testSecret)
+	if _, err := IssueToken("u1", "client", "Ann", TokenTTL(false)); err != nil {
+		t.Fatalf("long secret: %v", err)
+	}
+}
+
+/* ============================================================================
+   Tests — request id
+   ============================================================================ */
+
+// Every response carries an X-Request-ID: a sane incoming one is echoed,
+// otherwise one is generated; error bodies repeat it.
+func Test_RequestID_HeaderAndErrorBody(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(RequestID(), AccessLog())
+	app.Get("/ok", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Get("/boom", func(c *fiber.Ctx) error { return fiber.ErrInternalServerError })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/ok", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uuid.Parse(resp.Header.Get(RequestIDHeader)); err != nil {
+		t.Fatalf("want generated uuid request id, got %q", resp.Header.Get(RequestIDHeader))
+	}
+
+	req := httptest.NewRequest("GET", "/boom", nil)
+	req.Header.Set(RequestIDHeader, "lb-1234")
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 500 || resp.Header.Get(RequestIDHeader) != "lb-1234" {
+		t.Fatalf("want 500 echoing lb-1234, got %d %q", resp.StatusCode, resp.Header.Get(RequestIDHeader))
+	}
+	var body models.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.RequestID != "lb-1234" {
+		t.Fatalf("error body should carry the request id, got %+v", body)
+	}
+
+	// Malformed ids are replaced rather than logged verbatim
+	req = httptest.NewRequest("GET", "/ok", nil)
+	req.Header.Set(RequestIDHeader, "bad id\twith spaces")
+	resp, _ = app.Test(req)
+	if got := resp.Header.Get(RequestIDHeader); got == "" || strings.Contains(got, " ") {
+		t.Fatalf("malformed id should be replaced, got %q", got)
+	}
+}
+
+/* ============================================================================
+   Tests — error codes
+   ============================================================================ */
+
+// Domain errors surface their specific code with the unchanged HTTP status;
+// plain Fiber errors keep the generic status-derived code.
+func Test_ErrorHandler_DomainCodes(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{apperr.Conflict(apperr.CaseNotOpen, "case is not open"), 409, "CASE_NOT_OPEN"},
+		{apperr.Conflict(apperr.QuoteImmutable, "quote is immutable"), 409, "QUOTE_IMMUTABLE"},
+		{apperr.Conflict(apperr.AmountMismatch, "amount mismatch"), 409, "AMOUNT_MISMATCH"},
+		{apperr.Forbidden(apperr.FilesLocked, "files locked"), 403, "FILES_LOCKED"},
+		{apperr.Locked(apperr.AccountLocked, "locked"), 423, "ACCOUNT_LOCKED"},
+		{fiber.NewError(fiber.StatusConflict, "generic"), 409, "CONFLICT"},
+		{fiber.ErrNotFound, 404, "NOT_FOUND"},
+	}
+
+	for _, tc := range cases {
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+		app.Get("/x", func(c *fiber.Ctx) error { return tc.err })
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/x", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body models.ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		if resp.StatusCode != tc.status || body.Code != tc.code {
+			t.Fatalf("%v: want %d %s, got %d %s", tc.err, tc.status, tc.code, resp.StatusCode, body.Code)
+		}
+	}
+
+	// Without our handler, Fiber still sees the wrapped status
+	app := fiber.New()
+	app.Get("/x", func(c *fiber.Ctx) error { return apperr.Conflict(apperr.CaseNotOpen, "case is not open") })
+	if resp, _ := app.Test(httptest.NewRequest("GET", "/x", nil)); resp.StatusCode != 409 {
+		t.Fatalf("default handler: want 409, got %d", resp.StatusCode)
+	}
+}
+
+/* ============================================================================
+   Tests — deleted accounts
+   ============================================================================ */
+
+// A deleted account's token is refused by RequireAuth(db). A live account is
+// cached for AUTH_ACCOUNT_CHECK_TTL, so a deletion behind the cache's back
+// only bites once ForgetAccount runs (as account deletion does).
+func Test_RequireAuth_DeletedAccount(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+	t.Setenv("AUTH_ACCOUNT_CHECK_TTL", "1h")
+	db := openTestDB(t)
+	u := models.User{ID: uuid.New(), Email: "gone+" + uuid.NewString() + "@test.local", Role: models.RoleClient}
+	if err := db.Create(&u).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ForgetAccount(u.ID.String()) })
+	tok, err := IssueToken(u.ID.String(), string(u.Role), "", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/p", RequireAuth(db), func(c *fiber.Ctx) error { return c.SendStatus(200) })
+	call := func() int {
+		req := httptest.NewRequest("GET", "/p", nil)
+		req.Header.Set("Authorization", "Bearer "+tok)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+
+	if code := call(); code != 200 {
+		t.Fatalf("live account: want 200, got %d", code)
+	}
+	if err := db.Model(&models.User{}).Where("id = ?", u.ID).Update("deleted_at", time.Now()).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code := call(); code != 200 {
+		t.Fatalf("cached account: want 200 until forgotten, got %d", code)
+	}
+	ForgetAccount(u.ID.String())
+	if code := call(); code != 401 {
+		t.Fatalf("deleted account: want 401, got %d", code)
+	}
+	if code := call(); code != 401 {
+		t.Fatalf("deleted account is never cached: want 401, got %d", code)
+	}
+}
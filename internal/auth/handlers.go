@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"log/slog"
 	"strings"
 	"time"
 
@@ -9,7 +10,10 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/mailer"
 	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/utils"
 	"github.com/aldoetobex/legal-mp-backend/pkg/validation"
 )
 
@@ -20,7 +24,7 @@ type SignupRequest struct {
 	Role     string `json:"role" validate:"required,oneof=client lawyer"`
 	Name     string `json:"name" validate:"required,min=2,max=80"`
 	Email    string `json:"email" validate:"required,email,max=120"`
-	Password string `json:"password" validate:"required,min=6,max=72"`
+	Password string `json:"password" validate:"required,min=6,max=72,password"`
 	// Optional for lawyers
 	Jurisdiction string `json:"jurisdiction" validate:"omitempty,jurisdiction"`
 	BarNumber    string `json:"bar_number" validate:"omitempty,barnum"`
@@ -30,6 +34,17 @@ type SignupRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email,max=60"`
 	Password string `json:"password" validate:"required"`
+	Remember bool   `json:"remember"` // longer session (JWT_REMEMBER_TTL)
+}
+
+// Request body for PATCH /me. Omitted fields are left unchanged and an
+// empty contact field clears it; jurisdiction and bar number are lawyer-only.
+type UpdateProfileRequest struct {
+	Name             *string `json:"name" validate:"omitempty,min=2,max=80"`
+	Jurisdiction     *string `json:"jurisdiction" validate:"omitempty,jurisdiction"`
+	BarNumber        *string `json:"bar_number" validate:"omitempty,barnum"`
+	ContactPhone     *string `json:"contact_phone" validate:"omitempty,e164"`
+	PreferredContact *string `json:"preferred_contact" validate:"omitempty,oneof=email phone"`
 }
 
 // Standard auth response
@@ -47,16 +62,29 @@ type UserProfileResponse struct {
 	Jurisdiction string      `json:"jurisdiction"`
 	BarNumber    string      `json:"bar_number"`
 	CreatedAt    time.Time   `json:"created_at"`
+	// Lawyers may need a verified email to quote (see /auth/verify-email)
+	EmailVerified bool `json:"email_verified"`
+
+	// Shared with the counterpart of an engaged/closed case only
+	ContactPhone     string `json:"contact_phone"`
+	PreferredContact string `json:"preferred_contact"`
 }
 
 /* ============================== Handler ================================= */
 
-type Handler struct{ db *gorm.DB }
+type Handler struct {
+	db   *gorm.DB
+	mail mailer.Mailer // optional; nil disables email
+}
 
-func NewHandler(db *gorm.DB) *Handler { return &Handler{db: db} }
+func NewHandler(db *gorm.DB, mail mailer.Mailer) *Handler {
+	return &Handler{db: db, mail: mail}
+}
 
 /* =============================== Signup ================================= */
 
+const emailTakenMsg = "This email is already registered"
+
 // @Summary      Sign up
 // @Description  Register a new user (client or lawyer)
 // @Tags         auth
@@ -65,7 +93,8 @@ func NewHandler(db *gorm.DB) *Handler { return &Handler{db: db} }
 // @Param        payload  body  SignupRequest  true  "Signup payload"
 // @Success      201      {object}  AuthResponse
 // @Failure      400      {object}  models.ValidationErrorResponse
-// @Failure      409      {object}  models.ErrorResponse  "email already exists"
+// @Failure      409      {object}  models.ValidationErrorResponse  "email already registered"
+// @Failure      429      {object}  models.ErrorResponse  "too many attempts"
 // @Router       /signup [post]
 func (h *Handler) Signup(c *fiber.Ctx) error {
 	var in SignupRequest
@@ -81,6 +110,15 @@ func (h *Handler) Signup(c *fiber.Ctx) error {
 		return validation.Respond(c, errs)
 	}
 
+	// Emails are unique case-insensitively; older rows may not be lowercased
+	var taken int64
+	if err := h.db.Model(&models.User{}).Where("lower(email) = ?", in.Email).Count(&taken).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if taken > 0 {
+		return validation.RespondField(c, fiber.StatusConflict, "email", emailTakenMsg)
+	}
+
 	// Hash password
 	hash, _ := bcrypt.GenerateFromPassword([]byte(in.Password), bcrypt.DefaultCost)
 
@@ -90,22 +128,36 @@ func (h *Handler) Signup(c *fiber.Ctx) error {
 		PasswordHash: string(hash),
 		Role:         models.Role(in.Role),
 		Name:         in.Name,
-		Jurisdiction: in.Jurisdiction,
+		Jurisdiction: validation.NormalizeJurisdiction(in.Jurisdiction),
 		BarNumber:    in.BarNumber,
 	}
 	if err := h.db.Create(&u).Error; err != nil {
-		return fiber.NewError(fiber.StatusConflict, "email already exists")
+		// Lost a race with a concurrent signup for the same email
+		if utils.IsUniqueViolation(err) {
+			return validation.RespondField(c, fiber.StatusConflict, "email", emailTakenMsg)
+		}
+		return fiber.ErrInternalServerError
+	}
+
+	// Welcome and verification emails (best-effort, async); a failed token
+	// write only means the user has to ask for a resend
+	mailer.SendAsync(h.mail, mailer.Welcome(u.Email, u.Name))
+	if err := h.startEmailVerification(u); err != nil {
+		slog.Warn("email verification not started", "user_id", u.ID, "error", err)
 	}
 
 	// Issue JWT
-	token, _ := IssueToken(u.ID.String(), string(u.Role))
+	token, err := IssueToken(u.ID.String(), string(u.Role), u.Name, TokenTTL(false))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
 	return c.Status(fiber.StatusCreated).JSON(AuthResponse{Token: token, Role: string(u.Role)})
 }
 
 /* ================================ Login ================================= */
 
 // @Summary      Login
-// @Description  Authenticate and receive a JWT
+// @Description  Authenticate and receive a JWT. Repeated failures lock the account for a while.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -113,6 +165,8 @@ func (h *Handler) Signup(c *fiber.Ctx) error {
 // @Success      200      {object}  AuthResponse
 // @Failure      400      {object}  models.ValidationErrorResponse
 // @Failure      401      {object}  models.ErrorResponse
+// @Failure      423      {object}  models.ErrorResponse  "account temporarily locked"
+// @Failure      429      {object}  models.ErrorResponse  "too many attempts"
 // @Router       /login [post]
 func (h *Handler) Login(c *fiber.Ctx) error {
 	var in LoginRequest
@@ -128,19 +182,39 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 		return validation.Respond(c, errs)
 	}
 
-	// Find user by email
+	// Find user by email (case-insensitive); unknown emails still pay for a bcrypt compare so
+	// response timing doesn't reveal whether the account exists
 	var u models.User
-	if err := h.db.Where("email = ?", in.Email).First(&u).Error; err != nil {
+	if err := h.db.Where("lower(email) = ?", in.Email).First(&u).Error; err != nil {
+		_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(in.Password))
 		return fiber.ErrUnauthorized
 	}
 
+	// Locked accounts are rejected even with the right password
+	now := time.Now()
+	if isLocked(&u, now) {
+		_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(in.Password))
+		return apperr.Locked(apperr.AccountLocked, "account temporarily locked, please try again later")
+	}
+
 	// Verify password
 	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(in.Password)) != nil {
+		if err := recordFailedLogin(h.db, &u, now); err != nil {
+			return fiber.ErrInternalServerError
+		}
 		return fiber.ErrUnauthorized
 	}
 
-	// Issue JWT
-	token, _ := IssueToken(u.ID.String(), string(u.Role))
+	// Success clears any failure streak
+	if err := resetFailedLogins(h.db, &u); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	// Issue JWT ("remember me" gets the longer lifetime)
+	token, err := IssueToken(u.ID.String(), string(u.Role), u.Name, TokenTTL(in.Remember))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
 	return c.JSON(AuthResponse{Token: token, Role: string(u.Role)})
 }
 
@@ -166,8 +240,80 @@ func (h *Handler) Me(c *fiber.Ctx) error {
 		return fiber.ErrUnauthorized
 	}
 
-	// Map to a stable public profile shape
-	resp := UserProfileResponse{
+	return c.JSON(toProfile(u))
+}
+
+/* ============================== Update Me =============================== */
+
+// @Summary      Update current user profile
+// @Description  Update name and contact details (phone, preferred contact); lawyers may also update jurisdiction and bar number. Role and email cannot be changed here.
+// @Tags         auth
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body  UpdateProfileRequest  true  "Fields to change"
+// @Success      200  {object}  UserProfileResponse
+// @Failure      400  {object}  models.ValidationErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse  "lawyer-only field"
+// @Router       /me [patch]
+func (h *Handler) UpdateMe(c *fiber.Ctx) error {
+	userID := MustUserID(c)
+
+	var in UpdateProfileRequest
+	if err := c.BodyParser(&in); err != nil {
+		return fiber.ErrBadRequest
+	}
+	if errs, _ := validation.Validate(in); errs != nil {
+		return validation.Respond(c, errs)
+	}
+
+	// Professional details only make sense for lawyers
+	if MustRole(c) != string(models.RoleLawyer) && (in.Jurisdiction != nil || in.BarNumber != nil) {
+		return apperr.Forbidden(apperr.LawyerOnlyField, "only lawyers can update jurisdiction or bar number")
+	}
+
+	var u models.User
+	if err := h.db.First(&u, "id = ?", userID).Error; err != nil {
+		return fiber.ErrUnauthorized
+	}
+
+	updates := map[string]any{}
+	if in.Name != nil {
+		u.Name = strings.TrimSpace(*in.Name)
+		updates["name"] = u.Name
+	}
+	if in.Jurisdiction != nil {
+		u.Jurisdiction = validation.NormalizeJurisdiction(*in.Jurisdiction)
+		updates["jurisdiction"] = u.Jurisdiction
+	}
+	if in.BarNumber != nil {
+		u.BarNumber = strings.TrimSpace(*in.BarNumber)
+		updates["bar_number"] = u.BarNumber
+	}
+	if in.ContactPhone != nil {
+		u.ContactPhone = *in.ContactPhone
+		updates["contact_phone"] = u.ContactPhone
+	}
+	if in.PreferredContact != nil {
+		u.PreferredContact = *in.PreferredContact
+		updates["preferred_contact"] = u.PreferredContact
+	}
+	if u.PreferredContact == "phone" && u.ContactPhone == "" {
+		return validation.RespondField(c, fiber.StatusBadRequest, "preferred_contact", "Set contact_phone to prefer phone")
+	}
+	if len(updates) > 0 {
+		if err := h.db.Model(&models.User{}).Where("id = ?", u.ID).Updates(updates).Error; err != nil {
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	return c.JSON(toProfile(u))
+}
+
+// toProfile maps a user to the stable public profile shape.
+func toProfile(u models.User) UserProfileResponse {
+	return UserProfileResponse{
 		ID:           u.ID,
 		Email:        u.Email,
 		Role:         u.Role,
@@ -175,6 +321,10 @@ func (h *Handler) Me(c *fiber.Ctx) error {
 		Jurisdiction: u.Jurisdiction,
 		BarNumber:    u.BarNumber,
 		CreatedAt:    u.CreatedAt,
+
+		EmailVerified: u.EmailVerifiedAt != nil,
+
+		ContactPhone:     u.ContactPhone,
+		PreferredContact: u.PreferredContact,
 	}
-	return c.JSON(resp)
 }
@@ -0,0 +1,213 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+/* ============================================================================
+   Signing keys (HS256 default, RS256 with kid rotation)
+   ============================================================================
+
+   Env:
+     JWT_ALG               HS256 (default) | RS256
+     JWT_SECRET            HMAC secret (HS256)
+     JWT_SIGNING_KEY_FILE  PEM RSA private key used to sign (RS256)
+     JWT_SIGNING_KID       kid stamped into new tokens (RS256)
+     JWT_PUBLIC_KEYS       extra verification keys, "kid=path.pem,kid2=path2.pem"
+                           (keep retired keys here until their tokens expire)
+     JWT_ISSUER            "iss" stamped and required when set
+     JWT_AUDIENCE          "aud" stamped and required when set
+
+   The signing key's public half is always accepted under JWT_SIGNING_KID, so a
+   single-key setup only needs the first three RS256 variables.
+*/
+
+// MinSecretLen is the shortest JWT_SECRET accepted for HS256. Shorter (or
+// empty) secrets never sign or verify anything.
+const MinSecretLen = 32
+
+// errWeakSecret is returned when HS256 is configured without a usable secret.
+var errWeakSecret = fmt.Errorf("jwt: JWT_SECRET must be at least %d bytes", MinSecretLen)
+
+// keySet is the parsed key configuration.
+type keySet struct {
+	alg     string
+	secret  []byte
+	signKID string
+	signKey *rsa.PrivateKey
+	verify  map[string]*rsa.PublicKey
+
+	// Optional iss/aud binding so tokens from another environment are refused
+	issuer   string
+	audience string
+}
+
+// keyCache avoids re-reading PEM files on every request; it reloads only
+// when the relevant env vars change.
+var keyCache struct {
+	mu  sync.Mutex
+	sig string
+	ks  *keySet
+	err error
+}
+
+// ValidateKeyConfig loads the configured keys so misconfiguration fails at
+// startup instead of on the first login.
+func ValidateKeyConfig() error {
+	ks, err := currentKeys()
+	if err != nil {
+		return err
+	}
+	if ks.alg == "HS256" {
+		_, err = ks.hmacSecret()
+	}
+	return err
+}
+
+func currentKeys() (*keySet, error) {
+	sig := strings.Join([]string{
+		os.Getenv("JWT_ALG"), os.Getenv("JWT_SECRET"), os.Getenv("JWT_SIGNING_KEY_FILE"),
+		os.Getenv("JWT_SIGNING_KID"), os.Getenv("JWT_PUBLIC_KEYS"),
+		os.Getenv("JWT_ISSUER"), os.Getenv("JWT_AUDIENCE"),
+	}, "\x00")
+
+	keyCache.mu.Lock()
+	defer keyCache.mu.Unlock()
+	if keyCache.sig != sig { // never empty: always contains separators
+		keyCache.ks, keyCache.err = loadKeys()
+		keyCache.sig = sig
+	}
+	return keyCache.ks, keyCache.err
+}
+
+func loadKeys() (*keySet, error) {
+	issuer := strings.TrimSpace(os.Getenv("JWT_ISSUER"))
+	audience := strings.TrimSpace(os.Getenv("JWT_AUDIENCE"))
+
+	alg := strings.ToUpper(strings.TrimSpace(os.Getenv("JWT_ALG")))
+	switch alg {
+	case "", "HS256":
+		return &keySet{
+			alg: "HS256", secret: []byte(os.Getenv("JWT_SECRET")),
+			issuer: issuer, audience: audience,
+		}, nil
+	case "RS256":
+	default:
+		return nil, fmt.Errorf("jwt: unsupported JWT_ALG %q", alg)
+	}
+
+	ks := &keySet{alg: alg, verify: map[string]*rsa.PublicKey{}, issuer: issuer, audience: audience}
+
+	// Signing key (optional for verify-only deployments)
+	if path := os.Getenv("JWT_SIGNING_KEY_FILE"); path != "" {
+		ks.signKID = strings.TrimSpace(os.Getenv("JWT_SIGNING_KID"))
+		if ks.signKID == "" {
+			return nil, errors.New("jwt: JWT_SIGNING_KID is required with JWT_SIGNING_KEY_FILE")
+		}
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: read signing key: %w", err)
+		}
+		if ks.signKey, err = jwt.ParseRSAPrivateKeyFromPEM(pem); err != nil {
+			return nil, fmt.Errorf("jwt: parse signing key: %w", err)
+		}
+		ks.verify[ks.signKID] = &ks.signKey.PublicKey
+	}
+
+	// Additional verification keys (rotation)
+	for _, pair := range strings.Split(os.Getenv("JWT_PUBLIC_KEYS"), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kid, path, ok := strings.Cut(pair, "=")
+		if !ok || kid == "" || path == "" {
+			return nil, fmt.Errorf("jwt: bad JWT_PUBLIC_KEYS entry %q (want kid=path)", pair)
+		}
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: read public key %s: %w", kid, err)
+		}
+		pub, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: parse public key %s: %w", kid, err)
+		}
+		ks.verify[kid] = pub
+	}
+
+	if len(ks.verify) == 0 {
+		return nil, errors.New("jwt: RS256 needs JWT_SIGNING_KEY_FILE or JWT_PUBLIC_KEYS")
+	}
+	return ks, nil
+}
+
+// stamp fills iss/aud on outgoing claims when configured.
+func (ks *keySet) stamp(rc *jwt.RegisteredClaims) {
+	if ks.issuer != "" {
+		rc.Issuer = ks.issuer
+	}
+	if ks.audience != "" {
+		rc.Audience = jwt.ClaimStrings{ks.audience}
+	}
+}
+
+// hmacSecret returns the HS256 secret, or errWeakSecret when it is empty or
+// shorter than MinSecretLen.
+func (ks *keySet) hmacSecret() ([]byte, error) {
+	if len(ks.secret) < MinSecretLen {
+		return nil, errWeakSecret
+	}
+	return ks.secret, nil
+}
+
+// sign serializes claims with the configured algorithm (and kid for RS256).
+func (ks *keySet) sign(claims jwt.Claims) (string, error) {
+	if ks.alg == "HS256" {
+		secret, err := ks.hmacSecret()
+		if err != nil {
+			return "", err
+		}
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	}
+	if ks.signKey == nil {
+		return "", errors.New("jwt: no signing key configured")
+	}
+	t := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	t.Header["kid"] = ks.signKID
+	return t.SignedString(ks.signKey)
+}
+
+// keyFunc picks the verification key; for RS256 it is chosen by the token's kid.
+// Without a usable HS256 secret every token is rejected.
+func (ks *keySet) keyFunc(t *jwt.Token) (any, error) {
+	if ks.alg == "HS256" {
+		return ks.hmacSecret()
+	}
+	kid, _ := t.Header["kid"].(string)
+	pub, ok := ks.verify[kid]
+	if !ok {
+		return nil, fmt.Errorf("jwt: unknown kid %q", kid)
+	}
+	return pub, nil
+}
+
+// parse verifies a token string; only the configured algorithm is accepted,
+// which blocks alg-confusion (e.g. an HS256 token signed with a public key).
+// Issuer/audience are enforced only when configured.
+func (ks *keySet) parse(tokenStr string, claims jwt.Claims) (*jwt.Token, error) {
+	opts := []jwt.ParserOption{jwt.WithValidMethods([]string{ks.alg})}
+	if ks.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(ks.issuer))
+	}
+	if ks.audience != "" {
+		opts = append(opts, jwt.WithAudience(ks.audience))
+	}
+	return jwt.ParseWithClaims(tokenStr, claims, ks.keyFunc, opts...)
+}
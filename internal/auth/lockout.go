@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+/* ============================================================================
+   Account lockout after repeated failed logins
+   ============================================================================ */
+
+// lockoutThreshold is how many consecutive failures lock an account.
+// Env: AUTH_LOCKOUT_THRESHOLD (default 5).
+func lockoutThreshold() int {
+	if n, err := strconv.Atoi(os.Getenv("AUTH_LOCKOUT_THRESHOLD")); err == nil && n > 0 {
+		return n
+	}
+	return 5
+}
+
+// lockoutWindow is how long a locked account stays locked.
+// Env: AUTH_LOCKOUT_WINDOW (Go duration, default 15m).
+func lockoutWindow() time.Duration {
+	if v := os.Getenv("AUTH_LOCKOUT_WINDOW"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 15 * time.Minute
+}
+
+// dummyHash is compared against when the email is unknown or the account is
+// locked, so every login attempt pays the same bcrypt cost.
+var dummyHash = sync.OnceValue(func() []byte {
+	h, _ := bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+	return h
+})
+
+// isLocked reports whether the user is inside an active lockout window.
+func isLocked(u *models.User, now time.Time) bool {
+	return u.LockedUntil != nil && u.LockedUntil.After(now)
+}
+
+// recordFailedLogin bumps the counter; on reaching the threshold it locks the
+// account and resets the counter so the next window starts fresh.
+func recordFailedLogin(db *gorm.DB, u *models.User, now time.Time) error {
+	updates := map[string]any{"failed_attempts": gorm.Expr("failed_attempts + 1")}
+	if u.FailedAttempts+1 >= lockoutThreshold() {
+		until := now.Add(lockoutWindow())
+		updates["failed_attempts"] = 0
+		updates["locked_until"] = &until
+	}
+	return db.Model(&models.User{}).Where("id = ?", u.ID).Updates(updates).Error
+}
+
+// resetFailedLogins clears lockout state after a successful login.
+func resetFailedLogins(db *gorm.DB, u *models.User) error {
+	if u.FailedAttempts == 0 && u.LockedUntil == nil {
+		return nil
+	}
+	return db.Model(&models.User{}).Where("id = ?", u.ID).
+		Updates(map[string]any{"failed_attempts": 0, "locked_until": nil}).Error
+}
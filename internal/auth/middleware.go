@@ -2,44 +2,117 @@ package auth
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"log/slog"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
 	"github.com/aldoetobex/legal-mp-backend/pkg/models"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
 /* ============================== JWT Claims ============================== */
 
 // Claims represents the JWT payload we issue and expect.
 type Claims struct {
-	Sub  string `json:"sub"`  // user ID
-	Role string `json:"role"` // user role: "client" | "lawyer"
+	Sub  string `json:"sub"`            // user ID
+	Role string `json:"role"`           // user role: "client" | "lawyer" | "admin"
+	Name string `json:"name,omitempty"` // display name (truncated; see maxClaimName)
 	jwt.RegisteredClaims
 }
 
 /* ============================== JWT Helpers ============================= */
 
-// IssueToken signs a short-lived JWT (default 7 days) for the given user and role.
-func IssueToken(userID, role string) (string, error) {
+// maxClaimName caps the name claim so odd profiles can't bloat every request.
+const maxClaimName = 80
+
+// Token lifetimes when JWT_TTL / JWT_REMEMBER_TTL are unset.
+const (
+	defaultTokenTTL    = 24 * time.Hour
+	defaultRememberTTL = 7 * 24 * time.Hour
+)
+
+// TokenTTL is how long a new token lives: JWT_TTL normally, JWT_REMEMBER_TTL
+// when the user opted into "remember me" (Go durations).
+func TokenTTL(remember bool) time.Duration {
+	ttl, rem, err := tokenTTLs()
+	if err != nil {
+		// Startup validation rejects this; fall back rather than mint bad tokens
+		ttl, rem = defaultTokenTTL, defaultRememberTTL
+	}
+	if remember {
+		return rem
+	}
+	return ttl
+}
+
+// ValidateTokenTTLs checks JWT_TTL / JWT_REMEMBER_TTL so a typo fails at
+// startup instead of silently using the defaults.
+func ValidateTokenTTLs() error {
+	_, _, err := tokenTTLs()
+	return err
+}
+
+func tokenTTLs() (ttl, remember time.Duration, err error) {
+	parse := func(key string, def time.Duration) (time.Duration, error) {
+		raw := strings.TrimSpace(os.Getenv(key))
+		if raw == "" {
+			return def, nil
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("%s must be a positive duration, got %q", key, raw)
+		}
+		return d, nil
+	}
+	if ttl, err = parse("JWT_TTL", defaultTokenTTL); err != nil {
+		return 0, 0, err
+	}
+	if remember, err = parse("JWT_REMEMBER_TTL", max(defaultRememberTTL, ttl)); err != nil {
+		return 0, 0, err
+	}
+	if remember < ttl {
+		return 0, 0, fmt.Errorf("JWT_REMEMBER_TTL (%s) must not be shorter than JWT_TTL (%s)", remember, ttl)
+	}
+	return ttl, remember, nil
+}
+
+// IssueToken signs a JWT valid for ttl (see TokenTTL) for the given user, role and display name.
+// The algorithm and key come from the JWT_* env (see keys.go).
+func IssueToken(userID, role, name string, ttl time.Duration) (string, error) {
+	if r := []rune(name); len(r) > maxClaimName {
+		name = string(r[:maxClaimName])
+	}
 	claims := &Claims{
 		Sub:  userID,
 		Role: role,
+		Name: name,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
-	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return t.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	ks, err := currentKeys()
+	if err != nil {
+		return "", err
+	}
+	ks.stamp(&claims.RegisteredClaims)
+	return ks.sign(claims)
 }
 
 /* ============================== Middleware ============================== */
 
-// RequireAuth validates a Bearer JWT and injects userID and role into the context.
-func RequireAuth() fiber.Handler {
+// RequireAuth validates a Bearer JWT and injects userID, role and userName into the context.
+// Tokens whose account is gone or deleted (see account.DeleteMe) are rejected
+// too; the lookup goes through accountActive's short cache. A nil db (tests,
+// tools) trusts the token alone.
+func RequireAuth(db *gorm.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		h := c.Get("Authorization")
 		if !strings.HasPrefix(h, "Bearer ") {
@@ -47,9 +120,12 @@ func RequireAuth() fiber.Handler {
 		}
 		tokenStr := strings.TrimPrefix(h, "Bearer ")
 
-		token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(t *jwt.Token) (any, error) {
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
+		ks, err := currentKeys()
+		if err != nil {
+			log.Printf("auth: key config: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		token, err := ks.parse(tokenStr, &Claims{})
 		if err != nil || !token.Valid {
 			return fiber.ErrUnauthorized
 		}
@@ -58,9 +134,19 @@ func RequireAuth() fiber.Handler {
 		if !ok {
 			return fiber.ErrUnauthorized
 		}
+		if db != nil {
+			active, err := accountActive(db, claims.Sub)
+			if err != nil {
+				return fiber.ErrInternalServerError
+			}
+			if !active {
+				return fiber.ErrUnauthorized
+			}
+		}
 
 		c.Locals("userID", claims.Sub)
 		c.Locals("role", claims.Role)
+		c.Locals("userName", claims.Name)
 		return c.Next()
 	}
 }
@@ -81,6 +167,13 @@ func MustRole(c *fiber.Ctx) string {
 	panic(errors.New("role not in context"))
 }
 
+// UserName reads the display name from the token; empty for tokens issued
+// before the claim existed, so callers should fall back to the DB if needed.
+func UserName(c *fiber.Ctx) string {
+	name, _ := c.Locals("userName").(string)
+	return name
+}
+
 // RequireRole ensures the authenticated user has the expected role.
 func RequireRole(role string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -91,6 +184,43 @@ func RequireRole(role string) fiber.Handler {
 	}
 }
 
+// RequireAnyRole ensures the authenticated user has one of the given roles.
+func RequireAnyRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role := MustRole(c)
+		for _, r := range roles {
+			if role == r {
+				return c.Next()
+			}
+		}
+		return fiber.ErrForbidden
+	}
+}
+
+// IsAdmin reports whether the authenticated user is an admin.
+func IsAdmin(c *fiber.Ctx) bool {
+	role, _ := c.Locals("role").(string)
+	return role == string(models.RoleAdmin)
+}
+
+// LogAdminAccess writes an audit line for a request served with admin privileges.
+func LogAdminAccess(c *fiber.Ctx) {
+	slog.Info("admin access",
+		"request_id", GetRequestID(c),
+		"user_id", MustUserID(c),
+		"method", c.Method(),
+		"url", c.OriginalURL(),
+	)
+}
+
+// AuditAdmin logs every request passing through it (use after RequireRole("admin")).
+func AuditAdmin() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		LogAdminAccess(c)
+		return c.Next()
+	}
+}
+
 /* =========================== Error Formatting =========================== */
 
 // httpCodeToString converts an HTTP status code to a short, stable string.
@@ -106,23 +236,33 @@ func httpCodeToString(code int) string {
 		return "NOT_FOUND"
 	case fiber.StatusConflict:
 		return "CONFLICT"
+	case fiber.StatusLocked:
+		return "LOCKED"
 	case fiber.StatusUnprocessableEntity:
 		return "UNPROCESSABLE_ENTITY"
 	case fiber.StatusRequestEntityTooLarge:
 		return "PAYLOAD_TOO_LARGE"
+	case fiber.StatusUnsupportedMediaType:
+		return "UNSUPPORTED_MEDIA_TYPE"
+	case fiber.StatusTooManyRequests:
+		return "TOO_MANY_REQUESTS"
 	default:
 		return "INTERNAL_SERVER_ERROR"
 	}
 }
 
+// defaultRetryAfter (seconds) is sent on a 429 that didn't set Retry-After.
+const defaultRetryAfter = 60
+
 // ErrorHandler is a global Fiber error handler that returns a consistent JSON shape.
 func ErrorHandler(c *fiber.Ctx, err error) error {
 	// Defaults
 	code := fiber.StatusInternalServerError
 	msg := "Internal Server Error"
 
-	// Fiber errors carry status codes
-	if e, ok := err.(*fiber.Error); ok {
+	// Fiber errors carry status codes (domain errors wrap one, see apperr)
+	var e *fiber.Error
+	if errors.As(err, &e) {
 		code = e.Code
 		if strings.TrimSpace(e.Message) != "" {
 			msg = e.Message
@@ -140,13 +280,36 @@ func ErrorHandler(c *fiber.Ctx, err error) error {
 				msg = fiber.ErrNotFound.Message
 			case fiber.StatusConflict:
 				msg = fiber.ErrConflict.Message
+			case fiber.StatusRequestEntityTooLarge:
+				msg = fiber.ErrRequestEntityTooLarge.Message
+			case fiber.StatusTooManyRequests:
+				msg = fiber.ErrTooManyRequests.Message
 			}
 		}
 	}
 
+	// Server-side failures are logged with the id the client can quote back to us
+	reqID := GetRequestID(c)
+	if code >= fiber.StatusInternalServerError {
+		slog.Error("request failed", "request_id", reqID, "status", code, "error", err)
+	}
+
+	// Domain errors carry a specific code (e.g. CASE_NOT_OPEN) instead of the generic one
+	errCode := httpCodeToString(code)
+	var de *apperr.Error
+	if errors.As(err, &de) {
+		errCode = de.Code
+	}
+
+	// A 429 always tells the client when to come back (limiters set their own)
+	if code == fiber.StatusTooManyRequests && c.GetRespHeader(fiber.HeaderRetryAfter) == "" {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(defaultRetryAfter))
+	}
+
 	return c.Status(code).JSON(models.ErrorResponse{
-		Code:    httpCodeToString(code),
-		Error:   true,
-		Message: msg,
+		Code:      errCode,
+		Error:     true,
+		Message:   msg,
+		RequestID: reqID,
 	})
 }
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+/* ============================================================================
+   Rate limiting for public auth endpoints
+   ============================================================================ */
+
+// RateLimitConfig holds the per-window budgets for login/signup.
+// The per-IP budget is deliberately generous so many users behind one NAT
+// are not locked out together; the per-email budget is the tight one.
+type RateLimitConfig struct {
+	IPMax    int
+	EmailMax int
+	Window   time.Duration
+}
+
+// RateLimitConfigFromEnv reads AUTH_RATE_IP_MAX (default 30),
+// AUTH_RATE_EMAIL_MAX (default 5) and AUTH_RATE_WINDOW (default 1m).
+func RateLimitConfigFromEnv() RateLimitConfig {
+	cfg := RateLimitConfig{IPMax: 30, EmailMax: 5, Window: time.Minute}
+	if n, err := strconv.Atoi(os.Getenv("AUTH_RATE_IP_MAX")); err == nil && n > 0 {
+		cfg.IPMax = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("AUTH_RATE_EMAIL_MAX")); err == nil && n > 0 {
+		cfg.EmailMax = n
+	}
+	if d, err := time.ParseDuration(os.Getenv("AUTH_RATE_WINDOW")); err == nil && d > 0 {
+		cfg.Window = d
+	}
+	return cfg
+}
+
+// RateLimit returns the middleware chain for one auth route: a per-IP limiter
+// followed by a per-email limiter. Each call gets its own in-memory counters,
+// so login and signup are budgeted separately.
+func RateLimit(cfg RateLimitConfig) []fiber.Handler {
+	return []fiber.Handler{
+		limiter.New(limiter.Config{
+			Max:          cfg.IPMax,
+			Expiration:   cfg.Window,
+			KeyGenerator: func(c *fiber.Ctx) string { return "ip:" + c.IP() },
+			LimitReached: tooManyAttempts,
+		}),
+		limiter.New(limiter.Config{
+			Max:        cfg.EmailMax,
+			Expiration: cfg.Window,
+			// No email in the body → nothing to key on; the IP limiter still applies
+			Next:         func(c *fiber.Ctx) bool { return emailKey(c) == "" },
+			KeyGenerator: func(c *fiber.Ctx) string { return "email:" + emailKey(c) },
+			LimitReached: tooManyAttempts,
+		}),
+	}
+}
+
+// tooManyAttempts goes through ErrorHandler; limiter already set Retry-After.
+func tooManyAttempts(c *fiber.Ctx) error {
+	return fiber.NewError(fiber.StatusTooManyRequests, "too many attempts, please try again later")
+}
+
+// emailKey peeks at the JSON body and returns the normalized email (or "").
+func emailKey(c *fiber.Ctx) string {
+	var in struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(c.Body(), &in); err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(in.Email))
+}
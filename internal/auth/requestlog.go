@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+/* ============================================================================
+   Request IDs & access logging
+   ============================================================================ */
+
+// RequestIDHeader carries the correlation id in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// reRequestID bounds what we accept from clients/proxies so ids stay log-safe.
+var reRequestID = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// RequestID reuses a well-formed incoming X-Request-ID or generates one,
+// stores it in Locals("requestID") and echoes it on the response.
+// Register it first so every later log line can carry the id.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(RequestIDHeader)
+		if !reRequestID.MatchString(id) {
+			id = uuid.NewString()
+		}
+		c.Locals("requestID", id)
+		c.Set(RequestIDHeader, id)
+		return c.Next()
+	}
+}
+
+// GetRequestID returns the current request's id (empty outside RequestID).
+func GetRequestID(c *fiber.Ctx) string {
+	id, _ := c.Locals("requestID").(string)
+	return id
+}
+
+// AccessLog writes one structured line per request: request id, method,
+// path, status, latency and (when authenticated) the user id.
+// Errors are rendered here via the app's ErrorHandler so the logged
+// status is the one the client actually receives.
+func AccessLog() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		if err := c.Next(); err != nil {
+			if herr := c.App().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		userID, _ := c.Locals("userID").(string)
+		slog.Info("request",
+			"request_id", GetRequestID(c),
+			"method", c.Method(),
+			"path", c.Path(),
+			"status", c.Response().StatusCode(),
+			"latency_ms", time.Since(start).Milliseconds(),
+			"user_id", userID,
+			"ip", c.IP(),
+		)
+		return nil
+	}
+}
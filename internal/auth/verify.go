@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/mailer"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/validation"
+)
+
+/* ============================================================================
+   Email verification
+   ============================================================================ */
+
+// Request body for POST /auth/verify-email
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required,len=64,hexadecimal"`
+}
+
+// LawyerVerificationRequired reports whether lawyers must verify their email
+// before quoting. Env: REQUIRE_LAWYER_EMAIL_VERIFICATION (bool, default off
+// so dev works without SMTP).
+func LawyerVerificationRequired() bool {
+	on, _ := strconv.ParseBool(os.Getenv("REQUIRE_LAWYER_EMAIL_VERIFICATION"))
+	return on
+}
+
+// emailVerifyTTL is how long an emailed token stays valid.
+// Env: EMAIL_VERIFY_TTL (Go duration, default 48h).
+func emailVerifyTTL() time.Duration {
+	if v := os.Getenv("EMAIL_VERIFY_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 48 * time.Hour
+}
+
+// hashVerifyToken is what gets stored and looked up; the raw token only
+// travels in the email.
+func hashVerifyToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(token)))
+	return hex.EncodeToString(sum[:])
+}
+
+// startEmailVerification stores a fresh token for u (replacing any earlier
+// one) and emails the link. Email is best-effort like the welcome mail.
+func (h *Handler) startEmailVerification(u models.User) error {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(buf)
+	expires := time.Now().Add(emailVerifyTTL())
+
+	if err := h.db.Model(&models.User{}).Where("id = ?", u.ID).Updates(map[string]any{
+		"email_verify_token_hash": hashVerifyToken(token),
+		"email_verify_expires_at": expires,
+	}).Error; err != nil {
+		return err
+	}
+
+	link := os.Getenv("PUBLIC_BASE_URL") + "/verify-email?token=" + token
+	mailer.SendAsync(h.mail, mailer.VerifyEmail(u.Email, u.Name, link, expires))
+	return nil
+}
+
+// EnsureEmailVerified returns a 403 EMAIL_NOT_VERIFIED for a user whose
+// email isn't verified yet, or nil. Callers decide when the gate applies.
+func EnsureEmailVerified(db *gorm.DB, userID uuid.UUID) error {
+	var u models.User
+	if err := db.Select("id, email_verified_at").First(&u, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrUnauthorized
+		}
+		return fiber.ErrInternalServerError
+	}
+	if u.EmailVerifiedAt == nil {
+		return apperr.Forbidden(apperr.EmailNotVerified, "verify your email address first")
+	}
+	return nil
+}
+
+// @Summary      Verify email
+// @Description  Confirms the account's email with the token from the verification email. Tokens are single-use and expire (EMAIL_VERIFY_TTL, default 48h).
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body  VerifyEmailRequest  true  "Token from the email link"
+// @Success      200  {object}  map[string]string  "status: verified"
+// @Failure      400  {object}  models.ErrorResponse  "INVALID_VERIFICATION_TOKEN (unknown, used or expired)"
+// @Failure      429  {object}  models.ErrorResponse  "too many attempts"
+// @Router       /auth/verify-email [post]
+func (h *Handler) VerifyEmail(c *fiber.Ctx) error {
+	var in VerifyEmailRequest
+	if err := c.BodyParser(&in); err != nil {
+		return fiber.ErrBadRequest
+	}
+	if errs, _ := validation.Validate(in); errs != nil {
+		return validation.Respond(c, errs)
+	}
+
+	// One conditional update: a used or expired token matches nothing
+	now := time.Now()
+	res := h.db.Model(&models.User{}).
+		Where("email_verify_token_hash = ? AND email_verify_expires_at > ?", hashVerifyToken(in.Token), now).
+		Updates(map[string]any{
+			"email_verified_at":       now,
+			"email_verify_token_hash": "",
+			"email_verify_expires_at": nil,
+		})
+	if res.Error != nil {
+		return fiber.ErrInternalServerError
+	}
+	if res.RowsAffected == 0 {
+		return apperr.BadRequest(apperr.InvalidVerificationToken, "verification link is invalid or has expired")
+	}
+	return c.JSON(fiber.Map{"status": "verified"})
+}
+
+// @Summary      Resend verification email
+// @Description  Emails a new verification link to the current user; earlier links stop working
+// @Tags         auth
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  map[string]string  "status: sent"
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse  "EMAIL_ALREADY_VERIFIED"
+// @Failure      429  {object}  models.ErrorResponse  "too many attempts"
+// @Router       /auth/verify-email/resend [post]
+func (h *Handler) ResendVerification(c *fiber.Ctx) error {
+	var u models.User
+	if err := h.db.First(&u, "id = ?", MustUserID(c)).Error; err != nil {
+		return fiber.ErrUnauthorized
+	}
+	if u.EmailVerifiedAt != nil {
+		return apperr.Conflict(apperr.EmailAlreadyVerified, "email is already verified")
+	}
+	if err := h.startEmailVerification(u); err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(fiber.Map{"status": "sent"})
+}
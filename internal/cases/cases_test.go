@@ -1,10 +1,21 @@
 package cases
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
 	"net/http/httptest"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,8 +25,14 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/internal/storage"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apitime"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apptz"
 	"github.com/aldoetobex/legal-mp-backend/pkg/models"
 	"github.com/aldoetobex/legal-mp-backend/pkg/sanitize"
+	"github.com/aldoetobex/legal-mp-backend/pkg/utils"
 )
 
 /* ============================================================================
@@ -38,15 +55,22 @@ func openTestDB(t *testing.T) *gorm.DB {
 	}
 	if err := db.AutoMigrate(
 		&models.User{}, &models.Case{}, &models.CaseFile{},
-		&models.CaseHistory{}, &models.Quote{}, &models.Payment{},
+		&models.CaseHistory{}, &models.Quote{}, &models.Payment{}, &models.Notification{},
+		&models.SavedCase{}, &models.CaseCollaborator{},
 	); err != nil {
 		t.Fatalf("migrate: %v", err)
 	}
+	if err := EnsureReferenceSequence(db); err != nil {
+		t.Fatalf("sequence: %v", err)
+	}
 
 	// Truncate AFTER each test (data survives within a single test).
 	t.Cleanup(func() {
 		sql := `
 TRUNCATE TABLE
+	case_collaborators,
+	saved_cases,
+	notifications,
 	payments,
 	case_histories,
 	case_files,
@@ -112,12 +136,21 @@ func newTestApp(h *Handler, userID uuid.UUID, role string) *fiber.App {
 
 	// Static / explicit routes first
 	app.Get("/api/cases/mine", h.ListMine)
+	app.Get("/api/me/cases", h.MyCases)
 	app.Get("/api/marketplace", h.Marketplace)
+	app.Get("/api/marketplace/categories", h.MarketCategories)
+	app.Get("/api/marketplace/stats", h.MarketStats)
+	app.Get("/api/marketplace/saved", h.ListSaved)
+	app.Post("/api/marketplace/:id/save", h.SaveCase)
+	app.Delete("/api/marketplace/:id/save", h.UnsaveCase)
 
 	// File endpoints used by tests
+	app.Get("/api/cases/:id/files", h.ListFiles)
 	app.Post("/api/cases/:id/files", h.UploadFile)
 	app.Get("/api/files/:fileID/signed-url", h.SignedDownloadURL)
+	app.Put("/api/files/:fileID", h.ReplaceFile)
 	app.Delete("/api/files/:fileID", h.DeleteFile)
+	app.Post("/api/cases/:id/files/delete", h.DeleteFiles)
 
 	// Parameterized routes last
 	app.Get("/api/cases/:id", h.GetDetail)
@@ -125,6 +158,22 @@ func newTestApp(h *Handler, userID uuid.UUID, role string) *fiber.App {
 	// Create endpoint for validation tests
 	app.Post("/api/cases", h.Create)
 
+	// Status transitions
+	app.Post("/api/cases/:id/cancel", h.Cancel)
+	app.Post("/api/cases/:id/close", h.Close)
+	app.Post("/api/cases/:id/reopen", h.Reopen)
+	app.Post("/api/cases/:id/pause", h.Pause)
+	app.Post("/api/cases/:id/resume", h.Resume)
+	app.Delete("/api/cases/:id", h.Delete)
+
+	// History
+	app.Get("/api/cases/:id/history", h.ListHistory)
+
+	// Collaborators
+	app.Get("/api/cases/:id/collaborators", h.ListCollaborators)
+	app.Post("/api/cases/:id/collaborators", h.GrantCollaborator)
+	app.Delete("/api/cases/:id/collaborators/:userID", h.RevokeCollaborator)
+
 	return app
 }
 
@@ -336,7 +385,11 @@ func Test_ListMine_Pagination_And_QuoteCounts(t *testing.T) {
 			PageSize int `json:"pageSize"`
 			Total    int `json:"total"`
 			Pages    int `json:"pages"`
-			Items    []struct {
+			Links    struct {
+				Next *string `json:"next"`
+				Prev *string `json:"prev"`
+			} `json:"links"`
+			Items []struct {
 				ID     string `json:"id"`
 				Quotes int64  `json:"quotes"`
 			} `json:"items"`
@@ -346,6 +399,9 @@ func Test_ListMine_Pagination_And_QuoteCounts(t *testing.T) {
 		if out.Total != 3 {
 			t.Fatalf("want total=3, got %d", out.Total)
 		}
+		if out.Links.Prev != nil || out.Links.Next == nil || *out.Links.Next != "/api/cases/mine?page=2&pageSize=2" {
+			t.Fatalf("page 1 links: want next=page 2 and no prev, got %+v", out.Links)
+		}
 		if len(out.Items) != 2 {
 			t.Fatalf("want 2 items on first page, got %d", len(out.Items))
 		}
@@ -365,6 +421,10 @@ func Test_ListMine_Pagination_And_QuoteCounts(t *testing.T) {
 			t.Fatalf("got %d", resp2.StatusCode)
 		}
 		var out2 struct {
+			Links struct {
+				Next *string `json:"next"`
+				Prev *string `json:"prev"`
+			} `json:"links"`
 			Items []struct {
 				ID     string `json:"id"`
 				Quotes int64  `json:"quotes"`
@@ -374,6 +434,165 @@ func Test_ListMine_Pagination_And_QuoteCounts(t *testing.T) {
 		if len(out2.Items) != 1 || out2.Items[0].ID != c1.String() || out2.Items[0].Quotes != 2 {
 			t.Fatalf("page 2 should return c1 with 2 quotes, got %#v", out2.Items)
 		}
+		if out2.Links.Next != nil || out2.Links.Prev == nil || *out2.Links.Prev != "/api/cases/mine?page=1&pageSize=2" {
+			t.Fatalf("last page links: want prev=page 1 and no next, got %+v", out2.Links)
+		}
+	})
+}
+
+// File and quote counts on the same case don't inflate each other.
+func Test_ListMine_FileAndQuoteCounts(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		clientID := uuid.New()
+		if err := tx.Create(&models.User{ID: clientID, Email: "c_" + clientID.String()[:6] + "@x.com", Role: models.RoleClient}).Error; err != nil {
+			t.Fatal(err)
+		}
+		now := time.Now()
+		both := makeCase(t, tx, clientID, now.Add(-2*time.Minute))
+		filesOnly := makeCase(t, tx, clientID, now.Add(-1*time.Minute))
+
+		// both: 2 quotes × 3 files; filesOnly: 1 file, no quotes
+		addQuote(t, tx, both, uuid.New(), "Q1")
+		addQuote(t, tx, both, uuid.New(), "Q2")
+		for i, id := range []uuid.UUID{both, both, both, filesOnly} {
+			f := models.CaseFile{CaseID: id, Key: "case/" + id.String() + "/" + strconv.Itoa(i) + ".pdf", Mime: "application/pdf", Size: 1, OriginalName: "f.pdf"}
+			if err := tx.Create(&f).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		app := newTestApp(NewHandler(tx, nil), clientID, string(models.RoleClient))
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/cases/mine", nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("got %d", resp.StatusCode)
+		}
+		var out PageCases
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		got := map[string]CaseListItem{}
+		for _, it := range out.Items {
+			got[it.ID] = it
+		}
+		if it := got[both.String()]; it.Quotes != 2 || it.Files != 3 {
+			t.Fatalf("want 2 quotes / 3 files, got %d / %d", it.Quotes, it.Files)
+		}
+		if it := got[filesOnly.String()]; it.Quotes != 0 || it.Files != 1 {
+			t.Fatalf("want 0 quotes / 1 file, got %d / %d", it.Quotes, it.Files)
+		}
+	})
+}
+
+// ?status narrows /cases/mine to one status, total included; no filter
+// keeps every status and an unknown one is 400.
+func Test_ListMine_StatusFilter(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		clientID := uuid.New()
+		if err := tx.Create(&models.User{ID: clientID, Email: "c_" + clientID.String()[:6] + "@x.com", Role: models.RoleClient}).Error; err != nil {
+			t.Fatal(err)
+		}
+		statuses := []models.CaseStatus{models.CaseOpen, models.CasePaused, models.CaseEngaged, models.CaseClosed, models.CaseCancelled}
+		now := time.Now()
+		for i, st := range statuses {
+			id := makeCase(t, tx, clientID, now.Add(time.Duration(i)*time.Minute))
+			tx.Model(&models.Case{}).Where("id = ?", id).Update("status", st)
+		}
+		// A second open case, so the filtered total isn't always 1
+		makeCase(t, tx, clientID, now.Add(-time.Minute))
+
+		app := newTestApp(NewHandler(tx, nil), clientID, string(models.RoleClient))
+		list := func(query string) PageCases {
+			t.Helper()
+			resp, _ := app.Test(httptest.NewRequest("GET", "/api/cases/mine"+query, nil))
+			if resp.StatusCode != 200 {
+				t.Fatalf("%q: got %d", query, resp.StatusCode)
+			}
+			var out PageCases
+			_ = json.NewDecoder(resp.Body).Decode(&out)
+			return out
+		}
+
+		if out := list(""); out.Total != 6 {
+			t.Fatalf("unfiltered: want total 6, got %d", out.Total)
+		}
+		for _, st := range statuses {
+			want := int64(1)
+			if st == models.CaseOpen {
+				want = 2
+			}
+			out := list("?status=" + string(st))
+			if out.Total != want || int64(len(out.Items)) != want {
+				t.Fatalf("%s: want total %d, got total=%d items=%d", st, want, out.Total, len(out.Items))
+			}
+			for _, it := range out.Items {
+				if it.Status != string(st) {
+					t.Fatalf("%s: got a %s case", st, it.Status)
+				}
+			}
+		}
+
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/cases/mine?status=bogus", nil))
+		if resp.StatusCode != 400 {
+			t.Fatalf("unknown status: want 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+// /me/cases lists a client's own cases with counts, and a lawyer's quoted
+// cases with their quote status; soft-deleted cases drop out for both.
+func Test_MyCases_ClientAndLawyer(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		clientID, lawyerID := uuid.New(), uuid.New()
+		if err := tx.Create(&models.User{ID: clientID, Email: "c_" + clientID.String()[:6] + "@x.com", Role: models.RoleClient}).Error; err != nil {
+			t.Fatal(err)
+		}
+		now := time.Now()
+		quoted := makeCase(t, tx, clientID, now.Add(-3*time.Minute))
+		rejected := makeCase(t, tx, uuid.New(), now.Add(-2*time.Minute))
+		untouched := makeCase(t, tx, clientID, now.Add(-1*time.Minute))
+		deleted := makeCase(t, tx, uuid.New(), now)
+
+		addQuote(t, tx, quoted, lawyerID, "mine")
+		addQuote(t, tx, quoted, uuid.New(), "someone else")
+		r := addQuote(t, tx, rejected, lawyerID, "mine")
+		tx.Model(&r).Update("status", models.QuoteRejected)
+		addQuote(t, tx, deleted, lawyerID, "mine")
+		tx.Delete(&models.Case{}, "id = ?", deleted)
+
+		list := func(userID uuid.UUID, role models.Role) PageMyCases {
+			app := newTestApp(NewHandler(tx, nil), userID, string(role))
+			resp, _ := app.Test(httptest.NewRequest("GET", "/api/me/cases", nil))
+			if resp.StatusCode != 200 {
+				t.Fatalf("%s: got %d", role, resp.StatusCode)
+			}
+			var out PageMyCases
+			_ = json.NewDecoder(resp.Body).Decode(&out)
+			return out
+		}
+
+		mine := list(clientID, models.RoleClient)
+		if mine.Total != 2 || len(mine.Items) != 2 || mine.Items[0].ID != untouched.String() || mine.Items[1].ID != quoted.String() {
+			t.Fatalf("client: want untouched then quoted, got %+v", mine.Items)
+		}
+		if it := mine.Items[1]; it.Quotes == nil || *it.Quotes != 2 || it.Files == nil || it.MyQuoteID != nil {
+			t.Fatalf("client item: want counts and no quote fields, got %+v", it)
+		}
+
+		work := list(lawyerID, models.RoleLawyer)
+		if work.Total != 2 || len(work.Items) != 2 {
+			t.Fatalf("lawyer: want 2 quoted live cases, got total=%d %+v", work.Total, work.Items)
+		}
+		got := map[string]MyCaseItem{}
+		for _, it := range work.Items {
+			got[it.ID] = it
+		}
+		if it := got[quoted.String()]; it.MyQuoteStatus != string(models.QuoteProposed) || it.MyQuoteID == nil || it.Quotes != nil {
+			t.Fatalf("quoted case: want my proposed quote and no counts, got %+v", it)
+		}
+		if got[rejected.String()].MyQuoteStatus != string(models.QuoteRejected) {
+			t.Fatalf("rejected case: want my rejected quote, got %+v", got[rejected.String()])
+		}
 	})
 }
 
@@ -475,6 +694,87 @@ func Test_SignedURL_RandomUser_Forbidden(t *testing.T) {
 	})
 }
 
+// format=qr returns the signed URL as a PNG for a party; anyone else gets
+// the same 403 as the JSON form.
+func Test_SignedURL_QRCode(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedEngagedWithFile(t, tx)
+		target := "/files/" + s.FileID.String() + "/signed-url?format=qr"
+
+		owner := newTestAppFiles(NewHandler(tx, nil), s.ClientID, string(models.RoleClient))
+		resp, _ := owner.Test(httptest.NewRequest("GET", target, nil))
+		if resp.StatusCode != 200 || resp.Header.Get("Content-Type") != "image/png" {
+			t.Fatalf("owner: want 200 image/png, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		if _, err := png.Decode(resp.Body); err != nil {
+			t.Fatalf("owner: body is not a PNG: %v", err)
+		}
+
+		random := uuid.New()
+		_ = tx.Create(&models.User{ID: random, Email: "r_" + random.String()[:6] + "@t", Role: models.RoleClient}).Error
+		other := newTestAppFiles(NewHandler(tx, nil), random, string(models.RoleClient))
+		resp, _ = other.Test(httptest.NewRequest("GET", target, nil))
+		if resp.StatusCode != 403 {
+			t.Fatalf("other client: want 403, got %d", resp.StatusCode)
+		}
+
+		resp, _ = owner.Test(httptest.NewRequest("GET", "/files/"+s.FileID.String()+"/signed-url?format=svg", nil))
+		if resp.StatusCode != 400 {
+			t.Fatalf("unknown format: want 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+// A file row whose key points into another case is never signed or deleted,
+// even for the owner.
+func Test_FileKey_TamperedKeyRejected(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedEngagedWithFile(t, tx)
+		victim := uuid.New()
+		if err := tx.Model(&models.CaseFile{}).Where("id = ?", s.FileID).
+			Update("key", "case/"+victim.String()+"/secret.pdf").Error; err != nil {
+			t.Fatal(err)
+		}
+
+		lawyer := newTestApp(NewHandler(tx, nil), s.LawyerID, string(models.RoleLawyer))
+		resp, _ := lawyer.Test(httptest.NewRequest("GET", "/api/files/"+s.FileID.String()+"/signed-url", nil))
+		if resp.StatusCode != 500 {
+			t.Fatalf("signed url: want 500, got %d", resp.StatusCode)
+		}
+
+		tx.Model(&models.Case{}).Where("id = ?", s.CaseID).Update("status", models.CaseCancelled)
+		client := newTestApp(NewHandler(tx, nil), s.ClientID, string(models.RoleClient))
+		resp, _ = client.Test(httptest.NewRequest("DELETE", "/api/files/"+s.FileID.String(), nil))
+		if resp.StatusCode != 500 {
+			t.Fatalf("delete: want 500, got %d", resp.StatusCode)
+		}
+		var n int64
+		tx.Model(&models.CaseFile{}).Where("id = ?", s.FileID).Count(&n)
+		if n != 1 {
+			t.Fatal("tampered row should be left in place")
+		}
+	})
+}
+
+func TestKeyInCase(t *testing.T) {
+	id := uuid.New()
+	for key, want := range map[string]bool{
+		"case/" + id.String() + "/a.pdf":                  true,
+		"case/" + id.String() + "/123-a b.pdf":            true,
+		"case/" + id.String() + "/":                       false,
+		"case/" + uuid.NewString() + "/a.pdf":             false,
+		"case/" + id.String() + "/../" + uuid.NewString(): false,
+		"case/" + id.String() + "x/a.pdf":                 false,
+		"other/case/" + id.String() + "/a.pdf":            false,
+	} {
+		if got := keyInCase(key, id); got != want {
+			t.Errorf("keyInCase(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
 /* ============================================================================
    Helpers for marketplace tests
    ============================================================================ */
@@ -528,6 +828,41 @@ func Test_Marketplace_RedactsPreview(t *testing.T) {
 	})
 }
 
+// Marketplace timestamps are RFC3339 in UTC with whole seconds, whatever
+// zone and precision the row was written with.
+func Test_Marketplace_CreatedAtIsRFC3339UTC(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		lawyer := uuid.New()
+		_ = tx.Create(&models.User{ID: lawyer, Email: "l_" + lawyer.String()[:6] + "@t", Role: models.RoleLawyer}).Error
+
+		created := time.Now().Add(-time.Hour).In(time.FixedZone("UTC+8", 8*3600))
+		id := seedOpenCase(t, tx, "format", created)
+
+		app := newTestApp(NewHandler(tx, nil), lawyer, string(models.RoleLawyer))
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/marketplace?pageSize=50", nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("got %d", resp.StatusCode)
+		}
+		var out struct {
+			Items []struct {
+				ID        uuid.UUID `json:"id"`
+				CreatedAt string    `json:"created_at"`
+			} `json:"items"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		for _, it := range out.Items {
+			if it.ID == id {
+				if it.CreatedAt != apitime.Format(created) {
+					t.Fatalf("want created_at %s, got %s", apitime.Format(created), it.CreatedAt)
+				}
+				return
+			}
+		}
+		t.Fatal("seeded case not in marketplace")
+	})
+}
+
 // Marketplace should filter by created_since and support pagination.
 func Test_Marketplace_FilterCreatedSince_And_Pagination(t *testing.T) {
 	db := openTestDB(t)
@@ -567,6 +902,149 @@ func Test_Marketplace_FilterCreatedSince_And_Pagination(t *testing.T) {
 	})
 }
 
+// created_since and created_until compose into an inclusive date window.
+func Test_Marketplace_CreatedWindow(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		lawyer := uuid.New()
+		_ = tx.Create(&models.User{ID: lawyer, Email: "l3@t", Role: models.RoleLawyer}).Error
+
+		loc := apptz.Location()
+		day := func(daysAgo int) time.Time {
+			y, m, d := time.Now().In(loc).AddDate(0, 0, -daysAgo).Date()
+			return time.Date(y, m, d, 12, 0, 0, 0, loc)
+		}
+		_ = seedOpenCase(t, tx, "too old", day(10))
+		_ = seedOpenCase(t, tx, "in window", day(5))
+		_ = seedOpenCase(t, tx, "last day, late", day(3).Add(11*time.Hour)) // 23:00 local
+		_ = seedOpenCase(t, tx, "too new", day(1))
+
+		app := newTestApp(NewHandler(tx, nil), lawyer, string(models.RoleLawyer))
+		since := day(7).Format("2006-01-02")
+		until := day(3).Format("2006-01-02")
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/marketplace?created_since="+since+"&created_until="+until, nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("got %d", resp.StatusCode)
+		}
+
+		var out PageMarketCases
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		if out.Total != 2 || len(out.Items) != 2 {
+			t.Fatalf("want the 2 cases inside the window, got total=%d items=%d", out.Total, len(out.Items))
+		}
+		for _, it := range out.Items {
+			if it.CreatedAt.Before(day(7).Add(-12*time.Hour)) || it.CreatedAt.After(day(3).Add(12*time.Hour)) {
+				t.Fatalf("case outside window: %v", it.CreatedAt)
+			}
+		}
+	})
+}
+
+// Date filters follow the configured app timezone, not a hard-coded one:
+// under UTC-10 a case posted at 20:00 local lands on that local day even
+// though it is already the next day in UTC (and in Singapore).
+func Test_Marketplace_CreatedSinceUsesAppTZ(t *testing.T) {
+	loc := time.FixedZone("HST", -10*60*60)
+	apptz.Set(loc)
+	t.Cleanup(func() { apptz.Set(nil) })
+
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		lawyer := uuid.New()
+		_ = tx.Create(&models.User{ID: lawyer, Email: "l_" + lawyer.String()[:8] + "@t", Role: models.RoleLawyer}).Error
+
+		y, m, d := time.Now().In(loc).AddDate(0, 0, -3).Date()
+		_ = seedOpenCase(t, tx, "evening before", time.Date(y, m, d-1, 20, 0, 0, 0, loc))
+		_ = seedOpenCase(t, tx, "first hour", time.Date(y, m, d, 0, 30, 0, 0, loc))
+
+		app := newTestApp(NewHandler(tx, nil), lawyer, string(models.RoleLawyer))
+		since := time.Date(y, m, d, 0, 0, 0, 0, loc).Format("2006-01-02")
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/marketplace?created_since="+since, nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("got %d", resp.StatusCode)
+		}
+		var out PageMarketCases
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		if out.Total != 1 || len(out.Items) != 1 {
+			t.Fatalf("want only the case from %s local time on, got total=%d", since, out.Total)
+		}
+	})
+}
+
+// exclude_quoted drops cases the lawyer already quoted, and total follows.
+func Test_Marketplace_ExcludeQuoted(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		lawyer := uuid.New()
+		_ = tx.Create(&models.User{ID: lawyer, Email: "l4@t", Role: models.RoleLawyer}).Error
+
+		quoted := seedOpenCase(t, tx, "quoted", time.Now().Add(-2*time.Minute))
+		_ = seedOpenCase(t, tx, "fresh", time.Now().Add(-time.Minute))
+		addQuote(t, tx, quoted, lawyer, "mine")
+		other := seedOpenCase(t, tx, "quoted by someone else", time.Now())
+		addQuote(t, tx, other, uuid.New(), "theirs")
+
+		app := newTestApp(NewHandler(tx, nil), lawyer, string(models.RoleLawyer))
+		get := func(query string) PageMarketCases {
+			resp, _ := app.Test(httptest.NewRequest("GET", "/api/marketplace"+query, nil))
+			if resp.StatusCode != 200 {
+				t.Fatalf("%s: got %d", query, resp.StatusCode)
+			}
+			var out PageMarketCases
+			_ = json.NewDecoder(resp.Body).Decode(&out)
+			return out
+		}
+
+		if all := get(""); all.Total != 3 {
+			t.Fatalf("default: want total=3, got %d", all.Total)
+		}
+		out := get("?exclude_quoted=true")
+		if out.Total != 2 || len(out.Items) != 2 {
+			t.Fatalf("exclude_quoted: want total=2, got total=%d items=%d", out.Total, len(out.Items))
+		}
+		for _, it := range out.Items {
+			if it.ID == quoted || it.HasMyQuote {
+				t.Fatalf("quoted case should be excluded, got %+v", it)
+			}
+		}
+	})
+}
+
+// Items carry the live quote count and lowest amount; rejected quotes are ignored.
+func Test_Marketplace_QuoteCountAndLowest(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		busy := seedOpenCase(t, tx, "busy", time.Now().Add(-time.Minute))
+		quiet := seedOpenCase(t, tx, "quiet", time.Now())
+		for i, amount := range []int{900, 400, 700, 100} {
+			q := addQuote(t, tx, busy, uuid.New(), "q"+strconv.Itoa(i))
+			tx.Model(&q).Update("amount_cents", amount)
+			if amount == 100 {
+				tx.Model(&q).Update("status", models.QuoteRejected) // not live competition
+			}
+		}
+
+		app := newTestApp(NewHandler(tx, nil), uuid.New(), string(models.RoleLawyer))
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/marketplace", nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("want 200, got %d", resp.StatusCode)
+		}
+		var out PageMarketCases
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+
+		got := map[uuid.UUID]MarketCaseItem{}
+		for _, it := range out.Items {
+			got[it.ID] = it
+		}
+		if b := got[busy]; b.QuoteCount != 3 || b.LowestAmountCents == nil || *b.LowestAmountCents != 400 {
+			t.Fatalf("busy: want 3 quotes, lowest 400, got %d %v", b.QuoteCount, b.LowestAmountCents)
+		}
+		if q := got[quiet]; q.QuoteCount != 0 || q.LowestAmountCents != nil {
+			t.Fatalf("quiet: want no quotes, got %d %v", q.QuoteCount, q.LowestAmountCents)
+		}
+	})
+}
+
 // Marketplace should redact summaries, mark HasMyQuote correctly, and support created_since.
 func Test_Marketplace_Redaction_HasMyQuote_CreatedSince(t *testing.T) {
 	db := openTestDB(t)
@@ -657,66 +1135,2067 @@ func Test_Marketplace_Redaction_HasMyQuote_CreatedSince(t *testing.T) {
 	})
 }
 
-/* ============================================================================
-   Tests — signed URL auth with accepted lawyer
-   ============================================================================ */
-
-// Accepted lawyer OK, other lawyer 403.
-func Test_SignedURL_Lawyer_OnlyWhenEngagedAccepted(t *testing.T) {
+// Categories list only OPEN cases, one row per category, most cases first.
+func Test_MarketCategories_DistinctWithCounts(t *testing.T) {
 	db := openTestDB(t)
 	withTx(t, db, func(tx *gorm.DB) {
-		seed := seedCase(t, tx, models.CaseEngaged)
+		lawyer, client := uuid.New(), uuid.New()
+		_ = tx.Create(&models.User{ID: lawyer, Email: "l_" + lawyer.String()[:8] + "@x.com", Role: models.RoleLawyer}).Error
+		_ = tx.Create(&models.User{ID: client, Email: "c_" + client.String()[:8] + "@x.com", Role: models.RoleClient}).Error
 
-		// Create an accepted quote for the engaged case
-		q := models.Quote{
-			CaseID: seed.CaseID, LawyerID: seed.LawyerID,
-			AmountCents: 1000, Days: 3, Note: "ok",
-			Status: models.QuoteAccepted, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+		seedIn := func(category string, status models.CaseStatus) {
+			id := makeCase(t, tx, client, time.Now())
+			tx.Model(&models.Case{}).Where("id = ?", id).Updates(map[string]any{"category": category, "status": status})
 		}
-		if err := tx.Create(&q).Error; err != nil {
-			t.Fatal(err)
+		seedIn("Family", models.CaseOpen)
+		seedIn("Family", models.CaseOpen)
+		seedIn("Family", models.CaseEngaged) // not in the marketplace
+		seedIn("Property", models.CaseOpen)
+		seedIn("Property", models.CaseOpen)
+		seedIn("Property", models.CaseOpen)
+		seedIn("Tax", models.CaseOpen)
+		seedIn("Labour", models.CasePaused) // hidden while paused
+
+		app := newTestApp(NewHandler(tx, nil), lawyer, string(models.RoleLawyer))
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/marketplace/categories", nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("got %d", resp.StatusCode)
 		}
-		// Link accepted IDs on the case
-		if err := tx.Model(&models.Case{}).
-			Where("id = ?", seed.CaseID).
-			Updates(map[string]any{
-				"accepted_quote_id":  q.ID,
-				"accepted_lawyer_id": seed.LawyerID,
-			}).Error; err != nil {
-			t.Fatal(err)
+		var out []MarketCategory
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+
+		want := []MarketCategory{{"Property", 3}, {"Family", 2}, {"Tax", 1}}
+		if len(out) != len(want) {
+			t.Fatalf("want %v, got %v", want, out)
+		}
+		for i := range want {
+			if out[i] != want[i] {
+				t.Fatalf("want %v, got %v", want, out)
+			}
 		}
+	})
+}
 
-		// Add a file
-		f := models.CaseFile{
-			CaseID:       seed.CaseID,
-			Key:          "case/" + seed.CaseID.String() + "/doc.pdf",
-			Mime:         "application/pdf",
-			Size:         123,
-			OriginalName: "Secret.pdf",
-			CreatedAt:    time.Now(),
+// Stats count OPEN cases per category and, of those, the ones posted since
+// midnight (app TZ) six days ago.
+func Test_MarketStats_OpenAndNewPerCategory(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		lawyer, client := uuid.New(), uuid.New()
+		_ = tx.Create(&models.User{ID: lawyer, Email: "l_" + lawyer.String()[:8] + "@x.com", Role: models.RoleLawyer}).Error
+		_ = tx.Create(&models.User{ID: client, Email: "c_" + client.String()[:8] + "@x.com", Role: models.RoleClient}).Error
+
+		now := time.Now().In(apptz.Location())
+		y, m, d := now.Date()
+		since := time.Date(y, m, d-6, 0, 0, 0, 0, now.Location())
+
+		seedIn := func(category string, status models.CaseStatus, createdAt time.Time) {
+			id := makeCase(t, tx, client, createdAt)
+			tx.Model(&models.Case{}).Where("id = ?", id).Updates(map[string]any{"category": category, "status": status})
 		}
-		if err := tx.Create(&f).Error; err != nil {
-			t.Fatal(err)
+		seedIn("Family", models.CaseOpen, now)
+		seedIn("Family", models.CaseOpen, since.Add(time.Minute))  // first minute of the window
+		seedIn("Family", models.CaseOpen, since.Add(-time.Minute)) // just before it
+		seedIn("Family", models.CaseEngaged, now)                  // not in the marketplace
+		seedIn("Property", models.CaseOpen, now.AddDate(0, 0, -30))
+		seedIn("Property", models.CaseOpen, now.AddDate(0, 0, -10))
+		seedIn("Tax", models.CaseOpen, now)
+		seedIn("Labour", models.CasePaused, now) // hidden while paused
+
+		app := newTestApp(NewHandler(tx, nil), lawyer, string(models.RoleLawyer))
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/marketplace/stats", nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("got %d", resp.StatusCode)
 		}
+		var out MarketStats
+		_ = json.NewDecoder(resp.Body).Decode(&out)
 
-		h := NewHandler(tx, nil)
+		if !out.Since.Equal(since) {
+			t.Fatalf("since: want %v, got %v", since.UTC(), out.Since)
+		}
+		want := []MarketCategoryStats{
+			{Category: "Family", Open: 3, NewCases: 2},
+			{Category: "Property", Open: 2, NewCases: 0},
+			{Category: "Tax", Open: 1, NewCases: 1},
+		}
+		if len(out.Categories) != len(want) {
+			t.Fatalf("want %v, got %v", want, out.Categories)
+		}
+		for i := range want {
+			if out.Categories[i] != want[i] {
+				t.Fatalf("want %v, got %v", want, out.Categories)
+			}
+		}
+	})
+}
 
-		// Accepted lawyer → 200
-		appOK := newTestApp(h, seed.LawyerID, string(models.RoleLawyer))
-		req1 := httptest.NewRequest("GET", "/api/files/"+f.ID.String()+"/signed-url", nil)
-		resp1, _ := appOK.Test(req1)
-		if resp1.StatusCode != 200 {
-			t.Fatalf("accepted lawyer want 200, got %d", resp1.StatusCode)
+// With the toggle on, each item counts its poster's open cases (engaged ones
+// and other clients' don't count) and the response still carries no client
+// identifier; with it off the field is absent.
+func Test_Marketplace_ClientOpenCaseCount(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		lawyer := uuid.New()
+		_ = tx.Create(&models.User{ID: lawyer, Email: "l_" + lawyer.String()[:8] + "@x.com", Role: models.RoleLawyer}).Error
+		serial, once := uuid.New(), uuid.New()
+		for _, id := range []uuid.UUID{serial, once} {
+			_ = tx.Create(&models.User{ID: id, Email: "c_" + id.String()[:8] + "@x.com", Role: models.RoleClient}).Error
+		}
+		for i := 0; i < 3; i++ {
+			makeCase(t, tx, serial, time.Now())
 		}
+		engaged := makeCase(t, tx, serial, time.Now())
+		tx.Model(&models.Case{}).Where("id = ?", engaged).Update("status", models.CaseEngaged)
+		single := makeCase(t, tx, once, time.Now())
 
-		// Other random lawyer → 403
-		otherLawyer := uuid.New()
-		_ = tx.Create(&models.User{ID: otherLawyer, Email: "oth_" + otherLawyer.String()[:6] + "@x.com", Role: models.RoleLawyer})
-		app403 := newTestApp(h, otherLawyer, string(models.RoleLawyer))
+		app := newTestApp(NewHandler(tx, nil), lawyer, string(models.RoleLawyer))
+		get := func() (string, PageMarketCases) {
+			t.Helper()
+			resp, _ := app.Test(httptest.NewRequest("GET", "/api/marketplace", nil))
+			if resp.StatusCode != 200 {
+				t.Fatalf("got %d", resp.StatusCode)
+			}
+			raw, _ := io.ReadAll(resp.Body)
+			var out PageMarketCases
+			_ = json.Unmarshal(raw, &out)
+			return string(raw), out
+		}
+
+		raw, _ := get()
+		if strings.Contains(raw, "client_open_case_count") {
+			t.Fatalf("toggle off: field should be absent: %s", raw)
+		}
+
+		t.Setenv("MARKETPLACE_CLIENT_CASE_COUNT", "true")
+		raw, out := get()
+		if len(out.Items) != 4 {
+			t.Fatalf("want 4 open cases, got %d", len(out.Items))
+		}
+		for _, it := range out.Items {
+			want := int64(3)
+			if it.ID == single {
+				want = 1
+			}
+			if it.ClientOpenCaseCount == nil || *it.ClientOpenCaseCount != want {
+				t.Fatalf("case %s: want count %d, got %v", it.ID, want, it.ClientOpenCaseCount)
+			}
+		}
+		for _, id := range []uuid.UUID{serial, once} {
+			if strings.Contains(raw, id.String()) || strings.Contains(raw, id.String()[:8]+"@x.com") {
+				t.Fatalf("response leaks client %s: %s", id, raw)
+			}
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — case collaborators
+   ============================================================================ */
+
+// The accepted lawyer grants a paralegal read access: they can fetch a
+// signed URL and the detail until revoked. Nobody else can grant.
+func Test_Collaborator_GrantThenRevoke(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedEngagedWithFile(t, tx)
+		para := uuid.New()
+		paraEmail := "p_" + para.String()[:8] + "@x.com"
+		_ = tx.Create(&models.User{ID: para, Email: paraEmail, Name: "Para", Role: models.RoleLawyer}).Error
+
+		h := NewHandler(tx, nil)
+		lawyerApp := newTestApp(h, s.LawyerID, string(models.RoleLawyer))
+		paraApp := newTestApp(h, para, string(models.RoleLawyer))
+		signedURL := func() int {
+			resp, _ := paraApp.Test(httptest.NewRequest("GET", "/api/files/"+s.FileID.String()+"/signed-url", nil))
+			return resp.StatusCode
+		}
+		grant := func(app *fiber.App, email string) int {
+			req := httptest.NewRequest("POST", "/api/cases/"+s.CaseID.String()+"/collaborators",
+				strings.NewReader(`{"email":"`+email+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+			return resp.StatusCode
+		}
+
+		if code := signedURL(); code != 404 {
+			t.Fatalf("before grant: want 404, got %d", code)
+		}
+		// Only the accepted lawyer grants, and only to a lawyer account
+		if code := grant(paraApp, paraEmail); code != 404 {
+			t.Fatalf("non-accepted lawyer granting: want 404, got %d", code)
+		}
+		var client models.User
+		tx.First(&client, "id = ?", s.ClientID)
+		if code := grant(lawyerApp, client.Email); code != 400 {
+			t.Fatalf("granting a client account: want 400, got %d", code)
+		}
+
+		if code := grant(lawyerApp, strings.ToUpper(paraEmail)); code != 201 {
+			t.Fatalf("grant: want 201, got %d", code)
+		}
+		if code := grant(lawyerApp, paraEmail); code != 201 {
+			t.Fatalf("repeat grant: want 201, got %d", code)
+		}
+		if code := signedURL(); code != 200 {
+			t.Fatalf("granted: want 200, got %d", code)
+		}
+		resp, _ := paraApp.Test(httptest.NewRequest("GET", "/api/cases/"+s.CaseID.String(), nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("granted detail: want 200, got %d", resp.StatusCode)
+		}
+
+		resp, _ = lawyerApp.Test(httptest.NewRequest("GET", "/api/cases/"+s.CaseID.String()+"/collaborators", nil))
+		var list []CollaboratorItem
+		_ = json.NewDecoder(resp.Body).Decode(&list)
+		if len(list) != 1 || list[0].UserID != para {
+			t.Fatalf("want the one collaborator, got %+v", list)
+		}
+
+		resp, _ = lawyerApp.Test(httptest.NewRequest("DELETE", "/api/cases/"+s.CaseID.String()+"/collaborators/"+para.String(), nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("revoke: want 200, got %d", resp.StatusCode)
+		}
+		if code := signedURL(); code != 404 {
+			t.Fatalf("revoked: want 404, got %d", code)
+		}
+	})
+}
+
+// Access is per case: a grant on one case opens nothing on another.
+func Test_Collaborator_ScopedToOneCase(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		a, b := seedEngagedWithFile(t, tx), seedEngagedWithFile(t, tx)
+		para := uuid.New()
+		_ = tx.Create(&models.User{ID: para, Email: "p_" + para.String()[:8] + "@x.com", Role: models.RoleLawyer}).Error
+		_ = tx.Create(&models.CaseCollaborator{CaseID: a.CaseID, UserID: para, GrantedBy: a.LawyerID}).Error
+
+		app := newTestAppFiles(NewHandler(tx, nil), para, string(models.RoleLawyer))
+		for _, tc := range []struct {
+			file uuid.UUID
+			want int
+		}{{a.FileID, 200}, {b.FileID, 404}} {
+			resp, _ := app.Test(httptest.NewRequest("GET", "/files/"+tc.file.String()+"/signed-url", nil))
+			if resp.StatusCode != tc.want {
+				t.Fatalf("file %s: want %d, got %d", tc.file, tc.want, resp.StatusCode)
+			}
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — signed URL auth with accepted lawyer
+   ============================================================================ */
+
+// Accepted lawyer OK, other lawyer 404 (existence not confirmed).
+func Test_SignedURL_Lawyer_OnlyWhenEngagedAccepted(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseEngaged)
+
+		// Create an accepted quote for the engaged case
+		q := models.Quote{
+			CaseID: seed.CaseID, LawyerID: seed.LawyerID,
+			AmountCents: 1000, Days: 3, Note: "ok",
+			Status: models.QuoteAccepted, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+		}
+		if err := tx.Create(&q).Error; err != nil {
+			t.Fatal(err)
+		}
+		// Link accepted IDs on the case
+		if err := tx.Model(&models.Case{}).
+			Where("id = ?", seed.CaseID).
+			Updates(map[string]any{
+				"accepted_quote_id":  q.ID,
+				"accepted_lawyer_id": seed.LawyerID,
+			}).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		// Add a file
+		f := models.CaseFile{
+			CaseID:       seed.CaseID,
+			Key:          "case/" + seed.CaseID.String() + "/doc.pdf",
+			Mime:         "application/pdf",
+			Size:         123,
+			OriginalName: "Secret.pdf",
+			CreatedAt:    time.Now(),
+		}
+		if err := tx.Create(&f).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		h := NewHandler(tx, nil)
+
+		// Accepted lawyer → 200
+		appOK := newTestApp(h, seed.LawyerID, string(models.RoleLawyer))
+		req1 := httptest.NewRequest("GET", "/api/files/"+f.ID.String()+"/signed-url", nil)
+		resp1, _ := appOK.Test(req1)
+		if resp1.StatusCode != 200 {
+			t.Fatalf("accepted lawyer want 200, got %d", resp1.StatusCode)
+		}
+
+		// Other random lawyer → 404
+		otherLawyer := uuid.New()
+		_ = tx.Create(&models.User{ID: otherLawyer, Email: "oth_" + otherLawyer.String()[:6] + "@x.com", Role: models.RoleLawyer})
+		appOther := newTestApp(h, otherLawyer, string(models.RoleLawyer))
 		req2 := httptest.NewRequest("GET", "/api/files/"+f.ID.String()+"/signed-url", nil)
-		resp2, _ := app403.Test(req2)
-		if resp2.StatusCode != 403 {
-			t.Fatalf("other lawyer want 403, got %d", resp2.StatusCode)
+		resp2, _ := appOther.Test(req2)
+		if resp2.StatusCode != 404 {
+			t.Fatalf("other lawyer want 404, got %d", resp2.StatusCode)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — idempotent close / cancel
+   ============================================================================ */
+
+// Repeating the client's own close or cancel answers 200 without a second
+// history entry; illegal transitions (closing an open case, a case cancelled
+// by someone else) still answer 409.
+func Test_CloseCancel_IdempotentRepeat(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		post := func(s seedResult, action string) int {
+			app := newTestApp(NewHandler(tx, nil), s.ClientID, string(models.RoleClient))
+			resp, _ := app.Test(httptest.NewRequest("POST", "/api/cases/"+s.CaseID.String()+"/"+action, nil))
+			return resp.StatusCode
+		}
+		entries := func(s seedResult, action string) int64 {
+			var n int64
+			tx.Model(&models.CaseHistory{}).Where("case_id = ? AND action = ?", s.CaseID, action).Count(&n)
+			return n
+		}
+
+		engaged := seedCase(t, tx, models.CaseEngaged)
+		open := seedCase(t, tx, models.CaseOpen)
+		for _, tc := range []struct {
+			s               seedResult
+			action, history string
+		}{{engaged, "close", "closed"}, {open, "cancel", "cancelled"}} {
+			for i := range 2 {
+				if code := post(tc.s, tc.action); code != 200 {
+					t.Fatalf("%s #%d: want 200, got %d", tc.action, i+1, code)
+				}
+			}
+			if n := entries(tc.s, tc.history); n != 1 {
+				t.Fatalf("%s: want 1 history entry, got %d", tc.action, n)
+			}
+		}
+
+		if code := post(seedCase(t, tx, models.CaseOpen), "close"); code != 409 {
+			t.Fatalf("close open case: want 409, got %d", code)
+		}
+		if code := post(engaged, "cancel"); code != 409 {
+			t.Fatalf("cancel closed case: want 409, got %d", code)
+		}
+
+		// Cancelled by someone else (no history from this client)
+		byOther := seedCase(t, tx, models.CaseCancelled)
+		utils.LogCaseHistory(context.Background(), tx, byOther.CaseID, uuid.New(), models.ActorUser, "cancelled", models.CaseOpen, models.CaseCancelled, "")
+		if code := post(byOther, "cancel"); code != 409 {
+			t.Fatalf("cancelled by another user: want 409, got %d", code)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — reopen cancelled case
+   ============================================================================ */
+
+// Owner can reopen a recently cancelled, never-engaged case; quotes are kept.
+func Test_Reopen_CancelledWithinWindow_OK(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseCancelled)
+		cancelledAt := time.Now().Add(-time.Hour)
+		if err := tx.Model(&models.Case{}).Where("id = ?", seed.CaseID).
+			Update("cancelled_at", &cancelledAt).Error; err != nil {
+			t.Fatal(err)
+		}
+		q := addQuote(t, tx, seed.CaseID, seed.LawyerID, "still here")
+
+		app := newTestApp(NewHandler(tx, nil), seed.ClientID, string(models.RoleClient))
+		req := httptest.NewRequest("POST", "/api/cases/"+seed.CaseID.String()+"/reopen", nil)
+		resp, _ := app.Test(req)
+		if resp.StatusCode != 200 {
+			t.Fatalf("want 200, got %d", resp.StatusCode)
+		}
+
+		var cs models.Case
+		if err := tx.First(&cs, "id = ?", seed.CaseID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if cs.Status != models.CaseOpen || cs.CancelledAt != nil {
+			t.Fatalf("case should be open with cancelled_at cleared, got %+v", cs)
+		}
+
+		var kept models.Quote
+		if err := tx.First(&kept, "id = ?", q.ID).Error; err != nil || kept.Status != models.QuoteProposed {
+			t.Fatalf("existing quote should be untouched, got %+v (err=%v)", kept, err)
+		}
+
+		var cnt int64
+		tx.Model(&models.CaseHistory{}).Where("case_id = ? AND action = ?", seed.CaseID, "reopened").Count(&cnt)
+		if cnt != 1 {
+			t.Fatalf("want 1 reopened history row, got %d", cnt)
+		}
+	})
+}
+
+// Reopen is rejected for engaged cases, expired windows, and non-owners.
+func Test_Reopen_DisallowedTransitions(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		recent := time.Now().Add(-time.Hour)
+		stale := time.Now().Add(-30 * 24 * time.Hour)
+
+		// Engaged case → 409
+		engaged := seedCase(t, tx, models.CaseEngaged)
+		// Cancelled long ago → 409
+		expired := seedCase(t, tx, models.CaseCancelled)
+		_ = tx.Model(&models.Case{}).Where("id = ?", expired.CaseID).Update("cancelled_at", &stale).Error
+		// Cancelled after having been engaged → 409
+		wasEngaged := seedCase(t, tx, models.CaseCancelled)
+		_ = tx.Model(&models.Case{}).Where("id = ?", wasEngaged.CaseID).Updates(map[string]any{
+			"cancelled_at": &recent,
+			"engaged_at":   &stale,
+		}).Error
+
+		for name, s := range map[string]seedResult{"engaged": engaged, "expired": expired, "was_engaged": wasEngaged} {
+			app := newTestApp(NewHandler(tx, nil), s.ClientID, string(models.RoleClient))
+			req := httptest.NewRequest("POST", "/api/cases/"+s.CaseID.String()+"/reopen", nil)
+			resp, _ := app.Test(req)
+			if resp.StatusCode != 409 {
+				t.Fatalf("%s: want 409, got %d", name, resp.StatusCode)
+			}
+		}
+
+		// Someone else's cancelled case → 403
+		other := seedCase(t, tx, models.CaseCancelled)
+		_ = tx.Model(&models.Case{}).Where("id = ?", other.CaseID).Update("cancelled_at", &recent).Error
+		app := newTestApp(NewHandler(tx, nil), engaged.ClientID, string(models.RoleClient))
+		req := httptest.NewRequest("POST", "/api/cases/"+other.CaseID.String()+"/reopen", nil)
+		resp, _ := app.Test(req)
+		if resp.StatusCode != 403 {
+			t.Fatalf("non-owner: want 403, got %d", resp.StatusCode)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — pause / resume
+   ============================================================================ */
+
+// A paused case drops out of the marketplace but stays with its owner (quotes
+// kept); resuming puts it back. Both transitions are logged.
+func Test_Pause_HidesFromMarketplace_ResumeRestores(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseOpen)
+		q := addQuote(t, tx, seed.CaseID, seed.LawyerID, "kept")
+		h := NewHandler(tx, nil)
+		owner := newTestApp(h, seed.ClientID, string(models.RoleClient))
+		lawyer := newTestApp(h, seed.LawyerID, string(models.RoleLawyer))
+
+		inMarket := func() bool {
+			resp, _ := lawyer.Test(httptest.NewRequest("GET", "/api/marketplace?pageSize=50", nil))
+			var page PageMarketCases
+			_ = json.NewDecoder(resp.Body).Decode(&page)
+			for _, it := range page.Items {
+				if it.ID == seed.CaseID {
+					return true
+				}
+			}
+			return false
+		}
+		post := func(app *fiber.App, action string) int {
+			resp, _ := app.Test(httptest.NewRequest("POST", "/api/cases/"+seed.CaseID.String()+"/"+action, nil))
+			return resp.StatusCode
+		}
+
+		if !inMarket() {
+			t.Fatalf("open case should be in the marketplace")
+		}
+		if code := post(owner, "pause"); code != 200 {
+			t.Fatalf("pause: want 200, got %d", code)
+		}
+		if inMarket() {
+			t.Fatalf("paused case should be hidden from the marketplace")
+		}
+		if code := post(owner, "pause"); code != 409 {
+			t.Fatalf("pause twice: want 409, got %d", code)
+		}
+
+		// Owner still sees it, with the quote untouched
+		resp, _ := owner.Test(httptest.NewRequest("GET", "/api/cases/"+seed.CaseID.String(), nil))
+		var detail CaseDetailResponse
+		_ = json.NewDecoder(resp.Body).Decode(&detail)
+		if resp.StatusCode != 200 || detail.Status != models.CasePaused || len(detail.Quotes) != 1 || detail.Quotes[0].ID != q.ID {
+			t.Fatalf("owner should see paused case with its quote, got %d %+v", resp.StatusCode, detail.Case)
+		}
+
+		if code := post(owner, "resume"); code != 200 {
+			t.Fatalf("resume: want 200, got %d", code)
+		}
+		if !inMarket() {
+			t.Fatalf("resumed case should be back in the marketplace")
+		}
+		if code := post(owner, "resume"); code != 409 {
+			t.Fatalf("resume open case: want 409, got %d", code)
+		}
+
+		var cnt int64
+		tx.Model(&models.CaseHistory{}).Where("case_id = ? AND action IN ?", seed.CaseID, []string{"paused", "resumed"}).Count(&cnt)
+		if cnt != 2 {
+			t.Fatalf("want 2 history rows, got %d", cnt)
+		}
+	})
+}
+
+// Only the owner may pause.
+func Test_Pause_NonOwnerForbidden(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseOpen)
+		app := newTestApp(NewHandler(tx, nil), uuid.New(), string(models.RoleClient))
+		resp, _ := app.Test(httptest.NewRequest("POST", "/api/cases/"+seed.CaseID.String()+"/pause", nil))
+		if resp.StatusCode != 403 {
+			t.Fatalf("non-owner: want 403, got %d", resp.StatusCode)
+		}
+	})
+}
+
+// A case can't be paused while a checkout on it is in flight: the payment
+// would complete against a case that isn't open.
+func Test_Pause_RefusedDuringCheckout(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedCase(t, tx, models.CaseOpen)
+		pay := seedCheckout(t, tx, s)
+		app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+		app.Use(injectAuth(s.ClientID, string(models.RoleClient)))
+		app.Post("/api/cases/:id/pause", NewHandler(tx, nil).Pause)
+		pause := func() (int, string) {
+			resp, _ := app.Test(httptest.NewRequest("POST", "/api/cases/"+s.CaseID.String()+"/pause", nil))
+			var e models.ErrorResponse
+			_ = json.NewDecoder(resp.Body).Decode(&e)
+			return resp.StatusCode, e.Code
+		}
+
+		if code, ec := pause(); code != 409 || ec != apperr.CaseInCheckout {
+			t.Fatalf("during checkout: want 409 %s, got %d %s", apperr.CaseInCheckout, code, ec)
+		}
+		var cs models.Case
+		tx.First(&cs, "id = ?", s.CaseID)
+		if cs.Status != models.CaseOpen {
+			t.Fatalf("case should stay open, got %s", cs.Status)
+		}
+
+		tx.Model(&models.Payment{}).Where("id = ?", pay.ID).Update("status", models.PayFailed)
+		if code, _ := pause(); code != 200 {
+			t.Fatalf("after the payment failed: want 200, got %d", code)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — conditional GET
+   ============================================================================ */
+
+// getWithETag sends a GET with an optional If-None-Match and returns the
+// status and the response ETag.
+func getWithETag(t *testing.T, app *fiber.App, path, etag string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest("GET", path, nil)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, resp.Header.Get("ETag")
+}
+
+// An unchanged case (and history) answers 304; a status change busts the ETag.
+func Test_GetDetail_ETag_NotModifiedUntilChanged(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseOpen)
+		utils.LogCaseHistory(context.Background(), tx, seed.CaseID, seed.ClientID, models.ActorUser, "created", "", models.CaseOpen, "")
+		app := newTestApp(NewHandler(tx, nil), seed.ClientID, string(models.RoleClient))
+		detail := "/api/cases/" + seed.CaseID.String()
+		history := detail + "/history"
+
+		code, tag := getWithETag(t, app, detail, "")
+		if code != 200 || tag == "" {
+			t.Fatalf("first GET: want 200 with ETag, got %d %q", code, tag)
+		}
+		if code, _ := getWithETag(t, app, detail, tag); code != 304 {
+			t.Fatalf("unchanged: want 304, got %d", code)
+		}
+		_, htag := getWithETag(t, app, history, "")
+		if code, _ := getWithETag(t, app, history, htag); code != 304 {
+			t.Fatalf("unchanged history: want 304, got %d", code)
+		}
+
+		if err := tx.Model(&models.Case{}).Where("id = ?", seed.CaseID).
+			Update("status", models.CasePaused).Error; err != nil {
+			t.Fatal(err)
+		}
+		utils.LogCaseHistory(context.Background(), tx, seed.CaseID, seed.ClientID, models.ActorUser, "paused", models.CaseOpen, models.CasePaused, "")
+
+		code, newTag := getWithETag(t, app, detail, tag)
+		if code != 200 || newTag == tag {
+			t.Fatalf("after status change: want 200 with a new ETag, got %d %q", code, newTag)
+		}
+		if code, _ := getWithETag(t, app, history, htag); code != 200 {
+			t.Fatalf("after new history entry: want 200, got %d", code)
+		}
+
+		// Another client's ETag guess never gets past authorization
+		other := newTestApp(NewHandler(tx, nil), uuid.New(), string(models.RoleClient))
+		if code, _ := getWithETag(t, other, detail, newTag); code != 403 {
+			t.Fatalf("non-owner: want 403, got %d", code)
+		}
+	})
+}
+
+// since/until bound the history to local days (until inclusive); format=csv
+// returns the same rows as a CSV attachment.
+func Test_ListHistory_DateFilterAndCSV(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseOpen)
+		loc := apptz.Location()
+		day := func(daysAgo int) time.Time {
+			y, m, d := time.Now().In(loc).AddDate(0, 0, -daysAgo).Date()
+			return time.Date(y, m, d, 12, 0, 0, 0, loc)
+		}
+		for _, h := range []models.CaseHistory{
+			{Action: "created", NewStatus: models.CaseOpen, CreatedAt: day(10)},
+			{Action: "paused", OldStatus: models.CaseOpen, NewStatus: models.CasePaused, CreatedAt: day(5)},
+			{Action: "resumed", OldStatus: models.CasePaused, NewStatus: models.CaseOpen, Reason: "=back, on", CreatedAt: day(3).Add(11 * time.Hour)},
+			{Action: "paused", OldStatus: models.CaseOpen, NewStatus: models.CasePaused, CreatedAt: day(1)},
+		} {
+			h.CaseID, h.ActorID = seed.CaseID, seed.ClientID
+			if err := tx.Create(&h).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		app := newTestApp(NewHandler(tx, nil), seed.ClientID, string(models.RoleClient))
+		base := "/api/cases/" + seed.CaseID.String() + "/history?since=" + day(7).Format("2006-01-02") +
+			"&until=" + day(3).Format("2006-01-02")
+
+		resp, _ := app.Test(httptest.NewRequest("GET", base, nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("json: got %d", resp.StatusCode)
+		}
+		var rows []CaseHistoryDTO
+		_ = json.NewDecoder(resp.Body).Decode(&rows)
+		if len(rows) != 2 || rows[0].Action != "paused" || rows[1].Action != "resumed" {
+			t.Fatalf("want the 2 entries inside the window, got %+v", rows)
+		}
+
+		resp, _ = app.Test(httptest.NewRequest("GET", base+"&format=csv", nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("csv: got %d", resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+			t.Fatalf("want text/csv, got %q", ct)
+		}
+		recs, err := csv.NewReader(resp.Body).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(recs) != 3 || strings.Join(recs[0], ",") != "action,old_status,new_status,reason,actor,created_at" {
+			t.Fatalf("unexpected csv: %v", recs)
+		}
+		if recs[2][3] != "'=back, on" || recs[2][4] != seed.ClientID.String() {
+			t.Fatalf("unexpected csv row: %v", recs[2])
+		}
+
+		if resp, _ := app.Test(httptest.NewRequest("GET", base+"&format=xml", nil)); resp.StatusCode != 400 {
+			t.Fatalf("unknown format: want 400, got %d", resp.StatusCode)
+		}
+
+		// Same authorization as JSON
+		other := newTestApp(NewHandler(tx, nil), uuid.New(), string(models.RoleClient))
+		if resp, _ := other.Test(httptest.NewRequest("GET", base+"&format=csv", nil)); resp.StatusCode != 403 {
+			t.Fatalf("non-owner csv: want 403, got %d", resp.StatusCode)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — counterpart contact
+   ============================================================================ */
+
+// Contact details reach the counterpart only once the case is engaged; admins
+// and pre-engagement views never see them.
+func Test_GetDetail_CounterpartContactOnlyOnceEngaged(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseOpen)
+		tx.Model(&models.User{}).Where("id = ?", seed.ClientID).
+			Updates(map[string]any{"contact_phone": "+6591110000", "preferred_contact": "phone"})
+		tx.Model(&models.User{}).Where("id = ?", seed.LawyerID).
+			Updates(map[string]any{"contact_phone": "+6592220000", "preferred_contact": "email"})
+
+		detail := func(userID uuid.UUID, role models.Role) (int, string) {
+			app := newTestApp(NewHandler(tx, nil), userID, string(role))
+			resp, _ := app.Test(httptest.NewRequest("GET", "/api/cases/"+seed.CaseID.String(), nil))
+			b, _ := io.ReadAll(resp.Body)
+			return resp.StatusCode, string(b)
+		}
+
+		// Before engagement: no counterpart at all
+		if code, body := detail(seed.ClientID, models.RoleClient); code != 200 || strings.Contains(body, "+659") {
+			t.Fatalf("open case, client: want 200 without contact, got %d %s", code, body)
+		}
+		if code, _ := detail(seed.LawyerID, models.RoleLawyer); code != 404 {
+			t.Fatalf("open case, lawyer: want 404, got %d", code)
+		}
+
+		q := addQuote(t, tx, seed.CaseID, seed.LawyerID, "n")
+		tx.Model(&models.Case{}).Where("id = ?", seed.CaseID).Updates(map[string]any{
+			"status": models.CaseEngaged, "accepted_quote_id": q.ID, "accepted_lawyer_id": seed.LawyerID,
+		})
+
+		var client CaseDetailResponse
+		code, body := detail(seed.ClientID, models.RoleClient)
+		_ = json.Unmarshal([]byte(body), &client)
+		if code != 200 || client.AcceptedLawyer == nil || client.AcceptedLawyer.ContactPhone != "+6592220000" ||
+			client.AcceptedLawyer.PreferredContact != "email" {
+			t.Fatalf("engaged, client: want lawyer contact, got %d %s", code, body)
+		}
+
+		var lawyer CaseDetailResponse
+		code, body = detail(seed.LawyerID, models.RoleLawyer)
+		_ = json.Unmarshal([]byte(body), &lawyer)
+		if code != 200 || lawyer.Client == nil || lawyer.Client.ContactPhone != "+6591110000" ||
+			lawyer.Client.PreferredContact != "phone" {
+			t.Fatalf("engaged, lawyer: want client contact, got %d %s", code, body)
+		}
+
+		if code, body := detail(uuid.New(), models.RoleAdmin); code != 200 || strings.Contains(body, "+659") {
+			t.Fatalf("admin: want 200 without contact, got %d %s", code, body)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — admin read access
+   ============================================================================ */
+
+// Admin can read any case detail and history; a different client cannot.
+func Test_Admin_CanReadAnyCaseDetailAndHistory(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseOpen)
+		adminID := uuid.New()
+		_ = tx.Create(&models.User{ID: adminID, Email: "adm_" + adminID.String()[:6] + "@x.com", Role: models.RoleAdmin}).Error
+
+		h := NewHandler(tx, nil)
+		adminApp := newTestApp(h, adminID, string(models.RoleAdmin))
+		for _, path := range []string{"/api/cases/" + seed.CaseID.String(), "/api/cases/" + seed.CaseID.String() + "/history"} {
+			resp, _ := adminApp.Test(httptest.NewRequest("GET", path, nil))
+			if resp.StatusCode != 200 {
+				t.Fatalf("admin %s: want 200, got %d", path, resp.StatusCode)
+			}
+		}
+
+		// Admin detail includes the client profile
+		resp, _ := adminApp.Test(httptest.NewRequest("GET", "/api/cases/"+seed.CaseID.String(), nil))
+		var body CaseDetailResponse
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		if body.Client == nil || body.Client.ID != seed.ClientID {
+			t.Fatalf("admin detail should include client profile, got %#v", body.Client)
+		}
+
+		// Another client is still rejected
+		otherApp := newTestApp(h, uuid.New(), string(models.RoleClient))
+		resp2, _ := otherApp.Test(httptest.NewRequest("GET", "/api/cases/"+seed.CaseID.String(), nil))
+		if resp2.StatusCode != 403 {
+			t.Fatalf("other client: want 403, got %d", resp2.StatusCode)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — non-party lawyers
+   ============================================================================ */
+
+// A lawyer who isn't the accepted one gets 404 on detail, history and signed
+// URLs (403 with CASE_LAWYER_DENY_STATUS=403); clients keep getting 403.
+func Test_NonPartyLawyer_NotFoundPolicy(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedEngagedWithFile(t, tx)
+		open := seedCase(t, tx, models.CaseOpen)
+		h := NewHandler(tx, nil)
+
+		paths := []string{
+			"/api/cases/" + s.CaseID.String(),
+			"/api/cases/" + s.CaseID.String() + "/history",
+			"/api/files/" + s.FileID.String() + "/signed-url",
+			"/api/cases/" + open.CaseID.String(), // open case: nobody's engaged yet
+		}
+		status := func(userID uuid.UUID, role models.Role, path string) int {
+			resp, _ := newTestApp(h, userID, string(role)).Test(httptest.NewRequest("GET", path, nil))
+			return resp.StatusCode
+		}
+
+		stranger := uuid.New()
+		_ = tx.Create(&models.User{ID: stranger, Email: "sl_" + stranger.String()[:6] + "@x.com", Role: models.RoleLawyer}).Error
+
+		for _, p := range paths {
+			if code := status(stranger, models.RoleLawyer, p); code != 404 {
+				t.Fatalf("lawyer %s: want 404, got %d", p, code)
+			}
+			if code := status(uuid.New(), models.RoleClient, p); code != 403 {
+				t.Fatalf("client %s: want 403, got %d", p, code)
+			}
+		}
+
+		t.Setenv("CASE_LAWYER_DENY_STATUS", "403")
+		for _, p := range paths {
+			if code := status(stranger, models.RoleLawyer, p); code != 403 {
+				t.Fatalf("lawyer %s with 403 policy: want 403, got %d", p, code)
+			}
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — soft delete
+   ============================================================================ */
+
+// A soft-deleted case leaves the owner's list and the marketplace, but the row,
+// its files/history and an admin (unscoped) read remain.
+func Test_Delete_SoftDeletedCaseHiddenButKept(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseOpen)
+		if err := tx.Create(&models.CaseFile{
+			CaseID: seed.CaseID, Key: "case/x/a.pdf", Mime: "application/pdf", Size: 1, OriginalName: "a.pdf",
+		}).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		client := newTestApp(NewHandler(tx, nil), seed.ClientID, string(models.RoleClient))
+		resp, _ := client.Test(httptest.NewRequest("DELETE", "/api/cases/"+seed.CaseID.String(), nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("delete: want 200, got %d", resp.StatusCode)
+		}
+
+		// Gone from the owner's list and detail
+		var mine struct {
+			Total int64 `json:"total"`
+		}
+		resp, _ = client.Test(httptest.NewRequest("GET", "/api/cases/mine", nil))
+		_ = json.NewDecoder(resp.Body).Decode(&mine)
+		if mine.Total != 0 {
+			t.Fatalf("ListMine should hide deleted case, got total=%d", mine.Total)
+		}
+		resp, _ = client.Test(httptest.NewRequest("GET", "/api/cases/"+seed.CaseID.String(), nil))
+		if resp.StatusCode != 404 {
+			t.Fatalf("owner detail: want 404, got %d", resp.StatusCode)
+		}
+
+		// Gone from the marketplace
+		lawyer := newTestApp(NewHandler(tx, nil), seed.LawyerID, string(models.RoleLawyer))
+		var market struct {
+			Total int64 `json:"total"`
+		}
+		resp, _ = lawyer.Test(httptest.NewRequest("GET", "/api/marketplace", nil))
+		_ = json.NewDecoder(resp.Body).Decode(&market)
+		if market.Total != 0 {
+			t.Fatalf("marketplace should hide deleted case, got total=%d", market.Total)
+		}
+
+		// Still there unscoped, with files and a history row
+		var cs models.Case
+		if err := tx.Unscoped().First(&cs, "id = ?", seed.CaseID).Error; err != nil || !cs.DeletedAt.Valid {
+			t.Fatalf("row should remain with deleted_at set, got %+v (err=%v)", cs, err)
+		}
+		var files, hist int64
+		tx.Model(&models.CaseFile{}).Where("case_id = ?", seed.CaseID).Count(&files)
+		tx.Model(&models.CaseHistory{}).Where("case_id = ? AND action = ?", seed.CaseID, "deleted").Count(&hist)
+		if files != 1 || hist != 1 {
+			t.Fatalf("files/history should be kept, got files=%d history=%d", files, hist)
+		}
+
+		// Admins can still open it
+		admin := newTestApp(NewHandler(tx, nil), uuid.New(), string(models.RoleAdmin))
+		resp, _ = admin.Test(httptest.NewRequest("GET", "/api/cases/"+seed.CaseID.String(), nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("admin detail: want 200, got %d", resp.StatusCode)
+		}
+	})
+}
+
+// Engaged cases cannot be deleted, and only the owner may delete.
+func Test_Delete_DisallowedForEngagedOrNonOwner(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		engaged := seedCase(t, tx, models.CaseEngaged)
+		app := newTestApp(NewHandler(tx, nil), engaged.ClientID, string(models.RoleClient))
+		resp, _ := app.Test(httptest.NewRequest("DELETE", "/api/cases/"+engaged.CaseID.String(), nil))
+		if resp.StatusCode != 409 {
+			t.Fatalf("engaged: want 409, got %d", resp.StatusCode)
+		}
+
+		open := seedCase(t, tx, models.CaseOpen)
+		other := newTestApp(NewHandler(tx, nil), uuid.New(), string(models.RoleClient))
+		resp, _ = other.Test(httptest.NewRequest("DELETE", "/api/cases/"+open.CaseID.String(), nil))
+		if resp.StatusCode != 403 {
+			t.Fatalf("non-owner: want 403, got %d", resp.StatusCode)
+		}
+	})
+}
+
+// seedCheckout puts a proposed quote with an INITIATED payment on the case,
+// i.e. a Stripe session the client has opened but not finished.
+func seedCheckout(t *testing.T, tx *gorm.DB, s seedResult) models.Payment {
+	t.Helper()
+	q := models.Quote{CaseID: s.CaseID, LawyerID: s.LawyerID, AmountCents: 5000, Days: 3, Status: models.QuoteProposed}
+	if err := tx.Create(&q).Error; err != nil {
+		t.Fatal(err)
+	}
+	pay := models.Payment{CaseID: s.CaseID, QuoteID: q.ID, ClientID: s.ClientID, AmountCents: 5000, Status: models.PayInitiated}
+	if err := tx.Create(&pay).Error; err != nil {
+		t.Fatal(err)
+	}
+	return pay
+}
+
+// A case with a checkout in flight can't be deleted; once the payment has
+// failed it can.
+func Test_Delete_RefusedDuringCheckout(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedCase(t, tx, models.CaseOpen)
+		pay := seedCheckout(t, tx, s)
+		app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+		app.Use(injectAuth(s.ClientID, string(models.RoleClient)))
+		app.Delete("/api/cases/:id", NewHandler(tx, nil).Delete)
+		del := func() (int, string) {
+			resp, _ := app.Test(httptest.NewRequest("DELETE", "/api/cases/"+s.CaseID.String(), nil))
+			var e models.ErrorResponse
+			_ = json.NewDecoder(resp.Body).Decode(&e)
+			return resp.StatusCode, e.Code
+		}
+
+		if code, ec := del(); code != 409 || ec != apperr.CaseInCheckout {
+			t.Fatalf("during checkout: want 409 %s, got %d %s", apperr.CaseInCheckout, code, ec)
+		}
+		tx.Model(&models.Payment{}).Where("id = ?", pay.ID).Update("status", models.PayFailed)
+		if code, _ := del(); code != 200 {
+			t.Fatalf("after the payment failed: want 200, got %d", code)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — case references
+   ============================================================================ */
+
+// postCase posts a case with the given title and returns the response.
+func postCase(t *testing.T, app *fiber.App, title string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/cases",
+		strings.NewReader(`{"title":"`+title+`","category":"Property"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// createCase posts a valid case and returns the reference from the response.
+func createCase(t *testing.T, app *fiber.App, title string) string {
+	t.Helper()
+	resp := postCase(t, app, title)
+	if resp.StatusCode != 201 {
+		t.Errorf("create: want 201, got %d", resp.StatusCode)
+		return ""
+	}
+	var out struct {
+		Reference string `json:"reference"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return out.Reference
+}
+
+var refPattern = regexp.MustCompile(`^LMP-\d{4}-(\d{6,})$`)
+
+// refNumber extracts the sequence number from a reference.
+func refNumber(t *testing.T, ref string) int64 {
+	t.Helper()
+	m := refPattern.FindStringSubmatch(ref)
+	if m == nil {
+		t.Fatalf("malformed reference %q", ref)
+	}
+	n, _ := strconv.ParseInt(m[1], 10, 64)
+	return n
+}
+
+// Concurrent creations get distinct references, numbered after earlier ones,
+// and the reference is stored and listed with the case.
+func Test_Create_ConcurrentReferences_DistinctAndMonotonic(t *testing.T) {
+	db := openTestDB(t)
+	clientID := uuid.New()
+	if err := db.Create(&models.User{ID: clientID, Email: "c_" + clientID.String()[:8] + "@x.com", Role: models.RoleClient}).Error; err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(NewHandler(db, nil), clientID, string(models.RoleClient))
+
+	first := refNumber(t, createCase(t, app, "Lease dispute"))
+
+	const n = 2
+	refs := make([]string, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			refs[i] = createCase(t, app, "Lease dispute "+strconv.Itoa(i))
+		}(i)
+	}
+	wg.Wait()
+
+	a, b := refNumber(t, refs[0]), refNumber(t, refs[1])
+	if a == b {
+		t.Fatalf("concurrent creations share reference %q", refs[0])
+	}
+	if a <= first || b <= first {
+		t.Fatalf("references should follow %d, got %d and %d", first, a, b)
+	}
+
+	var stored int64
+	db.Model(&models.Case{}).Where("reference IN ?", refs).Count(&stored)
+	if stored != n {
+		t.Fatalf("want %d stored references, got %d", n, stored)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/cases/mine", nil))
+	if err != nil || resp.StatusCode != 200 {
+		t.Fatalf("list mine: want 200, got %v (err=%v)", resp, err)
+	}
+	var page PageCases
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		t.Fatal(err)
+	}
+	for _, it := range page.Items {
+		if it.Reference == nil || !refPattern.MatchString(*it.Reference) {
+			t.Fatalf("list item %s missing reference", it.ID)
+		}
+	}
+}
+
+/* ============================================================================
+   Tests — bulk file delete
+   ============================================================================ */
+
+// addFile inserts a file row for a case.
+func addFile(t *testing.T, tx *gorm.DB, caseID uuid.UUID, name string) models.CaseFile {
+	t.Helper()
+	f := models.CaseFile{CaseID: caseID, Key: "case/" + caseID.String() + "/" + name, Mime: "application/pdf", Size: 1, OriginalName: name, CreatedAt: time.Now()}
+	if err := tx.Create(&f).Error; err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// Own files are deleted; a file from another case fails on its own and survives.
+func Test_DeleteFiles_PartialSuccessWithForeignID(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		mine := seedCase(t, tx, models.CaseOpen)
+		other := seedCase(t, tx, models.CaseOpen)
+		a := addFile(t, tx, mine.CaseID, "a.pdf")
+		b := addFile(t, tx, mine.CaseID, "b.pdf")
+		foreign := addFile(t, tx, other.CaseID, "x.pdf")
+
+		app := newTestApp(NewHandler(tx, nil), mine.ClientID, string(models.RoleClient))
+		body := `{"ids":["` + a.ID.String() + `","` + foreign.ID.String() + `","` + b.ID.String() + `"]}`
+		req := httptest.NewRequest("POST", "/api/cases/"+mine.CaseID.String()+"/files/delete", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil || resp.StatusCode != 200 {
+			t.Fatalf("want 200, got %v (err=%v)", resp, err)
+		}
+
+		var out struct {
+			Results []struct {
+				ID      string `json:"id"`
+				Deleted bool   `json:"deleted"`
+				Error   string `json:"error"`
+			} `json:"results"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatal(err)
+		}
+		if len(out.Results) != 3 {
+			t.Fatalf("want 3 results, got %+v", out.Results)
+		}
+		if !out.Results[0].Deleted || !out.Results[2].Deleted {
+			t.Fatalf("own files should be deleted: %+v", out.Results)
+		}
+		if out.Results[1].Deleted || out.Results[1].Error == "" {
+			t.Fatalf("foreign file should fail: %+v", out.Results[1])
+		}
+
+		var left []uuid.UUID
+		tx.Model(&models.CaseFile{}).Where("id IN ?", []uuid.UUID{a.ID, b.ID, foreign.ID}).Pluck("id", &left)
+		if len(left) != 1 || left[0] != foreign.ID {
+			t.Fatalf("only the foreign file should remain, got %v", left)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — per-case storage quota
+   ============================================================================ */
+
+// fakeStorage points the Supabase client at a stub that accepts every call.
+func fakeStorage(t *testing.T) *storage.Supabase {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("SUPABASE_URL", srv.URL)
+	t.Setenv("SUPABASE_BUCKET", "test")
+	return storage.NewSupabase()
+}
+
+// pdfBytes returns a structurally valid n-byte PDF stub (header, padding,
+// %%EOF trailer).
+func pdfBytes(n int) []byte {
+	head, tail := "%PDF-1.4\n", "\n%%EOF\n"
+	return []byte(head + strings.Repeat("x", max(n-len(head)-len(tail), 0)) + tail)
+}
+
+// multipartFiles builds a files[] upload with one PDF per size.
+func multipartFiles(t *testing.T, sizes ...int) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for i, n := range sizes {
+		fw, err := w.CreateFormFile("files[]", "doc"+strconv.Itoa(i)+".pdf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = fw.Write(pdfBytes(n))
+	}
+	_ = w.Close()
+	return &buf, w.FormDataContentType()
+}
+
+// Files that would push the case past its quota are rejected one by one
+// (with the remaining bytes) while the ones that fit are stored.
+func Test_Upload_PartiallyRejectedOverQuota(t *testing.T) {
+	t.Setenv("CASE_STORAGE_QUOTA_MB", "1")
+	const mb = 1024 * 1024
+	sb := fakeStorage(t)
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedCase(t, tx, models.CaseOpen)
+		existing := models.CaseFile{CaseID: s.CaseID, Key: "case/old.pdf", Mime: "application/pdf", Size: 600 * 1024, OriginalName: "old.pdf", CreatedAt: time.Now()}
+		if err := tx.Create(&existing).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		app := fiber.New(fiber.Config{BodyLimit: 4 * mb})
+		app.Use(injectAuth(s.ClientID, string(models.RoleClient)))
+		app.Post("/api/cases/:id/files", NewHandler(tx, sb).UploadFile)
+
+		body, ct := multipartFiles(t, 300*1024, 300*1024)
+		req := httptest.NewRequest("POST", "/api/cases/"+s.CaseID.String()+"/files", body)
+		req.Header.Set("Content-Type", ct)
+		resp, err := app.Test(req, -1)
+		if err != nil || resp.StatusCode != 201 {
+			t.Fatalf("want 201, got %v (err=%v)", resp, err)
+		}
+
+		var out struct {
+			Results []struct {
+				ID             string `json:"id"`
+				Error          string `json:"error"`
+				RemainingBytes *int64 `json:"remaining_bytes"`
+			} `json:"results"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatal(err)
+		}
+		if len(out.Results) != 2 {
+			t.Fatalf("want 2 results, got %+v", out.Results)
+		}
+		if out.Results[0].ID == "" || out.Results[0].Error != "" {
+			t.Fatalf("first file fits and should be stored: %+v", out.Results[0])
+		}
+		r := out.Results[1]
+		if r.ID != "" || !strings.Contains(r.Error, "quota") || r.RemainingBytes == nil || *r.RemainingBytes != mb-900*1024 {
+			t.Fatalf("second file should be rejected with remaining bytes: %+v", r)
+		}
+
+		var count int64
+		tx.Model(&models.CaseFile{}).Where("case_id = ?", s.CaseID).Count(&count)
+		if count != 2 {
+			t.Fatalf("want 2 stored files (old + first), got %d", count)
+		}
+	})
+}
+
+// Per-file descriptions are stored on upload and returned in the case detail;
+// files uploaded without one keep an empty description.
+func Test_Upload_DescriptionRoundTrip(t *testing.T) {
+	sb := fakeStorage(t)
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedCase(t, tx, models.CaseOpen)
+		app := newTestApp(NewHandler(tx, sb), s.ClientID, string(models.RoleClient))
+
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		for i, desc := range []string{"  signed contract ", ""} {
+			fw, err := w.CreateFormFile("files[]", "doc"+strconv.Itoa(i)+".pdf")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = fw.Write(pdfBytes(64))
+			_ = w.WriteField("descriptions[]", desc)
+		}
+		_ = w.Close()
+
+		req := httptest.NewRequest("POST", "/api/cases/"+s.CaseID.String()+"/files", &buf)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		resp, err := app.Test(req, -1)
+		if err != nil || resp.StatusCode != 201 {
+			t.Fatalf("upload: want 201, got %v (err=%v)", resp, err)
+		}
+
+		resp, _ = app.Test(httptest.NewRequest("GET", "/api/cases/"+s.CaseID.String(), nil))
+		var detail struct {
+			Files []struct{ Description string }
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]bool{}
+		for _, f := range detail.Files {
+			got[f.Description] = true
+		}
+		if len(detail.Files) != 2 || !got["signed contract"] || !got[""] {
+			t.Fatalf("want one trimmed description and one empty, got %+v", detail.Files)
+		}
+
+		// Too long → whole request rejected before anything is stored
+		buf.Reset()
+		w = multipart.NewWriter(&buf)
+		fw, _ := w.CreateFormFile("files[]", "long.pdf")
+		_, _ = fw.Write(pdfBytes(64))
+		_ = w.WriteField("description", strings.Repeat("x", maxFileDescriptionLen+1))
+		_ = w.Close()
+		req = httptest.NewRequest("POST", "/api/cases/"+s.CaseID.String()+"/files", &buf)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		if resp, _ := app.Test(req, -1); resp.StatusCode != 400 {
+			t.Fatalf("long description: want 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+// The accepted lawyer can attach files to an engaged case; any other lawyer
+// is denied like on the case detail, and nothing is stored.
+func Test_Upload_AcceptedLawyerOnEngagedCase(t *testing.T) {
+	sb := fakeStorage(t)
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedEngagedWithFile(t, tx)
+		upload := func(userID uuid.UUID) int {
+			body, ct := multipartFiles(t, 64)
+			req := httptest.NewRequest("POST", "/api/cases/"+s.CaseID.String()+"/files", body)
+			req.Header.Set("Content-Type", ct)
+			app := newTestApp(NewHandler(tx, sb), userID, string(models.RoleLawyer))
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return resp.StatusCode
+		}
+
+		if code := upload(s.LawyerID); code != 201 {
+			t.Fatalf("accepted lawyer: want 201, got %d", code)
+		}
+		if code := upload(uuid.New()); code != 404 {
+			t.Fatalf("other lawyer: want 404, got %d", code)
+		}
+
+		var count int64
+		tx.Model(&models.CaseFile{}).Where("case_id = ?", s.CaseID).Count(&count)
+		if count != 2 {
+			t.Fatalf("want the seeded file plus the lawyer's upload, got %d", count)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — file listing
+   ============================================================================ */
+
+// Owner pages through masked file metadata; an unrelated client gets 403.
+func Test_ListFiles_OwnerPaginated_StrangerForbidden(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedCase(t, tx, models.CaseOpen)
+		first := addFile(t, tx, s.CaseID, "contract.pdf")
+		tx.Model(&models.CaseFile{}).Where("id = ?", first.ID).Update("created_at", time.Now().Add(-time.Hour))
+		_ = addFile(t, tx, s.CaseID, "id-scan.pdf")
+		_ = addFile(t, tx, s.CaseID, "letter.pdf")
+
+		app := newTestApp(NewHandler(tx, nil), s.ClientID, string(models.RoleClient))
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/cases/"+s.CaseID.String()+"/files?pageSize=2", nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("owner: want 200, got %d", resp.StatusCode)
+		}
+		var page PageCaseFiles
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 3 || page.Pages != 2 || len(page.Items) != 2 {
+			t.Fatalf("unexpected page: %+v", page)
+		}
+		if page.Items[0].ID != first.ID || page.Items[0].Name != maskFileName("contract.pdf") {
+			t.Fatalf("want oldest file first with masked name, got %+v", page.Items[0])
+		}
+
+		stranger := newTestApp(NewHandler(tx, nil), uuid.New(), string(models.RoleClient))
+		resp, _ = stranger.Test(httptest.NewRequest("GET", "/api/cases/"+s.CaseID.String()+"/files", nil))
+		if resp.StatusCode != 403 {
+			t.Fatalf("stranger: want 403, got %d", resp.StatusCode)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — updated_at
+   ============================================================================ */
+
+// A status change bumps updated_at, and sort=updated_desc lists the most
+// recently active case first.
+func Test_UpdatedAt_BumpedByStatusChange_AndSortable(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		clientID := uuid.New()
+		_ = tx.Create(&models.User{ID: clientID, Email: "c_" + clientID.String()[:8] + "@x.com", Role: models.RoleClient}).Error
+		older := makeCase(t, tx, clientID, time.Now().Add(-2*time.Hour))
+		newer := makeCase(t, tx, clientID, time.Now().Add(-time.Hour))
+		// Pretend neither changed since creation (UpdateColumn skips auto-update)
+		for _, id := range []uuid.UUID{older, newer} {
+			tx.Model(&models.Case{}).Where("id = ?", id).UpdateColumn("updated_at", gorm.Expr("created_at"))
+		}
+
+		app := newTestApp(NewHandler(tx, nil), clientID, string(models.RoleClient))
+		resp, _ := app.Test(httptest.NewRequest("POST", "/api/cases/"+older.String()+"/cancel", nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("cancel: want 200, got %d", resp.StatusCode)
+		}
+		var cs models.Case
+		if err := tx.First(&cs, "id = ?", older).Error; err != nil {
+			t.Fatal(err)
+		}
+		if time.Since(cs.UpdatedAt) > time.Minute {
+			t.Fatalf("cancel should bump updated_at, got %v", cs.UpdatedAt)
+		}
+
+		first := func(query string) string {
+			resp, _ := app.Test(httptest.NewRequest("GET", "/api/cases/mine"+query, nil))
+			if resp.StatusCode != 200 {
+				t.Fatalf("%s: got %d", query, resp.StatusCode)
+			}
+			var out PageCases
+			_ = json.NewDecoder(resp.Body).Decode(&out)
+			if len(out.Items) != 2 || out.Items[0].UpdatedAt == "" {
+				t.Fatalf("%s: unexpected items %+v", query, out.Items)
+			}
+			return out.Items[0].ID
+		}
+		if got := first(""); got != newer.String() {
+			t.Fatalf("default sort: want newest-created first, got %s", got)
+		}
+		if got := first("?sort=updated_desc"); got != older.String() {
+			t.Fatalf("updated_desc: want recently cancelled case first, got %s", got)
+		}
+
+		resp, _ = app.Test(httptest.NewRequest("GET", "/api/cases/mine?sort=bogus", nil))
+		if resp.StatusCode != 400 {
+			t.Fatalf("bad sort: want 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — duplicate case guard
+   ============================================================================ */
+
+// A rapid identical resubmission is rejected; once the window has passed
+// (or with allow_duplicate) the same case can be created again.
+func Test_Create_RapidDuplicateRejected_LaterAllowed(t *testing.T) {
+	t.Setenv("CASE_DUPLICATE_WINDOW", "5m")
+	db := openTestDB(t)
+	clientID := uuid.New()
+	if err := db.Create(&models.User{ID: clientID, Email: "c_" + clientID.String()[:8] + "@x.com", Role: models.RoleClient}).Error; err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(NewHandler(db, nil), clientID, string(models.RoleClient))
+
+	if resp := postCase(t, app, "Unpaid wages"); resp.StatusCode != 201 {
+		t.Fatalf("first: want 201, got %d", resp.StatusCode)
+	}
+	if resp := postCase(t, app, "unpaid wages"); resp.StatusCode != 409 {
+		t.Fatalf("duplicate: want 409, got %d", resp.StatusCode)
+	}
+
+	// Explicit opt-out
+	req := httptest.NewRequest("POST", "/api/cases",
+		strings.NewReader(`{"title":"Unpaid wages","category":"Property","allow_duplicate":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	if resp, _ := app.Test(req); resp.StatusCode != 201 {
+		t.Fatalf("allow_duplicate: want 201, got %d", resp.StatusCode)
+	}
+
+	// Outside the window
+	db.Model(&models.Case{}).Where("client_id = ?", clientID).
+		UpdateColumn("created_at", time.Now().Add(-10*time.Minute))
+	if resp := postCase(t, app, "Unpaid wages"); resp.StatusCode != 201 {
+		t.Fatalf("after window: want 201, got %d", resp.StatusCode)
+	}
+}
+
+/* ============================================================================
+   Tests — HTML stripping
+   ============================================================================ */
+
+// Markup in title/description is stripped before storing; the text and line
+// breaks survive, and a title that was only markup fails validation.
+func Test_Create_StripsHTML(t *testing.T) {
+	db := openTestDB(t)
+	clientID := uuid.New()
+	if err := db.Create(&models.User{ID: clientID, Email: "c_" + clientID.String()[:8] + "@x.com", Role: models.RoleClient}).Error; err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(NewHandler(db, nil), clientID, string(models.RoleClient))
+
+	post := func(body string) *http.Response {
+		req := httptest.NewRequest("POST", "/api/cases", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := post(`{"title":"<b>Lease</b> dispute<script>alert(1)</script>","category":"Property",` +
+		`"description":"Landlord kept deposit.\nSee <a href=\"javascript:x()\">notes</a><br>Thanks"}`)
+	if resp.StatusCode != 201 {
+		t.Fatalf("create: want 201, got %d", resp.StatusCode)
+	}
+	var out struct {
+		ID uuid.UUID `json:"id"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+
+	var cs models.Case
+	if err := db.First(&cs, "id = ?", out.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if cs.Title != "Lease dispute" {
+		t.Fatalf("title: got %q", cs.Title)
+	}
+	if cs.Description != "Landlord kept deposit.\nSee notes\nThanks" {
+		t.Fatalf("description: got %q", cs.Description)
+	}
+
+	if resp := post(`{"title":"<script>alert(1)</script>","category":"Property"}`); resp.StatusCode != 400 {
+		t.Fatalf("markup-only title: want 400, got %d", resp.StatusCode)
+	}
+}
+
+/* ============================================================================
+   Tests — replace file
+   ============================================================================ */
+
+// recordingStorage is fakeStorage that also logs "METHOD path" per call.
+func recordingStorage(t *testing.T) (*storage.Supabase, func() []string) {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("SUPABASE_URL", srv.URL)
+	t.Setenv("SUPABASE_BUCKET", "test")
+	return storage.NewSupabase(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), calls...)
+	}
+}
+
+// Replacing keeps the file id, swaps key/name/type/size, uploads the new
+// object before deleting the old one, and validates like uploads.
+func Test_ReplaceFile_KeepsIDDeletesOldObject(t *testing.T) {
+	sb, calls := recordingStorage(t)
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedCase(t, tx, models.CaseOpen)
+		old := models.CaseFile{CaseID: s.CaseID, Key: "case/" + s.CaseID.String() + "/v1.pdf", Mime: "application/pdf", Size: 64, OriginalName: "v1.pdf", CreatedAt: time.Now()}
+		if err := tx.Create(&old).Error; err != nil {
+			t.Fatal(err)
+		}
+		app := newTestApp(NewHandler(tx, sb), s.ClientID, string(models.RoleClient))
+
+		replace := func(name string, data []byte) *http.Response {
+			var buf bytes.Buffer
+			w := multipart.NewWriter(&buf)
+			fw, _ := w.CreateFormFile("file", name)
+			_, _ = fw.Write(data)
+			_ = w.Close()
+			req := httptest.NewRequest("PUT", "/api/files/"+old.ID.String(), &buf)
+			req.Header.Set("Content-Type", w.FormDataContentType())
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return resp
+		}
+
+		png := pngBytes(t)
+		if resp := replace("v2.png", png[:len(png)/2]); resp.StatusCode != 400 {
+			t.Fatalf("corrupt replacement: want 400, got %d", resp.StatusCode)
+		}
+		if resp := replace("v2.pdf", pdfBytes(200)); resp.StatusCode != 200 {
+			t.Fatalf("replace: want 200, got %d", resp.StatusCode)
+		}
+
+		var got models.CaseFile
+		if err := tx.First(&got, "id = ?", old.ID).Error; err != nil {
+			t.Fatalf("file id must survive: %v", err)
+		}
+		if got.OriginalName != "v2.pdf" || got.Size != 200 || got.Mime != "application/pdf" || got.Key == old.Key {
+			t.Fatalf("record not updated: %+v", got)
+		}
+		var n int64
+		tx.Model(&models.CaseFile{}).Where("case_id = ?", s.CaseID).Count(&n)
+		if n != 1 {
+			t.Fatalf("want 1 file row, got %d", n)
+		}
+
+		want := []string{"POST /storage/v1/object/test/" + got.Key, "DELETE /storage/v1/object/test/" + old.Key}
+		if c := calls(); len(c) != 2 || c[0] != want[0] || c[1] != want[1] {
+			t.Fatalf("storage calls: want %v, got %v", want, c)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — client dashboard stats
+   ============================================================================ */
+
+// Counts cover every status (cancelled included, deleted excluded), quotes on
+// live cases, spend per currency, and distinct lawyers ever engaged.
+func Test_Stats_ClientTotals(t *testing.T) {
+	t.Setenv("STRIPE_CURRENCY", "sgd")
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		clientID := uuid.New()
+		_ = tx.Create(&models.User{ID: clientID, Email: "c_" + clientID.String()[:8] + "@x.com", Role: models.RoleClient}).Error
+		lawyerA, lawyerB := uuid.New(), uuid.New()
+
+		// caseWith makes a case in the given status, engaged with lawyer (if
+		// any) and paid for in cur
+		caseWith := func(status models.CaseStatus, lawyer uuid.UUID, cents int, cur string) uuid.UUID {
+			id := makeCase(t, tx, clientID, time.Now())
+			tx.Model(&models.Case{}).Where("id = ?", id).Update("status", status)
+			if lawyer == uuid.Nil {
+				return id
+			}
+			q := addQuote(t, tx, id, lawyer, "won")
+			tx.Model(&models.Case{}).Where("id = ?", id).
+				Updates(map[string]any{"accepted_quote_id": q.ID, "accepted_lawyer_id": lawyer})
+			if err := tx.Create(&models.Payment{
+				CaseID: id, QuoteID: q.ID, ClientID: clientID, AmountCents: cents,
+				Currency: cur, Status: models.PayPaid, CreatedAt: time.Now(),
+			}).Error; err != nil {
+				t.Fatal(err)
+			}
+			return id
+		}
+
+		open := caseWith(models.CaseOpen, uuid.Nil, 0, "")
+		_ = caseWith(models.CaseOpen, uuid.Nil, 0, "")
+		_ = caseWith(models.CaseEngaged, lawyerA, 5000, "SGD")
+		_ = caseWith(models.CaseClosed, lawyerA, 1000, "")      // legacy currency → SGD
+		_ = caseWith(models.CaseCancelled, lawyerB, 300, "EUR") // cancelled after engagement
+		_ = caseWith(models.CaseCancelled, uuid.Nil, 0, "")     // never engaged
+		addQuote(t, tx, open, uuid.New(), "a")                  // proposed
+		q := addQuote(t, tx, open, uuid.New(), "b")             // rejected/expired still counts
+		tx.Model(&q).Update("status", models.QuoteRejected)
+
+		deleted := caseWith(models.CaseOpen, uuid.Nil, 0, "")
+		addQuote(t, tx, deleted, uuid.New(), "gone")
+		tx.Delete(&models.Case{ID: deleted})
+
+		other := uuid.New()
+		_ = tx.Create(&models.User{ID: other, Email: "o_" + other.String()[:8] + "@x.com", Role: models.RoleClient}).Error
+		addQuote(t, tx, makeCase(t, tx, other, time.Now()), uuid.New(), "not mine")
+
+		app := fiber.New()
+		app.Use(injectAuth(clientID, string(models.RoleClient)))
+		app.Get("/api/clients/me/stats", NewHandler(tx, nil).Stats)
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/clients/me/stats", nil))
+		if err != nil || resp.StatusCode != 200 {
+			t.Fatalf("want 200, got %v (err=%v)", resp, err)
+		}
+		var got ClientStats
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Open != 2 || got.Engaged != 1 || got.Closed != 1 || got.Cancelled != 2 {
+			t.Fatalf("cases: want open=2 engaged=1 closed=1 cancelled=2, got %+v", got)
+		}
+		if got.QuotesReceived != 5 { // 3 winning quotes + 2 on the open case
+			t.Fatalf("quotes received: want 5, got %d", got.QuotesReceived)
+		}
+		if len(got.SpentCents) != 2 || got.SpentCents["SGD"] != 6000 || got.SpentCents["EUR"] != 300 {
+			t.Fatalf("spent: want SGD 6000 + EUR 300, got %v", got.SpentCents)
+		}
+		if got.LawyersEngaged != 2 {
+			t.Fatalf("lawyers engaged: want 2, got %d", got.LawyersEngaged)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — upload integrity
+   ============================================================================ */
+
+// pngBytes encodes a small valid PNG.
+func pngBytes(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// Valid files pass; truncated ones (or a type mismatch) don't.
+func Test_FileIntact(t *testing.T) {
+	validPNG := pngBytes(t)
+	validPDF := pdfBytes(4096)
+	cases := []struct {
+		name string
+		data []byte
+		ct   string
+		want bool
+	}{
+		{"valid png", validPNG, "image/png", true},
+		{"truncated png", validPNG[:len(validPNG)-6], "image/png", false},
+		{"png header only", validPNG[:10], "image/png", false},
+		{"valid pdf", validPDF, "application/pdf", true},
+		{"pdf with trailing junk", append(append([]byte{}, validPDF...), "\n\n  "...), "application/pdf", true},
+		{"truncated pdf", validPDF[:2048], "application/pdf", false},
+		{"not a pdf", []byte("hello %%EOF"), "application/pdf", false},
+		{"png labelled pdf", validPNG, "application/pdf", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := fileIntact(bytes.NewReader(tc.data), int64(len(tc.data)), tc.ct); got != tc.want {
+				t.Fatalf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+// A truncated upload gets a per-file error while its valid sibling is stored.
+func Test_Upload_RejectsCorruptFile(t *testing.T) {
+	sb := fakeStorage(t)
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedCase(t, tx, models.CaseOpen)
+		app := newTestApp(NewHandler(tx, sb), s.ClientID, string(models.RoleClient))
+
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		valid := pngBytes(t)
+		for name, data := range map[string][]byte{"ok.png": valid, "broken.png": valid[:len(valid)/2]} {
+			fw, _ := w.CreateFormFile("files[]", name)
+			_, _ = fw.Write(data)
+		}
+		_ = w.Close()
+
+		req := httptest.NewRequest("POST", "/api/cases/"+s.CaseID.String()+"/files", &buf)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		resp, err := app.Test(req, -1)
+		if err != nil || resp.StatusCode != 201 {
+			t.Fatalf("want 201, got %v (err=%v)", resp, err)
+		}
+		var out struct {
+			Results []struct {
+				Name  string `json:"name"`
+				ID    string `json:"id"`
+				Error string `json:"error"`
+			} `json:"results"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		for _, r := range out.Results {
+			switch r.Name {
+			case "ok.png":
+				if r.ID == "" || r.Error != "" {
+					t.Fatalf("valid png should be stored: %+v", r)
+				}
+			case "broken.png":
+				if r.ID != "" || r.Error != "Corrupt or unreadable file" {
+					t.Fatalf("truncated png should be rejected: %+v", r)
+				}
+			}
+		}
+		var n int64
+		tx.Model(&models.CaseFile{}).Where("case_id = ?", s.CaseID).Count(&n)
+		if n != 1 {
+			t.Fatalf("want 1 stored file, got %d", n)
+		}
+	})
+}
+
+// Upload failures are told apart by code: a body that isn't multipart, a
+// form without files under an accepted key, and a batch where every file is
+// rejected (400 with the per-file results instead of a 201).
+func Test_Upload_MalformedRequestsHaveDistinctCodes(t *testing.T) {
+	sb := fakeStorage(t)
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedCase(t, tx, models.CaseOpen)
+		app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+		app.Use(injectAuth(s.ClientID, string(models.RoleClient)))
+		app.Post("/api/cases/:id/files", NewHandler(tx, sb).UploadFile)
+
+		post := func(body io.Reader, ct string) (int, models.ErrorResponse, []map[string]any) {
+			t.Helper()
+			req := httptest.NewRequest("POST", "/api/cases/"+s.CaseID.String()+"/files", body)
+			req.Header.Set("Content-Type", ct)
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out struct {
+				models.ErrorResponse
+				Results []map[string]any `json:"results"`
+			}
+			_ = json.NewDecoder(resp.Body).Decode(&out)
+			return resp.StatusCode, out.ErrorResponse, out.Results
+		}
+
+		// Not multipart at all
+		code, e, _ := post(strings.NewReader(`{"files":[]}`), "application/json")
+		if code != 400 || e.Code != apperr.MultipartRequired || !strings.Contains(e.Message, "files[]") {
+			t.Fatalf("json body: want 400 %s naming the keys, got %d %+v", apperr.MultipartRequired, code, e)
+		}
+
+		// Multipart, but the file sits under an unknown key
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		fw, _ := w.CreateFormFile("upload", "doc.pdf")
+		_, _ = fw.Write(pdfBytes(64))
+		_ = w.Close()
+		code, e, _ = post(&buf, w.FormDataContentType())
+		if code != 400 || e.Code != apperr.NoFilesProvided || !strings.Contains(e.Message, "files[]") {
+			t.Fatalf("wrong key: want 400 %s naming the keys, got %d %+v", apperr.NoFilesProvided, code, e)
+		}
+
+		// Every file fails validation
+		buf.Reset()
+		w = multipart.NewWriter(&buf)
+		for _, name := range []string{"notes.txt", "broken.png"} {
+			fw, _ := w.CreateFormFile("files[]", name)
+			_, _ = fw.Write([]byte("not a real file"))
+		}
+		_ = w.Close()
+		code, e, results := post(&buf, w.FormDataContentType())
+		if code != 400 || e.Code != apperr.NoFilesStored || len(results) != 2 {
+			t.Fatalf("all invalid: want 400 %s with 2 results, got %d %+v %v", apperr.NoFilesStored, code, e, results)
+		}
+		for _, r := range results {
+			if r["error"] == nil || r["id"] != nil {
+				t.Fatalf("each result should carry its error: %v", r)
+			}
+		}
+
+		var n int64
+		tx.Model(&models.CaseFile{}).Where("case_id = ?", s.CaseID).Count(&n)
+		if n != 0 {
+			t.Fatalf("nothing should be stored, got %d files", n)
+		}
+	})
+}
+
+// A single file of a disallowed type is a 415 in the usual error shape, on
+// upload and on replace; nothing reaches storage.
+func Test_Upload_SingleDisallowedFileIs415(t *testing.T) {
+	sb := fakeStorage(t)
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		s := seedCase(t, tx, models.CaseOpen)
+		existing := models.CaseFile{
+			CaseID: s.CaseID, Key: "case/" + s.CaseID.String() + "/a.pdf", Mime: "application/pdf",
+			Size: 64, OriginalName: "a.pdf", CreatedAt: time.Now(),
+		}
+		if err := tx.Create(&existing).Error; err != nil {
+			t.Fatal(err)
+		}
+		h := NewHandler(tx, sb)
+		app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+		app.Use(injectAuth(s.ClientID, string(models.RoleClient)))
+		app.Post("/api/cases/:id/files", h.UploadFile)
+		app.Put("/api/files/:fileID", h.ReplaceFile)
+
+		send := func(method, path, key string) (int, models.ErrorResponse) {
+			t.Helper()
+			var buf bytes.Buffer
+			w := multipart.NewWriter(&buf)
+			fw, _ := w.CreateFormFile(key, "notes.txt")
+			_, _ = fw.Write([]byte("plain text"))
+			_ = w.Close()
+			req := httptest.NewRequest(method, path, &buf)
+			req.Header.Set("Content-Type", w.FormDataContentType())
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var e models.ErrorResponse
+			_ = json.NewDecoder(resp.Body).Decode(&e)
+			return resp.StatusCode, e
+		}
+
+		code, e := send("POST", "/api/cases/"+s.CaseID.String()+"/files", "files[]")
+		if code != 415 || e.Code != "UNSUPPORTED_MEDIA_TYPE" || !e.Error || e.Message == "" {
+			t.Fatalf("upload: want 415 UNSUPPORTED_MEDIA_TYPE, got %d %+v", code, e)
+		}
+		code, e = send("PUT", "/api/files/"+existing.ID.String(), "file")
+		if code != 415 || e.Code != "UNSUPPORTED_MEDIA_TYPE" {
+			t.Fatalf("replace: want 415 UNSUPPORTED_MEDIA_TYPE, got %d %+v", code, e)
+		}
+
+		var n int64
+		tx.Model(&models.CaseFile{}).Where("case_id = ?", s.CaseID).Count(&n)
+		if n != 1 {
+			t.Fatalf("nothing new should be stored, got %d files", n)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — deadline and urgency
+   ============================================================================ */
+
+// Deadline and urgency given at creation come back on the case detail and
+// the marketplace item; a past deadline or unknown urgency is a 400.
+func Test_Create_DeadlineUrgencyRoundTrip(t *testing.T) {
+	db := openTestDB(t)
+	clientID, lawyerID := uuid.New(), uuid.New()
+	for id, role := range map[uuid.UUID]models.Role{clientID: models.RoleClient, lawyerID: models.RoleLawyer} {
+		if err := db.Create(&models.User{ID: id, Email: "u_" + id.String()[:8] + "@x.com", Role: role}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	app := newTestApp(NewHandler(db, nil), clientID, string(models.RoleClient))
+	post := func(body string) *http.Response {
+		req := httptest.NewRequest("POST", "/api/cases", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	deadline := time.Now().In(apptz.Location()).AddDate(0, 0, 14).Format("2006-01-02")
+	resp := post(`{"title":"Visa appeal","category":"Immigration","deadline":"` + deadline + `","urgency":"high"}`)
+	if resp.StatusCode != 201 {
+		t.Fatalf("create: want 201, got %d", resp.StatusCode)
+	}
+	var created struct {
+		ID uuid.UUID `json:"id"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&created)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/api/cases/"+created.ID.String(), nil))
+	var detail struct {
+		Deadline *time.Time
+		Urgency  *models.Urgency
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&detail)
+	if detail.Deadline == nil || detail.Deadline.Format("2006-01-02") != deadline ||
+		detail.Urgency == nil || *detail.Urgency != models.UrgencyHigh {
+		t.Fatalf("detail: want %s/high, got %v/%v", deadline, detail.Deadline, detail.Urgency)
+	}
+
+	lawyer := newTestApp(NewHandler(db, nil), lawyerID, string(models.RoleLawyer))
+	resp, _ = lawyer.Test(httptest.NewRequest("GET", "/api/marketplace", nil))
+	var page PageMarketCases
+	_ = json.NewDecoder(resp.Body).Decode(&page)
+	if len(page.Items) != 1 || page.Items[0].Deadline == nil || page.Items[0].Deadline.Format("2006-01-02") != deadline ||
+		page.Items[0].Urgency == nil || *page.Items[0].Urgency != models.UrgencyHigh {
+		t.Fatalf("marketplace: want the case with %s/high, got %+v", deadline, page.Items)
+	}
+
+	yesterday := time.Now().In(apptz.Location()).AddDate(0, 0, -1).Format("2006-01-02")
+	for _, body := range []string{
+		`{"title":"Late","category":"Immigration","deadline":"` + yesterday + `"}`,
+		`{"title":"Bad date","category":"Immigration","deadline":"14/02/2030"}`,
+		`{"title":"Bad urgency","category":"Immigration","urgency":"asap"}`,
+	} {
+		if resp := post(body); resp.StatusCode != 400 {
+			t.Fatalf("%s: want 400, got %d", body, resp.StatusCode)
+		}
+	}
+}
+
+// sort=urgency puts high before normal before low, cases without an urgency
+// last, and breaks ties by the nearest deadline.
+func Test_Marketplace_SortByUrgency(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		lawyer := uuid.New()
+		_ = tx.Create(&models.User{ID: lawyer, Email: "l_" + lawyer.String()[:6] + "@t", Role: models.RoleLawyer}).Error
+
+		urgency := func(u models.Urgency) *models.Urgency { return &u }
+		date := func(days int) *time.Time {
+			d := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, days)
+			return &d
+		}
+		seed := []struct {
+			title    string
+			urgency  *models.Urgency
+			deadline *time.Time
+		}{
+			{"none", nil, nil},
+			{"low", urgency(models.UrgencyLow), nil},
+			{"high-later", urgency(models.UrgencyHigh), date(30)},
+			{"normal", urgency(models.UrgencyNormal), date(1)},
+			{"high-soon", urgency(models.UrgencyHigh), date(5)},
+		}
+		for i, s := range seed {
+			cs := models.Case{
+				ClientID: uuid.New(), Title: s.title, Category: "Cat", Status: models.CaseOpen,
+				Urgency: s.urgency, Deadline: s.deadline, CreatedAt: time.Now().Add(time.Duration(i) * time.Minute),
+			}
+			if err := tx.Create(&cs).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		app := newTestApp(NewHandler(tx, nil), lawyer, string(models.RoleLawyer))
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/marketplace?sort=urgency", nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("got %d", resp.StatusCode)
+		}
+		var page PageMarketCases
+		_ = json.NewDecoder(resp.Body).Decode(&page)
+		var got []string
+		for _, it := range page.Items {
+			got = append(got, it.Title)
+		}
+		if want := "high-soon,high-later,normal,low,none"; strings.Join(got, ",") != want {
+			t.Fatalf("want %s, got %s", want, strings.Join(got, ","))
+		}
+
+		resp, _ = app.Test(httptest.NewRequest("GET", "/api/marketplace?sort=bogus", nil))
+		if resp.StatusCode != 400 {
+			t.Fatalf("unknown sort: want 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — saved cases
+   ============================================================================ */
+
+// A lawyer saves open cases (repeats are no-ops) and lists them anonymized;
+// a saved case drops out once engaged, and paused cases can't be saved.
+func Test_SavedCases_SaveListAndDropOnEngage(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		lawyer := uuid.New()
+		_ = tx.Create(&models.User{ID: lawyer, Email: "l_" + lawyer.String()[:6] + "@t", Role: models.RoleLawyer}).Error
+
+		now := time.Now()
+		first := seedOpenCase(t, tx, "call me at test@example.com", now.Add(-2*time.Minute))
+		second := seedOpenCase(t, tx, "second", now.Add(-time.Minute))
+		paused := seedOpenCase(t, tx, "paused", now)
+		tx.Model(&models.Case{}).Where("id = ?", paused).Update("status", models.CasePaused)
+
+		app := newTestApp(NewHandler(tx, nil), lawyer, string(models.RoleLawyer))
+		save := func(id uuid.UUID) int {
+			resp, _ := app.Test(httptest.NewRequest("POST", "/api/marketplace/"+id.String()+"/save", nil))
+			return resp.StatusCode
+		}
+		saved := func() PageMarketCases {
+			t.Helper()
+			resp, _ := app.Test(httptest.NewRequest("GET", "/api/marketplace/saved", nil))
+			if resp.StatusCode != 200 {
+				t.Fatalf("list saved: got %d", resp.StatusCode)
+			}
+			var out PageMarketCases
+			_ = json.NewDecoder(resp.Body).Decode(&out)
+			return out
+		}
+
+		for _, id := range []uuid.UUID{first, second, first} {
+			if code := save(id); code != 200 {
+				t.Fatalf("save %s: want 200, got %d", id, code)
+			}
+		}
+		if code := save(paused); code != 409 {
+			t.Fatalf("save paused: want 409, got %d", code)
+		}
+
+		out := saved()
+		if out.Total != 2 || len(out.Items) != 2 {
+			t.Fatalf("want 2 saved, got total=%d items=%d", out.Total, len(out.Items))
+		}
+		for _, it := range out.Items {
+			if strings.Contains(it.Preview, "test@example.com") {
+				t.Fatalf("preview should be redacted, got %q", it.Preview)
+			}
+		}
+
+		tx.Model(&models.Case{}).Where("id = ?", first).Update("status", models.CaseEngaged)
+		if out := saved(); out.Total != 1 || len(out.Items) != 1 || out.Items[0].ID != second {
+			t.Fatalf("after engage: want only the second case, got %+v", out.Items)
+		}
+
+		resp, _ := app.Test(httptest.NewRequest("DELETE", "/api/marketplace/"+second.String()+"/save", nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("unsave: want 200, got %d", resp.StatusCode)
+		}
+		if out := saved(); out.Total != 0 {
+			t.Fatalf("after unsave: want none, got %d", out.Total)
 		}
 	})
 }
@@ -0,0 +1,207 @@
+package cases
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apitime"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/validation"
+)
+
+// Request body for POST /cases/:id/collaborators
+type GrantCollaboratorRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// CollaboratorItem is one user with read access to an engaged case.
+type CollaboratorItem struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	GrantedAt time.Time `json:"granted_at"`
+}
+
+// lawyerCanRead reports whether a lawyer may read an engaged (or closed) case.
+func (h *Handler) lawyerCanRead(cs models.Case, userID string) bool {
+	return LawyerCanRead(h.db, cs, userID)
+}
+
+// LawyerCanRead reports whether a lawyer may read an engaged (or closed)
+// case: the accepted lawyer, or a collaborator they granted access to.
+// Grants from a previous accepted lawyer (before a reassign) don't count.
+// cs needs ID, Status and AcceptedLawyerID loaded.
+func LawyerCanRead(db *gorm.DB, cs models.Case, userID string) bool {
+	if cs.Status != models.CaseEngaged && cs.Status != models.CaseClosed {
+		return false
+	}
+	if cs.AcceptedLawyerID.String() == userID {
+		return true
+	}
+	var n int64
+	if err := db.Model(&models.CaseCollaborator{}).
+		Where("case_id = ? AND user_id = ? AND granted_by = ?", cs.ID, userID, cs.AcceptedLawyerID).
+		Count(&n).Error; err != nil {
+		return false
+	}
+	return n > 0
+}
+
+// acceptedLawyerCase loads the case for a collaborator endpoint; only its
+// accepted lawyer gets past this.
+func (h *Handler) acceptedLawyerCase(c *fiber.Ctx) (models.Case, error) {
+	var cs models.Case
+	caseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return cs, fiber.NewError(fiber.StatusBadRequest, "invalid case id")
+	}
+	if err := h.db.Select("id, status, accepted_lawyer_id").First(&cs, "id = ?", caseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return cs, fiber.ErrNotFound
+		}
+		return cs, fiber.ErrInternalServerError
+	}
+	if cs.AcceptedLawyerID.String() != auth.MustUserID(c) {
+		return cs, LawyerDenied()
+	}
+	return cs, nil
+}
+
+/* ============================ Collaborators ============================== */
+
+// @Summary      Case collaborators
+// @Description  Accepted lawyer lists who they granted read access to (case detail, file list and signed URLs)
+// @Tags         cases
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "case id (uuid)"
+// @Success      200  {array}   CollaboratorItem
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Router       /cases/{id}/collaborators [get]
+func (h *Handler) ListCollaborators(c *fiber.Ctx) error {
+	cs, err := h.acceptedLawyerCase(c)
+	if err != nil {
+		return err
+	}
+
+	out := make([]CollaboratorItem, 0)
+	if err := h.db.Table("case_collaborators").
+		Select("users.id AS user_id, users.name, users.email, case_collaborators.created_at AS granted_at").
+		Joins("JOIN users ON users.id = case_collaborators.user_id").
+		Where("case_collaborators.case_id = ? AND case_collaborators.granted_by = ?", cs.ID, cs.AcceptedLawyerID).
+		Order("case_collaborators.created_at ASC").
+		Scan(&out).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	for i := range out {
+		out[i].GrantedAt = apitime.Normalize(out[i].GrantedAt)
+	}
+	return c.JSON(out)
+}
+
+// @Summary      Grant case access
+// @Description  Accepted lawyer gives another lawyer account (e.g. a paralegal) read access to this ENGAGED case: detail, file list and signed download URLs. Scoped to this case only; granting twice is a no-op.
+// @Tags         cases
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string                    true  "case id (uuid)"
+// @Param        payload  body  GrantCollaboratorRequest  true  "Collaborator's account email"
+// @Success      201  {object}  CollaboratorItem
+// @Failure      400  {object}  models.ErrorResponse  "no lawyer account with that email, or your own"
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse  "CASE_NOT_ENGAGED"
+// @Router       /cases/{id}/collaborators [post]
+func (h *Handler) GrantCollaborator(c *fiber.Ctx) error {
+	cs, err := h.acceptedLawyerCase(c)
+	if err != nil {
+		return err
+	}
+	if cs.Status != models.CaseEngaged {
+		return apperr.Conflict(apperr.CaseNotEngaged, "access can only be granted on an engaged case")
+	}
+
+	var in GrantCollaboratorRequest
+	if err := c.BodyParser(&in); err != nil {
+		return fiber.ErrBadRequest
+	}
+	in.Email = strings.ToLower(strings.TrimSpace(in.Email))
+	if errs, _ := validation.Validate(in); errs != nil {
+		return validation.Respond(c, errs)
+	}
+
+	var u models.User
+	if err := h.db.Select("id, name, email, role").
+		Where("lower(email) = ?", in.Email).First(&u).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return validation.RespondField(c, fiber.StatusBadRequest, "email", "No lawyer account uses this email")
+		}
+		return fiber.ErrInternalServerError
+	}
+	if u.Role != models.RoleLawyer {
+		return validation.RespondField(c, fiber.StatusBadRequest, "email", "No lawyer account uses this email")
+	}
+	if u.ID == cs.AcceptedLawyerID {
+		return validation.RespondField(c, fiber.StatusBadRequest, "email", "You already have access to this case")
+	}
+
+	grant := models.CaseCollaborator{CaseID: cs.ID, UserID: u.ID, GrantedBy: cs.AcceptedLawyerID}
+	// A grant left over from a previous accepted lawyer is taken over
+	if err := h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "case_id"}, {Name: "user_id"}},
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Neq{Column: "case_collaborators.granted_by", Value: cs.AcceptedLawyerID}}},
+		DoUpdates: clause.AssignmentColumns([]string{"granted_by", "created_at"}),
+	}).Create(&grant).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	// A repeat grant keeps the original time
+	if err := h.db.First(&grant, "case_id = ? AND user_id = ?", cs.ID, u.ID).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return c.Status(fiber.StatusCreated).JSON(CollaboratorItem{
+		UserID:    u.ID,
+		Name:      u.Name,
+		Email:     u.Email,
+		GrantedAt: apitime.Normalize(grant.CreatedAt),
+	})
+}
+
+// @Summary      Revoke case access
+// @Description  Accepted lawyer removes a collaborator's access to this case (idempotent; works in any case status)
+// @Tags         cases
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id      path  string  true  "case id (uuid)"
+// @Param        userID  path  string  true  "collaborator user id (uuid)"
+// @Success      200  {object}  map[string]string  "status: revoked"
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Router       /cases/{id}/collaborators/{userID} [delete]
+func (h *Handler) RevokeCollaborator(c *fiber.Ctx) error {
+	cs, err := h.acceptedLawyerCase(c)
+	if err != nil {
+		return err
+	}
+	userID, err := uuid.Parse(c.Params("userID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user id")
+	}
+
+	if err := h.db.Where("case_id = ? AND user_id = ?", cs.ID, userID).
+		Delete(&models.CaseCollaborator{}).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(fiber.Map{"status": "revoked"})
+}
@@ -0,0 +1,62 @@
+package cases
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+/* ============================== ETags ==================================== */
+
+// caseETag fingerprints a case detail response without serializing it: the
+// case's updated_at and status, file/quote counts, the latest quote change,
+// plus whatever else shapes this caller's view (role, masking, payment).
+// It is a weak ETag since it describes the content, not the exact bytes.
+func caseETag(cs models.Case, view ...string) string {
+	var lastQuote time.Time
+	for _, q := range cs.Quotes {
+		if q.UpdatedAt.After(lastQuote) {
+			lastQuote = q.UpdatedAt
+		}
+	}
+	return weakETag(
+		cs.ID.String(),
+		string(cs.Status),
+		fmt.Sprint(cs.UpdatedAt.UnixNano()),
+		fmt.Sprint(len(cs.Files)),
+		fmt.Sprint(len(cs.Quotes)),
+		fmt.Sprint(lastQuote.UnixNano()),
+		strings.Join(view, ","),
+	)
+}
+
+// weakETag hashes parts into W/"<sha1>".
+func weakETag(parts ...string) string {
+	sum := sha1.Sum([]byte(strings.Join(parts, "|")))
+	return `W/"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// notModified sets the ETag header and reports whether the request's
+// If-None-Match already names it (weak comparison), in which case the
+// handler should answer 304 with no body.
+func notModified(c *fiber.Ctx, etag string) bool {
+	c.Set(fiber.HeaderETag, etag)
+	inm := c.Get(fiber.HeaderIfNoneMatch)
+	if inm == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(inm, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
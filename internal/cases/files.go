@@ -1,31 +1,145 @@
 package cases
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	"log"
 	"mime"
+	"mime/multipart"
+	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
 	"gorm.io/gorm"
 
 	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apitime"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
 	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/pagination"
+	"github.com/aldoetobex/legal-mp-backend/pkg/validation"
 )
 
 const (
 	// Per-request upload limits
 	maxFilesPerRequest = 10
 	maxFileBytes       = 10 * 1024 * 1024 // 10 MB
+
+	// Optional per-file label ("signed contract", "ID scan")
+	maxFileDescriptionLen = 200
 )
 
+// fileDescriptions aligns the optional description form values with the
+// uploaded files: one value labels every file, otherwise there must be one
+// per file (in order). Values are trimmed and capped at maxFileDescriptionLen.
+func fileDescriptions(values []string, n int) ([]string, error) {
+	out := make([]string, n)
+	switch len(values) {
+	case 0:
+		return out, nil
+	case 1, n:
+	default:
+		return nil, fiber.NewError(fiber.StatusBadRequest,
+			"Send one description for all files or one per file")
+	}
+	for i := range out {
+		d := values[0]
+		if len(values) == n {
+			d = values[i]
+		}
+		d = strings.TrimSpace(d)
+		if utf8.RuneCountInString(d) > maxFileDescriptionLen {
+			return nil, fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("Description must be at most %d characters", maxFileDescriptionLen))
+		}
+		out[i] = d
+	}
+	return out, nil
+}
+
+// caseStorageQuota is the total bytes of files one case may hold.
+// Reads CASE_STORAGE_QUOTA_MB (whole megabytes); defaults to 100 MB.
+func caseStorageQuota() int64 {
+	if n, err := strconv.Atoi(os.Getenv("CASE_STORAGE_QUOTA_MB")); err == nil && n > 0 {
+		return int64(n) * 1024 * 1024
+	}
+	return 100 * 1024 * 1024
+}
+
+// MaxRequestBodyBytes is the server-wide body limit (fiber.Config.BodyLimit):
+// room for a full upload (maxFilesPerRequest × maxFileBytes) plus 1 MB of
+// multipart overhead, so oversized files reach UploadFile's per-file errors.
+// Env: UPLOAD_BODY_LIMIT_MB overrides it.
+func MaxRequestBodyBytes() int {
+	if n, err := strconv.Atoi(os.Getenv("UPLOAD_BODY_LIMIT_MB")); err == nil && n > 0 {
+		return n * 1024 * 1024
+	}
+	return maxFilesPerRequest*maxFileBytes + 1024*1024
+}
+
+// BulkDeleteFilesRequest lists files to remove from one case (max 50 per request).
+type BulkDeleteFilesRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=50"`
+}
+
 // Allowed content types for uploads
 var allowedMIMEs = map[string]struct{}{
 	"application/pdf": {},
 	"image/png":       {},
 }
 
+// Structural markers checked by fileIntact
+var (
+	pngIEND   = []byte{0, 0, 0, 0, 'I', 'E', 'N', 'D', 0xAE, 0x42, 0x60, 0x82}
+	pdfHeader = []byte("%PDF-")
+	pdfEOF    = []byte("%%EOF")
+)
+
+// pdfTrailerWindow is how far from the end %%EOF may sit (writers often
+// append whitespace or a few junk bytes after it).
+const pdfTrailerWindow = 1024
+
+// fileIntact is a cheap structural check so truncated or broken uploads are
+// refused before they reach storage: a PNG must have a readable header and
+// end with its IEND chunk; a PDF must start with %PDF- and carry %%EOF near
+// the end. Only the head and tail are read; nothing is fully decoded.
+func fileIntact(r io.ReaderAt, size int64, ct string) bool {
+	switch ct {
+	case "image/png":
+		if _, err := png.DecodeConfig(io.NewSectionReader(r, 0, size)); err != nil {
+			return false
+		}
+		return bytes.Equal(readTail(r, size, int64(len(pngIEND))), pngIEND)
+	case "application/pdf":
+		head := make([]byte, len(pdfHeader))
+		if _, err := r.ReadAt(head, 0); err != nil || !bytes.Equal(head, pdfHeader) {
+			return false
+		}
+		return bytes.Contains(readTail(r, size, pdfTrailerWindow), pdfEOF)
+	}
+	return false
+}
+
+// readTail returns the last n bytes of r (fewer for a short file, nil on error).
+func readTail(r io.ReaderAt, size, n int64) []byte {
+	n = min(n, size)
+	buf := make([]byte, n)
+	if _, err := r.ReadAt(buf, size-n); err != nil && err != io.EOF {
+		return nil
+	}
+	return buf
+}
+
 // normalizeCT tries to determine a correct content type.
 // - Prefer the header value if provided.
 // - Fallback to file extension via mime.TypeByExtension.
@@ -48,22 +162,89 @@ func normalizeCT(fname, headerCT string) string {
 	return ct
 }
 
+// msgUnsupportedType is checkUpload's message for a disallowed content type.
+const msgUnsupportedType = "Only PDF or PNG are allowed"
+
+// errUnsupportedType answers a single-file request whose file has a
+// disallowed type; batches report it per file instead.
+func errUnsupportedType() error {
+	return fiber.NewError(fiber.StatusUnsupportedMediaType, msgUnsupportedType)
+}
+
+// checkUpload runs the per-file checks that need no I/O (size, type) and
+// returns the normalized content type, or a user-facing error message.
+func checkUpload(fh *multipart.FileHeader) (ct, msg string) {
+	if fh.Size <= 0 {
+		return "", "Empty file"
+	}
+	if fh.Size > maxFileBytes {
+		return "", "Each file must be <= 10MB"
+	}
+	ct = normalizeCT(fh.Filename, fh.Header.Get("Content-Type"))
+	if _, ok := allowedMIMEs[ct]; !ok {
+		return "", msgUnsupportedType
+	}
+	return ct, ""
+}
+
+// openUpload opens a checked file and verifies it is structurally intact.
+// The caller closes the returned file.
+func openUpload(fh *multipart.FileHeader, ct string) (multipart.File, string) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, "Open failed"
+	}
+	// Truncated/broken files would fail to open for the lawyer later
+	if !fileIntact(f, fh.Size, ct) {
+		f.Close()
+		return nil, "Corrupt or unreadable file"
+	}
+	return f, ""
+}
+
+// caseBytesUsed is the total size of the files stored for a case.
+func (h *Handler) caseBytesUsed(caseID uuid.UUID) (int64, error) {
+	var used int64
+	err := h.db.Model(&models.CaseFile{}).
+		Where("case_id = ?", caseID).
+		Select("COALESCE(SUM(size), 0)").
+		Scan(&used).Error
+	return used, err
+}
+
 // canModifyFiles returns true if files can be added while the case is in
-// this status. (Open or Engaged)
+// this status. (Open, Paused or Engaged)
 func canModifyFiles(st models.CaseStatus) bool {
 	switch st {
-	case models.CaseOpen, models.CaseEngaged:
+	case models.CaseOpen, models.CasePaused, models.CaseEngaged:
 		return true
 	default:
 		return false
 	}
 }
 
+// keyInCase reports whether an object key lives under its case's own prefix
+// (case/<caseID>/). Keys must already be clean, so ".." can't climb out.
+func keyInCase(key string, caseID uuid.UUID) bool {
+	prefix := "case/" + caseID.String() + "/"
+	return path.Clean(key) == key && strings.HasPrefix(key, prefix) && len(key) > len(prefix)
+}
+
+// checkFileKey refuses to touch storage for a file row whose key points
+// outside its case: such a row was tampered with, never written by us.
+func checkFileKey(cf models.CaseFile) error {
+	if keyInCase(cf.Key, cf.CaseID) {
+		return nil
+	}
+	log.Printf("case file %s: key %q is outside case %s", cf.ID, cf.Key, cf.CaseID)
+	return fiber.ErrInternalServerError
+}
+
 // canDeleteFiles returns true if files can be deleted while the case is in
-// this status. (Open or Cancelled)
+// this status. (Open, Paused or Cancelled)
 func canDeleteFiles(st models.CaseStatus) bool {
 	switch st {
-	case models.CaseOpen, models.CaseCancelled:
+	case models.CaseOpen, models.CasePaused, models.CaseCancelled:
 		return true
 	default:
 		return false
@@ -72,23 +253,36 @@ func canDeleteFiles(st models.CaseStatus) bool {
 
 /* ========================= Upload ========================= */
 
+// uploadFileKeys names the form keys UploadFile reads files from.
+const uploadFileKeys = "files[] (or files)"
+
+// uploadRejected is the 400 body when every file in a batch failed
+// validation: the usual error fields plus the per-file results.
+type uploadRejected struct {
+	models.ErrorResponse
+	Results []fiber.Map `json:"results"`
+}
+
 // Upload Case Files godoc
 // @Summary      Upload multiple case files (PDF/PNG)
-// @Description  Client (owner) uploads up to 10 files. Only allowed when case is open/engaged.
+// @Description  Client (owner) uploads up to 10 files while the case is open/paused/engaged; the accepted lawyer may upload on an engaged case. Files that would push the case past its storage quota (CASE_STORAGE_QUOTA_MB, default 100) are rejected individually with remaining_bytes.
 // @Tags         files
 // @Security     BearerAuth
 // @Accept       multipart/form-data
 // @Produce      json
 // @Param        id     path      string   true  "case id (uuid)"
 // @Param        files  formData  []file   true  "PDF/PNG (max 10; max 10MB each)"
-// @Success      201    {object}  map[string]any  "results: [{id,key,name,size,error?}]"
-// @Failure      400    {object}  models.ErrorResponse
+// @Param        description  formData  string  false  "optional label (max 200 chars); repeat once per file, in order, or send one for all"
+// @Success      201    {object}  map[string]any  "results: [{id,key,name,size,description?,error?,remaining_bytes?}]"
+// @Failure      400    {object}  models.ErrorResponse  "MULTIPART_REQUIRED | MULTIPART_INVALID | NO_FILES_PROVIDED | NO_FILES_STORED (every file rejected; body also has results)"
+// @Failure      415    {object}  models.ErrorResponse  "a single file that isn't PDF/PNG"
 // @Failure      403    {object}  models.ErrorResponse
 // @Failure      404    {object}  models.ErrorResponse
 // @Failure      500    {object}  models.ErrorResponse
 // @Router       /cases/{id}/files [post]
 func (h *Handler) UploadFile(c *fiber.Ctx) error {
-	clientID := auth.MustUserID(c)
+	userID := auth.MustUserID(c)
+	role := auth.MustRole(c)
 	caseID := c.Params("id")
 
 	// Storage must be configured for uploads.
@@ -104,61 +298,108 @@ func (h *Handler) UploadFile(c *fiber.Ctx) error {
 		}
 		return fiber.ErrInternalServerError
 	}
-	if cs.ClientID.String() != clientID {
+	// Authorization rules:
+	// - Owner client while files can be modified.
+	// - Accepted lawyer only while the case is engaged (deliverables).
+	switch role {
+	case string(models.RoleClient):
+		if cs.ClientID.String() != userID {
+			return fiber.ErrForbidden
+		}
+		if !canModifyFiles(cs.Status) {
+			return apperr.Forbidden(apperr.FilesLocked, "Files cannot be modified on a closed or cancelled case")
+		}
+	case string(models.RoleLawyer):
+		if cs.AcceptedLawyerID.String() != userID {
+			return LawyerDenied()
+		}
+		if cs.Status != models.CaseEngaged {
+			return apperr.Forbidden(apperr.FilesLocked, "Lawyers can only add files while the case is engaged")
+		}
+	default:
 		return fiber.ErrForbidden
 	}
-	if !canModifyFiles(cs.Status) {
-		return fiber.NewError(fiber.StatusForbidden, "Files cannot be modified on a closed or cancelled case")
-	}
 
-	// Parse multipart form input.
+	// Parse multipart form input. A body that isn't multipart at all, one
+	// that doesn't parse and one without files get different codes.
+	ctype := strings.ToLower(string(c.Request().Header.ContentType()))
+	if !strings.HasPrefix(ctype, fiber.MIMEMultipartForm) {
+		return apperr.BadRequest(apperr.MultipartRequired,
+			"Request must be multipart/form-data; send files under "+uploadFileKeys)
+	}
 	form, err := c.MultipartForm()
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Multipart form required; send files[]")
+		return apperr.BadRequest(apperr.MultipartInvalid, "Malformed multipart body")
 	}
 	files := form.File["files[]"]
 	if len(files) == 0 {
 		files = form.File["files"] // support both keys
 	}
 	if len(files) == 0 {
-		return fiber.NewError(fiber.StatusBadRequest, "No files provided (key: files[])")
+		return apperr.BadRequest(apperr.NoFilesProvided, "No files provided; send files under "+uploadFileKeys)
 	}
 	if len(files) > maxFilesPerRequest {
 		return fiber.NewError(fiber.StatusBadRequest, "Too many files; maximum is 10")
 	}
+	// A lone file of the wrong type fails the whole request; mixed batches
+	// keep their per-file results
+	if len(files) == 1 {
+		if _, msg := checkUpload(files[0]); msg == msgUnsupportedType {
+			return errUnsupportedType()
+		}
+	}
+	descValues := form.Value["descriptions[]"]
+	if len(descValues) == 0 {
+		descValues = form.Value["description"]
+	}
+	descs, err := fileDescriptions(descValues, len(files))
+	if err != nil {
+		return err
+	}
+
+	// Bytes already stored for this case count against the quota.
+	quota := caseStorageQuota()
+	used, err := h.caseBytesUsed(cs.ID)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
 
 	results := make([]fiber.Map, 0, len(files))
+	rejected := 0 // files that failed validation (not storage/DB errors)
 
-	for _, fh := range files {
+	for i, fh := range files {
 		item := fiber.Map{
 			"name": fh.Filename,
 			"size": fh.Size,
 		}
-
-		// Basic validations
-		if fh.Size <= 0 {
-			item["error"] = "Empty file"
-			results = append(results, item)
-			continue
+		if descs[i] != "" {
+			item["description"] = descs[i]
 		}
-		if fh.Size > maxFileBytes {
-			item["error"] = "Each file must be <= 10MB"
+
+		// Basic validations (size, content type with normalization/fallback)
+		ct, msg := checkUpload(fh)
+		if msg != "" {
+			item["error"] = msg
+			rejected++
 			results = append(results, item)
 			continue
 		}
 
-		// Content type check (with normalization/fallback)
-		ct := normalizeCT(fh.Filename, fh.Header.Get("Content-Type"))
-		if _, ok := allowedMIMEs[ct]; !ok {
-			item["error"] = "Only PDF or PNG are allowed"
+		// Per-case quota, checked before anything reaches storage
+		if used+fh.Size > quota {
+			remaining := max(quota-used, 0)
+			item["error"] = fmt.Sprintf("Case storage quota exceeded; %d bytes remaining", remaining)
+			item["remaining_bytes"] = remaining
+			rejected++
 			results = append(results, item)
 			continue
 		}
 
-		// Open the uploaded file stream
-		f, err := fh.Open()
-		if err != nil {
-			item["error"] = "Open failed"
+		// Open the uploaded file stream (checks it is intact)
+		f, msg := openUpload(fh, ct)
+		if msg != "" {
+			item["error"] = msg
+			rejected++
 			results = append(results, item)
 			continue
 		}
@@ -181,6 +422,7 @@ func (h *Handler) UploadFile(c *fiber.Ctx) error {
 			Mime:         ct,
 			Size:         int(fh.Size),
 			OriginalName: fh.Filename,
+			Description:  descs[i],
 		}
 		if err := h.db.Create(&rec).Error; err != nil {
 			item["error"] = "Database error"
@@ -190,24 +432,226 @@ func (h *Handler) UploadFile(c *fiber.Ctx) error {
 			continue
 		}
 
+		used += fh.Size
 		item["id"] = rec.ID
 		item["key"] = rec.Key
 		results = append(results, item)
 	}
 
+	// Nothing usable in the batch: the client has to fix it, not a 201
+	if rejected == len(files) {
+		return c.Status(fiber.StatusBadRequest).JSON(uploadRejected{
+			ErrorResponse: models.ErrorResponse{
+				Error:     true,
+				Message:   "No files were stored; see results for each file's error",
+				Code:      apperr.NoFilesStored,
+				RequestID: auth.GetRequestID(c),
+			},
+			Results: results,
+		})
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"results": results})
 }
 
+/* ========================= Replace ========================= */
+
+// Replace Case File godoc
+// @Summary      Replace a case file
+// @Description  Client (owner) uploads a new version of a file while the case is open/paused/engaged. The file keeps its id (links stay valid); name, type, size and storage key change and the old object is removed. Same checks as uploads, quota counted without the old version.
+// @Tags         files
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        fileID  path      string  true  "file id (uuid)"
+// @Param        file    formData  file    true  "PDF/PNG (max 10MB)"
+// @Success      200     {object}  map[string]any  "id, key, name, size"
+// @Failure      400     {object}  models.ErrorResponse
+// @Failure      403     {object}  models.ErrorResponse
+// @Failure      404     {object}  models.ErrorResponse
+// @Failure      415     {object}  models.ErrorResponse  "file isn't PDF/PNG"
+// @Failure      500     {object}  models.ErrorResponse
+// @Failure      502     {object}  models.ErrorResponse  "storage upload failed"
+// @Router       /files/{fileID} [put]
+func (h *Handler) ReplaceFile(c *fiber.Ctx) error {
+	userID := auth.MustUserID(c)
+	if h.sb == nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "storage not configured")
+	}
+
+	// Load file + case for checks
+	var cf models.CaseFile
+	if err := h.db.Preload("Case").First(&cf, "id = ?", c.Params("fileID")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	if cf.Case.ClientID.String() != userID {
+		return fiber.ErrForbidden
+	}
+	if !canModifyFiles(cf.Case.Status) {
+		return apperr.Forbidden(apperr.FilesLocked, "Files cannot be modified on a closed or cancelled case")
+	}
+	if err := checkFileKey(cf); err != nil {
+		return err
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Multipart form required; send file")
+	}
+	ct, msg := checkUpload(fh)
+	if msg == msgUnsupportedType {
+		return errUnsupportedType()
+	}
+	if msg != "" {
+		return fiber.NewError(fiber.StatusBadRequest, msg)
+	}
+
+	// The old version's bytes are freed by the swap
+	used, err := h.caseBytesUsed(cf.CaseID)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if quota := caseStorageQuota(); used-int64(cf.Size)+fh.Size > quota {
+		remaining := max(quota-used+int64(cf.Size), 0)
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Case storage quota exceeded; %d bytes remaining", remaining))
+	}
+
+	f, msg := openUpload(fh, ct)
+	if msg != "" {
+		return fiber.NewError(fiber.StatusBadRequest, msg)
+	}
+	defer f.Close()
+
+	// Always a fresh key: re-uploading the same name must not collide with
+	// (and then delete) the object being replaced
+	key := h.sb.MakeObjectKey(cf.CaseID.String(), strconv.FormatInt(time.Now().UnixNano(), 36)+"-"+fh.Filename)
+	if err := h.sb.Upload(key, f, ct, fh.Size); err != nil {
+		return fiber.NewError(fiber.StatusBadGateway, "Upload failed")
+	}
+
+	oldKey := cf.Key
+	if err := h.db.Model(&models.CaseFile{}).Where("id = ?", cf.ID).Updates(map[string]any{
+		"key":           key,
+		"mime":          ct,
+		"size":          int(fh.Size),
+		"original_name": fh.Filename,
+	}).Error; err != nil {
+		_ = h.sb.Delete(key) // best-effort cleanup of the new object
+		return fiber.ErrInternalServerError
+	}
+
+	// Best-effort delete of the previous version
+	_ = h.sb.Delete(oldKey)
+
+	return c.JSON(fiber.Map{"id": cf.ID, "key": key, "name": fh.Filename, "size": fh.Size})
+}
+
+/* ========================= List ========================= */
+
+// CaseFileItem is file metadata for the files tab (filename masked as in GetDetail).
+type CaseFileItem struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Mime        string    `json:"mime"`
+	Size        int       `json:"size"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type PageCaseFiles = pagination.Page[CaseFileItem]
+
+// List Case Files godoc
+// @Summary      List files of a case
+// @Description  Paginated file metadata without quotes/history. Same access as the case detail: owner client, admins, or the accepted lawyer (and their collaborators) once engaged/closed. Filenames are masked.
+// @Tags         files
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id        path   string  true   "case id (uuid)"
+// @Param        page      query  int     false  "page"
+// @Param        pageSize  query  int     false  "pageSize (default 10)"
+// @Success      200  {object}  pagination.Page[cases.CaseFileItem]
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /cases/{id}/files [get]
+func (h *Handler) ListFiles(c *fiber.Ctx) error {
+	userID := auth.MustUserID(c)
+	caseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid case id")
+	}
+
+	// Admins also see deleted cases (as in GetDetail)
+	var cs models.Case
+	if err := h.scoped(c).
+		Select("id, client_id, status, accepted_lawyer_id").
+		First(&cs, "id = ?", caseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+
+	switch {
+	case auth.IsAdmin(c):
+		auth.LogAdminAccess(c)
+	case cs.ClientID.String() == userID:
+	case auth.MustRole(c) == string(models.RoleLawyer) && h.lawyerCanRead(cs, userID):
+	case auth.MustRole(c) == string(models.RoleLawyer):
+		return LawyerDenied()
+	default:
+		return fiber.ErrForbidden
+	}
+
+	page, size := pagination.Parse(c)
+	q := h.db.Model(&models.CaseFile{}).Where("case_id = ?", cs.ID)
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	var files []models.CaseFile
+	if err := q.
+		Order("created_at ASC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Find(&files).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	items := make([]CaseFileItem, len(files))
+	for i, f := range files {
+		items[i] = CaseFileItem{
+			ID:          f.ID,
+			Name:        maskFileName(f.OriginalName),
+			Mime:        f.Mime,
+			Size:        f.Size,
+			Description: f.Description,
+			CreatedAt:   apitime.Normalize(f.CreatedAt),
+		}
+	}
+
+	return c.JSON(pagination.New(c, page, size, total, items))
+}
+
 /* ========================= Signed URL ========================= */
 
 // Signed Download URL godoc
 // @Summary      Get signed URL for a case file
-// @Description  Client owner or the accepted lawyer obtains a short-lived signed URL
+// @Description  Client owner, the accepted lawyer or a collaborator they granted access obtains a short-lived signed URL. With format=qr the same URL comes back as a PNG QR code (to open the file on a phone).
 // @Tags         files
 // @Security     BearerAuth
 // @Produce      json
+// @Produce      png
 // @Param        fileID  path string true "file id (uuid)"
+// @Param        format  query string false "json (default) | qr"
 // @Success      200  {object}  map[string]any  "url, expires_in, now"
+// @Failure      400  {object}  models.ErrorResponse
 // @Failure      403  {object}  models.ErrorResponse
 // @Failure      404  {object}  models.ErrorResponse
 // @Failure      500  {object}  models.ErrorResponse
@@ -216,6 +660,10 @@ func (h *Handler) SignedDownloadURL(c *fiber.Ctx) error {
 	userID := auth.MustUserID(c)
 	role := auth.MustRole(c)
 	fileID := c.Params("fileID")
+	format := c.Query("format", "json")
+	if format != "json" && format != "qr" {
+		return fiber.NewError(fiber.StatusBadRequest, "format must be json or qr")
+	}
 
 	// Load file and its parent case
 	var cf models.CaseFile
@@ -228,33 +676,44 @@ func (h *Handler) SignedDownloadURL(c *fiber.Ctx) error {
 
 	// Authorization rules:
 	// - Owner client always allowed.
-	// - Accepted lawyer allowed only when case is engaged or closed.
+	// - Accepted lawyer and their collaborators allowed only when case is
+	//   engaged or closed.
 	allowed := false
 	if role == string(models.RoleClient) && cf.Case.ClientID.String() == userID {
 		allowed = true
 	}
-	if role == string(models.RoleLawyer) &&
-		(cf.Case.Status == models.CaseEngaged || cf.Case.Status == models.CaseClosed) &&
-		cf.Case.AcceptedLawyerID.String() == userID {
+	if role == string(models.RoleLawyer) && h.lawyerCanRead(cf.Case, userID) {
 		allowed = true
 	}
 	if !allowed {
+		if role == string(models.RoleLawyer) {
+			return LawyerDenied()
+		}
 		return fiber.ErrForbidden
 	}
+	if err := checkFileKey(cf); err != nil {
+		return err
+	}
 
-	// Unit tests may not inject storage; return a dummy URL in that case.
-	if h.sb == nil {
-		return c.JSON(fiber.Map{
-			"url":        "https://example.com/test-signed-url",
-			"expires_in": 60,
-			"now":        time.Now().UTC(),
-		})
+	// Generate a short-lived signed URL. Unit tests may not inject storage;
+	// use a dummy URL in that case.
+	url := "https://example.com/test-signed-url"
+	if h.sb != nil {
+		var err error
+		if url, err = h.sb.SignedURL(cf.Key, 60); err != nil { // seconds
+			return fiber.ErrInternalServerError
+		}
 	}
 
-	// Generate a short-lived signed URL
-	url, err := h.sb.SignedURL(cf.Key, 60) // seconds
-	if err != nil {
-		return fiber.ErrInternalServerError
+	if format == "qr" {
+		img, err := qrcode.Encode(url, qrcode.Medium, 256)
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		// The code is as short-lived as the URL in it
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		c.Set(fiber.HeaderContentType, "image/png")
+		return c.Send(img)
 	}
 	return c.JSON(fiber.Map{"url": url, "expires_in": 60, "now": time.Now().UTC()})
 }
@@ -295,7 +754,10 @@ func (h *Handler) DeleteFile(c *fiber.Ctx) error {
 	}
 	// Deletions allowed only when case is open/cancelled
 	if !canDeleteFiles(cf.Case.Status) {
-		return fiber.NewError(fiber.StatusForbidden, "Files cannot be deleted on a closed or engaged case")
+		return apperr.Forbidden(apperr.FilesLocked, "Files cannot be deleted on a closed or engaged case")
+	}
+	if err := checkFileKey(cf); err != nil {
+		return err
 	}
 
 	// Best-effort delete from storage (skip if storage not configured)
@@ -310,3 +772,114 @@ func (h *Handler) DeleteFile(c *fiber.Ctx) error {
 
 	return c.JSON(fiber.Map{"status": "ok"})
 }
+
+/* ========================= Bulk Delete ========================= */
+
+// Bulk Delete Case Files godoc
+// @Summary      Delete several case files
+// @Description  Client owner deletes up to 50 files of one case at once, only while the case is open/cancelled. Per-ID results; IDs that are not files of this case fail individually.
+// @Tags         files
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string                  true  "case id (uuid)"
+// @Param        payload  body  BulkDeleteFilesRequest  true  "file ids"
+// @Success      200  {object}  map[string]any  "results: [{id,deleted?,error?}]"
+// @Failure      400  {object}  models.ValidationErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /cases/{id}/files/delete [post]
+func (h *Handler) DeleteFiles(c *fiber.Ctx) error {
+	clientID := auth.MustUserID(c)
+
+	// Check case existence, ownership and status.
+	var cs models.Case
+	if err := h.db.First(&cs, "id = ?", c.Params("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	if cs.ClientID.String() != clientID {
+		return fiber.ErrForbidden
+	}
+	if !canDeleteFiles(cs.Status) {
+		return apperr.Forbidden(apperr.FilesLocked, "Files cannot be deleted on a closed or engaged case")
+	}
+
+	var in BulkDeleteFilesRequest
+	if err := c.BodyParser(&in); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid json")
+	}
+	if errs, _ := validation.Validate(in); errs != nil {
+		return validation.Respond(c, errs)
+	}
+
+	// Parse IDs; bad or repeated ones fail on their own.
+	results := make([]fiber.Map, len(in.IDs))
+	ids := make([]uuid.UUID, 0, len(in.IDs))
+	seen := map[uuid.UUID]bool{}
+	for i, raw := range in.IDs {
+		results[i] = fiber.Map{"id": raw}
+		id, err := uuid.Parse(raw)
+		switch {
+		case err != nil:
+			results[i]["error"] = "Invalid file id"
+		case seen[id]:
+			results[i]["error"] = "Duplicate file id"
+		default:
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	// Only files of this case qualify (foreign and missing IDs look the same).
+	var files []models.CaseFile
+	if len(ids) > 0 {
+		if err := h.db.Where("case_id = ? AND id IN ?", cs.ID, ids).Find(&files).Error; err != nil {
+			return fiber.ErrInternalServerError
+		}
+	}
+	found := make(map[uuid.UUID]bool, len(files))
+	badKey := map[uuid.UUID]bool{}
+	keys := make([]string, 0, len(files))
+	fileIDs := make([]uuid.UUID, 0, len(files))
+	for _, f := range files {
+		found[f.ID] = true
+		if checkFileKey(f) != nil {
+			badKey[f.ID] = true // left in place, reported below
+			continue
+		}
+		keys = append(keys, f.Key)
+		fileIDs = append(fileIDs, f.ID)
+	}
+
+	if len(fileIDs) > 0 {
+		// Best-effort delete from storage (skip if storage not configured)
+		if h.sb != nil {
+			_ = h.sb.BulkDelete(keys) // ignore error
+		}
+
+		// One DB delete for the whole batch
+		if err := h.db.Where("id IN ?", fileIDs).Delete(&models.CaseFile{}).Error; err != nil {
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	for i, raw := range in.IDs {
+		if _, failed := results[i]["error"]; failed {
+			continue
+		}
+		id, _ := uuid.Parse(raw)
+		if badKey[id] {
+			results[i]["error"] = "File cannot be deleted"
+		} else if found[id] {
+			results[i]["deleted"] = true
+		} else {
+			results[i]["error"] = "File not found in this case"
+		}
+	}
+
+	return c.JSON(fiber.Map{"results": results})
+}
@@ -2,8 +2,9 @@ package cases
 
 import (
 	"crypto/sha1"
+	"encoding/csv"
 	"encoding/hex"
-	"math"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -16,7 +17,12 @@ import (
 
 	"github.com/aldoetobex/legal-mp-backend/internal/auth"
 	"github.com/aldoetobex/legal-mp-backend/internal/storage"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apitime"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apptz"
 	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/money"
+	"github.com/aldoetobex/legal-mp-backend/pkg/pagination"
 	"github.com/aldoetobex/legal-mp-backend/pkg/sanitize"
 	"github.com/aldoetobex/legal-mp-backend/pkg/utils"
 	"github.com/aldoetobex/legal-mp-backend/pkg/validation"
@@ -28,6 +34,14 @@ type CreateCaseRequest struct {
 	Title       string `json:"title" validate:"required,min=3,max=120"`
 	Category    string `json:"category" validate:"required,max=40"`
 	Description string `json:"description" validate:"max=2000"`
+
+	// Optional: a legal deadline (YYYY-MM-DD, today or later in the app TZ)
+	// and how urgent the matter is
+	Deadline string `json:"deadline" validate:"omitempty,datetime=2006-01-02"`
+	Urgency  string `json:"urgency" validate:"omitempty,oneof=low normal high"`
+
+	// Skip the duplicate check (the client really means a second, identical case)
+	AllowDuplicate bool `json:"allow_duplicate"`
 }
 
 type ActionRequest struct {
@@ -36,21 +50,18 @@ type ActionRequest struct {
 }
 
 type CaseListItem struct {
-	ID        string `json:"id"`
-	Title     string `json:"title"`
-	Category  string `json:"category"`
-	Status    string `json:"status"`
-	CreatedAt string `json:"created_at"`
-	Quotes    int64  `json:"quotes"`
+	ID        string  `json:"id"`
+	Reference *string `json:"reference,omitempty"` // nil on legacy cases
+	Title     string  `json:"title"`
+	Category  string  `json:"category"`
+	Status    string  `json:"status"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+	Quotes    int64   `json:"quotes"`
+	Files     int64   `json:"files"`
 }
 
-type PageCases struct {
-	Page     int            `json:"page"`
-	PageSize int            `json:"pageSize"`
-	Total    int64          `json:"total"`
-	Pages    int            `json:"pages"`
-	Items    []CaseListItem `json:"items"`
-}
+type PageCases = pagination.Page[CaseListItem]
 
 // ---- History DTO
 
@@ -60,7 +71,8 @@ type CaseHistoryDTO struct {
 	OldStatus models.CaseStatus `json:"old_status"`
 	NewStatus models.CaseStatus `json:"new_status"`
 	Reason    string            `json:"reason"`
-	ActorID   uuid.UUID         `json:"actor_id"`
+	ActorID   uuid.UUID         `json:"actor_id"` // uuid.Nil for system actions
+	ActorType models.ActorType  `json:"actor_type"`
 	CreatedAt time.Time         `json:"created_at"`
 }
 
@@ -75,8 +87,40 @@ func NewHandler(db *gorm.DB, sb *storage.Supabase) *Handler {
 	return &Handler{db: db, sb: sb}
 }
 
+// scoped returns the DB handle for loading a case: admins read soft-deleted
+// cases too (Unscoped); everyone else only sees live ones.
+func (h *Handler) scoped(c *fiber.Ctx) *gorm.DB {
+	if auth.IsAdmin(c) {
+		return h.db.Unscoped()
+	}
+	return h.db
+}
+
+// LawyerDenied is the error for a lawyer who is not a party to the case. It is
+// a 404 by default so the anonymized marketplace never confirms that a case
+// exists to non-parties; CASE_LAWYER_DENY_STATUS=403 restores a plain 403.
+// Owner mismatches for clients stay 403 either way.
+func LawyerDenied() error {
+	if strings.TrimSpace(os.Getenv("CASE_LAWYER_DENY_STATUS")) == "403" {
+		return fiber.ErrForbidden
+	}
+	return fiber.ErrNotFound
+}
+
 /* ============================ Create Case ================================ */
 
+// duplicateCaseWindow is how long an open case blocks an identical one
+// (same client, title and category) from being created.
+// Reads CASE_DUPLICATE_WINDOW (Go duration, e.g. "5m"); "0" disables the check.
+func duplicateCaseWindow() time.Duration {
+	if v := os.Getenv("CASE_DUPLICATE_WINDOW"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return 5 * time.Minute
+}
+
 // @Summary      Create case
 // @Description  Client creates a new case
 // @Tags         cases
@@ -84,15 +128,21 @@ func NewHandler(db *gorm.DB, sb *storage.Supabase) *Handler {
 // @Accept       json
 // @Produce      json
 // @Param        payload  body  CreateCaseRequest  true  "Case payload"
-// @Success      201  {object}  map[string]string  "id"
+// @Success      201  {object}  map[string]string  "id, reference"
 // @Failure      400  {object}  models.ValidationErrorResponse
 // @Failure      401  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse  "DUPLICATE_CASE"
 // @Router       /cases [post]
 func (h *Handler) Create(c *fiber.Ctx) error {
 	var in CreateCaseRequest
 	if err := c.BodyParser(&in); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid json")
 	}
+	// Plain text only: markup would run in the other party's browser.
+	// Stripped first so a title that is only tags fails "required".
+	in.Title = sanitize.StripHTML(in.Title)
+	in.Description = sanitize.StripHTML(in.Description)
+
 	// Laravel-style validation response
 	if errs, _ := validation.Validate(in); errs != nil {
 		return validation.Respond(c, errs)
@@ -106,38 +156,75 @@ func (h *Handler) Create(c *fiber.Ctx) error {
 		Description: strings.TrimSpace(in.Description),
 		Status:      models.CaseOpen,
 	}
-	if err := h.db.Create(&cs).Error; err != nil {
-		return fiber.ErrInternalServerError
+	if local, ok := parseLocalDate(in.Deadline); ok {
+		today := time.Now().In(apptz.Location())
+		if local.Before(time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())) {
+			return validation.RespondField(c, fiber.StatusBadRequest, "deadline", "Deadline cannot be in the past")
+		}
+		d := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+		cs.Deadline = &d
+	}
+	if in.Urgency != "" {
+		u := models.Urgency(in.Urgency)
+		cs.Urgency = &u
 	}
 
-	// History: created
-	utils.LogCaseHistory(c.Context(), h.db, cs.ID, clientUUID, "created", "", models.CaseOpen, "case created")
-
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": cs.ID})
-}
+	tx := h.db.Begin()
+	if tx.Error != nil {
+		return fiber.ErrInternalServerError
+	}
 
-/* ========================= Pagination Helper ============================= */
+	// Double-submit guard: serialize this client's creates, then look for a
+	// recent identical open case
+	if window := duplicateCaseWindow(); window > 0 && !in.AllowDuplicate {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", clientUUID.String()).Error; err != nil {
+			tx.Rollback()
+			return fiber.ErrInternalServerError
+		}
+		var dupes int64
+		if err := tx.Model(&models.Case{}).
+			Where("client_id = ? AND status = ? AND lower(title) = lower(?) AND category = ? AND created_at >= ?",
+				clientUUID, models.CaseOpen, cs.Title, cs.Category, time.Now().Add(-window)).
+			Count(&dupes).Error; err != nil {
+			tx.Rollback()
+			return fiber.ErrInternalServerError
+		}
+		if dupes > 0 {
+			tx.Rollback()
+			return apperr.Conflict(apperr.DuplicateCase, "an identical case was just created; set allow_duplicate to create another")
+		}
+	}
 
-// parsePage reads pagination params with sane defaults and bounds.
-func parsePage(c *fiber.Ctx) (page, size int) {
-	page, _ = strconv.Atoi(c.Query("page", "1"))
-	size, _ = strconv.Atoi(c.Query("pageSize", "10"))
-	if page < 1 {
-		page = 1
+	ref, err := nextReference(tx, time.Now())
+	if err != nil {
+		tx.Rollback()
+		return fiber.ErrInternalServerError
 	}
-	if size < 1 || size > 50 {
-		size = 10
+	cs.Reference = &ref
+	if err := tx.Create(&cs).Error; err != nil {
+		tx.Rollback()
+		return fiber.ErrInternalServerError
 	}
-	return
+	if err := tx.Commit().Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	// History: created
+	utils.LogCaseHistory(c.Context(), h.db, cs.ID, clientUUID, models.ActorUser, "created", "", models.CaseOpen, "case created")
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": cs.ID, "reference": ref})
 }
 
 type caseWithCounts struct {
 	ID        uuid.UUID `json:"id"`
+	Reference *string   `json:"reference"`
 	Title     string    `json:"title"`
 	Category  string    `json:"category"`
 	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 	Quotes    int64     `json:"quotes"`
+	Files     int64     `json:"files"`
 }
 
 /* ============================ List My Cases ============================== */
@@ -149,37 +236,78 @@ type caseWithCounts struct {
 // @Produce      json
 // @Param        page      query int false "page"
 // @Param        pageSize  query int false "pageSize"
-// @Success      200  {object}  PageCases
+// @Param        sort      query string false "created_desc (default) | updated_desc (recently active)"
+// @Param        status    query string false "open | paused | engaged | closed | cancelled (default: all)"
+// @Success      200  {object}  pagination.Page[cases.CaseListItem]
+// @Failure      400  {object}  models.ErrorResponse
 // @Failure      401  {object}  models.ErrorResponse
 // @Router       /cases/mine [get]
 func (h *Handler) ListMine(c *fiber.Ctx) error {
 	clientID := auth.MustUserID(c)
-	page, size := parsePage(c)
+	page, size := pagination.Parse(c)
+	order, err := listOrder(c)
+	if err != nil {
+		return err
+	}
+
+	// Optional status filter
+	status := strings.TrimSpace(c.Query("status"))
+	if status != "" {
+		switch models.CaseStatus(status) {
+		case models.CaseOpen, models.CasePaused, models.CaseEngaged, models.CaseClosed, models.CaseCancelled:
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "invalid status filter")
+		}
+	}
+
+	total, items, err := h.clientCases(clientID, status, page, size, order)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(pagination.New(c, page, size, total, items))
+}
+
+// listOrder maps ?sort to an ORDER BY over cases: created_desc (default) or
+// updated_desc (recently active first).
+func listOrder(c *fiber.Ctx) (string, error) {
+	switch c.Query("sort") {
+	case "", "created_desc":
+		return "cases.created_at DESC", nil
+	case "updated_desc":
+		return "cases.updated_at DESC, cases.created_at DESC", nil
+	default:
+		return "", fiber.NewError(fiber.StatusBadRequest, "invalid sort")
+	}
+}
+
+// clientCases returns one page of the client's cases with quote and file
+// counts, plus the total for pagination. A non-empty status keeps only the
+// cases in that status.
+func (h *Handler) clientCases(clientID, status string, page, size int, order string) (int64, []CaseListItem, error) {
+	base := h.db.Model(&models.Case{}).Where("cases.client_id = ?", clientID)
+	if status != "" {
+		base = base.Where("cases.status = ?", status)
+	}
 
 	// Count for pagination
 	var total int64
-	if err := h.db.Model(&models.Case{}).
-		Where("client_id = ?", clientID).
-		Count(&total).Error; err != nil {
-		return fiber.ErrInternalServerError
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return 0, nil, err
 	}
 
-	// Page data + quote counts (LEFT JOIN + GROUP BY)
+	// Page data + quote and file counts. Each count is its own correlated
+	// subquery: joining both tables would multiply the rows (quotes × files).
+	// Model keeps the soft-delete scope.
 	rows := make([]caseWithCounts, 0, size)
-	if err := h.db.
-		Table("cases").
-		Select(`cases.id, cases.title, cases.category, cases.status, cases.created_at,
-          COUNT(quotes.id) AS quotes`).
-		Joins("LEFT JOIN quotes ON quotes.case_id = cases.id").
-		Where("cases.client_id = ?", clientID).
-		Group("cases.id").
-		Order("cases.created_at DESC").
+	if err := base.Session(&gorm.Session{}).
+		Select(`cases.id, cases.reference, cases.title, cases.category, cases.status, cases.created_at,
+          cases.updated_at,
+          (SELECT COUNT(*) FROM quotes WHERE quotes.case_id = cases.id) AS quotes,
+          (SELECT COUNT(*) FROM case_files WHERE case_files.case_id = cases.id) AS files`).
+		Order(order).
 		Offset((page - 1) * size).Limit(size).
 		Scan(&rows).Error; err != nil {
-		return fiber.ErrInternalServerError
-	}
-	if rows == nil {
-		rows = []caseWithCounts{}
+		return 0, nil, err
 	}
 
 	// Map to stable DTO
@@ -187,21 +315,17 @@ func (h *Handler) ListMine(c *fiber.Ctx) error {
 	for _, r := range rows {
 		items = append(items, CaseListItem{
 			ID:        r.ID.String(),
+			Reference: r.Reference,
 			Title:     r.Title,
 			Category:  r.Category,
 			Status:    r.Status,
-			CreatedAt: r.CreatedAt.Format(time.RFC3339),
+			CreatedAt: apitime.Format(r.CreatedAt),
+			UpdatedAt: apitime.Format(r.UpdatedAt),
 			Quotes:    r.Quotes,
+			Files:     r.Files,
 		})
 	}
-
-	return c.JSON(fiber.Map{
-		"page":     page,
-		"pageSize": size,
-		"total":    total,
-		"pages":    int(math.Ceil(float64(total) / float64(size))),
-		"items":    items, // always [] when empty
-	})
+	return total, items, nil
 }
 
 /* ===================== Public Counterpart Profiles ======================= */
@@ -212,52 +336,74 @@ type PublicUser struct {
 	Email        string    `json:"email,omitempty"`
 	Jurisdiction string    `json:"jurisdiction,omitempty"`
 	BarNumber    string    `json:"bar_number,omitempty"`
+
+	// Counterpart of an engaged/closed case only
+	ContactPhone     string `json:"contact_phone,omitempty"`
+	PreferredContact string `json:"preferred_contact,omitempty"`
+}
+
+// CasePayment is the accepted lawyer's view of the engagement payment
+// (no provider IDs).
+type CasePayment struct {
+	Status models.PayStatus `json:"status"`
+	PaidAt *time.Time       `json:"paid_at,omitempty"`
 }
 
 type CaseDetailResponse struct {
 	models.Case
-	AcceptedLawyer *PublicUser `json:"accepted_lawyer,omitempty"`
-	Client         *PublicUser `json:"client,omitempty"`
+	AcceptedLawyer *PublicUser  `json:"accepted_lawyer,omitempty"`
+	Client         *PublicUser  `json:"client,omitempty"`
+	Payment        *CasePayment `json:"payment,omitempty"` // lawyer view only
 }
 
 // fetchPublicUser returns a minimal public profile.
-// If withLawyerFields is true, include lawyer-only fields.
-func (h *Handler) fetchPublicUser(uID uuid.UUID, withLawyerFields bool) *PublicUser {
+// If withLawyerFields is true, include lawyer-only fields; withContact adds
+// the contact details, for the counterpart of an engaged/closed case only.
+func (h *Handler) fetchPublicUser(uID uuid.UUID, withLawyerFields, withContact bool) *PublicUser {
 	if uID == uuid.Nil {
 		return nil
 	}
 	var row struct {
-		ID           uuid.UUID
-		Name         string
-		Email        string
-		Jurisdiction string
-		BarNumber    string
+		ID               uuid.UUID
+		Name             string
+		Email            string
+		Jurisdiction     string
+		BarNumber        string
+		ContactPhone     string
+		PreferredContact string
 	}
-	q := h.db.Model(&models.User{}).Select("id, name, email")
+	cols := []string{"id", "name", "email"}
 	if withLawyerFields {
-		q = q.Select("id, name, email, jurisdiction, bar_number")
+		cols = append(cols, "jurisdiction", "bar_number")
+	}
+	if withContact {
+		cols = append(cols, "contact_phone", "preferred_contact")
 	}
-	if err := q.First(&row, "id = ?", uID).Error; err != nil {
+	if err := h.db.Model(&models.User{}).Select(cols).First(&row, "id = ?", uID).Error; err != nil {
 		return nil
 	}
 	return &PublicUser{
-		ID:           row.ID,
-		Name:         row.Name,
-		Email:        row.Email,
-		Jurisdiction: row.Jurisdiction,
-		BarNumber:    row.BarNumber,
+		ID:               row.ID,
+		Name:             row.Name,
+		Email:            row.Email,
+		Jurisdiction:     row.Jurisdiction,
+		BarNumber:        row.BarNumber,
+		ContactPhone:     row.ContactPhone,
+		PreferredContact: row.PreferredContact,
 	}
 }
 
 /* ============================== Get Detail =============================== */
 
-// @Summary      Case detail (owner or accepted lawyer)
-// @Description  Client owner or accepted lawyer (engaged/closed) can view details, files, and counterpart
+// @Summary      Case detail (owner, accepted lawyer, or admin)
+// @Description  Client owner or accepted lawyer (engaged/closed) can view details, files, and counterpart; collaborators the accepted lawyer granted access get the lawyer view. The lawyer view also carries the payment status. Admins get a read-only owner view.
 // @Tags         cases
 // @Security     BearerAuth
 // @Produce      json
-// @Param        id   path string true "case id (uuid)"
+// @Param        id             path    string  true   "case id (uuid)"
+// @Param        If-None-Match  header  string  false  "ETag of a previous response"
 // @Success      200  {object}  CaseDetailResponse
+// @Success      304  {string}  string  "not modified"
 // @Failure      401  {object}  models.ErrorResponse
 // @Failure      403  {object}  models.ErrorResponse
 // @Failure      404  {object}  models.ErrorResponse
@@ -275,9 +421,9 @@ func (h *Handler) GetDetail(c *fiber.Ctx) error {
 	userID := auth.MustUserID(c)
 	role, _ := c.Locals("role").(string)
 
-	// Load case with files (ASC) and quotes (DESC)
+	// Load case with files (ASC) and quotes (DESC); admins also see deleted cases
 	var cs models.Case
-	if err := h.db.
+	if err := h.scoped(c).
 		Preload("Files", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
 		Preload("Quotes", func(db *gorm.DB) *gorm.DB { return db.Order("created_at DESC") }).
 		First(&cs, "id = ?", id).Error; err != nil {
@@ -294,6 +440,9 @@ func (h *Handler) GetDetail(c *fiber.Ctx) error {
 	if cs.Quotes == nil {
 		cs.Quotes = []models.Quote{}
 	}
+	for i := range cs.Quotes {
+		cs.Quotes[i].Currency = money.OrDefault(cs.Quotes[i].Currency) // legacy rows
+	}
 
 	// Mask filenames in API output
 	safeFiles := make([]models.CaseFile, len(cs.Files))
@@ -304,33 +453,47 @@ func (h *Handler) GetDetail(c *fiber.Ctx) error {
 	cs.Files = safeFiles
 
 	switch role {
-	case string(models.RoleClient):
-		// Only owner client
-		if cs.ClientID.String() != userID {
+	case string(models.RoleClient), string(models.RoleAdmin):
+		// Only owner client; admins get the owner view (read-only)
+		if role == string(models.RoleAdmin) {
+			auth.LogAdminAccess(c)
+		} else if cs.ClientID.String() != userID {
 			return fiber.ErrForbidden
 		}
 
 		// Redaction policy:
-		// - OPEN: redact all notes
-		// - ENGAGED/CLOSED: show note for accepted quote; redact others
+		// - OPEN: redact all notes and pitches
+		// - ENGAGED/CLOSED: show note/pitch for accepted quote; redact others
+		// Admins may get partial masking (PII_STAFF_MASKING) for fraud review.
+		mode := sanitize.ModeFull
+		if role == string(models.RoleAdmin) {
+			mode = sanitize.StaffMode()
+		}
+		// Polling clients: unchanged case → 304 without a body
+		if notModified(c, caseETag(cs, role, fmt.Sprint(mode))) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
 		if len(cs.Quotes) > 0 {
 			safeQuotes := make([]models.Quote, len(cs.Quotes))
 			switch cs.Status {
 			case models.CaseOpen:
 				for i, q := range cs.Quotes {
-					q.Note = sanitize.RedactPII(q.Note)
+					q.Note = sanitize.RedactPIIMode(q.Note, mode)
+					q.Pitch = sanitize.RedactPIIMode(q.Pitch, mode)
 					safeQuotes[i] = q
 				}
 			case models.CaseEngaged, models.CaseClosed:
 				for i, q := range cs.Quotes {
 					if q.ID != cs.AcceptedQuoteID {
-						q.Note = sanitize.RedactPII(q.Note)
+						q.Note = sanitize.RedactPIIMode(q.Note, mode)
+						q.Pitch = sanitize.RedactPIIMode(q.Pitch, mode)
 					}
 					safeQuotes[i] = q
 				}
 			default:
 				for i, q := range cs.Quotes {
-					q.Note = sanitize.RedactPII(q.Note)
+					q.Note = sanitize.RedactPIIMode(q.Note, mode)
+					q.Pitch = sanitize.RedactPIIMode(q.Pitch, mode)
 					safeQuotes[i] = q
 				}
 			}
@@ -339,20 +502,40 @@ func (h *Handler) GetDetail(c *fiber.Ctx) error {
 
 		resp := CaseDetailResponse{Case: cs}
 		if (cs.Status == models.CaseEngaged || cs.Status == models.CaseClosed) && cs.AcceptedLawyerID != uuid.Nil {
-			resp.AcceptedLawyer = h.fetchPublicUser(cs.AcceptedLawyerID, true)
+			// Contact details go to the client (the counterpart), not to admins
+			resp.AcceptedLawyer = h.fetchPublicUser(cs.AcceptedLawyerID, true, role == string(models.RoleClient))
+		}
+		if role == string(models.RoleAdmin) {
+			resp.Client = h.fetchPublicUser(cs.ClientID, false, false)
 		}
+		normalizeDetailTimes(&resp)
 		return c.JSON(resp)
 
 	case string(models.RoleLawyer):
-		// Only accepted lawyer, and only when engaged/closed
-		if (cs.Status != models.CaseEngaged && cs.Status != models.CaseClosed) || cs.AcceptedLawyerID.String() != userID {
-			return fiber.ErrForbidden
+		// Only the accepted lawyer (or a collaborator they granted), and only
+		// when engaged/closed
+		if !h.lawyerCanRead(cs, userID) {
+			return LawyerDenied()
+		}
+
+		// The lawyer view also carries the payment status
+		payment := h.acceptedPayment(cs)
+		payTag := ""
+		if payment != nil {
+			payTag = string(payment.Status)
+			if payment.PaidAt != nil {
+				payTag += fmt.Sprint(payment.PaidAt.UnixNano())
+			}
+		}
+		if notModified(c, caseETag(cs, role, payTag)) {
+			return c.SendStatus(fiber.StatusNotModified)
 		}
 
 		// For lawyers, only return the accepted quote when present
 		if cs.AcceptedQuoteID != uuid.Nil {
 			var q models.Quote
 			if err := h.db.First(&q, "id = ?", cs.AcceptedQuoteID).Error; err == nil {
+				q.Currency = money.OrDefault(q.Currency)
 				cs.Quotes = []models.Quote{q}
 			} else {
 				cs.Quotes = []models.Quote{}
@@ -362,9 +545,11 @@ func (h *Handler) GetDetail(c *fiber.Ctx) error {
 		}
 
 		resp := CaseDetailResponse{
-			Case:   cs,
-			Client: h.fetchPublicUser(cs.ClientID, false),
+			Case:    cs,
+			Client:  h.fetchPublicUser(cs.ClientID, false, true), // engaged/closed checked above
+			Payment: payment,
 		}
+		normalizeDetailTimes(&resp)
 		return c.JSON(resp)
 
 	default:
@@ -372,66 +557,129 @@ func (h *Handler) GetDetail(c *fiber.Ctx) error {
 	}
 }
 
+// acceptedPayment returns the payment for the case's accepted quote, or nil.
+// Legacy paid rows have no paid_at; the engagement time stands in for it.
+func (h *Handler) acceptedPayment(cs models.Case) *CasePayment {
+	if cs.AcceptedQuoteID == uuid.Nil {
+		return nil
+	}
+	var p CasePayment
+	if err := h.db.Model(&models.Payment{}).
+		Select("status, paid_at").
+		Where("quote_id = ?", cs.AcceptedQuoteID).
+		Take(&p).Error; err != nil {
+		return nil
+	}
+	if p.PaidAt == nil && p.Status == models.PayPaid {
+		p.PaidAt = cs.EngagedAt
+	}
+	return &p
+}
+
+// normalizeDetailTimes puts every timestamp of a detail response in the API
+// format (see apitime). It runs after the ETag, which wants full precision.
+func normalizeDetailTimes(resp *CaseDetailResponse) {
+	cs := &resp.Case
+	cs.CreatedAt = apitime.Normalize(cs.CreatedAt)
+	cs.UpdatedAt = apitime.Normalize(cs.UpdatedAt)
+	cs.EngagedAt = apitime.NormalizePtr(cs.EngagedAt)
+	cs.CancelledAt = apitime.NormalizePtr(cs.CancelledAt)
+	cs.Deadline = apitime.NormalizePtr(cs.Deadline)
+	if cs.DeletedAt.Valid {
+		cs.DeletedAt.Time = apitime.Normalize(cs.DeletedAt.Time)
+	}
+	for i := range cs.Files {
+		cs.Files[i].CreatedAt = apitime.Normalize(cs.Files[i].CreatedAt)
+	}
+	for i := range cs.Quotes {
+		q := &cs.Quotes[i]
+		q.CreatedAt = apitime.Normalize(q.CreatedAt)
+		q.UpdatedAt = apitime.Normalize(q.UpdatedAt)
+		q.ExpiresAt = apitime.NormalizePtr(q.ExpiresAt)
+	}
+	if resp.Payment != nil {
+		resp.Payment.PaidAt = apitime.NormalizePtr(resp.Payment.PaidAt)
+	}
+}
+
 /* ============================ Marketplace ================================ */
 
 // MarketCaseItem is the list item shape for the public marketplace.
 type MarketCaseItem struct {
 	ID         uuid.UUID `json:"id"`
+	Reference  *string   `json:"reference,omitempty"` // nil on legacy cases
 	Title      string    `json:"title"`
 	Category   string    `json:"category"`
 	CreatedAt  time.Time `json:"created_at"`
 	Preview    string    `json:"preview"`
 	HasMyQuote bool      `json:"has_my_quote"` // FE can use this to disable "submit quote"
-}
 
-type PageMarketCases struct {
-	Page     int              `json:"page"`
-	PageSize int              `json:"pageSize"`
-	Total    int64            `json:"total"`
-	Pages    int              `json:"pages"`
-	Items    []MarketCaseItem `json:"items"`
+	// Client-set priority hints; omitted when not given
+	Deadline *time.Time      `json:"deadline,omitempty"` // a date: always UTC midnight
+	Urgency  *models.Urgency `json:"urgency,omitempty"`
+
+	// Competitive context: aggregates over live (proposed) quotes only
+	QuoteCount        int64 `json:"quote_count"`
+	LowestAmountCents *int  `json:"lowest_amount_cents,omitempty"` // nil when no live quotes
+
+	// How many OPEN cases the poster has right now, this one included; a
+	// count only, never who they are. Omitted unless MARKETPLACE_CLIENT_CASE_COUNT is on.
+	ClientOpenCaseCount *int64 `json:"client_open_case_count,omitempty"`
 }
 
-// appLocation returns the app TZ from env or Asia/Singapore.
-// Falls back to a fixed zone when tzdb is not available.
-func appLocation() *time.Location {
-	if tz := os.Getenv("APP_TZ"); tz != "" {
-		if loc, err := time.LoadLocation(tz); err == nil {
-			return loc
-		}
-	}
-	if loc, err := time.LoadLocation("Asia/Singapore"); err == nil {
-		return loc
+type PageMarketCases = pagination.Page[MarketCaseItem]
+
+// parseLocalDate parses a YYYY-MM-DD date as midnight in the app TZ.
+// Empty or malformed input reports false (the filter is then ignored).
+func parseLocalDate(v string) (time.Time, bool) {
+	if v == "" {
+		return time.Time{}, false
 	}
-	return time.FixedZone("SGT", 8*60*60) // UTC+8
+	t, err := time.ParseInLocation("2006-01-02", v, apptz.Location())
+	return t, err == nil
 }
 
 // @Summary      Marketplace (anonymized)
-// @Description  Lawyer browses OPEN cases (server-side filters & pagination; no client identity)
+// @Description  Lawyer browses OPEN cases (server-side filters & pagination; no client identity). With MARKETPLACE_CLIENT_CASE_COUNT on, items also carry client_open_case_count.
 // @Tags         marketplace
 // @Security     BearerAuth
 // @Produce      json
 // @Param        page          query int    false "page"
 // @Param        pageSize      query int    false "pageSize"
 // @Param        category      query string false "category"
-// @Param        created_since query string false "YYYY-MM-DD (Asia/Singapore)"
-// @Success      200  {object}  PageMarketCases
+// @Param        created_since query string false "YYYY-MM-DD (app timezone, APP_TZ)"
+// @Param        created_until query string false "YYYY-MM-DD, inclusive (app timezone, APP_TZ)"
+// @Param        exclude_quoted query bool  false "true hides cases I have already quoted"
+// @Param        sort          query string false "newest (default) | urgency (high first, then nearest deadline) | deadline (nearest first)"
+// @Success      200  {object}  pagination.Page[cases.MarketCaseItem]
+// @Failure      400  {object}  models.ErrorResponse
 // @Failure      401  {object}  models.ErrorResponse
 // @Router       /marketplace [get]
 func (h *Handler) Marketplace(c *fiber.Ctx) error {
 	lawyerID := auth.MustUserID(c) // used for HasMyQuote
-	page, size := parsePage(c)
+	page, size := pagination.Parse(c)
 	category := strings.TrimSpace(c.Query("category"))
 	createdSince := c.Query("created_since") // ISO date (YYYY-MM-DD)
+	createdUntil := c.Query("created_until") // ISO date (YYYY-MM-DD), inclusive
 
-	// Parse created_since in app TZ; store as UTC for DB queries
-	var sinceUTC *time.Time
-	if createdSince != "" {
-		loc := appLocation()
-		if localMidnight, err := time.ParseInLocation("2006-01-02", createdSince, loc); err == nil {
-			u := localMidnight.UTC()
-			sinceUTC = &u
-		}
+	// Optional: hide cases this lawyer already quoted (clean "new cases" feed)
+	excludeQuoted, _ := strconv.ParseBool(c.Query("exclude_quoted"))
+
+	order, err := marketOrder(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse both bounds in app TZ; store as UTC for DB queries
+	var sinceUTC, untilUTC *time.Time
+	if localMidnight, ok := parseLocalDate(createdSince); ok {
+		u := localMidnight.UTC()
+		sinceUTC = &u
+	}
+	if localMidnight, ok := parseLocalDate(createdUntil); ok {
+		// End of that day: everything before the next local midnight
+		u := localMidnight.AddDate(0, 0, 1).UTC()
+		untilUTC = &u
 	}
 
 	// Base query: only open cases
@@ -442,6 +690,13 @@ func (h *Handler) Marketplace(c *fiber.Ctx) error {
 	if sinceUTC != nil {
 		dbq = dbq.Where("created_at >= ?", *sinceUTC)
 	}
+	if untilUTC != nil {
+		dbq = dbq.Where("created_at < ?", *untilUTC)
+	}
+	if excludeQuoted {
+		// Anti-join before counting so total/pages describe the filtered feed
+		dbq = dbq.Where("NOT EXISTS (SELECT 1 FROM quotes WHERE quotes.case_id = cases.id AND quotes.lawyer_id = ?)", lawyerID)
+	}
 
 	// Count first
 	var total int64
@@ -451,13 +706,31 @@ func (h *Handler) Marketplace(c *fiber.Ctx) error {
 
 	// Load page
 	var list []models.Case
-	if err := dbq.Order("created_at DESC").
+	if err := dbq.Order(order).
 		Offset((page - 1) * size).
 		Limit(size).
 		Find(&list).Error; err != nil {
 		return fiber.ErrInternalServerError
 	}
 
+	items, err := h.marketItems(lawyerID, list)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(pagination.New(c, page, size, total, items))
+}
+
+// showClientCaseCount reports whether marketplace items carry
+// client_open_case_count. Env: MARKETPLACE_CLIENT_CASE_COUNT (bool, default off).
+func showClientCaseCount() bool {
+	on, _ := strconv.ParseBool(os.Getenv("MARKETPLACE_CLIENT_CASE_COUNT"))
+	return on
+}
+
+// marketItems turns a page of open cases into anonymized marketplace items:
+// redacted preview, live-quote aggregates, whether lawyerID has quoted and,
+// when enabled, the poster's open case count.
+func (h *Handler) marketItems(lawyerID string, list []models.Case) ([]MarketCaseItem, error) {
 	// IDs on page
 	caseIDs := make([]uuid.UUID, 0, len(list))
 	for _, cs := range list {
@@ -472,43 +745,180 @@ func (h *Handler) Marketplace(c *fiber.Ctx) error {
 			Model(&models.Quote{}).
 			Where("lawyer_id = ? AND case_id IN ?", lawyerID, caseIDs).
 			Pluck("DISTINCT case_id", &quotedIDs).Error; err != nil {
-			return fiber.ErrInternalServerError
+			return nil, err
 		}
 		for _, qid := range quotedIDs {
 			quotedMap[qid] = true
 		}
 	}
 
+	// Quote count + lowest amount per case in one grouped query (no identities/notes)
+	type quoteStats struct {
+		CaseID uuid.UUID
+		Count  int64
+		Lowest int
+	}
+	statsMap := map[uuid.UUID]quoteStats{}
+	if len(caseIDs) > 0 {
+		var stats []quoteStats
+		if err := h.db.
+			Model(&models.Quote{}).
+			Select("case_id, COUNT(*) AS count, MIN(amount_cents) AS lowest").
+			Where("case_id IN ? AND status = ?", caseIDs, models.QuoteProposed).
+			Group("case_id").
+			Scan(&stats).Error; err != nil {
+			return nil, err
+		}
+		for _, st := range stats {
+			statsMap[st.CaseID] = st
+		}
+	}
+
+	// Open cases per poster in one grouped query; keyed by client inside
+	// the handler only, the ID itself never leaves it
+	var openByClient map[uuid.UUID]int64
+	if showClientCaseCount() && len(list) > 0 {
+		clientIDs := make([]uuid.UUID, 0, len(list))
+		for _, cs := range list {
+			clientIDs = append(clientIDs, cs.ClientID)
+		}
+		var counts []struct {
+			ClientID uuid.UUID
+			N        int64
+		}
+		if err := h.db.
+			Model(&models.Case{}).
+			Select("client_id, COUNT(*) AS n").
+			Where("client_id IN ? AND status = ?", clientIDs, models.CaseOpen).
+			Group("client_id").
+			Scan(&counts).Error; err != nil {
+			return nil, err
+		}
+		openByClient = make(map[uuid.UUID]int64, len(counts))
+		for _, r := range counts {
+			openByClient[r.ClientID] = r.N
+		}
+	}
+
 	// Build items with redacted preview
 	items := make([]MarketCaseItem, 0, len(list))
 	for _, cs := range list {
 		preview := sanitize.Summary(sanitize.RedactPII(cs.Description), 240)
-		items = append(items, MarketCaseItem{
+		item := MarketCaseItem{
 			ID:         cs.ID,
+			Reference:  cs.Reference,
 			Title:      cs.Title,
 			Category:   cs.Category,
-			CreatedAt:  cs.CreatedAt,
+			CreatedAt:  apitime.Normalize(cs.CreatedAt),
 			Preview:    preview,
 			HasMyQuote: quotedMap[cs.ID],
-		})
+			Deadline:   cs.Deadline,
+			Urgency:    cs.Urgency,
+		}
+		if st, ok := statsMap[cs.ID]; ok {
+			item.QuoteCount = st.Count
+			item.LowestAmountCents = &st.Lowest
+		}
+		if openByClient != nil {
+			n := openByClient[cs.ClientID]
+			item.ClientOpenCaseCount = &n
+		}
+		items = append(items, item)
 	}
-	if items == nil {
-		items = []MarketCaseItem{}
+	return items, nil
+}
+
+// marketOrder maps the marketplace ?sort to an ORDER BY. Cases without an
+// urgency or deadline go after those with one; ties fall back to newest.
+func marketOrder(c *fiber.Ctx) (string, error) {
+	switch c.Query("sort") {
+	case "", "newest":
+		return "created_at DESC", nil
+	case "urgency":
+		return "CASE urgency WHEN 'high' THEN 0 WHEN 'normal' THEN 1 WHEN 'low' THEN 2 ELSE 3 END, " +
+			"deadline ASC NULLS LAST, created_at DESC", nil
+	case "deadline":
+		return "deadline ASC NULLS LAST, created_at DESC", nil
+	default:
+		return "", fiber.NewError(fiber.StatusBadRequest, "invalid sort")
 	}
+}
 
-	return c.JSON(PageMarketCases{
-		Page:     page,
-		PageSize: size,
-		Total:    total,
-		Pages:    int(math.Ceil(float64(total) / float64(size))),
-		Items:    items,
-	})
+// MarketCategory is one category with open cases in the marketplace.
+type MarketCategory struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+// @Summary      Marketplace categories
+// @Description  Distinct categories among OPEN cases with a count each, most cases first (for building the category filter)
+// @Tags         marketplace
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   MarketCategory
+// @Failure      401  {object}  models.ErrorResponse
+// @Router       /marketplace/categories [get]
+func (h *Handler) MarketCategories(c *fiber.Ctx) error {
+	out := make([]MarketCategory, 0)
+	if err := h.db.Model(&models.Case{}).
+		Select("category, COUNT(*) AS count").
+		Where("status = ?", models.CaseOpen).
+		Group("category").
+		Order("count DESC, category").
+		Scan(&out).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(out)
+}
+
+// marketStatsDays is the "new cases" window of MarketStats, in app TZ days
+// counting today.
+const marketStatsDays = 7
+
+// MarketCategoryStats is the demand in one category of the marketplace.
+type MarketCategoryStats struct {
+	Category string `json:"category"`
+	Open     int64  `json:"open"`      // OPEN cases right now
+	NewCases int64  `json:"new_cases"` // of those, posted since MarketStats.Since
+}
+
+// MarketStats is the lawyer landing page summary of marketplace demand.
+type MarketStats struct {
+	Since      time.Time             `json:"since"` // start of the window: midnight app TZ, 6 days before today
+	Categories []MarketCategoryStats `json:"categories"`
+}
+
+// @Summary      Marketplace demand by category
+// @Description  Per category: OPEN cases now and how many of them were posted in the last 7 days (today and the 6 days before, app timezone APP_TZ). Most open cases first.
+// @Tags         marketplace
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  MarketStats
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /marketplace/stats [get]
+func (h *Handler) MarketStats(c *fiber.Ctx) error {
+	now := time.Now().In(apptz.Location())
+	y, m, d := now.Date()
+	since := time.Date(y, m, d-(marketStatsDays-1), 0, 0, 0, 0, now.Location())
+
+	out := MarketStats{Since: apitime.Normalize(since), Categories: make([]MarketCategoryStats, 0)}
+	if err := h.db.Model(&models.Case{}).
+		Select("category, COUNT(*) AS open, COUNT(*) FILTER (WHERE created_at >= ?) AS new_cases", since.UTC()).
+		Where("status = ?", models.CaseOpen).
+		Group("category").
+		Order("open DESC, category").
+		Scan(&out.Categories).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(out)
 }
 
 /* ============================= Cancel Case =============================== */
 
 // @Summary      Cancel case
-// @Description  Client cancels their own case (only if still open)
+// @Description  Client cancels their own case (only if still open or paused). Repeating it on a case the client already cancelled is a no-op 200.
 // @Tags         cases
 // @Security     BearerAuth
 // @Accept       json
@@ -542,13 +952,21 @@ func (h *Handler) Cancel(c *fiber.Ctx) error {
 	if cs.ClientID.String() != clientID {
 		return fiber.ErrForbidden
 	}
-	if cs.Status != models.CaseOpen {
-		return fiber.NewError(fiber.StatusConflict, "case cannot be cancelled")
+	// A repeat (e.g. double-click) of the client's own cancel is a no-op
+	if cs.Status == models.CaseCancelled && h.movedBy(cs.ID, models.CaseCancelled, clientID) {
+		return c.JSON(fiber.Map{"status": "cancelled"})
+	}
+	if cs.Status != models.CaseOpen && cs.Status != models.CasePaused {
+		return apperr.Conflict(apperr.CaseNotCancellable, "case cannot be cancelled")
 	}
 
 	// Update
 	old := cs.Status
-	if err := h.db.Model(&cs).Update("status", models.CaseCancelled).Error; err != nil {
+	now := time.Now()
+	if err := h.db.Model(&cs).Updates(map[string]any{
+		"status":       models.CaseCancelled,
+		"cancelled_at": &now,
+	}).Error; err != nil {
 		return fiber.ErrInternalServerError
 	}
 
@@ -558,6 +976,7 @@ func (h *Handler) Cancel(c *fiber.Ctx) error {
 		h.db,
 		cs.ID,
 		uuid.MustParse(clientID),
+		models.ActorUser,
 		"cancelled",
 		old,
 		models.CaseCancelled,
@@ -567,10 +986,290 @@ func (h *Handler) Cancel(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"status": "cancelled"})
 }
 
+// movedBy reports whether the latest history entry that moved the case into
+// status was made by actorID, i.e. a repeat of that user's own transition.
+func (h *Handler) movedBy(caseID uuid.UUID, status models.CaseStatus, actorID string) bool {
+	var last models.CaseHistory
+	if err := h.db.Where("case_id = ? AND new_status = ?", caseID, status).
+		Order("created_at DESC").First(&last).Error; err != nil {
+		return false
+	}
+	return last.ActorID.String() == actorID
+}
+
+/* ============================= Delete Case =============================== */
+
+// @Summary      Delete case
+// @Description  Client soft-deletes their own case (not once engaged/closed, nor while a checkout on one of its quotes is in progress). It disappears from listings; files, quotes and history are kept for audit.
+// @Tags         cases
+// @Security     BearerAuth
+// @Accept       json
+// @Param        id       path  string         true "case id (uuid)"
+// @Param        payload  body  ActionRequest  false "Optional comment"
+// @Success      200  {object}  map[string]string  "status"
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse  "CASE_NOT_DELETABLE, CASE_IN_CHECKOUT"
+// @Router       /cases/{id} [delete]
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	clientID := auth.MustUserID(c)
+	id := c.Params("id")
+
+	// Optional comment
+	var in ActionRequest
+	_ = c.BodyParser(&in)
+	if errs, _ := validation.Validate(in); errs != nil {
+		return validation.Respond(c, errs)
+	}
+
+	// Load + authorize
+	var cs models.Case
+	if err := h.db.First(&cs, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	if cs.ClientID.String() != clientID {
+		return fiber.ErrForbidden
+	}
+	// Engaged/closed cases carry a paid engagement; they stay visible
+	if cs.Status == models.CaseEngaged || cs.Status == models.CaseClosed {
+		return apperr.Conflict(apperr.CaseNotDeletable, "case cannot be deleted")
+	}
+	// A checkout in flight could still complete against the case
+	if inCheckout, err := h.checkoutInFlight(cs.ID); err != nil {
+		return fiber.ErrInternalServerError
+	} else if inCheckout {
+		return apperr.Conflict(apperr.CaseInCheckout, "a checkout is in progress on this case; it can't be deleted")
+	}
+
+	// Soft delete (sets deleted_at)
+	if err := h.db.Delete(&cs).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	// History (status itself is unchanged)
+	utils.LogCaseHistory(
+		c.Context(),
+		h.db,
+		cs.ID,
+		uuid.MustParse(clientID),
+		models.ActorUser,
+		"deleted",
+		cs.Status,
+		cs.Status,
+		strings.TrimSpace(in.Comment),
+	)
+
+	return c.JSON(fiber.Map{"status": "deleted"})
+}
+
+// checkoutInFlight reports whether the case has a payment that isn't FAILED
+// (a Stripe session that can still complete, or one already paid).
+func (h *Handler) checkoutInFlight(caseID uuid.UUID) (bool, error) {
+	var n int64
+	err := h.db.Model(&models.Payment{}).
+		Where("case_id = ? AND status <> ?", caseID, models.PayFailed).
+		Count(&n).Error
+	return n > 0, err
+}
+
+/* ============================= Reopen Case =============================== */
+
+// reopenGraceWindow returns how long after cancelling a case can be reopened.
+// Reads CASE_REOPEN_GRACE (Go duration, e.g. "72h"); defaults to 72 hours.
+func reopenGraceWindow() time.Duration {
+	if v := os.Getenv("CASE_REOPEN_GRACE"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 72 * time.Hour
+}
+
+// @Summary      Reopen case
+// @Description  Client reopens their own cancelled case (never engaged, within the grace window). Files and quotes are kept.
+// @Tags         cases
+// @Security     BearerAuth
+// @Accept       json
+// @Param        id       path  string         true  "case id (uuid)"
+// @Param        payload  body  ActionRequest  false "Optional comment"
+// @Success      200  {object}  map[string]string  "status"
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Router       /cases/{id}/reopen [post]
+func (h *Handler) Reopen(c *fiber.Ctx) error {
+	clientID := auth.MustUserID(c)
+	id := c.Params("id")
+
+	// Optional comment
+	var in ActionRequest
+	_ = c.BodyParser(&in)
+	if errs, _ := validation.Validate(in); errs != nil {
+		return validation.Respond(c, errs)
+	}
+
+	// Load + authorize
+	var cs models.Case
+	if err := h.db.First(&cs, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	if cs.ClientID.String() != clientID {
+		return fiber.ErrForbidden
+	}
+	if cs.Status != models.CaseCancelled {
+		return apperr.Conflict(apperr.CaseNotReopenable, "only cancelled cases can be reopened")
+	}
+	// A case that was ever engaged keeps its engagement trail; never reopen it
+	if cs.EngagedAt != nil || cs.AcceptedQuoteID != uuid.Nil {
+		return apperr.Conflict(apperr.CaseNotReopenable, "engaged cases cannot be reopened")
+	}
+	if cs.CancelledAt == nil || time.Since(*cs.CancelledAt) > reopenGraceWindow() {
+		return apperr.Conflict(apperr.ReopenWindowExpired, "reopen window has expired")
+	}
+
+	// Update (existing quotes and files are left untouched)
+	old := cs.Status
+	if err := h.db.Model(&cs).Updates(map[string]any{
+		"status":       models.CaseOpen,
+		"cancelled_at": nil,
+	}).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	// History
+	utils.LogCaseHistory(
+		c.Context(),
+		h.db,
+		cs.ID,
+		uuid.MustParse(clientID),
+		models.ActorUser,
+		"reopened",
+		old,
+		models.CaseOpen,
+		strings.TrimSpace(in.Comment),
+	)
+
+	return c.JSON(fiber.Map{"status": "open"})
+}
+
+/* ========================== Pause / Resume Case ========================== */
+
+// @Summary      Pause case
+// @Description  Client hides their own OPEN case from the marketplace (e.g. while gathering documents). Quotes and files are kept; lawyers can't quote until it is resumed. Not while a checkout on one of its quotes is in progress.
+// @Tags         cases
+// @Security     BearerAuth
+// @Accept       json
+// @Param        id       path  string         true  "case id (uuid)"
+// @Param        payload  body  ActionRequest  false "Optional comment"
+// @Success      200  {object}  map[string]string  "status"
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse  "CASE_NOT_PAUSABLE, CASE_IN_CHECKOUT"
+// @Router       /cases/{id}/pause [post]
+func (h *Handler) Pause(c *fiber.Ctx) error {
+	return h.setPaused(c, true)
+}
+
+// @Summary      Resume case
+// @Description  Client puts their own PAUSED case back on the marketplace
+// @Tags         cases
+// @Security     BearerAuth
+// @Accept       json
+// @Param        id       path  string         true  "case id (uuid)"
+// @Param        payload  body  ActionRequest  false "Optional comment"
+// @Success      200  {object}  map[string]string  "status"
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Router       /cases/{id}/resume [post]
+func (h *Handler) Resume(c *fiber.Ctx) error {
+	return h.setPaused(c, false)
+}
+
+// setPaused moves an owner's case OPEN → PAUSED (pause) or back (resume).
+func (h *Handler) setPaused(c *fiber.Ctx, pause bool) error {
+	clientID := auth.MustUserID(c)
+	id := c.Params("id")
+
+	// Optional comment
+	var in ActionRequest
+	_ = c.BodyParser(&in)
+	if errs, _ := validation.Validate(in); errs != nil {
+		return validation.Respond(c, errs)
+	}
+
+	from, to, action := models.CaseOpen, models.CasePaused, "paused"
+	if !pause {
+		from, to, action = models.CasePaused, models.CaseOpen, "resumed"
+	}
+
+	// Load + authorize
+	var cs models.Case
+	if err := h.db.First(&cs, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	if cs.ClientID.String() != clientID {
+		return fiber.ErrForbidden
+	}
+	if cs.Status != from {
+		if pause {
+			return apperr.Conflict(apperr.CaseNotPausable, "only open cases can be paused")
+		}
+		return apperr.Conflict(apperr.CaseNotPaused, "case is not paused")
+	}
+	// A checkout in flight would complete against a case that isn't open
+	if pause {
+		if inCheckout, err := h.checkoutInFlight(cs.ID); err != nil {
+			return fiber.ErrInternalServerError
+		} else if inCheckout {
+			return apperr.Conflict(apperr.CaseInCheckout, "a checkout is in progress on this case; it can't be paused")
+		}
+	}
+
+	// Conditional update: a concurrent payment may have engaged the case
+	res := h.db.Model(&models.Case{}).
+		Where("id = ? AND status = ?", cs.ID, from).
+		Update("status", to)
+	if res.Error != nil {
+		return fiber.ErrInternalServerError
+	}
+	if res.RowsAffected == 0 {
+		return apperr.Conflict(apperr.CaseNotOpen, "case status changed, try again")
+	}
+
+	// History
+	utils.LogCaseHistory(
+		c.Context(),
+		h.db,
+		cs.ID,
+		uuid.MustParse(clientID),
+		models.ActorUser,
+		action,
+		from,
+		to,
+		strings.TrimSpace(in.Comment),
+	)
+
+	return c.JSON(fiber.Map{"status": string(to)})
+}
+
 /* ============================== Close Case =============================== */
 
 // @Summary      Close case
-// @Description  Client closes their own case (only if engaged)
+// @Description  Client closes their own case (only if engaged). Repeating it on a case the client already closed is a no-op 200.
 // @Tags         cases
 // @Security     BearerAuth
 // @Accept       json
@@ -604,8 +1303,12 @@ func (h *Handler) Close(c *fiber.Ctx) error {
 	if cs.ClientID.String() != clientID {
 		return fiber.ErrForbidden
 	}
+	// A repeat (e.g. double-click) of the client's own close is a no-op
+	if cs.Status == models.CaseClosed && h.movedBy(cs.ID, models.CaseClosed, clientID) {
+		return c.JSON(fiber.Map{"status": "closed"})
+	}
 	if cs.Status != models.CaseEngaged {
-		return fiber.NewError(fiber.StatusConflict, "only engaged cases can be closed")
+		return apperr.Conflict(apperr.CaseNotEngaged, "only engaged cases can be closed")
 	}
 
 	// Update
@@ -620,6 +1323,7 @@ func (h *Handler) Close(c *fiber.Ctx) error {
 		h.db,
 		cs.ID,
 		uuid.MustParse(clientID),
+		models.ActorUser,
 		"closed",
 		old,
 		models.CaseClosed,
@@ -631,15 +1335,58 @@ func (h *Handler) Close(c *fiber.Ctx) error {
 
 /* ============================= List History ============================== */
 
+// historyCSVHeader are the columns of the CSV history export.
+var historyCSVHeader = []string{"action", "old_status", "new_status", "reason", "actor", "created_at"}
+
+// writeHistoryCSV streams rows as an attachment. Cells that a spreadsheet
+// would evaluate as a formula are prefixed with a quote.
+func writeHistoryCSV(c *fiber.Ctx, caseID uuid.UUID, rows []models.CaseHistory) error {
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="case-`+caseID.String()+`-history.csv"`)
+
+	w := csv.NewWriter(c)
+	_ = w.Write(historyCSVHeader)
+	for _, r := range rows {
+		_ = w.Write([]string{
+			r.Action,
+			string(r.OldStatus),
+			string(r.NewStatus),
+			csvSafe(r.Reason),
+			r.ActorID.String(),
+			apitime.Format(r.CreatedAt),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
+
+// csvSafe neutralizes spreadsheet formula injection in free text.
+func csvSafe(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
 // @Summary      Case history
-// @Description  List case status changes (owner & accepted lawyer only)
+// @Description  List case status changes (owner, accepted lawyer, or admin), optionally within a date window and as a CSV download
 // @Tags         cases
 // @Security     BearerAuth
 // @Produce      json
-// @Param        id   path string true "case id (uuid)"
+// @Produce      text/csv
+// @Param        id             path    string  true   "case id (uuid)"
+// @Param        since          query   string  false  "YYYY-MM-DD (app timezone, APP_TZ)"
+// @Param        until          query   string  false  "YYYY-MM-DD, inclusive (app timezone, APP_TZ)"
+// @Param        format         query   string  false  "json (default) | csv"
+// @Param        If-None-Match  header  string  false  "ETag of a previous response"
 // @Success      200  {array}  CaseHistoryDTO
+// @Success      304  {string}  string  "not modified"
 // @Failure      401  {object} models.ErrorResponse
 // @Failure      403  {object} models.ErrorResponse
+// @Failure      400  {object} models.ErrorResponse
 // @Failure      404  {object} models.ErrorResponse
 // @Router       /cases/{id}/history [get]
 func (h *Handler) ListHistory(c *fiber.Ctx) error {
@@ -647,9 +1394,14 @@ func (h *Handler) ListHistory(c *fiber.Ctx) error {
 	userID := auth.MustUserID(c)
 	role, _ := c.Locals("role").(string)
 
-	// Load minimal fields for auth check
+	format := c.Query("format", "json")
+	if format != "json" && format != "csv" {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid format")
+	}
+
+	// Load minimal fields for auth check (admins also see deleted cases)
 	var cs models.Case
-	if err := h.db.Select("id, client_id, status, accepted_lawyer_id").
+	if err := h.scoped(c).Select("id, client_id, status, accepted_lawyer_id").
 		First(&cs, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return fiber.ErrNotFound
@@ -665,20 +1417,51 @@ func (h *Handler) ListHistory(c *fiber.Ctx) error {
 		}
 	case string(models.RoleLawyer):
 		if cs.AcceptedLawyerID.String() != userID {
-			return fiber.ErrForbidden
+			return LawyerDenied()
 		}
+	case string(models.RoleAdmin):
+		auth.LogAdminAccess(c)
 	default:
 		return fiber.ErrForbidden
 	}
 
-	// Fetch history ascending
-	var rows []models.CaseHistory
-	if err := h.db.
+	// History is append-only: entry count + newest entry identify it
+	var stamp struct {
+		N    int64
+		Last *time.Time
+	}
+	if err := h.db.Model(&models.CaseHistory{}).
+		Select("COUNT(*) AS n, MAX(created_at) AS last").
 		Where("case_id = ?", cs.ID).
-		Order("created_at ASC").
-		Find(&rows).Error; err != nil {
+		Scan(&stamp).Error; err != nil {
 		return fiber.ErrInternalServerError
 	}
+	last := ""
+	if stamp.Last != nil {
+		last = fmt.Sprint(stamp.Last.UnixNano())
+	}
+	since, until := c.Query("since"), c.Query("until")
+	if notModified(c, weakETag(cs.ID.String(), "history", fmt.Sprint(stamp.N), last, since, until, format)) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	// Fetch history ascending, optionally bounded by local dates (same
+	// parsing as the marketplace window; malformed dates are ignored)
+	q := h.db.Where("case_id = ?", cs.ID)
+	if localMidnight, ok := parseLocalDate(since); ok {
+		q = q.Where("created_at >= ?", localMidnight.UTC())
+	}
+	if localMidnight, ok := parseLocalDate(until); ok {
+		q = q.Where("created_at < ?", localMidnight.AddDate(0, 0, 1).UTC())
+	}
+	var rows []models.CaseHistory
+	if err := q.Order("created_at ASC").Find(&rows).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if format == "csv" {
+		return writeHistoryCSV(c, cs.ID, rows)
+	}
 
 	// Map to DTO
 	out := make([]CaseHistoryDTO, 0, len(rows))
@@ -690,7 +1473,8 @@ func (h *Handler) ListHistory(c *fiber.Ctx) error {
 			NewStatus: r.NewStatus,
 			Reason:    r.Reason,
 			ActorID:   r.ActorID,
-			CreatedAt: r.CreatedAt,
+			ActorType: r.ActorType,
+			CreatedAt: apitime.Normalize(r.CreatedAt),
 		})
 	}
 	return c.JSON(out)
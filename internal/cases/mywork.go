@@ -0,0 +1,143 @@
+package cases
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apitime"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/pagination"
+)
+
+// MyCaseItem is one case in the role-aware "my work" list. Clients get the
+// quote/file counts of their own cases; lawyers get their own quote on it.
+type MyCaseItem struct {
+	ID        string  `json:"id"`
+	Reference *string `json:"reference,omitempty"` // nil on legacy cases
+	Title     string  `json:"title"`
+	Category  string  `json:"category"`
+	Status    string  `json:"status"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+
+	// Client only
+	Quotes *int64 `json:"quotes,omitempty"`
+	Files  *int64 `json:"files,omitempty"`
+
+	// Lawyer only
+	MyQuoteID     *uuid.UUID `json:"my_quote_id,omitempty"`
+	MyQuoteStatus string     `json:"my_quote_status,omitempty"`
+}
+
+type PageMyCases = pagination.Page[MyCaseItem]
+
+/* ============================== My Work ================================== */
+
+// @Summary      My cases (either role)
+// @Description  Clients get their own cases (as /cases/mine); lawyers get the cases they have quoted on, with their quote's id and status. Same pagination and sort for both.
+// @Tags         cases
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page      query int false "page"
+// @Param        pageSize  query int false "pageSize"
+// @Param        sort      query string false "created_desc (default) | updated_desc (recently active)"
+// @Success      200  {object}  pagination.Page[cases.MyCaseItem]
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Router       /me/cases [get]
+func (h *Handler) MyCases(c *fiber.Ctx) error {
+	userID := auth.MustUserID(c)
+	page, size := pagination.Parse(c)
+	order, err := listOrder(c)
+	if err != nil {
+		return err
+	}
+
+	switch auth.MustRole(c) {
+	case string(models.RoleClient):
+		total, list, err := h.clientCases(userID, "", page, size, order)
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		items := make([]MyCaseItem, 0, len(list))
+		for _, cs := range list {
+			items = append(items, MyCaseItem{
+				ID:        cs.ID,
+				Reference: cs.Reference,
+				Title:     cs.Title,
+				Category:  cs.Category,
+				Status:    cs.Status,
+				CreatedAt: cs.CreatedAt,
+				UpdatedAt: cs.UpdatedAt,
+				Quotes:    &cs.Quotes,
+				Files:     &cs.Files,
+			})
+		}
+		return c.JSON(pagination.New(c, page, size, total, items))
+
+	case string(models.RoleLawyer):
+		total, items, err := h.lawyerCases(userID, page, size, order)
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		return c.JSON(pagination.New(c, page, size, total, items))
+
+	default:
+		return fiber.ErrForbidden
+	}
+}
+
+// lawyerCases returns one page of the cases the lawyer has quoted on (one
+// quote per case and lawyer), with that quote, plus the total. Soft-deleted
+// cases are left out.
+func (h *Handler) lawyerCases(lawyerID string, page, size int, order string) (int64, []MyCaseItem, error) {
+	base := h.db.Table("quotes").
+		Joins("JOIN cases ON cases.id = quotes.case_id AND cases.deleted_at IS NULL").
+		Where("quotes.lawyer_id = ?", lawyerID)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return 0, nil, err
+	}
+
+	var rows []struct {
+		ID            uuid.UUID
+		Reference     *string
+		Title         string
+		Category      string
+		Status        string
+		CreatedAt     time.Time
+		UpdatedAt     time.Time
+		MyQuoteID     uuid.UUID
+		MyQuoteStatus string
+	}
+	if err := base.Session(&gorm.Session{}).
+		Select(`cases.id, cases.reference, cases.title, cases.category, cases.status, cases.created_at,
+          cases.updated_at, quotes.id AS my_quote_id, quotes.status AS my_quote_status`).
+		Order(order).
+		Offset((page - 1) * size).Limit(size).
+		Scan(&rows).Error; err != nil {
+		return 0, nil, err
+	}
+
+	items := make([]MyCaseItem, 0, len(rows))
+	for _, r := range rows {
+		items = append(items, MyCaseItem{
+			ID:            r.ID.String(),
+			Reference:     r.Reference,
+			Title:         r.Title,
+			Category:      r.Category,
+			Status:        r.Status,
+			CreatedAt:     apitime.Format(r.CreatedAt),
+			UpdatedAt:     apitime.Format(r.UpdatedAt),
+			MyQuoteID:     &r.MyQuoteID,
+			MyQuoteStatus: r.MyQuoteStatus,
+		})
+	}
+	return total, items, nil
+}
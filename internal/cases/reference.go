@@ -0,0 +1,30 @@
+package cases
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/apptz"
+)
+
+// caseRefSeq numbers case references. Postgres sequences hand out each value
+// exactly once, even to concurrent transactions, and never roll back.
+const caseRefSeq = "case_ref_seq"
+
+// EnsureReferenceSequence creates the case reference sequence (idempotent).
+// Run it at startup after migrations.
+func EnsureReferenceSequence(db *gorm.DB) error {
+	return db.Exec("CREATE SEQUENCE IF NOT EXISTS " + caseRefSeq).Error
+}
+
+// nextReference draws the next number and formats it as LMP-<year>-<number>,
+// e.g. LMP-2024-000123 (year in the app TZ; numbers keep growing across years).
+func nextReference(db *gorm.DB, now time.Time) (string, error) {
+	var n int64
+	if err := db.Raw("SELECT nextval('" + caseRefSeq + "')").Scan(&n).Error; err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("LMP-%d-%06d", now.In(apptz.Location()).Year(), n), nil
+}
@@ -0,0 +1,119 @@
+package cases
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/pagination"
+)
+
+/* ============================ Saved Cases ================================ */
+
+// @Summary      Save a marketplace case
+// @Description  Lawyer bookmarks an OPEN case to come back to (idempotent)
+// @Tags         marketplace
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "case id (uuid)"
+// @Success      200  {object}  map[string]string  "status: saved"
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse  "CASE_NOT_OPEN"
+// @Router       /marketplace/{id}/save [post]
+func (h *Handler) SaveCase(c *fiber.Ctx) error {
+	lawyerID, _ := uuid.Parse(auth.MustUserID(c))
+	caseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid case id")
+	}
+
+	var cs models.Case
+	if err := h.db.Select("id, status").First(&cs, "id = ?", caseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	if cs.Status != models.CaseOpen {
+		return apperr.Conflict(apperr.CaseNotOpen, "only open cases can be saved")
+	}
+
+	if err := h.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.SavedCase{LawyerID: lawyerID, CaseID: cs.ID}).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(fiber.Map{"status": "saved"})
+}
+
+// @Summary      Unsave a marketplace case
+// @Description  Lawyer removes a bookmark (idempotent; also works once the case has left the marketplace)
+// @Tags         marketplace
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "case id (uuid)"
+// @Success      200  {object}  map[string]string  "status: unsaved"
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Router       /marketplace/{id}/save [delete]
+func (h *Handler) UnsaveCase(c *fiber.Ctx) error {
+	lawyerID := auth.MustUserID(c)
+	caseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid case id")
+	}
+
+	if err := h.db.Where("lawyer_id = ? AND case_id = ?", lawyerID, caseID).
+		Delete(&models.SavedCase{}).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(fiber.Map{"status": "unsaved"})
+}
+
+// @Summary      My saved cases
+// @Description  Lawyer lists their saved cases that are still OPEN, most recently saved first, in the anonymized marketplace shape. Cases that were engaged, paused, closed or deleted drop out (and come back if reopened).
+// @Tags         marketplace
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page      query int false "page"
+// @Param        pageSize  query int false "pageSize"
+// @Success      200  {object}  pagination.Page[cases.MarketCaseItem]
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /marketplace/saved [get]
+func (h *Handler) ListSaved(c *fiber.Ctx) error {
+	lawyerID := auth.MustUserID(c)
+	page, size := pagination.Parse(c)
+
+	// Model keeps the soft-delete scope; the status filter hides cases that
+	// left the marketplace
+	base := h.db.Model(&models.Case{}).
+		Joins("JOIN saved_cases ON saved_cases.case_id = cases.id AND saved_cases.lawyer_id = ?", lawyerID).
+		Where("cases.status = ?", models.CaseOpen)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	var list []models.Case
+	if err := base.Session(&gorm.Session{}).
+		Order("saved_cases.created_at DESC").
+		Offset((page - 1) * size).Limit(size).
+		Find(&list).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	items, err := h.marketItems(lawyerID, list)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(pagination.New(c, page, size, total, items))
+}
@@ -0,0 +1,102 @@
+package cases
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/money"
+)
+
+// ClientStats is the client dashboard summary (deleted cases are left out).
+type ClientStats struct {
+	Open           int64 `json:"open"`
+	Paused         int64 `json:"paused"`
+	Engaged        int64 `json:"engaged"`
+	Closed         int64 `json:"closed"`
+	Cancelled      int64 `json:"cancelled"`
+	QuotesReceived int64 `json:"quotes_received"` // every quote on my cases, whatever its status
+	// Paid payments, per currency (ISO-4217 → cents)
+	SpentCents map[string]int64 `json:"spent_cents"`
+	// Distinct lawyers ever engaged (also on cases cancelled or closed since)
+	LawyersEngaged int64 `json:"lawyers_engaged"`
+}
+
+/* ========================= Client: Dashboard Stats ======================== */
+
+// @Summary      My dashboard stats (client)
+// @Description  Client's case counts by status, quotes received, spend per currency and distinct lawyers engaged (aggregates only)
+// @Tags         cases
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  ClientStats
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /clients/me/stats [get]
+func (h *Handler) Stats(c *fiber.Ctx) error {
+	clientID := auth.MustUserID(c)
+	out := ClientStats{SpentCents: map[string]int64{}}
+
+	// Cases by status, plus distinct engaged lawyers (Model keeps the soft-delete scope)
+	var byStatus []struct {
+		Status models.CaseStatus
+		N      int64
+	}
+	if err := h.db.Model(&models.Case{}).
+		Select("status, COUNT(*) AS n").
+		Where("client_id = ?", clientID).
+		Group("status").
+		Scan(&byStatus).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	for _, r := range byStatus {
+		switch r.Status {
+		case models.CaseOpen:
+			out.Open = r.N
+		case models.CasePaused:
+			out.Paused = r.N
+		case models.CaseEngaged:
+			out.Engaged = r.N
+		case models.CaseClosed:
+			out.Closed = r.N
+		case models.CaseCancelled:
+			out.Cancelled = r.N
+		}
+	}
+	if err := h.db.Model(&models.Case{}).
+		Select("COUNT(DISTINCT accepted_lawyer_id)").
+		Where("client_id = ? AND accepted_lawyer_id <> ?", clientID, uuid.Nil).
+		Scan(&out.LawyersEngaged).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	// Quotes received across my live cases
+	if err := h.db.Model(&models.Quote{}).
+		Joins("JOIN cases ON cases.id = quotes.case_id").
+		Where("cases.client_id = ? AND cases.deleted_at IS NULL", clientID).
+		Count(&out.QuotesReceived).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	// Spend: paid payments, per currency
+	var spent []struct {
+		Currency string
+		Cents    int64
+	}
+	if err := h.db.Model(&models.Payment{}).
+		Select("COALESCE(currency, '') AS currency, SUM(amount_cents) AS cents").
+		Where("client_id = ? AND status = ?", clientID, models.PayPaid).
+		Group("COALESCE(currency, '')").
+		Scan(&spent).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	for _, r := range spent {
+		out.SpentCents[money.OrDefault(r.Currency)] += r.Cents // legacy rows merge into the default
+	}
+
+	// Per-user data: browsers may reuse it briefly, shared caches must not
+	c.Set(fiber.HeaderCacheControl, "private, max-age=60")
+	return c.JSON(out)
+}
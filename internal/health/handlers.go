@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+/* =============================== Types =================================== */
+
+// Pinger is any dependency that can report whether it is reachable.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// ReadyResponse lists each dependency as "ok" or the error it returned.
+type ReadyResponse struct {
+	Status string            `json:"status"` // ok | unavailable
+	Checks map[string]string `json:"checks"`
+}
+
+/* ============================== Handler ================================== */
+
+type Handler struct {
+	db      *gorm.DB
+	storage Pinger // optional; checked only when HEALTH_CHECK_STORAGE=true
+}
+
+func NewHandler(db *gorm.DB, storage Pinger) *Handler {
+	return &Handler{db: db, storage: storage}
+}
+
+// timeout bounds each dependency check.
+// Env: HEALTH_TIMEOUT (Go duration, default 2s).
+func timeout() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("HEALTH_TIMEOUT")); err == nil && d > 0 {
+		return d
+	}
+	return 2 * time.Second
+}
+
+// pingDB checks the connection pool behind gorm.
+func (h *Handler) pingDB(ctx context.Context) error {
+	if h.db == nil {
+		return errors.New("database not configured")
+	}
+	sqlDB, err := h.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Live is a cheap check that the process is serving requests (no dependencies).
+// Mounted outside /api, so it is not part of the Swagger spec.
+func (h *Handler) Live(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// Ready pings the database (and storage when HEALTH_CHECK_STORAGE=true),
+// returning 503 with per-check details when any of them fails.
+func (h *Handler) Ready(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), timeout())
+	defer cancel()
+
+	out := ReadyResponse{Status: "ok", Checks: map[string]string{}}
+	record := func(name string, err error) {
+		if err != nil {
+			out.Status = "unavailable"
+			out.Checks[name] = err.Error()
+			return
+		}
+		out.Checks[name] = "ok"
+	}
+
+	record("database", h.pingDB(ctx))
+	if h.storage != nil {
+		if ok, _ := strconv.ParseBool(os.Getenv("HEALTH_CHECK_STORAGE")); ok {
+			record("storage", h.storage.Ping(ctx))
+		}
+	}
+
+	if out.Status != "ok" {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(out)
+	}
+	return c.JSON(out)
+}
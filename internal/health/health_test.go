@@ -0,0 +1,67 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+/* ============================================================================
+   Helpers
+   ============================================================================ */
+
+// newTestApp exposes the health endpoints.
+func newTestApp(h *Handler) *fiber.App {
+	app := fiber.New()
+	app.Get("/health/live", h.Live)
+	app.Get("/health/ready", h.Ready)
+	return app
+}
+
+// getReady calls /health/ready and decodes the body.
+func getReady(t *testing.T, app *fiber.App) (int, ReadyResponse) {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest("GET", "/health/ready", nil), 5000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out ReadyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, out
+}
+
+/* ============================================================================
+   Tests
+   ============================================================================ */
+
+// Readiness fails with 503 when there is no usable database; liveness doesn't care.
+func Test_Ready_NilOrBrokenDB_503(t *testing.T) {
+	t.Setenv("HEALTH_TIMEOUT", "1s")
+
+	// Nothing listens on port 1, so every ping is refused
+	broken, err := gorm.Open(postgres.Open("postgres://u:p@127.0.0.1:1/x?sslmode=disable"),
+		&gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, db := range map[string]*gorm.DB{"nil": nil, "broken": broken} {
+		app := newTestApp(NewHandler(db, nil))
+
+		code, out := getReady(t, app)
+		if code != 503 || out.Status != "unavailable" || out.Checks["database"] == "" || out.Checks["database"] == "ok" {
+			t.Fatalf("%s: want 503 with database error, got %d %+v", name, code, out)
+		}
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/health/live", nil))
+		if err != nil || resp.StatusCode != 200 {
+			t.Fatalf("%s: live should stay 200, got %v (err=%v)", name, resp.StatusCode, err)
+		}
+	}
+}
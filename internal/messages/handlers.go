@@ -0,0 +1,158 @@
+package messages
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/pagination"
+	"github.com/aldoetobex/legal-mp-backend/pkg/validation"
+)
+
+/* =============================== DTOs ==================================== */
+
+type SendMessageRequest struct {
+	Body string `json:"body" validate:"required,max=2000"`
+}
+
+type MessageItem struct {
+	ID        uuid.UUID `json:"id"`
+	CaseID    uuid.UUID `json:"case_id"`
+	SenderID  uuid.UUID `json:"sender_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PageMessages = pagination.Page[MessageItem]
+
+/* ============================== Handler ================================== */
+
+type Handler struct{ db *gorm.DB }
+
+func NewHandler(db *gorm.DB) *Handler { return &Handler{db: db} }
+
+/* ============================== Helpers ================================== */
+
+// participantCase loads the case in :id and checks the caller is one of its
+// two parties (owning client or accepted lawyer) and that it is engaged/closed.
+// The parties are already engaged, so messages are not PII-redacted.
+func (h *Handler) participantCase(c *fiber.Ctx) (models.Case, error) {
+	var cs models.Case
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return cs, fiber.NewError(fiber.StatusBadRequest, "invalid case id")
+	}
+	if err := h.db.First(&cs, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return cs, fiber.ErrNotFound
+		}
+		return cs, fiber.ErrInternalServerError
+	}
+
+	userID := auth.MustUserID(c)
+	isParty := cs.ClientID.String() == userID ||
+		(cs.AcceptedLawyerID != uuid.Nil && cs.AcceptedLawyerID.String() == userID)
+	if !isParty {
+		return cs, fiber.ErrForbidden
+	}
+	if cs.Status != models.CaseEngaged && cs.Status != models.CaseClosed {
+		return cs, fiber.NewError(fiber.StatusForbidden, "messaging opens once the case is engaged")
+	}
+	return cs, nil
+}
+
+/* ================================ Send =================================== */
+
+// @Summary      Send a case message
+// @Description  Owning client or accepted lawyer posts a message on an engaged/closed case (not redacted)
+// @Tags         messages
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string              true  "case id (uuid)"
+// @Param        payload  body  SendMessageRequest  true  "message"
+// @Success      201  {object}  MessageItem
+// @Failure      400  {object}  models.ValidationErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Router       /cases/{id}/messages [post]
+func (h *Handler) Send(c *fiber.Ctx) error {
+	cs, err := h.participantCase(c)
+	if err != nil {
+		return err
+	}
+
+	var in SendMessageRequest
+	if err := c.BodyParser(&in); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid json")
+	}
+	in.Body = strings.TrimSpace(in.Body)
+	if errs, _ := validation.Validate(in); errs != nil {
+		return validation.Respond(c, errs)
+	}
+
+	senderID, _ := uuid.Parse(auth.MustUserID(c))
+	m := models.Message{CaseID: cs.ID, SenderID: senderID, Body: in.Body}
+	if err := h.db.Create(&m).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(MessageItem{
+		ID:        m.ID,
+		CaseID:    m.CaseID,
+		SenderID:  m.SenderID,
+		Body:      m.Body,
+		CreatedAt: m.CreatedAt,
+	})
+}
+
+/* ================================ List =================================== */
+
+// @Summary      List case messages
+// @Description  Owning client or accepted lawyer reads the conversation, oldest first (paginated)
+// @Tags         messages
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id        path   string  true   "case id (uuid)"
+// @Param        page      query  int     false  "page"
+// @Param        pageSize  query  int     false  "pageSize (default 10)"
+// @Success      200  {object}  pagination.Page[messages.MessageItem]
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Router       /cases/{id}/messages [get]
+func (h *Handler) List(c *fiber.Ctx) error {
+	cs, err := h.participantCase(c)
+	if err != nil {
+		return err
+	}
+	page, size := pagination.Parse(c)
+
+	q := h.db.Model(&models.Message{}).Where("case_id = ?", cs.ID)
+
+	// Count before pagination
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	items := make([]MessageItem, 0, size)
+	if err := q.
+		Select("id, case_id, sender_id, body, created_at").
+		Order("created_at ASC, id ASC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Scan(&items).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(pagination.New(c, page, size, total, items))
+}
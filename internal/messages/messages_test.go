@@ -0,0 +1,187 @@
+package messages
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+/* ============================================================================
+   Helpers
+   ============================================================================ */
+
+// openTestDB connects to TEST_DATABASE_URL, migrates tables, and truncates them
+// after the test finishes.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	_ = godotenv.Load()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_URL is empty")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.User{}, &models.Case{}, &models.CaseFile{},
+		&models.Quote{}, &models.Message{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	t.Cleanup(func() {
+		sql := `
+TRUNCATE TABLE
+	messages,
+	case_files,
+	quotes,
+	cases,
+	users
+RESTART IDENTITY CASCADE`
+		if err := db.Exec(sql).Error; err != nil {
+			t.Logf("truncate failed (ignored): %v", err)
+		}
+	})
+
+	return db
+}
+
+// injectAuth sets Locals so MustUserID reads identity properly.
+func injectAuth(userID uuid.UUID, role string) fiber.Handler {
+	id := userID.String()
+	return func(c *fiber.Ctx) error {
+		c.Locals("userID", id)
+		c.Locals("role", role)
+		return c.Next()
+	}
+}
+
+func newTestApp(h *Handler, userID uuid.UUID, role string) *fiber.App {
+	app := fiber.New()
+	app.Use(injectAuth(userID, role))
+	app.Post("/api/cases/:id/messages", h.Send)
+	app.Get("/api/cases/:id/messages", h.List)
+	return app
+}
+
+// addEngagedCase inserts an engaged case between client and lawyer.
+func addEngagedCase(t *testing.T, db *gorm.DB, client, lawyer uuid.UUID) models.Case {
+	t.Helper()
+	now := time.Now()
+	cs := models.Case{
+		ClientID:         client,
+		Title:            "Lease dispute",
+		Category:         "property",
+		Status:           models.CaseEngaged,
+		EngagedAt:        &now,
+		AcceptedLawyerID: lawyer,
+	}
+	if err := db.Create(&cs).Error; err != nil {
+		t.Fatal(err)
+	}
+	return cs
+}
+
+func send(t *testing.T, app *fiber.App, caseID uuid.UUID, body string) int {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/cases/"+caseID.String()+"/messages",
+		strings.NewReader(`{"body":"`+body+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+/* ============================================================================
+   Tests — authorization
+   ============================================================================ */
+
+// Only the owning client and the accepted lawyer may read or write.
+func Test_Messages_RandomLawyerForbidden(t *testing.T) {
+	db := openTestDB(t)
+
+	client, lawyer, stranger := uuid.New(), uuid.New(), uuid.New()
+	cs := addEngagedCase(t, db, client, lawyer)
+
+	strangerApp := newTestApp(NewHandler(db), stranger, string(models.RoleLawyer))
+	if code := send(t, strangerApp, cs.ID, "hello"); code != 403 {
+		t.Fatalf("stranger send: want 403, got %d", code)
+	}
+	resp, _ := strangerApp.Test(httptest.NewRequest("GET", "/api/cases/"+cs.ID.String()+"/messages", nil))
+	if resp.StatusCode != 403 {
+		t.Fatalf("stranger list: want 403, got %d", resp.StatusCode)
+	}
+
+	// Parties can post
+	if code := send(t, newTestApp(NewHandler(db), lawyer, string(models.RoleLawyer)), cs.ID, "hi"); code != 201 {
+		t.Fatalf("lawyer send: want 201, got %d", code)
+	}
+
+	// Open case → not yet available even to its owner
+	open := models.Case{ClientID: client, Title: "Open", Category: "family", Status: models.CaseOpen}
+	if err := db.Create(&open).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code := send(t, newTestApp(NewHandler(db), client, string(models.RoleClient)), open.ID, "hi"); code != 403 {
+		t.Fatalf("open case: want 403, got %d", code)
+	}
+}
+
+/* ============================================================================
+   Tests — ordering
+   ============================================================================ */
+
+// Messages come back oldest first, across pages.
+func Test_Messages_ChronologicalAndPaginated(t *testing.T) {
+	db := openTestDB(t)
+
+	client, lawyer := uuid.New(), uuid.New()
+	cs := addEngagedCase(t, db, client, lawyer)
+	clientApp := newTestApp(NewHandler(db), client, string(models.RoleClient))
+	lawyerApp := newTestApp(NewHandler(db), lawyer, string(models.RoleLawyer))
+
+	bodies := []string{"one", "two", "three"}
+	for i, b := range bodies {
+		app := clientApp
+		if i%2 == 1 {
+			app = lawyerApp
+		}
+		if code := send(t, app, cs.ID, b); code != 201 {
+			t.Fatalf("send %q: want 201, got %d", b, code)
+		}
+	}
+
+	resp, _ := clientApp.Test(httptest.NewRequest("GET", "/api/cases/"+cs.ID.String()+"/messages?pageSize=2", nil))
+	var page PageMessages
+	_ = json.NewDecoder(resp.Body).Decode(&page)
+	if page.Total != 3 || page.Pages != 2 || len(page.Items) != 2 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	if page.Items[0].Body != "one" || page.Items[1].Body != "two" {
+		t.Fatalf("want one,two got %q,%q", page.Items[0].Body, page.Items[1].Body)
+	}
+	if page.Items[1].SenderID != lawyer {
+		t.Fatalf("second message should be from the lawyer")
+	}
+
+	resp, _ = lawyerApp.Test(httptest.NewRequest("GET", "/api/cases/"+cs.ID.String()+"/messages?pageSize=2&page=2", nil))
+	_ = json.NewDecoder(resp.Body).Decode(&page)
+	if len(page.Items) != 1 || page.Items[0].Body != "three" {
+		t.Fatalf("page 2: want [three], got %+v", page.Items)
+	}
+}
@@ -0,0 +1,176 @@
+package notifications
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/pagination"
+)
+
+/* =============================== DTOs ==================================== */
+
+type NotificationItem struct {
+	ID        uuid.UUID               `json:"id"`
+	Type      models.NotificationType `json:"type"`
+	CaseID    uuid.UUID               `json:"case_id"`
+	ReadAt    *time.Time              `json:"read_at"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+type PageNotifications = pagination.Page[NotificationItem]
+
+// NotificationCount is what the bell needs without loading the list.
+type NotificationCount struct {
+	Total  int64 `json:"total"`
+	Unread int64 `json:"unread"`
+}
+
+/* ============================== Handler ================================== */
+
+type Handler struct{ db *gorm.DB }
+
+func NewHandler(db *gorm.DB) *Handler { return &Handler{db: db} }
+
+/* ================================ List =================================== */
+
+// @Summary      List my notifications
+// @Description  Authenticated user lists their in-app notifications, newest first (paginated)
+// @Tags         notifications
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page      query int    false "page"
+// @Param        pageSize  query int    false "pageSize"
+// @Param        unread    query bool   false "only unread when true"
+// @Success      200  {object}  pagination.Page[notifications.NotificationItem]
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /notifications [get]
+func (h *Handler) List(c *fiber.Ctx) error {
+	userID := auth.MustUserID(c)
+	page, size := pagination.Parse(c)
+
+	q := h.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if c.QueryBool("unread") {
+		q = q.Where("read_at IS NULL")
+	}
+
+	// Count before pagination
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	items := make([]NotificationItem, 0, size)
+	if err := q.
+		Select("id, type, case_id, read_at, created_at").
+		Order("created_at DESC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Scan(&items).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(pagination.New(c, page, size, total, items))
+}
+
+/* ============================== Mark Read ================================ */
+
+// @Summary      Mark notification as read
+// @Description  Marks one of the caller's notifications as read (idempotent)
+// @Tags         notifications
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "notification id (uuid)"
+// @Success      200  {object}  NotificationItem
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Router       /notifications/{id}/read [post]
+func (h *Handler) MarkRead(c *fiber.Ctx) error {
+	userID := auth.MustUserID(c)
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid notification id")
+	}
+
+	// Scope by owner: other users' notifications look like they don't exist
+	var n models.Notification
+	if err := h.db.First(&n, "id = ? AND user_id = ?", id, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+
+	// Keep the first read time on repeats
+	if n.ReadAt == nil {
+		now := time.Now()
+		if err := h.db.Model(&n).Update("read_at", &now).Error; err != nil {
+			return fiber.ErrInternalServerError
+		}
+		n.ReadAt = &now
+	}
+
+	return c.JSON(NotificationItem{
+		ID:        n.ID,
+		Type:      n.Type,
+		CaseID:    n.CaseID,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	})
+}
+
+/* =============================== Counts ================================== */
+
+// @Summary      Count my notifications
+// @Description  Total and unread notification counts for the caller (for the bell badge)
+// @Tags         notifications
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  NotificationCount
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /notifications/count [get]
+func (h *Handler) Count(c *fiber.Ctx) error {
+	userID := auth.MustUserID(c)
+
+	// One pass over the user's rows for both numbers
+	var out NotificationCount
+	if err := h.db.Model(&models.Notification{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE read_at IS NULL) AS unread").
+		Where("user_id = ?", userID).
+		Scan(&out).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(out)
+}
+
+/* ============================== Read All ================================= */
+
+// @Summary      Mark all notifications as read
+// @Description  Marks every unread notification of the caller as read; returns how many changed (0 on repeats)
+// @Tags         notifications
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  map[string]int64  "updated"
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /notifications/read-all [post]
+func (h *Handler) MarkAllRead(c *fiber.Ctx) error {
+	userID := auth.MustUserID(c)
+
+	// Already-read rows keep their first read time
+	res := h.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	if res.Error != nil {
+		return fiber.ErrInternalServerError
+	}
+	return c.JSON(fiber.Map{"updated": res.RowsAffected})
+}
@@ -0,0 +1,180 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+/* ============================================================================
+   Helpers
+   ============================================================================ */
+
+// openTestDB connects to TEST_DATABASE_URL, migrates tables, and truncates them
+// after the test finishes.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	_ = godotenv.Load()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_URL is empty")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.Notification{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	t.Cleanup(func() {
+		sql := `
+TRUNCATE TABLE
+	notifications,
+	users
+RESTART IDENTITY CASCADE`
+		if err := db.Exec(sql).Error; err != nil {
+			t.Logf("truncate failed (ignored): %v", err)
+		}
+	})
+
+	return db
+}
+
+// injectAuth sets Locals so MustUserID reads identity properly.
+func injectAuth(userID uuid.UUID, role string) fiber.Handler {
+	id := userID.String()
+	return func(c *fiber.Ctx) error {
+		c.Locals("userID", id)
+		c.Locals("role", role)
+		return c.Next()
+	}
+}
+
+func newTestApp(h *Handler, userID uuid.UUID, role string) *fiber.App {
+	app := fiber.New()
+	app.Use(injectAuth(userID, role))
+	app.Get("/api/notifications", h.List)
+	app.Post("/api/notifications/:id/read", h.MarkRead)
+	app.Get("/api/notifications/count", h.Count)
+	app.Post("/api/notifications/read-all", h.MarkAllRead)
+	return app
+}
+
+// addNotification inserts an unread notification for a user.
+func addNotification(t *testing.T, db *gorm.DB, userID uuid.UUID) models.Notification {
+	t.Helper()
+	n := models.Notification{UserID: userID, Type: models.NotifyQuoteReceived, CaseID: uuid.New(), CreatedAt: time.Now()}
+	if err := db.Create(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+/* ============================================================================
+   Tests — read marking
+   ============================================================================ */
+
+// Marking a notification read removes it from the unread list; others can't touch it.
+func Test_MarkRead_OwnerOnly_AndUnreadFilter(t *testing.T) {
+	db := openTestDB(t)
+
+	owner, stranger := uuid.New(), uuid.New()
+	n1 := addNotification(t, db, owner)
+	_ = addNotification(t, db, owner)
+
+	// Stranger → 404 (does not reveal existence)
+	strangerApp := newTestApp(NewHandler(db), stranger, string(models.RoleClient))
+	resp, _ := strangerApp.Test(httptest.NewRequest("POST", "/api/notifications/"+n1.ID.String()+"/read", nil))
+	if resp.StatusCode != 404 {
+		t.Fatalf("stranger: want 404, got %d", resp.StatusCode)
+	}
+
+	// Owner marks one as read
+	app := newTestApp(NewHandler(db), owner, string(models.RoleClient))
+	resp, _ = app.Test(httptest.NewRequest("POST", "/api/notifications/"+n1.ID.String()+"/read", nil))
+	if resp.StatusCode != 200 {
+		t.Fatalf("owner: want 200, got %d", resp.StatusCode)
+	}
+	var item NotificationItem
+	_ = json.NewDecoder(resp.Body).Decode(&item)
+	if item.ReadAt == nil {
+		t.Fatalf("read_at should be set")
+	}
+
+	// Unread filter now returns only the other one
+	resp, _ = app.Test(httptest.NewRequest("GET", "/api/notifications?unread=true", nil))
+	var page PageNotifications
+	_ = json.NewDecoder(resp.Body).Decode(&page)
+	if page.Total != 1 || len(page.Items) != 1 || page.Items[0].ID == n1.ID {
+		t.Fatalf("want 1 unread (not n1), got total=%d items=%+v", page.Total, page.Items)
+	}
+
+	// Without filter both are listed
+	resp, _ = app.Test(httptest.NewRequest("GET", "/api/notifications", nil))
+	_ = json.NewDecoder(resp.Body).Decode(&page)
+	if page.Total != 2 {
+		t.Fatalf("want total=2, got %d", page.Total)
+	}
+}
+
+/* ============================================================================
+   Tests — counts and read-all
+   ============================================================================ */
+
+// The counts cover only the caller; read-all clears their unread ones and
+// leaves other users' untouched.
+func Test_Count_And_MarkAllRead(t *testing.T) {
+	db := openTestDB(t)
+
+	owner, other := uuid.New(), uuid.New()
+	n1 := addNotification(t, db, owner)
+	_ = addNotification(t, db, owner)
+	_ = addNotification(t, db, owner)
+	_ = addNotification(t, db, other)
+
+	app := newTestApp(NewHandler(db), owner, string(models.RoleClient))
+	if resp, _ := app.Test(httptest.NewRequest("POST", "/api/notifications/"+n1.ID.String()+"/read", nil)); resp.StatusCode != 200 {
+		t.Fatalf("mark read: want 200, got %d", resp.StatusCode)
+	}
+	count := func(app *fiber.App) NotificationCount {
+		t.Helper()
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/notifications/count", nil))
+		if resp.StatusCode != 200 {
+			t.Fatalf("count: want 200, got %d", resp.StatusCode)
+		}
+		var out NotificationCount
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return out
+	}
+
+	if got := count(app); got != (NotificationCount{Total: 3, Unread: 2}) {
+		t.Fatalf("before: want 3/2, got %+v", got)
+	}
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/api/notifications/read-all", nil))
+	var out struct{ Updated int64 }
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	if resp.StatusCode != 200 || out.Updated != 2 {
+		t.Fatalf("read-all: want 200 updated=2, got %d %+v", resp.StatusCode, out)
+	}
+	if got := count(app); got != (NotificationCount{Total: 3, Unread: 0}) {
+		t.Fatalf("after: want 3/0, got %+v", got)
+	}
+
+	otherApp := newTestApp(NewHandler(db), other, string(models.RoleClient))
+	if got := count(otherApp); got != (NotificationCount{Total: 1, Unread: 1}) {
+		t.Fatalf("other user: want 1/1, got %+v", got)
+	}
+}
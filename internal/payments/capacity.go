@@ -0,0 +1,44 @@
+package payments
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+/* ========================= Lawyer Engagement Cap ========================== */
+
+// MaxEngagedCases is how many ENGAGED cases one lawyer may hold at once.
+// Reads LAWYER_MAX_ENGAGED_CASES (positive int); 0 means no cap (the default).
+func MaxEngagedCases() int {
+	if n, err := strconv.Atoi(os.Getenv("LAWYER_MAX_ENGAGED_CASES")); err == nil && n > 0 {
+		return n
+	}
+	return 0
+}
+
+// lawyerAtCapacity reports whether engaging one more case would put the
+// lawyer over MaxEngagedCases. It locks the lawyer's user row first, so two
+// finalizations for the same lawyer on different cases count one at a time.
+func lawyerAtCapacity(tx *gorm.DB, lawyerID uuid.UUID) (bool, error) {
+	limit := MaxEngagedCases()
+	if limit == 0 {
+		return false, nil
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").First(&models.User{}, "id = ?", lawyerID).Error; err != nil {
+		return false, err
+	}
+	var engaged int64
+	if err := tx.Model(&models.Case{}).
+		Where("accepted_lawyer_id = ? AND status = ?", lawyerID, models.CaseEngaged).
+		Count(&engaged).Error; err != nil {
+		return false, err
+	}
+	return engaged >= int64(limit), nil
+}
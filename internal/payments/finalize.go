@@ -0,0 +1,84 @@
+package payments
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v82"
+	"github.com/stripe/stripe-go/v82/refund"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/utils"
+)
+
+/* ======================== Payments That Can't Engage ====================== */
+
+// engageBlocker says why a completed payment can't engage its case, or nil
+// when it can. It runs under the case lock (the case loaded Unscoped, so a
+// deleted case is seen rather than missing), right before engaging.
+func engageBlocker(tx *gorm.DB, cs models.Case, q models.Quote) (*apperr.Error, error) {
+	if cs.DeletedAt.Valid {
+		return apperr.Conflict(apperr.CaseNotOpen, "case was deleted"), nil
+	}
+	// Paused, cancelled, or engaged through another quote meanwhile
+	if cs.Status != models.CaseOpen {
+		return apperr.Conflict(apperr.CaseNotOpen, "case is no longer open"), nil
+	}
+	// Rejected since checkout started (the owner, or a withdrawn proposal)
+	if q.Status != models.QuoteProposed {
+		return apperr.Conflict(apperr.QuoteNotProposed, "quote is no longer proposed"), nil
+	}
+	// Opt-in cap: a lawyer at their limit can't win another case
+	full, err := lawyerAtCapacity(tx, q.LawyerID)
+	if err != nil {
+		return nil, err
+	}
+	if full {
+		return apperr.Conflict(apperr.LawyerAtCapacity, "lawyer has reached their engaged case limit"), nil
+	}
+	return nil, nil
+}
+
+// failUnengaged marks the payment FAILED instead of engaging the case and logs
+// why. The case and its quotes are left as they are. The caller commits.
+func failUnengaged(ctx context.Context, tx *gorm.DB, cs models.Case, pay models.Payment, reason string) error {
+	if err := tx.Model(&models.Payment{}).Where("id = ?", pay.ID).
+		Update("status", models.PayFailed).Error; err != nil {
+		return err
+	}
+	utils.LogCaseHistory(ctx, tx, cs.ID, uuid.Nil, models.ActorSystem,
+		"payment_failed", cs.Status, cs.Status, reason)
+	return nil
+}
+
+// refundPayment refunds a captured Stripe payment that could not engage the
+// case and records the outcome with the payment intent in the case history,
+// which keeps the charge traceable after a restarted checkout clears it from
+// the payment row. The idempotency key is per intent: webhook retries don't
+// refund twice, yet a later attempt on the same payment gets its own refund.
+// Failures are logged for a manual refund.
+func refundPayment(ctx context.Context, db *gorm.DB, cs models.Case, pay models.Payment, reason string) {
+	record := func(action, note string) {
+		utils.LogCaseHistory(ctx, db, cs.ID, uuid.Nil, models.ActorSystem, action, cs.Status, cs.Status, note)
+	}
+	if pay.StripePaymentIntent == nil || *pay.StripePaymentIntent == "" {
+		log.Printf("payments: payment %s needs a manual refund (no payment intent)", pay.ID)
+		record("refund_failed", "payment "+pay.ID.String()+" has no payment intent; refund manually")
+		return
+	}
+	pi := *pay.StripePaymentIntent
+	stripe.Key = os.Getenv("STRIPE_SECRET")
+	params := &stripe.RefundParams{PaymentIntent: stripe.String(pi)}
+	params.SetIdempotencyKey("refund-" + pi)
+	if _, err := refund.New(params); err != nil {
+		log.Printf("payments: refund of payment %s failed, refund manually: %v", pay.ID, err)
+		record("refund_failed", "refund of "+pi+" failed; refund manually ("+reason+")")
+		return
+	}
+	log.Printf("payments: refunded payment %s (%s)", pay.ID, reason)
+	record("payment_refunded", "refunded "+pi+" ("+reason+")")
+}
@@ -1,28 +1,66 @@
 package payments
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/stripe/stripe-go/v82"
 	"github.com/stripe/stripe-go/v82/checkout/session"
+	"github.com/stripe/stripe-go/v82/paymentintent"
 	"github.com/stripe/stripe-go/v82/webhook"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
 	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/internal/webhooks"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apitime"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/mailer"
+	"github.com/aldoetobex/legal-mp-backend/pkg/metrics"
 	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/money"
+	"github.com/aldoetobex/legal-mp-backend/pkg/origins"
+	"github.com/aldoetobex/legal-mp-backend/pkg/sanitize"
 	"github.com/aldoetobex/legal-mp-backend/pkg/utils"
 )
 
 /* =============================== Types =================================== */
 
+// IdempotencyKeyHeader lets clients retry checkout creation safely: a repeat
+// with the same key returns the original response instead of re-processing.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLen matches the payments.idempotency_key column.
+const maxIdempotencyKeyLen = 255
+
+// errKeyReused is returned when an Idempotency-Key was already used for
+// another quote by the same client.
+var errKeyReused = apperr.New(fiber.StatusUnprocessableEntity, apperr.IdempotencyKeyReused,
+	"idempotency key was already used for a different quote")
+
+// errPriceChanged is returned when a checkout in progress no longer matches
+// its quote's price. The payment keeps the price frozen at checkout; only a
+// failed (abandoned) payment is re-priced when checkout restarts.
+var errPriceChanged = apperr.Conflict(apperr.AmountMismatch,
+	"quote price changed since checkout started")
+
+// CheckoutRequest is the optional checkout body.
+type CheckoutRequest struct {
+	// Frontend to send the client back to after Stripe (must be one of the
+	// FRONTEND_ORIGIN origins); PUBLIC_BASE_URL when empty
+	ReturnOrigin string `json:"return_origin" example:"https://partners.example.com"`
+}
+
 type MockCompleteRequest struct {
 	PaymentID string `json:"payment_id"`
 }
@@ -33,9 +71,282 @@ type CheckoutResponse struct {
 	Provider    string `json:"provider"`
 }
 
-type Handler struct{ db *gorm.DB }
+// PaymentSummary is what the checkout page shows before the client confirms.
+type PaymentSummary struct {
+	ID          uuid.UUID        `json:"id"`
+	CaseID      uuid.UUID        `json:"case_id"`
+	CaseTitle   string           `json:"case_title"`
+	QuoteID     uuid.UUID        `json:"quote_id"`
+	QuoteNote   string           `json:"quote_note"` // PII-redacted until the quote is accepted
+	AmountCents int              `json:"amount_cents"`
+	Currency    string           `json:"currency"`
+	Status      models.PayStatus `json:"status"`
+	CreatedAt   time.Time        `json:"created_at"`
+
+	// Stripe-hosted receipt once paid (stripe provider only)
+	ReceiptURL *string `json:"receipt_url,omitempty"`
+}
+
+// CasePaymentStatus is the latest payment on a case, for the success page to
+// poll until it reads "paid".
+type CasePaymentStatus struct {
+	ID          uuid.UUID        `json:"id"`
+	Status      models.PayStatus `json:"status"`
+	AmountCents int              `json:"amount_cents"`
+	Currency    string           `json:"currency"`
+	Provider    string           `json:"provider"` // stripe | mock
+	CreatedAt   time.Time        `json:"created_at"`
+	PaidAt      *time.Time       `json:"paid_at,omitempty"`
+	ReceiptURL  *string          `json:"receipt_url,omitempty"`
+}
+
+type Handler struct {
+	db      *gorm.DB
+	mail    mailer.Mailer    // optional; nil disables email
+	metrics *metrics.Metrics // optional; nil records nothing
+
+	hooks *webhooks.Dispatcher // optional; nil sends no webhooks
+}
+
+func NewHandler(db *gorm.DB, mail mailer.Mailer, m *metrics.Metrics, hooks *webhooks.Dispatcher) *Handler {
+	return &Handler{db: db, mail: mail, metrics: m, hooks: hooks}
+}
+
+// notifyEngaged emails both parties after a case becomes engaged (best-effort).
+func (h *Handler) notifyEngaged(caseTitle string, clientID, lawyerID uuid.UUID) {
+	if h.mail == nil {
+		return
+	}
+	var users []models.User
+	if err := h.db.Select("id, email").Where("id IN ?", []uuid.UUID{clientID, lawyerID}).
+		Find(&users).Error; err != nil {
+		return
+	}
+	for _, u := range users {
+		switch u.ID {
+		case clientID:
+			mailer.SendAsync(h.mail, mailer.EngagedClient(u.Email, caseTitle))
+		case lawyerID:
+			mailer.SendAsync(h.mail, mailer.EngagedLawyer(u.Email, caseTitle))
+		}
+	}
+}
+
+// publishPaid sends payment.paid and, when this payment engaged the case
+// (engagedAt != nil), case.engaged to webhook subscribers (async).
+func (h *Handler) publishPaid(cs models.Case, q models.Quote, pay models.Payment, engagedAt *time.Time, paidAt time.Time) {
+	if engagedAt != nil {
+		h.hooks.Dispatch(webhooks.EventCaseEngaged, webhooks.CaseEngaged{
+			CaseID:    cs.ID,
+			Reference: cs.Reference,
+			ClientID:  cs.ClientID,
+			LawyerID:  q.LawyerID,
+			QuoteID:   q.ID,
+			EngagedAt: *engagedAt,
+		})
+	}
+	h.hooks.Dispatch(webhooks.EventPaymentPaid, webhooks.PaymentPaid{
+		PaymentID:   pay.ID,
+		CaseID:      cs.ID,
+		QuoteID:     q.ID,
+		AmountCents: pay.AmountCents,
+		Currency:    money.OrDefault(pay.Currency),
+		PaidAt:      paidAt,
+	})
+}
+
+// acceptError maps a failure to mark the winning quote ACCEPTED. A unique
+// violation means ux_quotes_one_accepted caught a second winner on the case,
+// which the transaction logic should never allow: log it loudly and refuse.
+func acceptError(caseID uuid.UUID, err error) error {
+	if utils.IsUniqueViolation(err) {
+		log.Printf("payments: case %s already has an accepted quote; refusing a second one", caseID)
+		return apperr.Conflict(apperr.QuoteAlreadyAccepted, "case already has an accepted quote")
+	}
+	return fiber.ErrInternalServerError
+}
+
+// idempotencyKey reads the optional Idempotency-Key header (nil when absent).
+func idempotencyKey(c *fiber.Ctx) (*string, error) {
+	key := strings.TrimSpace(c.Get(IdempotencyKeyHeader))
+	if key == "" {
+		return nil, nil
+	}
+	if len(key) > maxIdempotencyKeyLen {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Idempotency-Key is too long")
+	}
+	return &key, nil
+}
+
+// replayedPayment returns the payment a client already created with this
+// Idempotency-Key (nil if the key is new), or errKeyReused if that payment
+// belongs to a different quote.
+func (h *Handler) replayedPayment(clientID string, key *string, quoteID uuid.UUID) (*models.Payment, error) {
+	if key == nil {
+		return nil, nil
+	}
+	var pay models.Payment
+	err := h.db.Where("client_id = ? AND idempotency_key = ?", clientID, *key).First(&pay).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fiber.ErrInternalServerError
+	}
+	if pay.QuoteID != quoteID {
+		return nil, errKeyReused
+	}
+	return &pay, nil
+}
+
+// initiatePayment returns the quote's payment, creating an INITIATED one if
+// none exists yet. The quote row is locked so concurrent checkouts serialize;
+// ux_pay_quote backs this up, and a losing insert reloads the winner's row.
+// A non-nil key is recorded on the payment unless it already has one.
+func (h *Handler) initiatePayment(cs models.Case, q models.Quote, key *string) (models.Payment, error) {
+	var pay models.Payment
+	tx := h.db.Begin()
+	if tx.Error != nil {
+		return pay, tx.Error
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&models.Quote{}, "id = ?", q.ID).Error; err != nil {
+		tx.Rollback()
+		return pay, err
+	}
+
+	err := tx.Where("quote_id = ?", q.ID).First(&pay).Error
+	if err == nil {
+		// Swept as stale: start a fresh attempt on the same row, at the
+		// quote's current price. The old attempt's Stripe references and key
+		// go too, so the new session's webhook records its own intent; a
+		// charged (and refunded) intent is kept in the case history first.
+		if pay.Status == models.PayFailed {
+			if pay.StripePaymentIntent != nil && *pay.StripePaymentIntent != "" {
+				utils.LogCaseHistory(context.Background(), tx, cs.ID, uuid.Nil, models.ActorSystem,
+					"payment_restarted", cs.Status, cs.Status,
+					"payment "+pay.ID.String()+" restarted; previous attempt "+*pay.StripePaymentIntent)
+			}
+			now := time.Now()
+			currency := money.OrDefault(q.Currency)
+			if err := tx.Model(&pay).Updates(map[string]any{
+				"status": models.PayInitiated, "created_at": now, "updated_at": now,
+				"amount_cents": q.AmountCents, "currency": currency,
+				"stripe_session_id": nil, "stripe_payment_intent": nil,
+				"receipt_url": nil, "idempotency_key": nil,
+			}).Error; err != nil {
+				tx.Rollback()
+				return pay, err
+			}
+			pay.Status, pay.CreatedAt = models.PayInitiated, now
+			pay.AmountCents, pay.Currency = q.AmountCents, currency
+			pay.StripeSessionID, pay.StripePaymentIntent = nil, nil
+			pay.ReceiptURL, pay.IdempotencyKey = nil, nil
+		}
+		if pay.Status == models.PayInitiated && !sameCharge(pay, q) {
+			tx.Rollback()
+			return pay, errPriceChanged
+		}
+		if key != nil && pay.IdempotencyKey == nil {
+			if err := tx.Model(&pay).Update("idempotency_key", key).Error; err != nil {
+				tx.Rollback()
+				if utils.IsUniqueViolation(err) {
+					return pay, errKeyReused
+				}
+				return pay, err
+			}
+		}
+		return pay, tx.Commit().Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		return pay, err
+	}
+
+	pay = models.Payment{
+		CaseID:         cs.ID,
+		QuoteID:        q.ID,
+		ClientID:       cs.ClientID,
+		IdempotencyKey: key,
+		AmountCents:    q.AmountCents,
+		Currency:       money.OrDefault(q.Currency),
+		Status:         models.PayInitiated,
+		CreatedAt:      time.Now(),
+	}
+	if err := tx.Create(&pay).Error; err != nil {
+		tx.Rollback()
+		if !utils.IsUniqueViolation(err) {
+			return pay, err
+		}
+		// Either another checkout won the quote, or the key is taken
+		pay = models.Payment{}
+		err = h.db.Where("quote_id = ?", q.ID).First(&pay).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pay, errKeyReused
+		}
+		return pay, err
+	}
+	return pay, tx.Commit().Error
+}
+
+// sameCharge reports whether a payment still matches its quote's price
+// (amount and currency; legacy empty currencies mean the default).
+func sameCharge(pay models.Payment, q models.Quote) bool {
+	return pay.AmountCents == q.AmountCents &&
+		money.OrDefault(pay.Currency) == money.OrDefault(q.Currency)
+}
 
-func NewHandler(db *gorm.DB) *Handler { return &Handler{db: db} }
+// sessionCharged reports whether a completed Checkout session charged the
+// payment's frozen price. Metadata is only trusted to agree, never as the
+// source of the amount; fields Stripe left empty are skipped.
+func sessionCharged(s stripe.CheckoutSession, pay models.Payment) bool {
+	currency := money.OrDefault(pay.Currency)
+	if s.Currency != "" && !strings.EqualFold(string(s.Currency), currency) {
+		return false
+	}
+	if s.AmountTotal != 0 && s.AmountTotal != money.StripeUnitAmount(int64(pay.AmountCents), currency) {
+		return false
+	}
+	if v, ok := s.Metadata["amount_cents"]; ok && v != strconv.Itoa(pay.AmountCents) {
+		return false
+	}
+	return true
+}
+
+// returnOrigin reads the optional return_origin from the checkout body.
+// It returns "" when none is given and a 400 for origins outside the
+// FRONTEND_ORIGIN allowlist, so checkout can't become an open redirect.
+func returnOrigin(c *fiber.Ctx) (string, error) {
+	if len(c.Body()) == 0 {
+		return "", nil
+	}
+	var in CheckoutRequest
+	if err := c.BodyParser(&in); err != nil {
+		return "", fiber.NewError(fiber.StatusBadRequest, "invalid json")
+	}
+	if strings.TrimSpace(in.ReturnOrigin) == "" {
+		return "", nil
+	}
+	if !origins.Allowed(origins.FromEnv(), in.ReturnOrigin) {
+		return "", apperr.BadRequest(apperr.ReturnOriginNotAllowed, "return_origin is not an allowed frontend origin")
+	}
+	o, _ := origins.Normalize(in.ReturnOrigin)
+	return o, nil
+}
+
+// mockCheckoutResponse is what the mock provider returns for a payment,
+// redirecting to base (the local frontend when empty).
+func mockCheckoutResponse(pay models.Payment, base string) CheckoutResponse {
+	if base == "" {
+		base = "http://localhost:3000"
+	}
+	return CheckoutResponse{
+		PaymentID:   pay.ID.String(),
+		RedirectURL: base + "/mock/checkout?pid=" + pay.ID.String(),
+		Provider:    "mock",
+	}
+}
 
 /* ============================== MOCK FLOW ================================= */
 
@@ -43,9 +354,14 @@ func NewHandler(db *gorm.DB) *Handler { return &Handler{db: db} }
 // @Description  Create or reuse an initiated payment using the mock provider
 // @Tags         payments
 // @Security     BearerAuth
+// @Accept       json
 // @Produce      json
-// @Param        quoteID  path  string  true  "quote id (uuid)"
+// @Param        quoteID          path    string  true   "quote id (uuid)"
+// @Param        Idempotency-Key  header  string  false  "repeat returns the original response"
+// @Param        payload          body    CheckoutRequest  false  "optional return origin"
 // @Success      201  {object}  CheckoutResponse
+// @Failure      400  {object}  models.ErrorResponse  "RETURN_ORIGIN_NOT_ALLOWED"
+// @Failure      422  {object}  models.ErrorResponse  "IDEMPOTENCY_KEY_REUSED"
 // @Router       /checkout/{quoteID} [post]
 func (h *Handler) CreateCheckoutMock(c *fiber.Ctx) error {
 	quoteID := c.Params("quoteID")
@@ -56,6 +372,24 @@ func (h *Handler) CreateCheckoutMock(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid quote id")
 	}
+	clientID := auth.MustUserID(c)
+
+	// Retried request: answer as the first time, without re-checking state
+	key, err := idempotencyKey(c)
+	if err != nil {
+		return err
+	}
+	base, err := returnOrigin(c)
+	if err != nil {
+		return err
+	}
+	prev, err := h.replayedPayment(clientID, key, qid)
+	if err != nil {
+		return err
+	}
+	if prev != nil {
+		return c.Status(fiber.StatusCreated).JSON(mockCheckoutResponse(*prev, base))
+	}
 
 	// Load quote & case
 	var q models.Quote
@@ -71,52 +405,53 @@ func (h *Handler) CreateCheckoutMock(c *fiber.Ctx) error {
 	}
 
 	// Authorization & state checks
-	clientID := auth.MustUserID(c)
 	if cs.ClientID.String() != clientID {
 		return fiber.ErrForbidden
 	}
 	if cs.Status != models.CaseOpen {
-		return fiber.NewError(fiber.StatusConflict, "case is not open")
+		return apperr.Conflict(apperr.CaseNotOpen, "case is not open")
 	}
-
-	// Idempotent by quote
-	var pay models.Payment
-	if err := h.db.Where("quote_id = ?", q.ID).First(&pay).Error; err != nil {
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			return fiber.ErrInternalServerError
-		}
-		pay = models.Payment{
-			CaseID:      cs.ID,
-			QuoteID:     q.ID,
-			ClientID:    cs.ClientID,
-			AmountCents: q.AmountCents,
-			Status:      models.PayInitiated,
-			CreatedAt:   time.Now(),
-		}
-		if err := h.db.Create(&pay).Error; err != nil {
-			return fiber.ErrInternalServerError
-		}
-	} else if pay.Status == models.PayPaid {
-		return fiber.NewError(fiber.StatusConflict, "quote already paid")
+	if q.Expired(time.Now()) {
+		return apperr.Conflict(apperr.QuoteExpired, "quote expired")
+	}
+	if q.Status != models.QuoteProposed {
+		return apperr.Conflict(apperr.QuoteNotProposed, "quote is not open for checkout")
+	}
+	// A legacy quote can't be charged if its currency has no unit that small
+	if !money.Aligned(int64(q.AmountCents), q.Currency) {
+		return apperr.Conflict(apperr.AmountNotChargeable, "quote amount can't be charged in its currency")
 	}
 
-	resp := CheckoutResponse{
-		PaymentID:   pay.ID.String(),
-		RedirectURL: "http://localhost:3000/mock/checkout?pid=" + pay.ID.String(),
-		Provider:    "mock",
+	// Idempotent by quote (safe under concurrent requests)
+	pay, err := h.initiatePayment(cs, q, key)
+	if errors.Is(err, errKeyReused) || errors.Is(err, errPriceChanged) {
+		return err
 	}
-	return c.Status(fiber.StatusCreated).JSON(resp)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if pay.Status == models.PayPaid {
+		return apperr.Conflict(apperr.QuoteAlreadyPaid, "quote already paid")
+	}
+
+	h.metrics.CheckoutCreated("mock")
+	return c.Status(fiber.StatusCreated).JSON(mockCheckoutResponse(pay, base))
 }
 
 /* ============================== STRIPE FLOW =============================== */
 
 // @Summary      Create checkout (Stripe)
-// @Description  Create a Stripe Checkout Session using amount from DB
+// @Description  Create a Stripe Checkout Session using amount from DB. Stripe returns the client to return_origin (one of FRONTEND_ORIGIN) or PUBLIC_BASE_URL.
 // @Tags         payments
 // @Security     BearerAuth
+// @Accept       json
 // @Produce      json
-// @Param        quoteID  path  string  true  "quote id (uuid)"
+// @Param        quoteID          path    string  true   "quote id (uuid)"
+// @Param        Idempotency-Key  header  string  false  "repeat returns the original response"
+// @Param        payload          body    CheckoutRequest  false  "optional return origin"
 // @Success      201  {object}  CheckoutResponse
+// @Failure      400  {object}  models.ErrorResponse  "RETURN_ORIGIN_NOT_ALLOWED"
+// @Failure      422  {object}  models.ErrorResponse  "IDEMPOTENCY_KEY_REUSED"
 // @Router       /checkout/{quoteID} [post]
 func (h *Handler) CreateCheckout(c *fiber.Ctx) error {
 	// Fallback to mock provider if configured
@@ -125,10 +460,6 @@ func (h *Handler) CreateCheckout(c *fiber.Ctx) error {
 	}
 
 	stripe.Key = os.Getenv("STRIPE_SECRET")
-	currency := os.Getenv("STRIPE_CURRENCY")
-	if currency == "" {
-		currency = "usd"
-	}
 
 	clientID := auth.MustUserID(c)
 	qid, err := uuid.Parse(c.Params("quoteID"))
@@ -136,6 +467,34 @@ func (h *Handler) CreateCheckout(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid quote id")
 	}
 
+	// Retried request: return the session created the first time
+	key, err := idempotencyKey(c)
+	if err != nil {
+		return err
+	}
+	base, err := returnOrigin(c)
+	if err != nil {
+		return err
+	}
+	if base == "" {
+		base = os.Getenv("PUBLIC_BASE_URL")
+	}
+	prev, err := h.replayedPayment(clientID, key, qid)
+	if err != nil {
+		return err
+	}
+	if prev != nil && prev.StripeSessionID != nil {
+		sess, err := session.Get(*prev.StripeSessionID, nil)
+		if err != nil {
+			return fiber.NewError(http.StatusBadGateway, err.Error())
+		}
+		return c.Status(fiber.StatusCreated).JSON(CheckoutResponse{
+			PaymentID:   prev.ID.String(),
+			RedirectURL: sess.URL,
+			Provider:    "stripe",
+		})
+	}
+
 	// Load quote & case
 	var q models.Quote
 	if err := h.db.First(&q, "id = ?", qid).Error; err != nil {
@@ -154,33 +513,37 @@ func (h *Handler) CreateCheckout(c *fiber.Ctx) error {
 		return fiber.ErrForbidden
 	}
 	if cs.Status != models.CaseOpen {
-		return fiber.NewError(fiber.StatusConflict, "case is not open")
+		return apperr.Conflict(apperr.CaseNotOpen, "case is not open")
+	}
+	if q.Expired(time.Now()) {
+		return apperr.Conflict(apperr.QuoteExpired, "quote expired")
+	}
+	if q.Status != models.QuoteProposed {
+		return apperr.Conflict(apperr.QuoteNotProposed, "quote is not open for checkout")
+	}
+	// A legacy quote can't be charged if its currency has no unit that small
+	if !money.Aligned(int64(q.AmountCents), q.Currency) {
+		return apperr.Conflict(apperr.AmountNotChargeable, "quote amount can't be charged in its currency")
 	}
 
-	// Idempotent by quote
-	var pay models.Payment
-	if err := h.db.Where("quote_id = ?", q.ID).First(&pay).Error; err != nil {
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			return fiber.ErrInternalServerError
-		}
-		pay = models.Payment{
-			CaseID:      cs.ID,
-			QuoteID:     q.ID,
-			ClientID:    cs.ClientID,
-			AmountCents: q.AmountCents,
-			Status:      models.PayInitiated,
-			CreatedAt:   time.Now(),
-		}
-		if err := h.db.Create(&pay).Error; err != nil {
-			return fiber.ErrInternalServerError
-		}
-	} else if pay.Status == models.PayPaid {
-		return fiber.NewError(fiber.StatusConflict, "quote already paid")
+	// Idempotent by quote (safe under concurrent requests)
+	pay, err := h.initiatePayment(cs, q, key)
+	if errors.Is(err, errKeyReused) || errors.Is(err, errPriceChanged) {
+		return err
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
 	}
+	if pay.Status == models.PayPaid {
+		return apperr.Conflict(apperr.QuoteAlreadyPaid, "quote already paid")
+	}
+
+	// Charge the price frozen on the payment (Stripe expects lower case)
+	currency := strings.ToLower(money.OrDefault(pay.Currency))
 
-	// Build success/cancel URLs
-	successURL := os.Getenv("PUBLIC_BASE_URL") + "/payments/success?pid=" + pay.ID.String()
-	cancelURL := os.Getenv("PUBLIC_BASE_URL") + "/payments/cancel?pid=" + pay.ID.String()
+	// Build success/cancel URLs on the requesting frontend
+	successURL := base + "/payments/success?pid=" + pay.ID.String()
+	cancelURL := base + "/payments/cancel?pid=" + pay.ID.String()
 
 	// Create Stripe Checkout Session
 	params := &stripe.CheckoutSessionParams{
@@ -193,7 +556,8 @@ func (h *Handler) CreateCheckout(c *fiber.Ctx) error {
 			"quote_id":     q.ID.String(),
 			"case_id":      cs.ID.String(),
 			"client_id":    cs.ClientID.String(),
-			"amount_cents": fmt.Sprintf("%d", q.AmountCents),
+			"amount_cents": fmt.Sprintf("%d", pay.AmountCents),
+			"currency":     currency,
 		},
 		LineItems: []*stripe.CheckoutSessionLineItemParams{
 			{
@@ -203,7 +567,7 @@ func (h *Handler) CreateCheckout(c *fiber.Ctx) error {
 						Name:        stripe.String(fmt.Sprintf("Legal case #%s", cs.ID.String())),
 						Description: stripe.String(fmt.Sprintf("Case engagement (%s)", q.Note)),
 					},
-					UnitAmount: stripe.Int64(int64(q.AmountCents)),
+					UnitAmount: stripe.Int64(money.StripeUnitAmount(int64(pay.AmountCents), currency)),
 				},
 				Quantity: stripe.Int64(1),
 			},
@@ -224,6 +588,7 @@ func (h *Handler) CreateCheckout(c *fiber.Ctx) error {
 		return fiber.ErrInternalServerError
 	}
 
+	h.metrics.CheckoutCreated("stripe")
 	resp := CheckoutResponse{
 		PaymentID:   pay.ID.String(),
 		RedirectURL: sess.URL,
@@ -232,6 +597,129 @@ func (h *Handler) CreateCheckout(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(resp)
 }
 
+/* ============================== GET PAYMENT =============================== */
+
+// @Summary      Get a payment
+// @Description  Owning client only: amount, case title, quote note, status and (once paid via Stripe) the receipt URL
+// @Tags         payments
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "payment id (uuid)"
+// @Success      200  {object}  PaymentSummary
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /payments/{id} [get]
+func (h *Handler) GetPayment(c *fiber.Ctx) error {
+	clientID := auth.MustUserID(c)
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid payment id")
+	}
+
+	// Non-owners get 404 so payment ids can't be probed
+	var pay models.Payment
+	if err := h.db.First(&pay, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	if pay.ClientID.String() != clientID {
+		return fiber.ErrNotFound
+	}
+
+	var cs models.Case
+	if err := h.db.First(&cs, "id = ?", pay.CaseID).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	var q models.Quote
+	if err := h.db.First(&q, "id = ?", pay.QuoteID).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	// Same note policy as the case detail: only the accepted quote is unredacted
+	note := q.Note
+	if cs.AcceptedQuoteID != q.ID {
+		note = sanitize.RedactPII(note)
+	}
+
+	return c.JSON(PaymentSummary{
+		ID:          pay.ID,
+		CaseID:      cs.ID,
+		CaseTitle:   cs.Title,
+		QuoteID:     q.ID,
+		QuoteNote:   note,
+		AmountCents: pay.AmountCents,
+		Currency:    money.OrDefault(pay.Currency),
+		Status:      pay.Status,
+		CreatedAt:   apitime.Normalize(pay.CreatedAt),
+		ReceiptURL:  pay.ReceiptURL,
+	})
+}
+
+/* =========================== CASE PAYMENT ================================ */
+
+// @Summary      Latest payment on a case
+// @Description  Owning client only: status, amount, provider and (once paid via Stripe) the receipt URL of the case's most recent payment. Poll it from the success page until status is "paid".
+// @Tags         payments
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "case id (uuid)"
+// @Success      200  {object}  CasePaymentStatus
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse  "no such case, or no payment yet"
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /cases/{id}/payment [get]
+func (h *Handler) GetCasePayment(c *fiber.Ctx) error {
+	clientID := auth.MustUserID(c)
+	caseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid case id")
+	}
+
+	var cs models.Case
+	if err := h.db.Select("id, client_id").First(&cs, "id = ?", caseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	if cs.ClientID.String() != clientID {
+		return fiber.ErrForbidden
+	}
+
+	var pay models.Payment
+	if err := h.db.Where("case_id = ?", cs.ID).
+		Order("created_at DESC").
+		First(&pay).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "no payment for this case")
+		}
+		return fiber.ErrInternalServerError
+	}
+
+	// Only Stripe checkouts get a session; mock payments never do
+	provider := "mock"
+	if pay.StripeSessionID != nil {
+		provider = "stripe"
+	}
+
+	return c.JSON(CasePaymentStatus{
+		ID:          pay.ID,
+		Status:      pay.Status,
+		AmountCents: pay.AmountCents,
+		Currency:    money.OrDefault(pay.Currency),
+		Provider:    provider,
+		CreatedAt:   apitime.Normalize(pay.CreatedAt),
+		PaidAt:      apitime.NormalizePtr(pay.PaidAt),
+		ReceiptURL:  pay.ReceiptURL,
+	})
+}
+
 /* ============================ MOCK COMPLETE ============================== */
 
 // @Summary      Complete payment (mock)
@@ -277,9 +765,9 @@ func (h *Handler) MockComplete(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{"ok": true, "message": "already paid (idempotent)"})
 	}
 
-	// Lock case, load quote
+	// Lock case (deleted ones too), load quote
 	var cs models.Case
-	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+	if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
 		First(&cs, "id = ?", pay.CaseID).Error; err != nil {
 		tx.Rollback()
 		return fiber.ErrInternalServerError
@@ -290,45 +778,63 @@ func (h *Handler) MockComplete(c *fiber.Ctx) error {
 		return fiber.ErrInternalServerError
 	}
 
-	// Validate amount
-	if pay.AmountCents != q.AmountCents {
+	// Validate amount and currency
+	if !sameCharge(pay, q) {
 		tx.Rollback()
-		return fiber.NewError(http.StatusConflict, "amount mismatch")
+		h.metrics.PaymentFailed()
+		return apperr.Conflict(apperr.AmountMismatch, "amount mismatch")
 	}
 
-	// Accept selected quote, reject the rest, move case → engaged
-	if cs.Status == models.CaseOpen {
-		if err := tx.Model(&models.Quote{}).Where("id = ?", q.ID).
-			Update("status", models.QuoteAccepted).Error; err != nil {
-			tx.Rollback()
-			return fiber.ErrInternalServerError
-		}
-		if err := tx.Model(&models.Quote{}).
-			Where("case_id = ? AND id <> ? AND status = ?", cs.ID, q.ID, models.QuoteProposed).
-			Update("status", models.QuoteRejected).Error; err != nil {
+	blocked, err := engageBlocker(tx, cs, q)
+	if err != nil {
+		tx.Rollback()
+		return fiber.ErrInternalServerError
+	}
+	if blocked != nil {
+		if err := failUnengaged(c.Context(), tx, cs, pay, blocked.Error()); err != nil {
 			tx.Rollback()
 			return fiber.ErrInternalServerError
 		}
-		now := time.Now()
-		if err := tx.Model(&models.Case{}).Where("id = ?", cs.ID).
-			Updates(map[string]any{
-				"status":             models.CaseEngaged,
-				"engaged_at":         &now,
-				"accepted_quote_id":  q.ID,
-				"accepted_lawyer_id": q.LawyerID,
-			}).Error; err != nil {
-			tx.Rollback()
+		if err := tx.Commit().Error; err != nil {
 			return fiber.ErrInternalServerError
 		}
-		// History
-		utils.LogCaseHistory(c.Context(), tx, cs.ID, cs.ClientID,
-			"engaged", models.CaseOpen, models.CaseEngaged, "payment completed (mock)")
+		h.metrics.PaymentFailed()
+		return blocked
 	}
+	// Accept selected quote, reject the rest, move case → engaged
+	if err := tx.Model(&models.Quote{}).Where("id = ?", q.ID).
+		Update("status", models.QuoteAccepted).Error; err != nil {
+		tx.Rollback()
+		return acceptError(cs.ID, err)
+	}
+	if err := tx.Model(&models.Quote{}).
+		Where("case_id = ? AND id <> ? AND status = ?", cs.ID, q.ID, models.QuoteProposed).
+		Update("status", models.QuoteRejected).Error; err != nil {
+		tx.Rollback()
+		return fiber.ErrInternalServerError
+	}
+	now := time.Now()
+	engagedAt := &now
+	if err := tx.Model(&models.Case{}).Where("id = ?", cs.ID).
+		Updates(map[string]any{
+			"status":             models.CaseEngaged,
+			"engaged_at":         &now,
+			"accepted_quote_id":  q.ID,
+			"accepted_lawyer_id": q.LawyerID,
+		}).Error; err != nil {
+		tx.Rollback()
+		return fiber.ErrInternalServerError
+	}
+	// History: the (mock) provider drove this, not the client
+	utils.LogCaseHistory(c.Context(), tx, cs.ID, uuid.Nil, models.ActorSystem,
+		"engaged", models.CaseOpen, models.CaseEngaged, "payment completed (mock)")
 
 	// Mark payment as paid
+	paidAt := time.Now()
 	if err := tx.Model(&models.Payment{}).Where("id = ?", pay.ID).
 		Updates(map[string]any{
-			"status": models.PayPaid,
+			"status":  models.PayPaid,
+			"paid_at": paidAt,
 		}).Error; err != nil {
 		tx.Rollback()
 		return fiber.ErrInternalServerError
@@ -337,11 +843,50 @@ func (h *Handler) MockComplete(c *fiber.Ctx) error {
 	if err := tx.Commit().Error; err != nil {
 		return fiber.ErrInternalServerError
 	}
+	h.metrics.PaymentPaid()
+	h.notifyEngaged(cs.Title, cs.ClientID, q.LawyerID)
+	h.publishPaid(cs, q, pay, engagedAt, paidAt)
 	return c.JSON(fiber.Map{"ok": true})
 }
 
 /* ============================ STRIPE WEBHOOK ============================== */
 
+// stripeReceiptURL loads the PaymentIntent with its latest charge expanded and
+// returns the charge's hosted receipt URL, or nil when unavailable. Failures
+// are logged and never block finalization.
+func stripeReceiptURL(piID string) *string {
+	stripe.Key = os.Getenv("STRIPE_SECRET")
+	params := &stripe.PaymentIntentParams{}
+	params.AddExpand("latest_charge")
+	pi, err := paymentintent.Get(piID, params)
+	if err != nil {
+		log.Printf("stripe: fetch receipt for %s failed: %v", piID, err)
+		return nil
+	}
+	if pi.LatestCharge == nil || pi.LatestCharge.ReceiptURL == "" {
+		return nil
+	}
+	return &pi.LatestCharge.ReceiptURL
+}
+
+// constructEvent verifies a webhook payload against STRIPE_WEBHOOK_SECRET,
+// which may list several comma-separated secrets while one is being rotated:
+// the event is accepted if any of them verifies it.
+func constructEvent(payload []byte, sig, secrets string) (stripe.Event, error) {
+	err := errors.New("no webhook secret configured")
+	for _, secret := range strings.Split(secrets, ",") {
+		secret = strings.TrimSpace(secret)
+		if secret == "" {
+			continue
+		}
+		var evt stripe.Event
+		if evt, err = webhook.ConstructEvent(payload, sig, secret); err == nil {
+			return evt, nil
+		}
+	}
+	return stripe.Event{}, err
+}
+
 // @Summary      Stripe webhook endpoint
 // @Description  Verify signature and finalize payment (checkout.session.completed)
 // @Tags         payments
@@ -352,9 +897,7 @@ func (h *Handler) MockComplete(c *fiber.Ctx) error {
 func (h *Handler) StripeWebhook(c *fiber.Ctx) error {
 	payload := c.Body()
 	sig := c.Get("Stripe-Signature")
-	secret := os.Getenv("STRIPE_WEBHOOK_SECRET")
-
-	evt, err := webhook.ConstructEvent(payload, sig, secret)
+	evt, err := constructEvent(payload, sig, os.Getenv("STRIPE_WEBHOOK_SECRET"))
 	if err != nil {
 		return fiber.NewError(http.StatusBadRequest, "signature verification failed")
 	}
@@ -382,6 +925,12 @@ func (h *Handler) StripeWebhook(c *fiber.Ctx) error {
 			return fiber.NewError(http.StatusBadRequest, "invalid payment_id")
 		}
 
+		// Hosted receipt from the charge (best-effort; fetched before any row locks)
+		var receiptURL *string
+		if s.PaymentIntent != nil && s.PaymentIntent.ID != "" {
+			receiptURL = stripeReceiptURL(s.PaymentIntent.ID)
+		}
+
 		// Begin transaction
 		tx := h.db.Begin()
 
@@ -416,9 +965,9 @@ func (h *Handler) StripeWebhook(c *fiber.Ctx) error {
 			pay.StripePaymentIntent = &piID
 		}
 
-		// Lock case & load quote
+		// Lock case (deleted ones too) & load quote
 		var cs models.Case
-		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
 			First(&cs, "id = ?", pay.CaseID).Error; err != nil {
 			tx.Rollback()
 			return fiber.ErrInternalServerError
@@ -429,58 +978,80 @@ func (h *Handler) StripeWebhook(c *fiber.Ctx) error {
 			return fiber.ErrInternalServerError
 		}
 
-		// Validate amount
-		if pay.AmountCents != q.AmountCents {
+		// Validate amount and currency (also against what Stripe actually charged)
+		if !sameCharge(pay, q) || !sessionCharged(s, pay) {
 			tx.Rollback()
-			return fiber.NewError(http.StatusConflict, "amount mismatch")
+			h.metrics.PaymentFailed()
+			return apperr.Conflict(apperr.AmountMismatch, "amount mismatch")
 		}
 
-		// Accept the winning quote, reject the rest, move case → engaged
-		if cs.Status == models.CaseOpen {
-			if err := tx.Model(&models.Quote{}).Where("id = ?", q.ID).
-				Update("status", models.QuoteAccepted).Error; err != nil {
-				tx.Rollback()
-				return fiber.ErrInternalServerError
-			}
-			if err := tx.Model(&models.Quote{}).
-				Where("case_id = ? AND id <> ? AND status = ?", cs.ID, q.ID, models.QuoteProposed).
-				Update("status", models.QuoteRejected).Error; err != nil {
+		// The money is already taken, so refund instead of engaging
+		blocked, err := engageBlocker(tx, cs, q)
+		if err != nil {
+			tx.Rollback()
+			return fiber.ErrInternalServerError
+		}
+		if blocked != nil {
+			if err := failUnengaged(c.Context(), tx, cs, pay, blocked.Error()); err != nil {
 				tx.Rollback()
 				return fiber.ErrInternalServerError
 			}
-			now := time.Now()
-			if err := tx.Model(&models.Case{}).Where("id = ?", cs.ID).
-				Updates(map[string]any{
-					"status":             models.CaseEngaged,
-					"engaged_at":         &now,
-					"accepted_quote_id":  q.ID,
-					"accepted_lawyer_id": q.LawyerID,
-				}).Error; err != nil {
-				tx.Rollback()
+			if err := tx.Commit().Error; err != nil {
 				return fiber.ErrInternalServerError
 			}
+			h.metrics.PaymentFailed()
+			refundPayment(c.Context(), h.db, cs, pay, blocked.Error())
+			return c.SendStatus(http.StatusOK) // handled; Stripe must not retry
+		}
+		// Accept the winning quote, reject the rest, move case → engaged
+		if err := tx.Model(&models.Quote{}).Where("id = ?", q.ID).
+			Update("status", models.QuoteAccepted).Error; err != nil {
+			tx.Rollback()
+			return acceptError(cs.ID, err)
+		}
+		if err := tx.Model(&models.Quote{}).
+			Where("case_id = ? AND id <> ? AND status = ?", cs.ID, q.ID, models.QuoteProposed).
+			Update("status", models.QuoteRejected).Error; err != nil {
+			tx.Rollback()
+			return fiber.ErrInternalServerError
+		}
+		now := time.Now()
+		engagedAt := &now
+		if err := tx.Model(&models.Case{}).Where("id = ?", cs.ID).
+			Updates(map[string]any{
+				"status":             models.CaseEngaged,
+				"engaged_at":         &now,
+				"accepted_quote_id":  q.ID,
+				"accepted_lawyer_id": q.LawyerID,
+			}).Error; err != nil {
+			tx.Rollback()
+			return fiber.ErrInternalServerError
+		}
 
-			// Build reason using stripe_payment_intent (no extra Stripe API calls)
-			reason := "payment completed (stripe)"
-			if pay.StripePaymentIntent != nil && *pay.StripePaymentIntent != "" {
-				reason = fmt.Sprintf("payment completed (stripe: %s)", *pay.StripePaymentIntent)
-			}
-			utils.LogCaseHistory(
-				c.Context(),
-				tx,
-				cs.ID,
-				cs.ClientID,
-				"engaged",
-				models.CaseOpen,
-				models.CaseEngaged,
-				reason,
-			)
+		// Build reason using stripe_payment_intent (no extra Stripe API calls)
+		reason := "payment completed (stripe)"
+		if pay.StripePaymentIntent != nil && *pay.StripePaymentIntent != "" {
+			reason = fmt.Sprintf("payment completed (stripe: %s)", *pay.StripePaymentIntent)
 		}
+		utils.LogCaseHistory(
+			c.Context(),
+			tx,
+			cs.ID,
+			uuid.Nil, // webhook-driven: no user actor
+			models.ActorSystem,
+			"engaged",
+			models.CaseOpen,
+			models.CaseEngaged,
+			reason,
+		)
 
 		// Mark payment as paid
+		paidAt := time.Now()
 		if err := tx.Model(&models.Payment{}).Where("id = ?", pay.ID).
 			Updates(map[string]any{
-				"status": models.PayPaid,
+				"status":      models.PayPaid,
+				"paid_at":     paidAt,
+				"receipt_url": receiptURL,
 			}).Error; err != nil {
 			tx.Rollback()
 			return fiber.ErrInternalServerError
@@ -489,6 +1060,9 @@ func (h *Handler) StripeWebhook(c *fiber.Ctx) error {
 		if err := tx.Commit().Error; err != nil {
 			return fiber.ErrInternalServerError
 		}
+		h.metrics.PaymentPaid()
+		h.notifyEngaged(cs.Title, cs.ClientID, q.LawyerID)
+		h.publishPaid(cs, q, pay, engagedAt, paidAt)
 		return c.SendStatus(http.StatusOK)
 
 	default:
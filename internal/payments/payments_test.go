@@ -0,0 +1,1487 @@
+package payments
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/stripe/stripe-go/v82"
+	"github.com/stripe/stripe-go/v82/webhook"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/internal/cases"
+	"github.com/aldoetobex/legal-mp-backend/internal/quotes"
+	"github.com/aldoetobex/legal-mp-backend/pkg/mailer"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+/* ============================================================================
+   Helpers
+   ============================================================================ */
+
+// openTestDB connects to TEST_DATABASE_URL, migrates tables, and truncates them
+// after the test finishes.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	_ = godotenv.Load()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_URL is empty")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.User{}, &models.Case{}, &models.CaseFile{},
+		&models.CaseHistory{}, &models.Quote{}, &models.Payment{}, &models.Notification{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := quotes.EnsureSingleAcceptedQuote(db); err != nil {
+		t.Fatalf("accepted index: %v", err)
+	}
+
+	t.Cleanup(func() {
+		sql := `
+TRUNCATE TABLE
+	notifications,
+	payments,
+	case_histories,
+	case_files,
+	quotes,
+	cases,
+	users
+RESTART IDENTITY CASCADE`
+		if err := db.Exec(sql).Error; err != nil {
+			t.Logf("truncate failed (ignored): %v", err)
+		}
+	})
+
+	return db
+}
+
+// injectAuth sets Locals so MustUserID/MustRole read identity and role properly.
+func injectAuth(userID uuid.UUID, role string) fiber.Handler {
+	id := userID.String()
+	return func(c *fiber.Ctx) error {
+		c.Locals("userID", id)
+		c.Locals("role", role)
+		return c.Next()
+	}
+}
+
+// newTestApp exposes the payment endpoints used in these tests.
+func newTestApp(h *Handler, userID uuid.UUID, role string) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+	app.Use(injectAuth(userID, role))
+	app.Post("/api/checkout/:quoteID", h.CreateCheckout)
+	app.Post("/api/payments/mock/complete", h.MockComplete)
+	app.Get("/api/payments/:id", h.GetPayment)
+	app.Get("/api/cases/:id/payment", h.GetCasePayment)
+	return app
+}
+
+// useMockProvider enables the dev-only mock payment flow for a test.
+func useMockProvider(t *testing.T) {
+	t.Setenv("APP_ENV", "dev")
+	t.Setenv("PAYMENT_PROVIDER", "mock")
+	t.Setenv("DEV_PAYMENT_SECRET", "test-secret")
+}
+
+type seedOut struct {
+	ClientID, LawyerID, CaseID uuid.UUID
+	ClientEmail, LawyerEmail   string
+	Quote                      models.Quote
+}
+
+// seedQuote inserts a client, a lawyer, an OPEN case, and one PROPOSED quote.
+func seedQuote(t *testing.T, db *gorm.DB) seedOut {
+	t.Helper()
+	s := seedOut{ClientID: uuid.New(), LawyerID: uuid.New(), CaseID: uuid.New()}
+	s.ClientEmail = "c_" + s.ClientID.String()[:8] + "@x.com"
+	s.LawyerEmail = "l_" + s.LawyerID.String()[:8] + "@x.com"
+
+	if err := db.Create(&models.User{ID: s.ClientID, Email: s.ClientEmail, Role: models.RoleClient}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.User{ID: s.LawyerID, Email: s.LawyerEmail, Role: models.RoleLawyer}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Case{
+		ID: s.CaseID, ClientID: s.ClientID, Title: "T", Category: "Cat",
+		Status: models.CaseOpen, CreatedAt: time.Now(),
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	s.Quote = models.Quote{
+		CaseID: s.CaseID, LawyerID: s.LawyerID, AmountCents: 5000, Days: 3,
+		Status: models.QuoteProposed, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	if err := db.Create(&s.Quote).Error; err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// createPayment seeds an initiated payment for the seeded quote.
+func createPayment(t *testing.T, db *gorm.DB, s seedOut) models.Payment {
+	t.Helper()
+	pay := models.Payment{
+		CaseID: s.CaseID, QuoteID: s.Quote.ID, ClientID: s.ClientID,
+		AmountCents: s.Quote.AmountCents, Status: models.PayInitiated, CreatedAt: time.Now(),
+	}
+	if err := db.Create(&pay).Error; err != nil {
+		t.Fatal(err)
+	}
+	return pay
+}
+
+// mockComplete calls the dev-only completion endpoint for a payment.
+func mockComplete(app *fiber.App, paymentID uuid.UUID) (int, error) {
+	req := httptest.NewRequest("POST", "/api/payments/mock/complete",
+		strings.NewReader(`{"payment_id":"`+paymentID.String()+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Dev-Secret", "test-secret")
+	resp, err := app.Test(req)
+	if err != nil {
+		return 0, err
+	}
+	return resp.StatusCode, nil
+}
+
+// checkoutCode starts checkout and returns the status and error code (if any).
+func checkoutCode(t *testing.T, app *fiber.App, quoteID uuid.UUID) (int, string) {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest("POST", "/api/checkout/"+quoteID.String(), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body models.ErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body.Code
+}
+
+// checkoutWithKey starts checkout with an Idempotency-Key and decodes the
+// success body (or the error code).
+func checkoutWithKey(t *testing.T, app *fiber.App, quoteID uuid.UUID, key string) (int, CheckoutResponse, string) {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/checkout/"+quoteID.String(), nil)
+	req.Header.Set(IdempotencyKeyHeader, key)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out CheckoutResponse
+	var eb models.ErrorResponse
+	if resp.StatusCode == 201 {
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+	} else {
+		_ = json.NewDecoder(resp.Body).Decode(&eb)
+	}
+	return resp.StatusCode, out, eb.Code
+}
+
+// checkout starts checkout for a quote and returns the status code.
+func checkout(app *fiber.App, quoteID uuid.UUID) (int, error) {
+	resp, err := app.Test(httptest.NewRequest("POST", "/api/checkout/"+quoteID.String(), nil))
+	if err != nil {
+		return 0, err
+	}
+	return resp.StatusCode, nil
+}
+
+/* ============================================================================
+   Tests — engagement emails
+   ============================================================================ */
+
+// Finalizing a payment emails both the client and the accepted lawyer.
+func Test_MockComplete_EmailsBothParties(t *testing.T) {
+	useMockProvider(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	pay := createPayment(t, db, s)
+
+	rec := &mailer.Recorder{}
+	app := newTestApp(NewHandler(db, rec, nil, nil), s.ClientID, string(models.RoleClient))
+
+	code, err := mockComplete(app, pay.ID)
+	if err != nil || code != 200 {
+		t.Fatalf("mock complete: code=%d err=%v", code, err)
+	}
+
+	msgs := rec.WaitFor(2, time.Second)
+	got := map[string]string{}
+	for _, m := range msgs {
+		got[m.To] = m.Subject
+	}
+	if got[s.ClientEmail] != mailer.EngagedClient("", "").Subject {
+		t.Fatalf("client email missing/wrong: %+v", msgs)
+	}
+	if got[s.LawyerEmail] != mailer.EngagedLawyer("", "").Subject {
+		t.Fatalf("lawyer email missing/wrong: %+v", msgs)
+	}
+
+	// Repeat is idempotent and sends nothing new
+	if code, _ := mockComplete(app, pay.ID); code != 200 {
+		t.Fatalf("repeat: want 200, got %d", code)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := len(rec.Messages()); n != 2 {
+		t.Fatalf("repeat should not re-send, got %d messages", n)
+	}
+}
+
+/* ============================================================================
+   Tests — lawyer engagement cap
+   ============================================================================ */
+
+// A lawyer already at LAWYER_MAX_ENGAGED_CASES can't win another case: the
+// payment fails and the case stays open. Under the cap it engages as usual.
+func Test_MockComplete_LawyerEngagementCap(t *testing.T) {
+	useMockProvider(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	pay := createPayment(t, db, s)
+
+	// The lawyer already holds one engaged case
+	if err := db.Create(&models.Case{
+		ID: uuid.New(), ClientID: s.ClientID, Title: "Other", Category: "Cat",
+		Status: models.CaseEngaged, AcceptedLawyerID: s.LawyerID, CreatedAt: time.Now(),
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+
+	t.Setenv("LAWYER_MAX_ENGAGED_CASES", "1")
+	if code, err := mockComplete(app, pay.ID); err != nil || code != 409 {
+		t.Fatalf("at cap: want 409, got %d (err=%v)", code, err)
+	}
+	var cs models.Case
+	db.First(&cs, "id = ?", s.CaseID)
+	var got models.Payment
+	db.First(&got, "id = ?", pay.ID)
+	var q models.Quote
+	db.First(&q, "id = ?", s.Quote.ID)
+	if cs.Status != models.CaseOpen || got.Status != models.PayFailed || q.Status != models.QuoteProposed {
+		t.Fatalf("at cap: want open/failed/proposed, got %s/%s/%s", cs.Status, got.Status, q.Status)
+	}
+
+	t.Setenv("LAWYER_MAX_ENGAGED_CASES", "2")
+	if code, err := mockComplete(app, pay.ID); err != nil || code != 200 {
+		t.Fatalf("under cap: want 200, got %d (err=%v)", code, err)
+	}
+	db.First(&cs, "id = ?", s.CaseID)
+	if cs.Status != models.CaseEngaged || cs.AcceptedLawyerID != s.LawyerID {
+		t.Fatalf("under cap: want engaged with the lawyer, got %s", cs.Status)
+	}
+}
+
+/* ============================================================================
+   Tests — quote expiry
+   ============================================================================ */
+
+// Checkout refuses a quote past its validity and accepts one still valid.
+func Test_Checkout_ExpiredVsValidQuote(t *testing.T) {
+	useMockProvider(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+
+	past := time.Now().Add(-time.Hour)
+	if err := db.Model(&models.Quote{}).Where("id = ?", s.Quote.ID).Update("expires_at", &past).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, err := checkout(app, s.Quote.ID); err != nil || code != 409 {
+		t.Fatalf("expired: want 409, got %d (err=%v)", code, err)
+	}
+	var n int64
+	db.Model(&models.Payment{}).Where("quote_id = ?", s.Quote.ID).Count(&n)
+	if n != 0 {
+		t.Fatalf("expired quote must not create a payment, got %d", n)
+	}
+
+	future := time.Now().Add(24 * time.Hour)
+	if err := db.Model(&models.Quote{}).Where("id = ?", s.Quote.ID).Update("expires_at", &future).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, err := checkout(app, s.Quote.ID); err != nil || code != 201 {
+		t.Fatalf("valid: want 201, got %d (err=%v)", code, err)
+	}
+}
+
+// Checkout refuses a quote the client already rejected, in both providers.
+func Test_Checkout_RejectedQuote_Conflict(t *testing.T) {
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	if err := db.Model(&models.Quote{}).Where("id = ?", s.Quote.ID).Update("status", models.QuoteRejected).Error; err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler(db, nil, nil, nil)
+
+	// Mock provider
+	useMockProvider(t)
+	app := newTestApp(h, s.ClientID, string(models.RoleClient))
+	if code, err := checkout(app, s.Quote.ID); err != nil || code != 409 {
+		t.Fatalf("mock: want 409, got %d (err=%v)", code, err)
+	}
+
+	// Stripe provider is refused before any session is created
+	t.Setenv("PAYMENT_PROVIDER", "stripe")
+	if code, err := checkout(app, s.Quote.ID); err != nil || code != 409 {
+		t.Fatalf("stripe: want 409, got %d (err=%v)", code, err)
+	}
+
+	var n int64
+	db.Model(&models.Payment{}).Where("quote_id = ?", s.Quote.ID).Count(&n)
+	if n != 0 {
+		t.Fatalf("rejected quote must not create a payment, got %d", n)
+	}
+}
+
+/* ============================================================================
+   Tests — checkout idempotency
+   ============================================================================ */
+
+// Concurrent checkouts for the same quote end up sharing a single payment.
+func Test_Checkout_ConcurrentRequests_SinglePayment(t *testing.T) {
+	useMockProvider(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+
+	const n = 2
+	codes := make([]int, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i], _ = checkout(app, s.Quote.ID)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, code := range codes {
+		if code != 201 {
+			t.Fatalf("request %d: want 201, got %d", i, code)
+		}
+	}
+	var count int64
+	db.Model(&models.Payment{}).Where("quote_id = ?", s.Quote.ID).Count(&count)
+	if count != 1 {
+		t.Fatalf("want exactly 1 payment, got %d", count)
+	}
+}
+
+/* ============================================================================
+   Tests — currencies
+   ============================================================================ */
+
+// A EUR quote checks out in EUR and completes; a payment whose currency
+// drifted from the quote is refused as a mismatch.
+func Test_Checkout_NonDefaultCurrency(t *testing.T) {
+	useMockProvider(t)
+	t.Setenv("STRIPE_CURRENCY", "sgd")
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	if err := db.Model(&models.Quote{}).Where("id = ?", s.Quote.ID).Update("currency", "EUR").Error; err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+
+	if code, err := checkout(app, s.Quote.ID); err != nil || code != 201 {
+		t.Fatalf("checkout: want 201, got %d (err=%v)", code, err)
+	}
+	var pay models.Payment
+	if err := db.Where("quote_id = ?", s.Quote.ID).First(&pay).Error; err != nil {
+		t.Fatal(err)
+	}
+	if pay.Currency != "EUR" || pay.AmountCents != s.Quote.AmountCents {
+		t.Fatalf("payment should carry the quote's price, got %d %q", pay.AmountCents, pay.Currency)
+	}
+
+	// Currency drift is a mismatch even when the amount matches
+	if err := db.Model(&models.Payment{}).Where("id = ?", pay.ID).Update("currency", "USD").Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, err := mockComplete(app, pay.ID); err != nil || code != 409 {
+		t.Fatalf("mismatch: want 409, got %d (err=%v)", code, err)
+	}
+
+	if err := db.Model(&models.Payment{}).Where("id = ?", pay.ID).Update("currency", "EUR").Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, err := mockComplete(app, pay.ID); err != nil || code != 200 {
+		t.Fatalf("complete: want 200, got %d (err=%v)", code, err)
+	}
+}
+
+/* ============================================================================
+   Tests — payment summary
+   ============================================================================ */
+
+// The owning client sees the checkout summary; anyone else gets 404.
+func Test_GetPayment_SummaryForOwner(t *testing.T) {
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	if err := db.Model(&models.Quote{}).Where("id = ?", s.Quote.ID).
+		Update("note", "Call me at +65 9123 4567").Error; err != nil {
+		t.Fatal(err)
+	}
+	pay := createPayment(t, db, s)
+
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/payments/"+pay.ID.String(), nil))
+	if err != nil || resp.StatusCode != 200 {
+		t.Fatalf("owner: want 200, got %v (err=%v)", resp.StatusCode, err)
+	}
+	var out PaymentSummary
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != pay.ID || out.CaseTitle != "T" || out.AmountCents != 5000 || out.Status != models.PayInitiated {
+		t.Fatalf("unexpected summary: %+v", out)
+	}
+	if out.Currency == "" {
+		t.Fatalf("currency should default, got empty")
+	}
+	if strings.Contains(out.QuoteNote, "9123") {
+		t.Fatalf("note should be redacted before acceptance, got %q", out.QuoteNote)
+	}
+
+	other := newTestApp(NewHandler(db, nil, nil, nil), uuid.New(), string(models.RoleClient))
+	resp, err = other.Test(httptest.NewRequest("GET", "/api/payments/"+pay.ID.String(), nil))
+	if err != nil || resp.StatusCode != 404 {
+		t.Fatalf("non-owner: want 404, got %v (err=%v)", resp.StatusCode, err)
+	}
+}
+
+// The case owner polls the latest payment: 404 before checkout, then the
+// initiated payment, then paid with its paid time. Other clients get 403.
+func Test_GetCasePayment_InitiatedThenPaid(t *testing.T) {
+	useMockProvider(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+
+	get := func(app *fiber.App) (int, CasePaymentStatus) {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/cases/"+s.CaseID.String()+"/payment", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out CasePaymentStatus
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+
+	if code, _ := get(app); code != 404 {
+		t.Fatalf("no payment yet: want 404, got %d", code)
+	}
+
+	pay := createPayment(t, db, s)
+	code, out := get(app)
+	if code != 200 || out.ID != pay.ID || out.Status != models.PayInitiated || out.AmountCents != 5000 ||
+		out.Provider != "mock" || out.Currency == "" || out.PaidAt != nil {
+		t.Fatalf("initiated: unexpected %d %+v", code, out)
+	}
+
+	if code, err := mockComplete(app, pay.ID); err != nil || code != 200 {
+		t.Fatalf("mock complete: want 200, got %d (err=%v)", code, err)
+	}
+	code, out = get(app)
+	if code != 200 || out.Status != models.PayPaid || out.PaidAt == nil {
+		t.Fatalf("paid: unexpected %d %+v", code, out)
+	}
+
+	other := newTestApp(NewHandler(db, nil, nil, nil), uuid.New(), string(models.RoleClient))
+	if code, _ := get(other); code != 403 {
+		t.Fatalf("non-owner: want 403, got %d", code)
+	}
+}
+
+/* ============================================================================
+   Tests — error codes
+   ============================================================================ */
+
+// Each checkout conflict carries its own code while staying a 409.
+func Test_Checkout_ConflictCodes(t *testing.T) {
+	useMockProvider(t)
+	db := openTestDB(t)
+	app := func(s seedOut) *fiber.App {
+		return newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+	}
+
+	// Expired quote
+	expired := seedQuote(t, db)
+	past := time.Now().Add(-time.Hour)
+	db.Model(&models.Quote{}).Where("id = ?", expired.Quote.ID).Update("expires_at", &past)
+	if code, ec := checkoutCode(t, app(expired), expired.Quote.ID); code != 409 || ec != "QUOTE_EXPIRED" {
+		t.Fatalf("expired: want 409 QUOTE_EXPIRED, got %d %s", code, ec)
+	}
+
+	// Rejected quote
+	rejected := seedQuote(t, db)
+	db.Model(&models.Quote{}).Where("id = ?", rejected.Quote.ID).Update("status", models.QuoteRejected)
+	if code, ec := checkoutCode(t, app(rejected), rejected.Quote.ID); code != 409 || ec != "QUOTE_NOT_PROPOSED" {
+		t.Fatalf("rejected: want 409 QUOTE_NOT_PROPOSED, got %d %s", code, ec)
+	}
+
+	// Case no longer open
+	closed := seedQuote(t, db)
+	db.Model(&models.Case{}).Where("id = ?", closed.CaseID).Update("status", models.CaseCancelled)
+	if code, ec := checkoutCode(t, app(closed), closed.Quote.ID); code != 409 || ec != "CASE_NOT_OPEN" {
+		t.Fatalf("cancelled case: want 409 CASE_NOT_OPEN, got %d %s", code, ec)
+	}
+
+	// Already paid
+	paid := seedQuote(t, db)
+	pay := createPayment(t, db, paid)
+	db.Model(&models.Payment{}).Where("id = ?", pay.ID).Update("status", models.PayPaid)
+	if code, ec := checkoutCode(t, app(paid), paid.Quote.ID); code != 409 || ec != "QUOTE_ALREADY_PAID" {
+		t.Fatalf("paid: want 409 QUOTE_ALREADY_PAID, got %d %s", code, ec)
+	}
+
+	// Amount drifted between checkout and completion
+	drift := seedQuote(t, db)
+	dpay := createPayment(t, db, drift)
+	db.Model(&models.Payment{}).Where("id = ?", dpay.ID).Update("amount_cents", 1)
+	req := httptest.NewRequest("POST", "/api/payments/mock/complete",
+		strings.NewReader(`{"payment_id":"`+dpay.ID.String()+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Dev-Secret", "test-secret")
+	resp, err := app(drift).Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body models.ErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != 409 || body.Code != "AMOUNT_MISMATCH" {
+		t.Fatalf("mismatch: want 409 AMOUNT_MISMATCH, got %d %s", resp.StatusCode, body.Code)
+	}
+}
+
+// If another quote on the case is somehow already accepted, completion is
+// refused with a 409 by the database index and nothing is committed.
+func Test_MockComplete_SecondAcceptedQuote_Conflict(t *testing.T) {
+	useMockProvider(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+
+	rivalID := uuid.New()
+	if err := db.Create(&models.User{ID: rivalID, Email: "r_" + rivalID.String()[:8] + "@x.com", Role: models.RoleLawyer}).Error; err != nil {
+		t.Fatal(err)
+	}
+	rival := models.Quote{
+		CaseID: s.CaseID, LawyerID: rivalID, AmountCents: 7000, Days: 2,
+		Status: models.QuoteAccepted, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	if err := db.Create(&rival).Error; err != nil {
+		t.Fatal(err)
+	}
+	pay := createPayment(t, db, s)
+
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+	req := httptest.NewRequest("POST", "/api/payments/mock/complete",
+		strings.NewReader(`{"payment_id":"`+pay.ID.String()+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Dev-Secret", "test-secret")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body models.ErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != 409 || body.Code != "QUOTE_ALREADY_ACCEPTED" {
+		t.Fatalf("want 409 QUOTE_ALREADY_ACCEPTED, got %d %s", resp.StatusCode, body.Code)
+	}
+
+	var got models.Payment
+	db.First(&got, "id = ?", pay.ID)
+	var cs models.Case
+	db.First(&cs, "id = ?", s.CaseID)
+	if got.Status == models.PayPaid || cs.Status != models.CaseOpen {
+		t.Fatalf("nothing should be committed: payment %s, case %s", got.Status, cs.Status)
+	}
+}
+
+/* ============================================================================
+   Tests — idempotency keys
+   ============================================================================ */
+
+// Repeating a key returns the original response, even after the quote left
+// the PROPOSED state, and records the key on the payment.
+func Test_Checkout_IdempotencyKey_Replay(t *testing.T) {
+	useMockProvider(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+
+	code, first, _ := checkoutWithKey(t, app, s.Quote.ID, "tap-1")
+	if code != 201 {
+		t.Fatalf("first: want 201, got %d", code)
+	}
+	var pay models.Payment
+	if err := db.Where("quote_id = ?", s.Quote.ID).First(&pay).Error; err != nil {
+		t.Fatal(err)
+	}
+	if pay.IdempotencyKey == nil || *pay.IdempotencyKey != "tap-1" {
+		t.Fatalf("key should be stored on the payment, got %v", pay.IdempotencyKey)
+	}
+
+	db.Model(&models.Quote{}).Where("id = ?", s.Quote.ID).Update("status", models.QuoteRejected)
+	code, again, _ := checkoutWithKey(t, app, s.Quote.ID, "tap-1")
+	if code != 201 || again != first {
+		t.Fatalf("replay: want 201 %+v, got %d %+v", first, code, again)
+	}
+
+	var count int64
+	db.Model(&models.Payment{}).Where("quote_id = ?", s.Quote.ID).Count(&count)
+	if count != 1 {
+		t.Fatalf("want exactly 1 payment, got %d", count)
+	}
+}
+
+// Reusing a key for another quote is rejected instead of replayed.
+func Test_Checkout_IdempotencyKey_Conflict(t *testing.T) {
+	useMockProvider(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	lawyer2 := uuid.New()
+	if err := db.Create(&models.User{ID: lawyer2, Email: "l2_" + lawyer2.String()[:8] + "@x.com", Role: models.RoleLawyer}).Error; err != nil {
+		t.Fatal(err)
+	}
+	other := models.Quote{
+		CaseID: s.CaseID, LawyerID: lawyer2, AmountCents: 7000, Days: 5,
+		Status: models.QuoteProposed, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+
+	if code, _, _ := checkoutWithKey(t, app, s.Quote.ID, "tap-2"); code != 201 {
+		t.Fatalf("first: want 201, got %d", code)
+	}
+	code, _, ec := checkoutWithKey(t, app, other.ID, "tap-2")
+	if code != 422 || ec != "IDEMPOTENCY_KEY_REUSED" {
+		t.Fatalf("reused key: want 422 IDEMPOTENCY_KEY_REUSED, got %d %s", code, ec)
+	}
+
+	var count int64
+	db.Model(&models.Payment{}).Where("quote_id = ?", other.ID).Count(&count)
+	if count != 0 {
+		t.Fatalf("no payment should be created for the second quote, got %d", count)
+	}
+}
+
+/* ============================================================================
+   Tests — lawyer payment status
+   ============================================================================ */
+
+// caseDetail fetches the case detail as the given user and role.
+func caseDetail(t *testing.T, db *gorm.DB, caseID, userID uuid.UUID, role models.Role) map[string]any {
+	t.Helper()
+	app := fiber.New()
+	app.Use(injectAuth(userID, string(role)))
+	app.Get("/api/cases/:id", cases.NewHandler(db, nil).GetDetail)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/cases/"+caseID.String(), nil))
+	if err != nil || resp.StatusCode != 200 {
+		t.Fatalf("detail as %s: want 200, got %v (err=%v)", role, resp, err)
+	}
+	var out map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// Once the payment finalizes, the accepted lawyer sees it as paid (without
+// provider IDs); the client's view carries no payment block.
+func Test_LawyerDetail_ShowsPaidAfterCompletion(t *testing.T) {
+	useMockProvider(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	pay := createPayment(t, db, s)
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+
+	if code, err := mockComplete(app, pay.ID); err != nil || code != 200 {
+		t.Fatalf("mock complete: code=%d err=%v", code, err)
+	}
+
+	got := caseDetail(t, db, s.CaseID, s.LawyerID, models.RoleLawyer)
+	p, ok := got["payment"].(map[string]any)
+	if !ok {
+		t.Fatalf("lawyer view should include payment, got %v", got["payment"])
+	}
+	if p["status"] != string(models.PayPaid) || p["paid_at"] == nil {
+		t.Fatalf("want paid with paid_at, got %v", p)
+	}
+	for _, k := range []string{"stripe_session_id", "stripe_payment_intent", "StripeSessionID", "StripePaymentIntent"} {
+		if _, leaked := p[k]; leaked {
+			t.Fatalf("payment block leaks %s", k)
+		}
+	}
+
+	if _, has := caseDetail(t, db, s.CaseID, s.ClientID, models.RoleClient)["payment"]; has {
+		t.Fatalf("client view should be unchanged (no payment block)")
+	}
+}
+
+/* ============================================================================
+   Tests — stripe receipt
+   ============================================================================ */
+
+// stubStripe points the Stripe client at a fake API that serves one
+// PaymentIntent with an expanded latest_charge.
+func stubStripe(t *testing.T, piID, receiptURL string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/payment_intents/"+piID || !strings.Contains(r.URL.RawQuery, "latest_charge") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":     piID,
+			"object": "payment_intent",
+			"latest_charge": map[string]any{
+				"id": "ch_test", "object": "charge", "receipt_url": receiptURL,
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("STRIPE_SECRET", "sk_test_stub")
+	stripe.SetBackend(stripe.APIBackend, stripe.GetBackendWithConfig(stripe.APIBackend,
+		&stripe.BackendConfig{URL: stripe.String(srv.URL)}))
+	t.Cleanup(func() { stripe.SetBackend(stripe.APIBackend, nil) })
+}
+
+// The webhook stores the charge's hosted receipt URL, and the owner sees it
+// in the payment summary.
+func Test_StripeWebhook_PersistsReceiptURL(t *testing.T) {
+	const secret = "whsec_test"
+	const receipt = "https://pay.stripe.com/receipts/acct_1/ch_test/rcpt_1"
+	t.Setenv("STRIPE_WEBHOOK_SECRET", secret)
+	stubStripe(t, "pi_test", receipt)
+
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	pay := createPayment(t, db, s)
+
+	payload, _ := json.Marshal(map[string]any{
+		"id":          "evt_test",
+		"object":      "event",
+		"type":        "checkout.session.completed",
+		"api_version": stripe.APIVersion,
+		"data": map[string]any{"object": map[string]any{
+			"id":                  "cs_test",
+			"object":              "checkout.session",
+			"client_reference_id": pay.ID.String(),
+			"payment_intent":      "pi_test",
+		}},
+	})
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{Payload: payload, Secret: secret})
+
+	app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+	app.Post("/api/payments/stripe/webhook", NewHandler(db, nil, nil, nil).StripeWebhook)
+	req := httptest.NewRequest("POST", "/api/payments/stripe/webhook", bytes.NewReader(payload))
+	req.Header.Set("Stripe-Signature", signed.Header)
+	resp, err := app.Test(req, -1)
+	if err != nil || resp.StatusCode != 200 {
+		b, _ := io.ReadAll(resp.Body)
+		t.Fatalf("webhook: want 200, got %v (err=%v) %s", resp.StatusCode, err, b)
+	}
+
+	var got models.Payment
+	if err := db.First(&got, "id = ?", pay.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != models.PayPaid || got.ReceiptURL == nil || *got.ReceiptURL != receipt {
+		t.Fatalf("want paid with receipt url, got status=%s receipt=%v", got.Status, got.ReceiptURL)
+	}
+
+	summaryApp := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+	resp, _ = summaryApp.Test(httptest.NewRequest("GET", "/api/payments/"+pay.ID.String(), nil))
+	var sum PaymentSummary
+	_ = json.NewDecoder(resp.Body).Decode(&sum)
+	if sum.ReceiptURL == nil || *sum.ReceiptURL != receipt {
+		t.Fatalf("summary should expose receipt_url, got %v", sum.ReceiptURL)
+	}
+}
+
+// The engagement a verified webhook triggers is logged as a system action,
+// not under the client's id.
+func Test_StripeWebhook_EngagementIsSystemActor(t *testing.T) {
+	const secret = "whsec_test"
+	t.Setenv("STRIPE_WEBHOOK_SECRET", secret)
+	stubStripe(t, "pi_test", "")
+
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	pay := createPayment(t, db, s)
+
+	payload, _ := json.Marshal(map[string]any{
+		"id":          "evt_test",
+		"object":      "event",
+		"type":        "checkout.session.completed",
+		"api_version": stripe.APIVersion,
+		"data": map[string]any{"object": map[string]any{
+			"id":                  "cs_test",
+			"object":              "checkout.session",
+			"client_reference_id": pay.ID.String(),
+			"payment_intent":      "pi_test",
+		}},
+	})
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{Payload: payload, Secret: secret})
+
+	app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+	app.Post("/api/payments/stripe/webhook", NewHandler(db, nil, nil, nil).StripeWebhook)
+	req := httptest.NewRequest("POST", "/api/payments/stripe/webhook", bytes.NewReader(payload))
+	req.Header.Set("Stripe-Signature", signed.Header)
+	resp, err := app.Test(req, -1)
+	if err != nil || resp.StatusCode != 200 {
+		b, _ := io.ReadAll(resp.Body)
+		t.Fatalf("webhook: want 200, got %v (err=%v) %s", resp.StatusCode, err, b)
+	}
+
+	var h models.CaseHistory
+	if err := db.Where("case_id = ? AND new_status = ?", s.CaseID, models.CaseEngaged).
+		Order("created_at DESC").First(&h).Error; err != nil {
+		t.Fatalf("engagement history: %v", err)
+	}
+	if h.ActorType != models.ActorSystem || h.ActorID != uuid.Nil {
+		t.Fatalf("want system actor with nil id, got type=%q id=%s", h.ActorType, h.ActorID)
+	}
+}
+
+// With a comma-separated STRIPE_WEBHOOK_SECRET (rotation), an event signed
+// with any listed secret verifies; one signed with an unknown secret doesn't.
+func Test_ConstructEvent_AcceptsAnyRotatedSecret(t *testing.T) {
+	payload, _ := json.Marshal(map[string]any{
+		"id":          "evt_test",
+		"object":      "event",
+		"type":        "checkout.session.completed",
+		"api_version": stripe.APIVersion,
+	})
+	sign := func(secret string) string {
+		return webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{Payload: payload, Secret: secret}).Header
+	}
+
+	for _, secrets := range []string{"whsec_new", "whsec_old, whsec_new", "whsec_new,"} {
+		if evt, err := constructEvent(payload, sign("whsec_new"), secrets); err != nil || evt.ID != "evt_test" {
+			t.Fatalf("%q: want verified event, got %v (err=%v)", secrets, evt.ID, err)
+		}
+	}
+	if _, err := constructEvent(payload, sign("whsec_other"), "whsec_old,whsec_new"); err == nil {
+		t.Fatal("unknown secret should fail verification")
+	}
+	if _, err := constructEvent(payload, sign("whsec_new"), ""); err == nil {
+		t.Fatal("no secret configured should fail verification")
+	}
+}
+
+/* ============================================================================
+   Tests — price frozen at checkout
+   ============================================================================ */
+
+// Checkout freezes the quote's price on the payment. If the quote changes
+// afterwards, completing or re-starting that checkout is a 409
+// AMOUNT_MISMATCH; once the payment has failed, checkout re-prices it.
+func Test_Checkout_QuoteAmountChangedAfterCheckout(t *testing.T) {
+	useMockProvider(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+
+	if code, _ := checkoutCode(t, app, s.Quote.ID); code != 201 {
+		t.Fatalf("checkout: want 201, got %d", code)
+	}
+	var pay models.Payment
+	if err := db.Where("quote_id = ?", s.Quote.ID).First(&pay).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Quote edited behind the checkout's back
+	if err := db.Model(&models.Quote{}).Where("id = ?", s.Quote.ID).Update("amount_cents", 9000).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, err := mockComplete(app, pay.ID); err != nil || code != 409 {
+		t.Fatalf("complete after change: want 409, got %d (err=%v)", code, err)
+	}
+	if code, ec := checkoutCode(t, app, s.Quote.ID); code != 409 || ec != "AMOUNT_MISMATCH" {
+		t.Fatalf("checkout after change: want 409 AMOUNT_MISMATCH, got %d %s", code, ec)
+	}
+	var cs models.Case
+	db.First(&cs, "id = ?", s.CaseID)
+	db.First(&pay, "id = ?", pay.ID)
+	if cs.Status != models.CaseOpen || pay.Status != models.PayInitiated || pay.AmountCents != 5000 {
+		t.Fatalf("nothing should change on a mismatch: case=%s payment=%s %d", cs.Status, pay.Status, pay.AmountCents)
+	}
+
+	// Abandoned (swept) → the restart picks up the new price
+	db.Model(&models.Payment{}).Where("id = ?", pay.ID).Update("status", models.PayFailed)
+	if code, _ := checkoutCode(t, app, s.Quote.ID); code != 201 {
+		t.Fatalf("restart: want 201, got %d", code)
+	}
+	db.First(&pay, "id = ?", pay.ID)
+	if pay.Status != models.PayInitiated || pay.AmountCents != 9000 {
+		t.Fatalf("restart should re-price the payment, got %s %d", pay.Status, pay.AmountCents)
+	}
+	if code, err := mockComplete(app, pay.ID); err != nil || code != 200 {
+		t.Fatalf("complete: want 200, got %d (err=%v)", code, err)
+	}
+}
+
+// A completed session whose charged total or metadata amount disagrees with
+// the frozen price doesn't engage the case.
+func Test_StripeWebhook_RejectsTamperedAmount(t *testing.T) {
+	const secret = "whsec_test"
+	t.Setenv("STRIPE_WEBHOOK_SECRET", secret)
+	stubStripe(t, "pi_test", "")
+
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	pay := createPayment(t, db, s)
+	app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+	app.Post("/api/payments/stripe/webhook", NewHandler(db, nil, nil, nil).StripeWebhook)
+
+	send := func(amountTotal int64, metaAmount string) int {
+		t.Helper()
+		payload, _ := json.Marshal(map[string]any{
+			"id":          "evt_test",
+			"object":      "event",
+			"type":        "checkout.session.completed",
+			"api_version": stripe.APIVersion,
+			"data": map[string]any{"object": map[string]any{
+				"id":                  "cs_test",
+				"object":              "checkout.session",
+				"client_reference_id": pay.ID.String(),
+				"payment_intent":      "pi_test",
+				"amount_total":        amountTotal,
+				"metadata":            map[string]string{"amount_cents": metaAmount},
+			}},
+		})
+		signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{Payload: payload, Secret: secret})
+		req := httptest.NewRequest("POST", "/api/payments/stripe/webhook", bytes.NewReader(payload))
+		req.Header.Set("Stripe-Signature", signed.Header)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+
+	if code := send(100, "5000"); code != 409 {
+		t.Fatalf("short charge: want 409, got %d", code)
+	}
+	if code := send(5000, "100"); code != 409 {
+		t.Fatalf("tampered metadata: want 409, got %d", code)
+	}
+	var got models.Payment
+	db.First(&got, "id = ?", pay.ID)
+	if got.Status != models.PayInitiated {
+		t.Fatalf("mismatched sessions must not pay, got %s", got.Status)
+	}
+
+	if code := send(5000, "5000"); code != 200 {
+		t.Fatalf("matching session: want 200, got %d", code)
+	}
+	db.First(&got, "id = ?", pay.ID)
+	if got.Status != models.PayPaid {
+		t.Fatalf("want paid, got %s", got.Status)
+	}
+}
+
+/* ============================================================================
+   Tests — checkout return origin
+   ============================================================================ */
+
+// stubStripeCheckout serves Checkout session creation and records the
+// success_url of each session created.
+func stubStripeCheckout(t *testing.T) *[]string {
+	t.Helper()
+	var mu sync.Mutex
+	urls := &[]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1/checkout/sessions" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = r.ParseForm()
+		mu.Lock()
+		*urls = append(*urls, r.PostForm.Get("success_url"))
+		n := len(*urls)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id": "cs_" + strconv.Itoa(n), "object": "checkout.session", "url": "https://checkout.stripe.test/" + strconv.Itoa(n),
+		})
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("STRIPE_SECRET", "sk_test_stub")
+	stripe.SetBackend(stripe.APIBackend, stripe.GetBackendWithConfig(stripe.APIBackend,
+		&stripe.BackendConfig{URL: stripe.String(srv.URL)}))
+	t.Cleanup(func() { stripe.SetBackend(stripe.APIBackend, nil) })
+	return urls
+}
+
+// An allowlisted return_origin becomes the Stripe success URL's origin;
+// without one PUBLIC_BASE_URL is used, and an unlisted origin is a 400
+// before any payment exists.
+func Test_Checkout_ReturnOrigin(t *testing.T) {
+	t.Setenv("PAYMENT_PROVIDER", "stripe")
+	t.Setenv("PUBLIC_BASE_URL", "https://app.example.com")
+	t.Setenv("FRONTEND_ORIGIN", "https://app.example.com,https://*.partner.io")
+	urls := stubStripeCheckout(t)
+	db := openTestDB(t)
+
+	start := func(s seedOut, body string) (int, string) {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/api/checkout/"+s.Quote.ID.String(), strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var eb models.ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&eb)
+		return resp.StatusCode, eb.Code
+	}
+
+	partner := seedQuote(t, db)
+	if code, _ := start(partner, `{"return_origin":"https://Portal.Partner.io"}`); code != 201 {
+		t.Fatalf("allowed origin: want 201, got %d", code)
+	}
+	if code, _ := start(seedQuote(t, db), ""); code != 201 {
+		t.Fatalf("no origin: want 201, got %d", code)
+	}
+	if len(*urls) != 2 ||
+		!strings.HasPrefix((*urls)[0], "https://portal.partner.io/payments/success?pid=") ||
+		!strings.HasPrefix((*urls)[1], "https://app.example.com/payments/success?pid=") {
+		t.Fatalf("unexpected success urls: %v", *urls)
+	}
+
+	evil := seedQuote(t, db)
+	if code, ec := start(evil, `{"return_origin":"https://evil.example.net"}`); code != 400 || ec != "RETURN_ORIGIN_NOT_ALLOWED" {
+		t.Fatalf("disallowed origin: want 400 RETURN_ORIGIN_NOT_ALLOWED, got %d %s", code, ec)
+	}
+	var n int64
+	db.Model(&models.Payment{}).Where("quote_id = ?", evil.Quote.ID).Count(&n)
+	if n != 0 || len(*urls) != 2 {
+		t.Fatalf("a rejected origin must not start checkout (payments=%d sessions=%d)", n, len(*urls))
+	}
+}
+
+/* ============================================================================
+   Tests — stale payment sweep
+   ============================================================================ */
+
+// stubStripeSessions points the Stripe client at a fake API serving Checkout
+// sessions with the given statuses.
+func stubStripeSessions(t *testing.T, statuses map[string]string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v1/checkout/sessions/")
+		st, ok := statuses[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "object": "checkout.session", "status": st})
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("STRIPE_SECRET", "sk_test_stub")
+	stripe.SetBackend(stripe.APIBackend, stripe.GetBackendWithConfig(stripe.APIBackend,
+		&stripe.BackendConfig{URL: stripe.String(srv.URL)}))
+	t.Cleanup(func() { stripe.SetBackend(stripe.APIBackend, nil) })
+}
+
+// stalePayment seeds an initiated payment created age ago, optionally tied
+// to a Stripe session.
+func stalePayment(t *testing.T, db *gorm.DB, age time.Duration, sessionID string) models.Payment {
+	t.Helper()
+	pay := createPayment(t, db, seedQuote(t, db))
+	upd := map[string]any{"created_at": time.Now().Add(-age)}
+	if sessionID != "" {
+		upd["stripe_session_id"] = sessionID
+	}
+	if err := db.Model(&models.Payment{}).Where("id = ?", pay.ID).Updates(upd).Error; err != nil {
+		t.Fatal(err)
+	}
+	return pay
+}
+
+// Only old INITIATED payments without an open session are failed, each with a
+// history entry; a second run changes nothing.
+func Test_FailStale_SelectionAndTransition(t *testing.T) {
+	stubStripeSessions(t, map[string]string{"cs_open": "open", "cs_expired": "expired"})
+	db := openTestDB(t)
+
+	old := stalePayment(t, db, 2*time.Hour, "")
+	expired := stalePayment(t, db, 2*time.Hour, "cs_expired")
+	open := stalePayment(t, db, 2*time.Hour, "cs_open")
+	fresh := stalePayment(t, db, 5*time.Minute, "")
+	paid := stalePayment(t, db, 2*time.Hour, "")
+	db.Model(&models.Payment{}).Where("id = ?", paid.ID).Update("status", models.PayPaid)
+
+	n, err := FailStale(db, time.Now(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("want 2 payments failed, got %d", n)
+	}
+
+	want := map[uuid.UUID]models.PayStatus{
+		old.ID:     models.PayFailed,
+		expired.ID: models.PayFailed,
+		open.ID:    models.PayInitiated,
+		fresh.ID:   models.PayInitiated,
+		paid.ID:    models.PayPaid,
+	}
+	for id, st := range want {
+		var got models.Payment
+		if err := db.First(&got, "id = ?", id).Error; err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != st {
+			t.Fatalf("payment %s: want %s, got %s", id, st, got.Status)
+		}
+	}
+
+	var hist int64
+	db.Model(&models.CaseHistory{}).Where("case_id = ? AND action = ?", old.CaseID, "payment_failed").Count(&hist)
+	if hist != 1 {
+		t.Fatalf("want 1 payment_failed history entry, got %d", hist)
+	}
+
+	if n, err := FailStale(db, time.Now(), time.Hour); err != nil || n != 0 {
+		t.Fatalf("second run: want 0, got %d (err=%v)", n, err)
+	}
+}
+
+// Checking out a quote whose payment was swept restarts the same payment.
+func Test_Checkout_AfterSweep_RestartsPayment(t *testing.T) {
+	useMockProvider(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	pay := createPayment(t, db, s)
+	db.Model(&models.Payment{}).Where("id = ?", pay.ID).Update("created_at", time.Now().Add(-2*time.Hour))
+	if n, err := FailStale(db, time.Now(), time.Hour); err != nil || n != 1 {
+		t.Fatalf("sweep: want 1, got %d (err=%v)", n, err)
+	}
+
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+	code, out, ec := checkoutWithKey(t, app, s.Quote.ID, "retry-1")
+	if code != 201 || out.PaymentID != pay.ID.String() {
+		t.Fatalf("want 201 for the same payment, got %d %s %+v", code, ec, out)
+	}
+	var got models.Payment
+	db.First(&got, "id = ?", pay.ID)
+	if got.Status != models.PayInitiated || time.Since(got.CreatedAt) > time.Minute {
+		t.Fatalf("want a fresh initiated payment, got %s created %s", got.Status, got.CreatedAt)
+	}
+}
+
+// A restarted payment drops the failed attempt's Stripe references and key,
+// so the new session's webhook stores its own payment intent.
+func Test_Checkout_AfterFailure_WebhookStoresNewIntent(t *testing.T) {
+	const secret = "whsec_test"
+	t.Setenv("PAYMENT_PROVIDER", "stripe")
+	t.Setenv("PUBLIC_BASE_URL", "https://app.example.com")
+	t.Setenv("STRIPE_WEBHOOK_SECRET", secret)
+	stubStripeCheckout(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	pay := createPayment(t, db, s)
+	if err := db.Model(&models.Payment{}).Where("id = ?", pay.ID).Updates(map[string]any{
+		"status": models.PayFailed, "stripe_session_id": "cs_old", "stripe_payment_intent": "pi_old",
+		"receipt_url": "https://pay.stripe.com/receipts/old", "idempotency_key": "first-try",
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+	if code, out, ec := checkoutWithKey(t, app, s.Quote.ID, "retry-1"); code != 201 || out.PaymentID != pay.ID.String() {
+		t.Fatalf("retry: want 201 for the same payment, got %d %s %+v", code, ec, out)
+	}
+	var got models.Payment
+	db.First(&got, "id = ?", pay.ID)
+	if got.Status != models.PayInitiated || got.StripePaymentIntent != nil || got.ReceiptURL != nil ||
+		got.StripeSessionID == nil || *got.StripeSessionID == "cs_old" ||
+		got.IdempotencyKey == nil || *got.IdempotencyKey != "retry-1" {
+		t.Fatalf("want a clean restarted attempt, got %+v", got)
+	}
+
+	payload, _ := json.Marshal(map[string]any{
+		"id":          "evt_test",
+		"object":      "event",
+		"type":        "checkout.session.completed",
+		"api_version": stripe.APIVersion,
+		"data": map[string]any{"object": map[string]any{
+			"id":                  *got.StripeSessionID,
+			"object":              "checkout.session",
+			"client_reference_id": pay.ID.String(),
+			"payment_intent":      "pi_new",
+		}},
+	})
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{Payload: payload, Secret: secret})
+	wh := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+	wh.Post("/api/payments/stripe/webhook", NewHandler(db, nil, nil, nil).StripeWebhook)
+	req := httptest.NewRequest("POST", "/api/payments/stripe/webhook", bytes.NewReader(payload))
+	req.Header.Set("Stripe-Signature", signed.Header)
+	resp, err := wh.Test(req, -1)
+	if err != nil || resp.StatusCode != 200 {
+		b, _ := io.ReadAll(resp.Body)
+		t.Fatalf("webhook: want 200, got %v (err=%v) %s", resp.StatusCode, err, b)
+	}
+
+	db.First(&got, "id = ?", pay.ID)
+	if got.Status != models.PayPaid || got.StripePaymentIntent == nil || *got.StripePaymentIntent != "pi_new" {
+		t.Fatalf("want paid with the new intent, got status=%s intent=%v", got.Status, got.StripePaymentIntent)
+	}
+}
+
+/* ============================================================================
+   Tests — payments that can't engage
+   ============================================================================ */
+
+// stubStripeRefunds serves refund creation and records the payment intent of
+// each refund; every other Stripe call is a 404 (receipts are best-effort).
+func stubStripeRefunds(t *testing.T) func() []string {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		refunded []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1/refunds" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = r.ParseForm()
+		mu.Lock()
+		refunded = append(refunded, r.PostForm.Get("payment_intent"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "re_test", "object": "refund", "status": "succeeded"})
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("STRIPE_SECRET", "sk_test_stub")
+	stripe.SetBackend(stripe.APIBackend, stripe.GetBackendWithConfig(stripe.APIBackend,
+		&stripe.BackendConfig{URL: stripe.String(srv.URL)}))
+	t.Cleanup(func() { stripe.SetBackend(stripe.APIBackend, nil) })
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), refunded...)
+	}
+}
+
+// completeViaWebhook delivers a signed checkout.session.completed event for
+// the payment (STRIPE_WEBHOOK_SECRET must be set to secret) and returns the
+// status code.
+func completeViaWebhook(t *testing.T, db *gorm.DB, secret string, paymentID uuid.UUID, piID string) int {
+	t.Helper()
+	payload, _ := json.Marshal(map[string]any{
+		"id":          "evt_test",
+		"object":      "event",
+		"type":        "checkout.session.completed",
+		"api_version": stripe.APIVersion,
+		"data": map[string]any{"object": map[string]any{
+			"id":                  "cs_test",
+			"object":              "checkout.session",
+			"client_reference_id": paymentID.String(),
+			"payment_intent":      piID,
+		}},
+	})
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{Payload: payload, Secret: secret})
+	app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+	app.Post("/api/payments/stripe/webhook", NewHandler(db, nil, nil, nil).StripeWebhook)
+	req := httptest.NewRequest("POST", "/api/payments/stripe/webhook", bytes.NewReader(payload))
+	req.Header.Set("Stripe-Signature", signed.Header)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+// A payment completing on a case deleted meanwhile doesn't 500 (Stripe would
+// keep retrying): it is marked failed, refunded, and the case stays deleted.
+func Test_StripeWebhook_DeletedCase_RefundsPayment(t *testing.T) {
+	const secret = "whsec_test"
+	t.Setenv("STRIPE_WEBHOOK_SECRET", secret)
+	refunded := stubStripeRefunds(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	pay := createPayment(t, db, s)
+	if err := db.Delete(&models.Case{}, "id = ?", s.CaseID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if code := completeViaWebhook(t, db, secret, pay.ID, "pi_deleted"); code != 200 {
+		t.Fatalf("webhook: want 200, got %d", code)
+	}
+	if code := completeViaWebhook(t, db, secret, pay.ID, "pi_deleted"); code != 200 {
+		t.Fatalf("retried webhook: want 200, got %d", code)
+	}
+
+	var got models.Payment
+	db.First(&got, "id = ?", pay.ID)
+	var cs models.Case
+	db.Unscoped().First(&cs, "id = ?", s.CaseID)
+	if got.Status != models.PayFailed || cs.Status != models.CaseOpen || !cs.DeletedAt.Valid {
+		t.Fatalf("want failed payment on a still-deleted open case, got %s / %s deleted=%v", got.Status, cs.Status, cs.DeletedAt.Valid)
+	}
+	if r := refunded(); len(r) == 0 || r[0] != "pi_deleted" {
+		t.Fatalf("want pi_deleted refunded, got %v", r)
+	}
+}
+
+// A quote rejected while its checkout was in flight isn't accepted by the
+// payment completing: the payment fails (mock: 409) and Stripe refunds it.
+func Test_Complete_RejectedQuote_NotAccepted(t *testing.T) {
+	const secret = "whsec_test"
+	t.Setenv("STRIPE_WEBHOOK_SECRET", secret)
+	refunded := stubStripeRefunds(t)
+	db := openTestDB(t)
+
+	check := func(s seedOut, pay models.Payment) {
+		t.Helper()
+		var q models.Quote
+		db.First(&q, "id = ?", s.Quote.ID)
+		var cs models.Case
+		db.First(&cs, "id = ?", s.CaseID)
+		var got models.Payment
+		db.First(&got, "id = ?", pay.ID)
+		if q.Status != models.QuoteRejected || cs.Status != models.CaseOpen || got.Status != models.PayFailed {
+			t.Fatalf("want rejected/open/failed, got %s/%s/%s", q.Status, cs.Status, got.Status)
+		}
+	}
+
+	s := seedQuote(t, db)
+	pay := createPayment(t, db, s)
+	db.Model(&models.Quote{}).Where("id = ?", s.Quote.ID).Update("status", models.QuoteRejected)
+	if code := completeViaWebhook(t, db, secret, pay.ID, "pi_rejected"); code != 200 {
+		t.Fatalf("webhook: want 200, got %d", code)
+	}
+	check(s, pay)
+	if r := refunded(); len(r) != 1 || r[0] != "pi_rejected" {
+		t.Fatalf("want pi_rejected refunded, got %v", r)
+	}
+
+	useMockProvider(t)
+	s = seedQuote(t, db)
+	pay = createPayment(t, db, s)
+	db.Model(&models.Quote{}).Where("id = ?", s.Quote.ID).Update("status", models.QuoteRejected)
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+	if code, err := mockComplete(app, pay.ID); err != nil || code != 409 {
+		t.Fatalf("mock: want 409, got %d (err=%v)", code, err)
+	}
+	check(s, pay)
+}
+
+// A payment completing on a case that is no longer open (paused or
+// cancelled meanwhile) doesn't leave the client charged for nothing: it is
+// marked failed and refunded, and the case keeps its status.
+func Test_StripeWebhook_CaseNotOpen_RefundsPayment(t *testing.T) {
+	const secret = "whsec_test"
+	t.Setenv("STRIPE_WEBHOOK_SECRET", secret)
+	refunded := stubStripeRefunds(t)
+	db := openTestDB(t)
+
+	for i, status := range []models.CaseStatus{models.CasePaused, models.CaseCancelled} {
+		s := seedQuote(t, db)
+		pay := createPayment(t, db, s)
+		db.Model(&models.Case{}).Where("id = ?", s.CaseID).Update("status", status)
+
+		pi := "pi_" + string(status)
+		if code := completeViaWebhook(t, db, secret, pay.ID, pi); code != 200 {
+			t.Fatalf("%s: want 200, got %d", status, code)
+		}
+		var got models.Payment
+		db.First(&got, "id = ?", pay.ID)
+		var cs models.Case
+		db.First(&cs, "id = ?", s.CaseID)
+		if got.Status != models.PayFailed || cs.Status != status {
+			t.Fatalf("%s: want failed payment and unchanged case, got %s / %s", status, got.Status, cs.Status)
+		}
+		if r := refunded(); len(r) != i+1 || r[i] != pi {
+			t.Fatalf("%s: want %s refunded, got %v", status, pi, r)
+		}
+	}
+}
+
+// A payment refunded because the lawyer was at capacity keeps its payment
+// intent in the case history when checkout restarts it, and the next attempt
+// (a new intent) gets a refund of its own.
+func Test_CapacityRefund_SurvivesRestart(t *testing.T) {
+	const secret = "whsec_test"
+	t.Setenv("STRIPE_WEBHOOK_SECRET", secret)
+	t.Setenv("LAWYER_MAX_ENGAGED_CASES", "1")
+	refunded := stubStripeRefunds(t)
+	db := openTestDB(t)
+	s := seedQuote(t, db)
+	pay := createPayment(t, db, s)
+	if err := db.Create(&models.Case{
+		ID: uuid.New(), ClientID: s.ClientID, Title: "Other", Category: "Cat",
+		Status: models.CaseEngaged, AcceptedLawyerID: s.LawyerID, CreatedAt: time.Now(),
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	notes := func(action string) []string {
+		var out []string
+		db.Model(&models.CaseHistory{}).Where("case_id = ? AND action = ?", s.CaseID, action).
+			Order("created_at").Pluck("reason", &out)
+		return out
+	}
+
+	if code := completeViaWebhook(t, db, secret, pay.ID, "pi_first"); code != 200 {
+		t.Fatalf("first attempt: want 200, got %d", code)
+	}
+	if n := notes("payment_refunded"); len(n) != 1 || !strings.Contains(n[0], "pi_first") {
+		t.Fatalf("want the refund of pi_first recorded, got %v", n)
+	}
+
+	useMockProvider(t)
+	app := newTestApp(NewHandler(db, nil, nil, nil), s.ClientID, string(models.RoleClient))
+	if code, out, ec := checkoutWithKey(t, app, s.Quote.ID, "retry-1"); code != 201 || out.PaymentID != pay.ID.String() {
+		t.Fatalf("restart: want 201 for the same payment, got %d %s %+v", code, ec, out)
+	}
+	if n := notes("payment_restarted"); len(n) != 1 || !strings.Contains(n[0], "pi_first") {
+		t.Fatalf("want the previous intent kept in history, got %v", n)
+	}
+
+	if code := completeViaWebhook(t, db, secret, pay.ID, "pi_second"); code != 200 {
+		t.Fatalf("second attempt: want 200, got %d", code)
+	}
+	if r := refunded(); len(r) != 2 || r[0] != "pi_first" || r[1] != "pi_second" {
+		t.Fatalf("want both intents refunded, got %v", r)
+	}
+	if n := notes("payment_refunded"); len(n) != 2 || !strings.Contains(n[1], "pi_second") {
+		t.Fatalf("want the second refund recorded, got %v", n)
+	}
+}
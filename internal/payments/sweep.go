@@ -0,0 +1,124 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v82"
+	"github.com/stripe/stripe-go/v82/checkout/session"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/utils"
+)
+
+/* ============================ Stale Sweep ================================= */
+
+// SweepInterval is how often the stale-payment sweep runs.
+// Reads PAYMENT_SWEEP_INTERVAL (Go duration, default 10m).
+func SweepInterval() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("PAYMENT_SWEEP_INTERVAL")); err == nil && d > 0 {
+		return d
+	}
+	return 10 * time.Minute
+}
+
+// StaleAfter is how long a payment may stay INITIATED before the sweep fails it.
+// Reads PAYMENT_STALE_AFTER (Go duration, default 1h).
+func StaleAfter() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("PAYMENT_STALE_AFTER")); err == nil && d > 0 {
+		return d
+	}
+	return time.Hour
+}
+
+// stripeSessionOpen reports whether a Checkout session can still be paid.
+func stripeSessionOpen(id string) (bool, error) {
+	stripe.Key = os.Getenv("STRIPE_SECRET")
+	sess, err := session.Get(id, nil)
+	if err != nil {
+		return false, err
+	}
+	return sess.Status == stripe.CheckoutSessionStatusOpen, nil
+}
+
+// FailStale marks INITIATED payments created before now-maxAge as FAILED and
+// logs a payment_failed history entry on the case. Payments whose Stripe
+// session is verifiably still open are left alone; one that can't be checked
+// is failed anyway (a late webhook still finalizes it). Each row is re-read
+// under a row lock, so a webhook that wins the race is never overwritten, and
+// rows already locked are skipped until the next run.
+func FailStale(db *gorm.DB, now time.Time, maxAge time.Duration) (int, error) {
+	var stale []models.Payment
+	if err := db.Select("id, stripe_session_id").
+		Where("status = ? AND created_at < ?", models.PayInitiated, now.Add(-maxAge)).
+		Find(&stale).Error; err != nil {
+		return 0, err
+	}
+
+	failed := 0
+	for _, p := range stale {
+		// Ask Stripe before taking any locks
+		if p.StripeSessionID != nil && *p.StripeSessionID != "" {
+			open, err := stripeSessionOpen(*p.StripeSessionID)
+			if err != nil {
+				log.Printf("payment sweep: session %s for %s not verified: %v", *p.StripeSessionID, p.ID, err)
+			}
+			if open {
+				continue
+			}
+		}
+		ok, err := failPayment(db, p.ID, now)
+		if err != nil {
+			return failed, err
+		}
+		if ok {
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+// failPayment moves one payment INITIATED → FAILED. It reports false when the
+// row is locked elsewhere or no longer INITIATED.
+func failPayment(db *gorm.DB, id uuid.UUID, now time.Time) (bool, error) {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	var pay models.Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		First(&pay, "id = ?", id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if pay.Status != models.PayInitiated {
+		tx.Rollback()
+		return false, nil
+	}
+
+	if err := tx.Model(&models.Payment{}).Where("id = ?", pay.ID).
+		Updates(map[string]any{"status": models.PayFailed, "updated_at": now}).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	var cs models.Case
+	if err := tx.Unscoped().Select("id, status").First(&cs, "id = ?", pay.CaseID).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	// System action: no user actor
+	utils.LogCaseHistory(context.Background(), tx, cs.ID, uuid.Nil, models.ActorSystem,
+		"payment_failed", cs.Status, cs.Status, "payment "+pay.ID.String()+" not completed in time")
+
+	return true, tx.Commit().Error
+}
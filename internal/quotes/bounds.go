@@ -0,0 +1,85 @@
+package quotes
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/money"
+)
+
+// amountBounds is an optional band for quote amounts in one case category and
+// currency. Zero means "no limit" on that side.
+type amountBounds struct {
+	Min, Max int
+}
+
+// check returns a validation message when cents falls outside the band.
+func (b amountBounds) check(cents int, category string) string {
+	switch {
+	case b.Min > 0 && b.Max > 0 && (cents < b.Min || cents > b.Max):
+		return fmt.Sprintf("Amount must be between %d and %d cents for %s cases", b.Min, b.Max, category)
+	case b.Min > 0 && cents < b.Min:
+		return fmt.Sprintf("Amount must be at least %d cents for %s cases", b.Min, category)
+	case b.Max > 0 && cents > b.Max:
+		return fmt.Sprintf("Amount must be at most %d cents for %s cases", b.Max, category)
+	}
+	return ""
+}
+
+// bandKey identifies a band: lower-cased category and upper-cased currency.
+type bandKey struct {
+	Category, Currency string
+}
+
+// categoryBounds reads per-category amount bands, in cents of one currency.
+// Env: QUOTE_CATEGORY_BOUNDS="family=50000:500000,family/EUR=45000:450000,corporate=100000:"
+// where either side may be empty. A band without "/CUR" is in the default
+// currency (STRIPE_CURRENCY); quotes in a currency with no band for their
+// category are not checked. Unset (the default) disables the check;
+// malformed entries are skipped. Category names match case-insensitively.
+// The request's min/max tags stay the hard limits either way.
+func categoryBounds() map[bandKey]amountBounds {
+	out := map[bandKey]amountBounds{}
+	for _, entry := range strings.Split(os.Getenv("QUOTE_CATEGORY_BOUNDS"), ",") {
+		name, band, ok := strings.Cut(entry, "=")
+		lo, hi, ok2 := strings.Cut(band, ":")
+		name, cur, _ := strings.Cut(name, "/")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || !ok2 || name == "" {
+			continue
+		}
+		if cur = strings.TrimSpace(cur); cur == "" {
+			cur = money.DefaultCurrency()
+		}
+		var b amountBounds
+		var err error
+		if lo = strings.TrimSpace(lo); lo != "" {
+			if b.Min, err = strconv.Atoi(lo); err != nil || b.Min < 0 {
+				continue
+			}
+		}
+		if hi = strings.TrimSpace(hi); hi != "" {
+			if b.Max, err = strconv.Atoi(hi); err != nil || b.Max < 0 {
+				continue
+			}
+		}
+		if b.Max > 0 && b.Min > b.Max {
+			continue
+		}
+		out[bandKey{name, money.OrDefault(cur)}] = b
+	}
+	return out
+}
+
+// boundsError returns the amount_cents message for a quote in currency on a
+// case in category, or "" when no band is configured for that pair or the
+// amount fits.
+func boundsError(category, currency string, cents int) string {
+	b, ok := categoryBounds()[bandKey{strings.ToLower(strings.TrimSpace(category)), money.OrDefault(currency)}]
+	if !ok {
+		return ""
+	}
+	return b.check(cents, strings.TrimSpace(category))
+}
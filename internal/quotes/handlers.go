@@ -2,8 +2,7 @@ package quotes
 
 import (
 	"errors"
-	"math"
-	"strconv"
+	"os"
 	"strings"
 	"time"
 
@@ -13,8 +12,17 @@ import (
 	"gorm.io/gorm/clause"
 
 	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/internal/cases"
+	"github.com/aldoetobex/legal-mp-backend/internal/webhooks"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apitime"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/mailer"
+	"github.com/aldoetobex/legal-mp-backend/pkg/metrics"
 	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/money"
+	"github.com/aldoetobex/legal-mp-backend/pkg/pagination"
 	"github.com/aldoetobex/legal-mp-backend/pkg/sanitize"
+	"github.com/aldoetobex/legal-mp-backend/pkg/utils"
 	"github.com/aldoetobex/legal-mp-backend/pkg/validation"
 )
 
@@ -22,57 +30,79 @@ import (
 
 type UpsertQuoteRequest struct {
 	CaseID      string `json:"case_id" validate:"required,uuid4"`
-	AmountCents int    `json:"amount_cents" validate:"required,min=1,max=100000000"` // min S$10, max S$1,000,000
-	Days        int    `json:"days" validate:"required,min=1,max=365"`
+	AmountCents int    `json:"amount_cents" validate:"required,min=1,max=100000000" label:"Amount" unit:"cent"` // max S$1,000,000
+	Currency    string `json:"currency" validate:"omitempty,currency"`                                          // ISO-4217; defaults to STRIPE_CURRENCY
+	Days        int    `json:"days" validate:"required,min=1,max=365" label:"Duration" unit:"day"`
 	Note        string `json:"note" validate:"omitempty,max=500"`
+	Pitch       string `json:"pitch" validate:"omitempty,max=2000"` // cover letter for the client
+	// Optional; defaults to now + QUOTE_VALIDITY. Must be in the future.
+	ValidUntil *time.Time `json:"valid_until"`
 }
 
 // Returned to the lawyer in /quotes/mine (includes case metadata for FE display)
 type MyQuoteItem struct {
-	ID           string `json:"id"`
-	CaseID       string `json:"case_id"`
-	CaseTitle    string `json:"case_title"`    // from cases.title
-	CaseCategory string `json:"case_category"` // optional
-	CaseStatus   string `json:"case_status"`   // optional
-	AmountCents  int    `json:"amount_cents"`
-	Days         int    `json:"days"`
-	Note         string `json:"note"`
-	Status       string `json:"status"`
-	CreatedAt    string `json:"created_at"`
+	ID           string     `json:"id"`
+	CaseID       string     `json:"case_id"`
+	CaseTitle    string     `json:"case_title"`    // from cases.title
+	CaseCategory string     `json:"case_category"` // optional
+	CaseStatus   string     `json:"case_status"`   // optional
+	AmountCents  int        `json:"amount_cents"`
+	Currency     string     `json:"currency"`
+	Days         int        `json:"days"`
+	Note         string     `json:"note"`
+	Pitch        string     `json:"pitch"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    *time.Time `json:"expires_at"`
 }
 
-type PageMyQuotes struct {
-	Page     int           `json:"page"`
-	PageSize int           `json:"pageSize"`
-	Total    int64         `json:"total"`
-	Pages    int           `json:"pages"`
-	Items    []MyQuoteItem `json:"items"`
-}
+type PageMyQuotes = pagination.Page[MyQuoteItem]
 
 /* ============================== Handler =================================== */
 
 type Handler struct {
-	db *gorm.DB
+	db      *gorm.DB
+	mail    mailer.Mailer    // optional; nil disables email
+	metrics *metrics.Metrics // optional; nil records nothing
+
+	hooks *webhooks.Dispatcher // optional; nil sends no webhooks
+}
+
+func NewHandler(db *gorm.DB, mail mailer.Mailer, m *metrics.Metrics, hooks *webhooks.Dispatcher) *Handler {
+	return &Handler{db: db, mail: mail, metrics: m, hooks: hooks}
 }
 
-func NewHandler(db *gorm.DB) *Handler { return &Handler{db: db} }
+/* ============================ Upsert Quote ================================ */
 
-/* ============================== Helpers =================================== */
+// maxQuoteValidity caps how far ahead a lawyer may hold a price.
+const maxQuoteValidity = 90 * 24 * time.Hour
 
-// parsePage reads ?page and ?pageSize with sane bounds (1..50)
-func parsePage(c *fiber.Ctx) (page, size int) {
-	page, _ = strconv.Atoi(c.Query("page", "1"))
-	size, _ = strconv.Atoi(c.Query("pageSize", "10"))
-	if page < 1 {
-		page = 1
-	}
-	if size < 1 || size > 50 {
-		size = 10
+// defaultQuoteValidity reads QUOTE_VALIDITY (Go duration, default 7 days).
+func defaultQuoteValidity() time.Duration {
+	if v := os.Getenv("QUOTE_VALIDITY"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
 	}
-	return
+	return 7 * 24 * time.Hour
 }
 
-/* ============================ Upsert Quote ================================ */
+// canQuote returns nil when lawyers may submit or update quotes on a case in
+// this status (only OPEN), otherwise the 409 to send. An expired case gets
+// its own code and message so it can't be mistaken for "not open"; any other
+// status is refused with its name in the message.
+func canQuote(st models.CaseStatus) error {
+	switch st {
+	case models.CaseOpen:
+		return nil
+	case models.CaseEngaged, models.CaseClosed, models.CaseCancelled:
+		return apperr.Conflict(apperr.CaseNotOpen, "case is not open")
+	case models.CaseExpired:
+		return apperr.Conflict(apperr.CaseExpired, "case has expired")
+	default:
+		return apperr.Conflict(apperr.CaseNotOpen, "case is "+string(st))
+	}
+}
 
 // @Summary      Submit or update a quote (1 active per case per lawyer)
 // @Description  Lawyer creates or updates a quote while the case is still OPEN
@@ -81,11 +111,12 @@ func parsePage(c *fiber.Ctx) (page, size int) {
 // @Accept       json
 // @Produce      json
 // @Param        payload  body  UpsertQuoteRequest  true  "Quote upsert payload"
-// @Success      201  {object}  map[string]any  "id, status, amount_cents, days, note"
+// @Success      201  {object}  map[string]any  "id, status, amount_cents, currency, days, note, pitch, expires_at"
 // @Failure      400  {object}  models.ValidationErrorResponse
 // @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse  "EMAIL_NOT_VERIFIED (when REQUIRE_LAWYER_EMAIL_VERIFICATION is on)"
 // @Failure      404  {object}  models.ErrorResponse
-// @Failure      409  {object}  models.ErrorResponse  "immutable or case not open"
+// @Failure      409  {object}  models.ErrorResponse  "immutable, CASE_NOT_OPEN, CASE_EXPIRED, or QUOTE_IN_CHECKOUT (checkout started)"
 // @Failure      500  {object}  models.ErrorResponse
 // @Router       /quotes [post]
 func (h *Handler) Upsert(c *fiber.Ctx) error {
@@ -106,6 +137,31 @@ func (h *Handler) Upsert(c *fiber.Ctx) error {
 	}
 	lawyerID := uuid.MustParse(lawyerIDStr)
 
+	// Opt-in gate against throwaway lawyer accounts
+	if auth.LawyerVerificationRequired() {
+		if err := auth.EnsureEmailVerified(h.db, lawyerID); err != nil {
+			return err
+		}
+	}
+
+	currency := money.OrDefault(in.Currency)
+	if !money.Aligned(int64(in.AmountCents), currency) {
+		return validation.RespondField(c, fiber.StatusBadRequest, "amount_cents",
+			"Amount must be a whole number of "+currency+" (a multiple of 100 cents)")
+	}
+
+	// Validity window (re-submitting a quote restarts it)
+	now := time.Now()
+	expiresAt := now.Add(defaultQuoteValidity())
+	if in.ValidUntil != nil {
+		if !in.ValidUntil.After(now) || in.ValidUntil.Sub(now) > maxQuoteValidity {
+			return validation.Respond(c, map[string][]string{
+				"valid_until": {"Must be in the future and within 90 days"},
+			})
+		}
+		expiresAt = *in.ValidUntil
+	}
+
 	// Quick pre-check: case must exist and be OPEN (no transaction yet)
 	var cs models.Case
 	if err := h.db.First(&cs, "id = ?", caseID).Error; err != nil {
@@ -114,8 +170,13 @@ func (h *Handler) Upsert(c *fiber.Ctx) error {
 		}
 		return fiber.ErrInternalServerError
 	}
-	if cs.Status != models.CaseOpen {
-		return fiber.NewError(fiber.StatusConflict, "case is not open")
+	if err := canQuote(cs.Status); err != nil {
+		return err
+	}
+
+	// Optional per-category amount band in the quote's currency (QUOTE_CATEGORY_BOUNDS)
+	if msg := boundsError(cs.Category, currency, in.AmountCents); msg != "" {
+		return validation.Respond(c, map[string][]string{"amount_cents": {msg}})
 	}
 
 	// Start TX and lock the case row to avoid races against accept/close
@@ -139,28 +200,37 @@ func (h *Handler) Upsert(c *fiber.Ctx) error {
 		}
 		return fiber.ErrInternalServerError
 	}
-	if cs.Status != models.CaseOpen {
+	if err := canQuote(cs.Status); err != nil {
 		_ = tx.Rollback()
-		return fiber.NewError(fiber.StatusConflict, "case is not open")
+		return err
 	}
 
 	// Enforce single active quote per (case_id, lawyer_id).
 	// Create new or update only if the current one is still PROPOSED.
 	var q models.Quote
-	err = tx.Where("case_id = ? AND lawyer_id = ?", caseID, lawyerID).First(&q).Error
+	created, changed := false, false
+	var prev models.Quote // the version being replaced (updates only)
+	// The quote row is locked like checkout does, so a price change and a new
+	// checkout can't interleave
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("case_id = ? AND lawyer_id = ?", caseID, lawyerID).First(&q).Error
 
 	switch {
 	case errors.Is(err, gorm.ErrRecordNotFound):
 		// Insert a new proposed quote
+		created, changed = true, true
 		q = models.Quote{
 			CaseID:      caseID,
 			LawyerID:    lawyerID,
 			AmountCents: in.AmountCents,
+			Currency:    currency,
 			Days:        in.Days,
 			Note:        strings.TrimSpace(in.Note),
+			Pitch:       strings.TrimSpace(in.Pitch),
 			Status:      models.QuoteProposed,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
+			ExpiresAt:   &expiresAt,
 		}
 		if err := tx.Create(&q).Error; err != nil {
 			_ = tx.Rollback()
@@ -171,39 +241,103 @@ func (h *Handler) Upsert(c *fiber.Ctx) error {
 		// Allow updates only when the quote is still PROPOSED
 		if q.Status != models.QuoteProposed {
 			_ = tx.Rollback()
-			return fiber.NewError(fiber.StatusConflict, "quote is immutable (already accepted/rejected)")
+			return apperr.Conflict(apperr.QuoteImmutable, "quote is immutable (already accepted/rejected)")
 		}
 		// Extra safety: ensure ownership
 		if q.LawyerID != lawyerID {
 			_ = tx.Rollback()
 			return fiber.ErrForbidden
 		}
+		// Once checkout starts the agreed quote stays as it is; only a failed
+		// (abandoned) payment frees it again
+		var inCheckout int64
+		if err := tx.Model(&models.Payment{}).
+			Where("quote_id = ? AND status <> ?", q.ID, models.PayFailed).
+			Count(&inCheckout).Error; err != nil {
+			_ = tx.Rollback()
+			return fiber.ErrInternalServerError
+		}
+		if inCheckout > 0 {
+			_ = tx.Rollback()
+			return apperr.Conflict(apperr.QuoteInCheckout, "the client has started checkout for this quote; it can't be changed")
+		}
+		prev = q
+		// Re-submitting the same terms only renews validity; no new revision
+		changed = in.AmountCents != q.AmountCents || currency != money.OrDefault(q.Currency) ||
+			in.Days != q.Days || strings.TrimSpace(in.Note) != q.Note
 		// Apply updates
 		if err := tx.Model(&q).Updates(map[string]any{
 			"amount_cents": in.AmountCents,
+			"currency":     currency,
 			"days":         in.Days,
 			"note":         strings.TrimSpace(in.Note),
+			"pitch":        strings.TrimSpace(in.Pitch),
 			"updated_at":   time.Now(),
+			"expires_at":   &expiresAt,
 		}).Error; err != nil {
 			_ = tx.Rollback()
 			return fiber.ErrInternalServerError
 		}
+		q.ExpiresAt = &expiresAt
+		q.Currency = currency
 
 	default:
 		_ = tx.Rollback()
 		return fiber.ErrInternalServerError
 	}
 
+	// Keep a trace of every version the client may have seen
+	if changed {
+		if err := backfillRevision(tx, prev, created); err != nil {
+			_ = tx.Rollback()
+			return fiber.ErrInternalServerError
+		}
+		if err := tx.Create(&models.QuoteRevision{
+			QuoteID:     q.ID,
+			AmountCents: in.AmountCents,
+			Currency:    currency,
+			Days:        in.Days,
+			Note:        strings.TrimSpace(in.Note),
+			CreatedAt:   time.Now(),
+		}).Error; err != nil {
+			_ = tx.Rollback()
+			return fiber.ErrInternalServerError
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return fiber.ErrInternalServerError
 	}
 
+	// Let the case owner know a new quote arrived (best-effort; updates are silent)
+	if created {
+		h.metrics.QuoteSubmitted()
+		utils.Notify(c.Context(), h.db, cs.ClientID, models.NotifyQuoteReceived, cs.ID)
+		if h.mail != nil {
+			var owner models.User
+			if err := h.db.Select("email").First(&owner, "id = ?", cs.ClientID).Error; err == nil {
+				mailer.SendAsync(h.mail, mailer.NewQuote(owner.Email, cs.Title))
+			}
+		}
+		h.hooks.Dispatch(webhooks.EventQuoteSubmitted, webhooks.QuoteSubmitted{
+			QuoteID:     q.ID,
+			CaseID:      cs.ID,
+			LawyerID:    q.LawyerID,
+			AmountCents: q.AmountCents,
+			Currency:    q.Currency,
+			Days:        q.Days,
+		})
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"id":           q.ID,
 		"status":       q.Status,
 		"amount_cents": q.AmountCents,
+		"currency":     q.Currency,
 		"days":         q.Days,
 		"note":         strings.TrimSpace(q.Note),
+		"pitch":        q.Pitch,
+		"expires_at":   apitime.NormalizePtr(q.ExpiresAt),
 	})
 }
 
@@ -217,14 +351,14 @@ func (h *Handler) Upsert(c *fiber.Ctx) error {
 // @Param        page      query int    false "page"
 // @Param        pageSize  query int    false "pageSize"
 // @Param        status    query string false "proposed|accepted|rejected"
-// @Success      200  {object}  PageMyQuotes
+// @Success      200  {object}  pagination.Page[quotes.MyQuoteItem]
 // @Failure      400  {object}  models.ErrorResponse
 // @Failure      401  {object}  models.ErrorResponse
 // @Failure      500  {object}  models.ErrorResponse
 // @Router       /quotes/mine [get]
 func (h *Handler) ListMine(c *fiber.Ctx) error {
 	lawyerID := auth.MustUserID(c)
-	page, size := parsePage(c)
+	page, size := pagination.Parse(c)
 	status := strings.TrimSpace(c.Query("status"))
 
 	base := h.db.Table("quotes").Where("quotes.lawyer_id = ?", lawyerID)
@@ -252,10 +386,13 @@ func (h *Handler) ListMine(c *fiber.Ctx) error {
 			quotes.id,
 			quotes.case_id,
 			quotes.amount_cents,
+			quotes.currency,
 			quotes.days,
 			quotes.note,
+			quotes.pitch,
 			quotes.status,
 			quotes.created_at,
+			quotes.expires_at,
 			cases.title    AS case_title,
 			cases.category AS case_category,
 			cases.status   AS case_status
@@ -267,59 +404,68 @@ func (h *Handler) ListMine(c *fiber.Ctx) error {
 		Scan(&rows).Error; err != nil {
 		return fiber.ErrInternalServerError
 	}
+	for i := range rows {
+		rows[i].Currency = money.OrDefault(rows[i].Currency) // legacy rows
+		rows[i].CreatedAt = apitime.Normalize(rows[i].CreatedAt)
+		rows[i].ExpiresAt = apitime.NormalizePtr(rows[i].ExpiresAt)
+	}
 
-	return c.JSON(fiber.Map{
-		"page":     page,
-		"pageSize": size,
-		"total":    total,
-		"pages":    int(math.Ceil(float64(total) / float64(size))),
-		"items":    rows,
-	})
+	return c.JSON(pagination.New(c, page, size, total, rows))
 }
 
 /* ===================== Client: Quotes by Case (owner) ===================== */
 
 // For owner view: list all quotes under a case
 type caseQuoteItem struct {
-	ID          uuid.UUID `json:"id"`
-	LawyerID    uuid.UUID `json:"lawyer_id"`
-	AmountCents int       `json:"amount_cents"`
-	Days        int       `json:"days"`
-	Note        string    `json:"note"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID  `json:"id"`
+	LawyerID    uuid.UUID  `json:"lawyer_id"`
+	AmountCents int        `json:"amount_cents"`
+	Currency    string     `json:"currency"`
+	Days        int        `json:"days"`
+	Note        string     `json:"note"`
+	Pitch       string     `json:"pitch"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
 }
 
-// @Summary      Quotes by case (owner)
-// @Description  Client owner sees all quotes for their case (paginated)
+// @Summary      Quotes by case (owner or admin)
+// @Description  Client owner sees all quotes for their case (filter by status, with pagination); notes and pitches are redacted except the accepted quote's once engaged/closed. Admins get every case (deleted ones too) with notes and pitches unredacted for moderation; each admin read is audit-logged.
 // @Tags         quotes
 // @Security     BearerAuth
 // @Produce      json
 // @Param        id        path  string true "case id (uuid)"
 // @Param        page      query int    false "page"
 // @Param        pageSize  query int    false "pageSize"
-// @Success      200  {object}  PageMyQuotes
+// @Param        status    query string false "proposed|accepted|rejected"
+// @Success      200  {object}  pagination.Page[quotes.MyQuoteItem]
 // @Failure      400  {object}  models.ErrorResponse
 // @Failure      401  {object}  models.ErrorResponse
 // @Failure      403  {object}  models.ErrorResponse
 // @Failure      500  {object}  models.ErrorResponse
 // @Router       /cases/{id}/quotes [get]
 func (h *Handler) ListByCaseForOwner(c *fiber.Ctx) error {
-	clientID := auth.MustUserID(c)
+	userID := auth.MustUserID(c)
+	admin := auth.IsAdmin(c)
 	caseID := c.Params("id")
 	if _, err := uuid.Parse(caseID); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid case id")
 	}
 
-	// Load case ownership + status + accepted quote
+	// Load case ownership + status + accepted quote (admins also see
+	// deleted cases)
+	db := h.db
+	if admin {
+		db = db.Unscoped()
+	}
 	var cs struct {
 		ID              uuid.UUID
 		ClientID        uuid.UUID
 		Status          models.CaseStatus
 		AcceptedQuoteID uuid.UUID
 	}
-	if err := h.db.
+	if err := db.
 		Model(&models.Case{}).
 		Select("id, client_id, status, accepted_quote_id").
 		Where("id = ?", caseID).
@@ -329,14 +475,26 @@ func (h *Handler) ListByCaseForOwner(c *fiber.Ctx) error {
 		}
 		return fiber.ErrInternalServerError
 	}
-	if cs.ClientID.String() != clientID {
+	switch {
+	case admin:
+		// Support moderating abusive notes reads them as written; audited
+		auth.LogAdminAccess(c)
+	case cs.ClientID.String() != userID:
 		return fiber.ErrForbidden
 	}
 
-	page, size := parsePage(c)
+	page, size := pagination.Parse(c)
 
-	// Fetch quotes for this case (all statuses)
+	// Fetch quotes for this case (all statuses unless filtered)
 	q := h.db.Model(&models.Quote{}).Where("case_id = ?", cs.ID)
+	if status := strings.TrimSpace(c.Query("status")); status != "" {
+		switch status {
+		case string(models.QuoteProposed), string(models.QuoteAccepted), string(models.QuoteRejected):
+			q = q.Where("status = ?", status)
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "invalid status filter")
+		}
+	}
 
 	var total int64
 	if err := q.Count(&total).Error; err != nil {
@@ -352,27 +510,368 @@ func (h *Handler) ListByCaseForOwner(c *fiber.Ctx) error {
 		return fiber.ErrInternalServerError
 	}
 
-	// Redaction rules for the owner:
-	// - OPEN or CANCELLED → redact all notes
-	// - ENGAGED or CLOSED → show accepted note in full; redact the rest
-	switch cs.Status {
-	case models.CaseOpen, models.CaseCancelled:
-		for i := range rows {
-			rows[i].Note = sanitize.RedactPII(rows[i].Note)
+	for i := range rows {
+		if !admin {
+			rows[i].Note = ownerNote(cs.Status, cs.AcceptedQuoteID, rows[i].ID, rows[i].Note)
+			rows[i].Pitch = ownerNote(cs.Status, cs.AcceptedQuoteID, rows[i].ID, rows[i].Pitch)
 		}
+		rows[i].Currency = money.OrDefault(rows[i].Currency)
+		rows[i].CreatedAt = apitime.Normalize(rows[i].CreatedAt)
+		rows[i].UpdatedAt = apitime.Normalize(rows[i].UpdatedAt)
+		rows[i].ExpiresAt = apitime.NormalizePtr(rows[i].ExpiresAt)
+	}
+
+	return c.JSON(pagination.New(c, page, size, total, rows))
+}
+
+// ownerNote applies the redaction rules for the case owner:
+// - OPEN or CANCELLED → redact all notes
+// - ENGAGED or CLOSED → show accepted note in full; redact the rest
+func ownerNote(status models.CaseStatus, acceptedID, quoteID uuid.UUID, note string) string {
+	switch status {
 	case models.CaseEngaged, models.CaseClosed:
-		for i := range rows {
-			if rows[i].ID != cs.AcceptedQuoteID {
-				rows[i].Note = sanitize.RedactPII(rows[i].Note)
-			}
+		if quoteID == acceptedID {
+			return note
+		}
+	}
+	return sanitize.RedactPII(note)
+}
+
+/* ============================ Get Single Quote ============================ */
+
+// QuoteDetail is a single quote with its case reference (deep links).
+type QuoteDetail struct {
+	ID          uuid.UUID  `json:"id"`
+	CaseID      uuid.UUID  `json:"case_id"`
+	LawyerID    uuid.UUID  `json:"lawyer_id"`
+	AmountCents int        `json:"amount_cents"`
+	Currency    string     `json:"currency"`
+	Days        int        `json:"days"`
+	Note        string     `json:"note"`
+	Pitch       string     `json:"pitch"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// @Summary      Get a quote
+// @Description  Owning lawyer sees their quote as-is; the owning client of the case sees it with the same note redaction as the case quote list. Once the case is soft-deleted only the lawyer and admins can read it (404 for others).
+// @Tags         quotes
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "quote id (uuid)"
+// @Success      200  {object}  QuoteDetail
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /quotes/{id} [get]
+func (h *Handler) GetByID(c *fiber.Ctx) error {
+	userID := auth.MustUserID(c)
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid quote id")
+	}
+
+	var q models.Quote
+	if err := h.db.First(&q, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+
+	out := QuoteDetail{
+		ID:          q.ID,
+		CaseID:      q.CaseID,
+		LawyerID:    q.LawyerID,
+		AmountCents: q.AmountCents,
+		Currency:    money.OrDefault(q.Currency),
+		Days:        q.Days,
+		Note:        q.Note,
+		Pitch:       q.Pitch,
+		Status:      string(q.Status),
+		CreatedAt:   apitime.Normalize(q.CreatedAt),
+		UpdatedAt:   apitime.Normalize(q.UpdatedAt),
+		ExpiresAt:   apitime.NormalizePtr(q.ExpiresAt),
+	}
+
+	// Owning lawyer: their own quote, unredacted
+	if q.LawyerID.String() == userID {
+		return c.JSON(out)
+	}
+
+	// Otherwise only the case owner (or an admin) may read it. Admins still
+	// see quotes on soft-deleted cases; for anyone else the case is gone.
+	var cs struct {
+		ClientID        uuid.UUID
+		Status          models.CaseStatus
+		AcceptedQuoteID uuid.UUID
+	}
+	caseQ := h.db.Model(&models.Case{})
+	if auth.IsAdmin(c) {
+		caseQ = caseQ.Unscoped()
+	}
+	if err := caseQ.
+		Select("client_id, status, accepted_quote_id").
+		Where("id = ?", q.CaseID).
+		First(&cs).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
 		}
+		return fiber.ErrInternalServerError
+	}
+
+	switch {
+	case auth.IsAdmin(c):
+		auth.LogAdminAccess(c)
+	case cs.ClientID.String() == userID:
+		out.Note = ownerNote(cs.Status, cs.AcceptedQuoteID, q.ID, q.Note)
+		out.Pitch = ownerNote(cs.Status, cs.AcceptedQuoteID, q.ID, q.Pitch)
+	default:
+		return fiber.ErrForbidden
 	}
+	return c.JSON(out)
+}
+
+/* =========================== Get Accepted Quote =========================== */
 
-	return c.JSON(fiber.Map{
-		"page":     page,
-		"pageSize": size,
-		"total":    total,
-		"pages":    int(math.Ceil(float64(total) / float64(size))),
-		"items":    rows,
+// @Summary      Accepted quote of a case
+// @Description  Lightweight engagement summary. Same access as the case detail: owner client, accepted lawyer and their collaborators see the full note (other lawyers get 404, or 403 with CASE_LAWYER_DENY_STATUS=403); admins see it redacted (partially with PII_STAFF_MASKING=partial). 404 unless the case is engaged/closed.
+// @Tags         quotes
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "case id (uuid)"
+// @Success      200  {object}  QuoteDetail
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /cases/{id}/quotes/accepted [get]
+func (h *Handler) GetAccepted(c *fiber.Ctx) error {
+	userID := auth.MustUserID(c)
+	caseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid case id")
+	}
+
+	// Admins also read soft-deleted cases, as on the case detail
+	caseQ := h.db.Model(&models.Case{})
+	if auth.IsAdmin(c) {
+		caseQ = caseQ.Unscoped()
+	}
+	var cs models.Case
+	if err := caseQ.
+		Select("id, client_id, status, accepted_quote_id, accepted_lawyer_id").
+		Where("id = ?", caseID).
+		First(&cs).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	engaged := cs.Status == models.CaseEngaged || cs.Status == models.CaseClosed
+
+	// Same rules as the case detail: owner client, admins (read-only), and
+	// the accepted lawyer or their collaborators once engaged/closed
+	party := true
+	switch auth.MustRole(c) {
+	case string(models.RoleAdmin):
+		auth.LogAdminAccess(c)
+		party = false
+	case string(models.RoleClient):
+		if cs.ClientID.String() != userID {
+			return fiber.ErrForbidden
+		}
+	case string(models.RoleLawyer):
+		if !cases.LawyerCanRead(h.db, cs, userID) {
+			return cases.LawyerDenied()
+		}
+	default:
+		return fiber.ErrForbidden
+	}
+
+	if !engaged || cs.AcceptedQuoteID == uuid.Nil {
+		return fiber.NewError(fiber.StatusNotFound, "case has no accepted quote")
+	}
+	var q models.Quote
+	if err := h.db.First(&q, "id = ?", cs.AcceptedQuoteID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+
+	note, pitch := q.Note, q.Pitch
+	if !party {
+		note = sanitize.RedactPIIMode(note, sanitize.StaffMode())
+		pitch = sanitize.RedactPIIMode(pitch, sanitize.StaffMode())
+	}
+	return c.JSON(QuoteDetail{
+		ID:          q.ID,
+		CaseID:      q.CaseID,
+		LawyerID:    q.LawyerID,
+		AmountCents: q.AmountCents,
+		Currency:    money.OrDefault(q.Currency),
+		Days:        q.Days,
+		Note:        note,
+		Pitch:       pitch,
+		Status:      string(q.Status),
+		CreatedAt:   apitime.Normalize(q.CreatedAt),
+		UpdatedAt:   apitime.Normalize(q.UpdatedAt),
+		ExpiresAt:   apitime.NormalizePtr(q.ExpiresAt),
 	})
 }
+
+/* ========================== Client: Reject Quote ========================== */
+
+// Optional reason when a client rejects a quote
+type RejectQuoteRequest struct {
+	Comment string `json:"comment" validate:"max=500"`
+}
+
+// @Summary      Reject a quote
+// @Description  Case owner rejects a still-proposed quote while the case is OPEN. Accepted quotes cannot be rejected, nor a quote whose checkout is in progress.
+// @Tags         quotes
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string              true  "case id (uuid)"
+// @Param        quoteID  path  string              true  "quote id (uuid)"
+// @Param        payload  body  RejectQuoteRequest  false "Optional comment"
+// @Success      200  {object}  map[string]string  "status"
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse  "CASE_NOT_OPEN, QUOTE_NOT_PROPOSED, QUOTE_IN_CHECKOUT"
+// @Router       /cases/{id}/quotes/{quoteID}/reject [post]
+func (h *Handler) RejectByOwner(c *fiber.Ctx) error {
+	clientID := auth.MustUserID(c)
+	caseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid case id")
+	}
+	quoteID, err := uuid.Parse(c.Params("quoteID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid quote id")
+	}
+
+	var in RejectQuoteRequest
+	_ = c.BodyParser(&in)
+	if errs, _ := validation.Validate(in); errs != nil {
+		return validation.Respond(c, errs)
+	}
+
+	// Lock the case so a concurrent accept/payment can't interleave
+	tx := h.db.Begin()
+	if tx.Error != nil {
+		return fiber.ErrInternalServerError
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	var cs models.Case
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&cs, "id = ?", caseID).Error; err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	if cs.ClientID.String() != clientID {
+		_ = tx.Rollback()
+		return fiber.ErrForbidden
+	}
+	if cs.Status != models.CaseOpen {
+		_ = tx.Rollback()
+		return apperr.Conflict(apperr.CaseNotOpen, "case is not open")
+	}
+
+	var q models.Quote
+	if err := tx.First(&q, "id = ? AND case_id = ?", quoteID, caseID).Error; err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	if q.Status != models.QuoteProposed {
+		_ = tx.Rollback()
+		return apperr.Conflict(apperr.QuoteNotProposed, "only proposed quotes can be rejected")
+	}
+	// A checkout in flight could still complete and accept it
+	var inCheckout int64
+	if err := tx.Model(&models.Payment{}).
+		Where("quote_id = ? AND status <> ?", q.ID, models.PayFailed).
+		Count(&inCheckout).Error; err != nil {
+		_ = tx.Rollback()
+		return fiber.ErrInternalServerError
+	}
+	if inCheckout > 0 {
+		_ = tx.Rollback()
+		return apperr.Conflict(apperr.QuoteInCheckout, "you have started checkout for this quote; it can't be rejected")
+	}
+
+	if err := tx.Model(&q).Updates(map[string]any{
+		"status":     models.QuoteRejected,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		_ = tx.Rollback()
+		return fiber.ErrInternalServerError
+	}
+	if err := tx.Commit().Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	// History (case status itself doesn't change); reason names the quote
+	reason := "quote " + q.ID.String()
+	if cm := strings.TrimSpace(in.Comment); cm != "" {
+		reason += ": " + cm
+	}
+	utils.LogCaseHistory(
+		c.Context(),
+		h.db,
+		cs.ID,
+		uuid.MustParse(clientID),
+		models.ActorUser,
+		"quote_rejected",
+		cs.Status,
+		cs.Status,
+		reason,
+	)
+
+	return c.JSON(fiber.Map{"status": string(models.QuoteRejected)})
+}
+
+/* ============================ Expiry Sweep ================================ */
+
+// ExpireStale marks PROPOSED quotes whose validity has passed as REJECTED so
+// listings reflect reality. Checkout refuses expired quotes regardless; a
+// quote whose checkout already started is left for the payment to settle.
+func ExpireStale(db *gorm.DB, now time.Time) (int64, error) {
+	res := db.Model(&models.Quote{}).
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at < ?", models.QuoteProposed, now).
+		Where("NOT EXISTS (SELECT 1 FROM payments WHERE payments.quote_id = quotes.id AND payments.status <> ?)", models.PayFailed).
+		Updates(map[string]any{"status": models.QuoteRejected, "updated_at": now})
+	return res.RowsAffected, res.Error
+}
+
+/* ========================= Single Accepted Quote ========================== */
+
+// EnsureSingleAcceptedQuote creates a partial unique index so the database
+// itself refuses a second ACCEPTED quote on the same case (idempotent). It is
+// a safety net behind the payment transactions, not a replacement for them.
+// Run it at startup after migrations.
+func EnsureSingleAcceptedQuote(db *gorm.DB) error {
+	return db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS ux_quotes_one_accepted
+		ON quotes (case_id) WHERE status = 'accepted'`).Error
+}
@@ -2,6 +2,7 @@ package quotes
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http/httptest"
@@ -16,7 +17,12 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apitime"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apperr"
+	"github.com/aldoetobex/legal-mp-backend/pkg/mailer"
 	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/utils"
 )
 
 /* ============================================================================
@@ -39,15 +45,22 @@ func openTestDB(t *testing.T) *gorm.DB {
 	}
 	if err := db.AutoMigrate(
 		&models.User{}, &models.Case{}, &models.CaseFile{},
-		&models.CaseHistory{}, &models.Quote{}, &models.Payment{},
+		&models.CaseHistory{}, &models.Quote{}, &models.QuoteRevision{}, &models.Payment{}, &models.Notification{},
+		&models.CaseCollaborator{},
 	); err != nil {
 		t.Fatalf("migrate: %v", err)
 	}
+	if err := EnsureSingleAcceptedQuote(db); err != nil {
+		t.Fatalf("accepted index: %v", err)
+	}
 
 	t.Cleanup(func() {
 		sql := `
 TRUNCATE TABLE
+	notifications,
+	case_collaborators,
 	payments,
+	quote_revisions,
 	case_histories,
 	case_files,
 	quotes,
@@ -149,6 +162,7 @@ func newTestApp(h *Handler, userID uuid.UUID, role string) *fiber.App {
 	app.Use(injectAuth(userID, role))
 	app.Post("/api/quotes", h.Upsert)
 	app.Get("/api/quotes/mine", h.ListMine)
+	app.Get("/api/quotes/:id/revisions", h.ListRevisions)
 	return app
 }
 
@@ -163,7 +177,7 @@ func Test_UpsertQuote_UpdatesExistingNotCreateNew(t *testing.T) {
 	// Seed without tx so data is committed for the handler.
 	seed := seedCaseNoTx(t, db, models.CaseOpen)
 
-	hq := NewHandler(db)
+	hq := NewHandler(db, nil, nil, nil)
 	app := newTestApp(hq, seed.LawyerID, string(models.RoleLawyer))
 
 	body1 := `{"case_id":"` + seed.CaseID.String() + `","amount_cents":5000,"days":5,"note":"A"}`
@@ -231,7 +245,7 @@ func Test_ListMine_ReturnsOnlyMyQuotes(t *testing.T) {
 			Status: models.QuoteProposed, CreatedAt: time.Now(), UpdatedAt: time.Now(),
 		}).Error
 
-		hq := NewHandler(tx)
+		hq := NewHandler(tx, nil, nil, nil)
 		app := newTestApp(hq, s1.LawyerID, string(models.RoleLawyer))
 
 		req := httptest.NewRequest("GET", "/api/quotes/mine?status=proposed&page=1&pageSize=50", nil)
@@ -257,30 +271,1184 @@ func Test_ListMine_ReturnsOnlyMyQuotes(t *testing.T) {
    Tests — state validation
    ============================================================================ */
 
-// Upsert should be rejected when the case is not OPEN (ENGAGED/CLOSED/CANCELLED).
+// Upsert is rejected with 409 for every status but OPEN; unknown statuses
+// (e.g. a future "expired") name themselves instead of reading "not open".
 func Test_UpsertQuote_Forbidden_WhenCaseNotOpen(t *testing.T) {
 	db := openTestDB(t)
 
-	for _, st := range []models.CaseStatus{
-		models.CaseEngaged,
-		models.CaseClosed,
-		models.CaseCancelled,
+	for _, tc := range []struct {
+		status models.CaseStatus
+		code   int
+		msg    string
+	}{
+		{models.CaseOpen, 201, ""},
+		{models.CaseEngaged, 409, "case is not open"},
+		{models.CaseClosed, 409, "case is not open"},
+		{models.CaseCancelled, 409, "case is not open"},
+		{models.CaseStatus("expired"), 409, "case is expired"},
+	} {
+		t.Run(string(tc.status), func(t *testing.T) {
+			withTx(t, db, func(tx *gorm.DB) {
+				seed := seedCase(t, tx, tc.status)
+
+				h := NewHandler(tx, nil, nil, nil)
+				app := newTestApp(h, seed.LawyerID, string(models.RoleLawyer))
+
+				body := `{"case_id":"` + seed.CaseID.String() + `","amount_cents":12345,"days":3,"note":"try"}`
+				req := httptest.NewRequest("POST", "/api/quotes", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				resp, _ := app.Test(req)
+
+				b, _ := io.ReadAll(resp.Body)
+				if resp.StatusCode != tc.code {
+					t.Fatalf("want %d, got %d. body=%s", tc.code, resp.StatusCode, string(b))
+				}
+				if tc.msg != "" && string(b) != tc.msg {
+					t.Fatalf("want message %q, got %q", tc.msg, string(b))
+				}
+			})
+		})
+	}
+}
+
+// canQuote allows OPEN only. Expired cases get 409 CASE_EXPIRED; every other
+// status is a 409 CASE_NOT_OPEN.
+func Test_CanQuote(t *testing.T) {
+	if err := canQuote(models.CaseOpen); err != nil {
+		t.Fatalf("open: want nil, got %v", err)
+	}
+	for _, tc := range []struct {
+		status models.CaseStatus
+		code   string
+	}{
+		{models.CaseEngaged, apperr.CaseNotOpen},
+		{models.CaseClosed, apperr.CaseNotOpen},
+		{models.CaseCancelled, apperr.CaseNotOpen},
+		{models.CasePaused, apperr.CaseNotOpen},
+		{models.CaseExpired, apperr.CaseExpired},
+	} {
+		var ae *apperr.Error
+		if err := canQuote(tc.status); !errors.As(err, &ae) || ae.Status() != 409 || ae.Code != tc.code {
+			t.Fatalf("%s: want 409 %s, got %v", tc.status, tc.code, err)
+		}
+	}
+}
+
+// Each invalid field gets a readable message (validation runs before any DB work).
+func Test_UpsertQuote_ValidationMessages(t *testing.T) {
+	app := newTestApp(NewHandler(nil, nil, nil, nil), uuid.New(), string(models.RoleLawyer))
+	caseID := uuid.New().String()
+
+	cases := []struct {
+		body, field, want string
+	}{
+		{`{"case_id":"nope","amount_cents":100,"days":3}`, "case_id", "Invalid UUID format"},
+		{`{"amount_cents":100,"days":3}`, "case_id", "This field is required"},
+		{`{"case_id":"` + caseID + `","amount_cents":-5,"days":3}`, "amount_cents", "Amount must be at least 1 cent"},
+		{`{"case_id":"` + caseID + `","amount_cents":100000001,"days":3}`, "amount_cents", "Amount must be at most 100,000,000 cents"},
+		{`{"case_id":"` + caseID + `","days":3}`, "amount_cents", "Amount is required"},
+		{`{"case_id":"` + caseID + `","amount_cents":100,"days":400}`, "days", "Duration must be at most 365 days"},
+		{`{"case_id":"` + caseID + `","amount_cents":100,"days":-1}`, "days", "Duration must be at least 1 day"},
+		{`{"case_id":"` + caseID + `","amount_cents":100,"days":3,"note":"` + strings.Repeat("x", 501) + `"}`, "note", "Must be at most 500 characters"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("POST", "/api/quotes", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		if resp.StatusCode != 400 {
+			t.Fatalf("%s: want 400, got %d", tc.field, resp.StatusCode)
+		}
+		var out struct {
+			Errors map[string][]string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		if got := out.Errors[tc.field]; len(got) != 1 || got[0] != tc.want {
+			t.Fatalf("%s: want %q, got %v", tc.field, tc.want, out.Errors)
+		}
+	}
+}
+
+// A JPY quote must be whole yen (a multiple of 100 cents); USD takes any cent.
+func Test_UpsertQuote_ZeroDecimalCurrency(t *testing.T) {
+	t.Setenv("CURRENCIES", "USD,JPY")
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	app := newTestApp(NewHandler(db, nil, nil, nil), seed.LawyerID, string(models.RoleLawyer))
+
+	upsert := func(amount int, currency string) (int, []string) {
+		body := fmt.Sprintf(`{"case_id":"%s","amount_cents":%d,"currency":"%s","days":3}`, seed.CaseID, amount, currency)
+		req := httptest.NewRequest("POST", "/api/quotes", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		var out struct {
+			Errors map[string][]string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out.Errors["amount_cents"]
+	}
+
+	if code, errs := upsert(150050, "JPY"); code != 400 || len(errs) != 1 {
+		t.Fatalf("JPY with cents: want 400 on amount_cents, got %d %v", code, errs)
+	}
+	if code, _ := upsert(150000, "JPY"); code != 201 {
+		t.Fatalf("whole JPY: want 201, got %d", code)
+	}
+	if code, _ := upsert(150050, "USD"); code != 201 {
+		t.Fatalf("USD with cents: want 201, got %d", code)
+	}
+}
+
+/* ============================================================================
+   Tests — lawyer email verification gate
+   ============================================================================ */
+
+// With REQUIRE_LAWYER_EMAIL_VERIFICATION off an unverified lawyer can quote;
+// with it on they get 403 EMAIL_NOT_VERIFIED until their email is verified.
+func Test_UpsertQuote_EmailVerificationGate(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+	app.Use(injectAuth(seed.LawyerID, string(models.RoleLawyer)))
+	app.Post("/api/quotes", NewHandler(db, nil, nil, nil).Upsert)
+
+	upsert := func() (int, string) {
+		body := `{"case_id":"` + seed.CaseID.String() + `","amount_cents":12345,"days":3}`
+		req := httptest.NewRequest("POST", "/api/quotes", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out models.ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out.Code
+	}
+
+	t.Setenv("REQUIRE_LAWYER_EMAIL_VERIFICATION", "false")
+	if code, _ := upsert(); code != 201 {
+		t.Fatalf("gate off: want 201, got %d", code)
+	}
+
+	t.Setenv("REQUIRE_LAWYER_EMAIL_VERIFICATION", "true")
+	if code, errCode := upsert(); code != 403 || errCode != apperr.EmailNotVerified {
+		t.Fatalf("gate on, unverified: want 403 %s, got %d %s", apperr.EmailNotVerified, code, errCode)
+	}
+
+	if err := db.Model(&models.User{}).Where("id = ?", seed.LawyerID).
+		Update("email_verified_at", time.Now()).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := upsert(); code != 201 {
+		t.Fatalf("gate on, verified: want 201, got %d", code)
+	}
+}
+
+/* ============================================================================
+   Tests — quote locked during checkout
+   ============================================================================ */
+
+// Once the client has started checkout, any edit to the quote is a 409
+// QUOTE_IN_CHECKOUT; a failed payment releases it.
+func Test_UpsertQuote_LockedDuringCheckout(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+	app.Use(injectAuth(seed.LawyerID, string(models.RoleLawyer)))
+	app.Post("/api/quotes", NewHandler(db, nil, nil, nil).Upsert)
+
+	upsert := func(amount, days int) (int, string) {
+		t.Helper()
+		body := fmt.Sprintf(`{"case_id":"%s","amount_cents":%d,"days":%d}`, seed.CaseID, amount, days)
+		req := httptest.NewRequest("POST", "/api/quotes", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out models.ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out.Code
+	}
+
+	if code, _ := upsert(5000, 3); code != 201 {
+		t.Fatalf("create: want 201, got %d", code)
+	}
+	var q models.Quote
+	db.First(&q, "case_id = ? AND lawyer_id = ?", seed.CaseID, seed.LawyerID)
+	pay := models.Payment{
+		CaseID: seed.CaseID, QuoteID: q.ID, ClientID: seed.ClientID,
+		AmountCents: 5000, Currency: q.Currency, Status: models.PayInitiated,
+	}
+	if err := db.Create(&pay).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name         string
+		amount, days int
+	}{
+		{"new amount", 9000, 3},
+		{"same amount, new days", 5000, 5},
 	} {
-		withTx(t, db, func(tx *gorm.DB) {
-			seed := seedCase(t, tx, st)
+		if code, errCode := upsert(tc.amount, tc.days); code != 409 || errCode != apperr.QuoteInCheckout {
+			t.Fatalf("%s: want 409 %s, got %d %s", tc.name, apperr.QuoteInCheckout, code, errCode)
+		}
+	}
+	db.First(&q, "id = ?", q.ID)
+	if q.AmountCents != 5000 || q.Days != 3 {
+		t.Fatalf("quote should be unchanged, got %d cents / %d days", q.AmountCents, q.Days)
+	}
 
-			h := NewHandler(tx)
-			app := newTestApp(h, seed.LawyerID, string(models.RoleLawyer))
+	// Checkout abandoned → the quote can be edited again
+	db.Model(&models.Payment{}).Where("id = ?", pay.ID).Update("status", models.PayFailed)
+	if code, _ := upsert(9000, 5); code != 201 {
+		t.Fatalf("after failed payment: want 201, got %d", code)
+	}
+}
 
-			body := `{"case_id":"` + seed.CaseID.String() + `","amount_cents":12345,"days":3,"note":"try"}`
+/* ============================================================================
+   Tests — quote revisions
+   ============================================================================ */
+
+// Each change through Upsert adds one revision (re-sending the same terms
+// doesn't); the lawyer sees notes as written, the case owner redacted, and
+// anyone else is refused.
+func Test_UpsertQuote_RecordsRevisions(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	h := NewHandler(db, nil, nil, nil)
+	lawyerApp := newTestApp(h, seed.LawyerID, string(models.RoleLawyer))
+
+	upsert := func(amount, days int, note string) {
+		t.Helper()
+		body := fmt.Sprintf(`{"case_id":"%s","amount_cents":%d,"days":%d,"note":%q}`, seed.CaseID, amount, days, note)
+		req := httptest.NewRequest("POST", "/api/quotes", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if resp, _ := lawyerApp.Test(req); resp.StatusCode != 201 {
+			t.Fatalf("upsert: want 201, got %d", resp.StatusCode)
+		}
+	}
+	upsert(5000, 3, "first")
+	upsert(7000, 3, "call me at +65 9123 4567")
+	upsert(7000, 3, "call me at +65 9123 4567") // same terms
+	upsert(6500, 4, "final")
+
+	var q models.Quote
+	db.First(&q, "case_id = ? AND lawyer_id = ?", seed.CaseID, seed.LawyerID)
+	var n int64
+	db.Model(&models.QuoteRevision{}).Where("quote_id = ?", q.ID).Count(&n)
+	if n != 3 {
+		t.Fatalf("want 3 revisions (create + 2 changes), got %d", n)
+	}
+
+	list := func(app *fiber.App) (int, []QuoteRevisionItem) {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/quotes/"+q.ID.String()+"/revisions", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []QuoteRevisionItem
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+
+	code, revs := list(lawyerApp)
+	if code != 200 || len(revs) != 3 {
+		t.Fatalf("lawyer: want 200 with 3 revisions, got %d %+v", code, revs)
+	}
+	if revs[0].AmountCents != 5000 || revs[1].AmountCents != 7000 || revs[2].AmountCents != 6500 || revs[2].Days != 4 {
+		t.Fatalf("revisions out of order or wrong: %+v", revs)
+	}
+	if !strings.Contains(revs[1].Note, "9123") {
+		t.Fatalf("lawyer should see their note as written, got %q", revs[1].Note)
+	}
+
+	code, revs = list(newTestApp(h, seed.ClientID, string(models.RoleClient)))
+	if code != 200 || len(revs) != 3 || strings.Contains(revs[1].Note, "9123") {
+		t.Fatalf("owner: want 3 revisions with redacted notes, got %d %+v", code, revs)
+	}
+
+	if code, _ := list(newTestApp(h, uuid.New(), string(models.RoleLawyer))); code != 403 {
+		t.Fatalf("other lawyer: want 403, got %d", code)
+	}
+}
+
+// A quote written before revisions existed gets its old version recorded
+// on its first change.
+func Test_UpsertQuote_BackfillsLegacyRevision(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	legacy := models.Quote{
+		CaseID: seed.CaseID, LawyerID: seed.LawyerID, AmountCents: 4000, Days: 2,
+		Status: models.QuoteProposed, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(NewHandler(db, nil, nil, nil), seed.LawyerID, string(models.RoleLawyer))
+	body := `{"case_id":"` + seed.CaseID.String() + `","amount_cents":4500,"days":2}`
+	req := httptest.NewRequest("POST", "/api/quotes", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if resp, _ := app.Test(req); resp.StatusCode != 201 {
+		t.Fatalf("upsert: want 201, got %d", resp.StatusCode)
+	}
+
+	var revs []models.QuoteRevision
+	db.Where("quote_id = ?", legacy.ID).Order("created_at").Find(&revs)
+	if len(revs) != 2 || revs[0].AmountCents != 4000 || revs[1].AmountCents != 4500 {
+		t.Fatalf("want old and new version, got %+v", revs)
+	}
+}
+
+/* ============================================================================
+   Tests — category amount bounds
+   ============================================================================ */
+
+// With a band configured for the case's category, a too-low amount gets a
+// validation error and an amount inside the band is accepted.
+func Test_UpsertQuote_CategoryBounds(t *testing.T) {
+	t.Setenv("QUOTE_CATEGORY_BOUNDS", "cat=50000:500000, other=:100")
+	db := openTestDB(t)
+
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseOpen) // category "Cat"
+		app := newTestApp(NewHandler(tx, nil, nil, nil), seed.LawyerID, string(models.RoleLawyer))
+
+		post := func(amount int) (int, map[string][]string) {
+			body := fmt.Sprintf(`{"case_id":%q,"amount_cents":%d,"days":3}`, seed.CaseID.String(), amount)
 			req := httptest.NewRequest("POST", "/api/quotes", strings.NewReader(body))
 			req.Header.Set("Content-Type", "application/json")
 			resp, _ := app.Test(req)
+			var out struct {
+				Errors map[string][]string `json:"errors"`
+			}
+			_ = json.NewDecoder(resp.Body).Decode(&out)
+			return resp.StatusCode, out.Errors
+		}
 
-			if resp.StatusCode != 409 && resp.StatusCode != 403 {
-				b, _ := io.ReadAll(resp.Body)
-				t.Fatalf("status for %s must be 409/403, got %d. body=%s", st, resp.StatusCode, string(b))
+		code, errs := post(1000)
+		if code != 400 || len(errs["amount_cents"]) != 1 ||
+			errs["amount_cents"][0] != "Amount must be between 50000 and 500000 cents for Cat cases" {
+			t.Fatalf("below band: want 400 with band message, got %d %v", code, errs)
+		}
+		if code, errs := post(120000); code != 201 {
+			t.Fatalf("within band: want 201, got %d %v", code, errs)
+		}
+	})
+}
+
+// Malformed entries are ignored; either side of a band may be open. Bands
+// without a currency are in the default one.
+func Test_CategoryBounds_Parse(t *testing.T) {
+	t.Setenv("STRIPE_CURRENCY", "usd")
+	t.Setenv("QUOTE_CATEGORY_BOUNDS", "Family=100:200,corporate=500:,family/eur=300:, bad, x=abc:1, y=9:3")
+	got := categoryBounds()
+	if len(got) != 3 ||
+		got[bandKey{"family", "USD"}] != (amountBounds{100, 200}) ||
+		got[bandKey{"corporate", "USD"}] != (amountBounds{Min: 500}) ||
+		got[bandKey{"family", "EUR"}] != (amountBounds{Min: 300}) {
+		t.Fatalf("unexpected bounds: %+v", got)
+	}
+	if boundsError("Corporate", "USD", 499) == "" || boundsError("Corporate", "", 10_000_000) != "" {
+		t.Fatalf("corporate: min only")
+	}
+	if boundsError("Unlisted", "USD", 1) != "" {
+		t.Fatalf("categories without a band are unrestricted")
+	}
+
+	t.Setenv("QUOTE_CATEGORY_BOUNDS", "")
+	if len(categoryBounds()) != 0 {
+		t.Fatalf("unset: feature off")
+	}
+}
+
+// A band applies only to its own currency: a USD band doesn't judge a JPY
+// quote (no JPY band → unchecked), while a EUR band checks EUR quotes.
+func Test_CategoryBounds_NonDefaultCurrency(t *testing.T) {
+	t.Setenv("STRIPE_CURRENCY", "USD")
+	t.Setenv("QUOTE_CATEGORY_BOUNDS", "family=50000:500000,family/EUR=40000:400000")
+
+	if boundsError("Family", "JPY", 100) != "" || boundsError("Family", "JPY", 10_000_000) != "" {
+		t.Fatalf("JPY has no band: want unchecked")
+	}
+	if boundsError("Family", "EUR", 45000) != "" {
+		t.Fatalf("EUR within its own band should pass")
+	}
+	if boundsError("Family", "eur", 30000) == "" {
+		t.Fatalf("EUR below its band should fail")
+	}
+	if boundsError("Family", "USD", 45000) == "" {
+		t.Fatalf("USD below the default band should fail")
+	}
+}
+
+/* ============================================================================
+   Tests — notifications
+   ============================================================================ */
+
+// A new quote notifies the case owner once; updating the same quote does not.
+func Test_UpsertQuote_NotifiesCaseOwner_OnNewQuoteOnly(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseOpen)
+		app := newTestApp(NewHandler(tx, nil, nil, nil), seed.LawyerID, string(models.RoleLawyer))
+
+		for _, amount := range []string{"5000", "6000"} {
+			body := `{"case_id":"` + seed.CaseID.String() + `","amount_cents":` + amount + `,"days":5,"note":"A"}`
+			req := httptest.NewRequest("POST", "/api/quotes", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+			if resp.StatusCode != 201 {
+				t.Fatalf("upsert got %d", resp.StatusCode)
 			}
-		})
+		}
+
+		var notes []models.Notification
+		if err := tx.Where("user_id = ?", seed.ClientID).Find(&notes).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(notes) != 1 {
+			t.Fatalf("want 1 notification for the owner, got %d", len(notes))
+		}
+		if notes[0].Type != models.NotifyQuoteReceived || notes[0].CaseID != seed.CaseID || notes[0].ReadAt != nil {
+			t.Fatalf("unexpected notification: %+v", notes[0])
+		}
+	})
+}
+
+// A new quote emails the case owner (async, best-effort).
+func Test_UpsertQuote_EmailsCaseOwner(t *testing.T) {
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseOpen)
+		var owner models.User
+		if err := tx.First(&owner, "id = ?", seed.ClientID).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		rec := &mailer.Recorder{}
+		app := newTestApp(NewHandler(tx, rec, nil, nil), seed.LawyerID, string(models.RoleLawyer))
+
+		body := `{"case_id":"` + seed.CaseID.String() + `","amount_cents":5000,"days":5,"note":"A"}`
+		req := httptest.NewRequest("POST", "/api/quotes", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		if resp.StatusCode != 201 {
+			t.Fatalf("upsert got %d", resp.StatusCode)
+		}
+
+		msgs := rec.WaitFor(1, time.Second)
+		if len(msgs) != 1 || msgs[0].To != owner.Email || msgs[0].Subject != mailer.NewQuote("", "").Subject {
+			t.Fatalf("want one new-quote email to owner, got %+v", msgs)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — single quote
+   ============================================================================ */
+
+// The owning lawyer sees their note as-is; the case owner gets it redacted
+// while OPEN; anyone else is forbidden.
+func Test_GetQuoteByID_Visibility(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+
+	q := models.Quote{
+		CaseID: seed.CaseID, LawyerID: seed.LawyerID, AmountCents: 5000, Days: 3,
+		Note: "call me at me@law.com", Status: models.QuoteProposed,
+	}
+	if err := db.Create(&q).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(userID uuid.UUID, role models.Role) (int, QuoteDetail) {
+		app := fiber.New()
+		app.Use(injectAuth(userID, string(role)))
+		app.Get("/api/quotes/:id", NewHandler(db, nil, nil, nil).GetByID)
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/quotes/"+q.ID.String(), nil))
+		var out QuoteDetail
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+
+	code, out := get(seed.LawyerID, models.RoleLawyer)
+	if code != 200 || out.Note != q.Note || out.CaseID != seed.CaseID {
+		t.Fatalf("lawyer-owner: code=%d out=%+v", code, out)
+	}
+
+	code, out = get(seed.ClientID, models.RoleClient)
+	if code != 200 || strings.Contains(out.Note, "me@law.com") {
+		t.Fatalf("client-owner: want 200 with redacted note, code=%d note=%q", code, out.Note)
+	}
+
+	if code, _ := get(uuid.New(), models.RoleLawyer); code != 403 {
+		t.Fatalf("unrelated lawyer: want 403, got %d", code)
+	}
+	if code, _ := get(uuid.New(), models.RoleClient); code != 403 {
+		t.Fatalf("unrelated client: want 403, got %d", code)
+	}
+}
+
+// A quote on a soft-deleted case is a 404 for its client and for strangers
+// (not a 500), while admins and the quoting lawyer can still read it.
+func Test_GetQuoteByID_DeletedCase(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	q := models.Quote{CaseID: seed.CaseID, LawyerID: seed.LawyerID, AmountCents: 5000, Days: 3, Status: models.QuoteProposed}
+	if err := db.Create(&q).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete(&models.Case{}, "id = ?", seed.CaseID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(userID uuid.UUID, role models.Role) int {
+		app := fiber.New()
+		app.Use(injectAuth(userID, string(role)))
+		app.Get("/api/quotes/:id", NewHandler(db, nil, nil, nil).GetByID)
+		resp, _ := app.Test(httptest.NewRequest("GET", "/api/quotes/"+q.ID.String(), nil))
+		return resp.StatusCode
+	}
+	for _, tc := range []struct {
+		name string
+		user uuid.UUID
+		role models.Role
+		want int
+	}{
+		{"lawyer", seed.LawyerID, models.RoleLawyer, 200},
+		{"admin", uuid.New(), models.RoleAdmin, 200},
+		{"client", seed.ClientID, models.RoleClient, 404},
+		{"stranger", uuid.New(), models.RoleClient, 404},
+	} {
+		if code := get(tc.user, tc.role); code != tc.want {
+			t.Fatalf("%s: want %d, got %d", tc.name, tc.want, code)
+		}
+	}
+}
+
+/* ============================================================================
+   Tests — client rejects a quote
+   ============================================================================ */
+
+// rejectQuote calls the owner reject endpoint as the given user.
+func rejectQuote(db *gorm.DB, userID, caseID, quoteID uuid.UUID) int {
+	app := fiber.New()
+	app.Use(injectAuth(userID, string(models.RoleClient)))
+	app.Post("/api/cases/:id/quotes/:quoteID/reject", NewHandler(db, nil, nil, nil).RejectByOwner)
+	resp, _ := app.Test(httptest.NewRequest("POST",
+		"/api/cases/"+caseID.String()+"/quotes/"+quoteID.String()+"/reject", nil))
+	return resp.StatusCode
+}
+
+// Owner rejects a proposed quote (logged in history); non-owners are forbidden;
+// accepted or already-rejected quotes can't be rejected.
+func Test_RejectQuote_TransitionAndOwnership(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+
+	q := models.Quote{CaseID: seed.CaseID, LawyerID: seed.LawyerID, AmountCents: 5000, Days: 3, Status: models.QuoteProposed}
+	if err := db.Create(&q).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if code := rejectQuote(db, uuid.New(), seed.CaseID, q.ID); code != 403 {
+		t.Fatalf("non-owner: want 403, got %d", code)
+	}
+	if code := rejectQuote(db, seed.ClientID, seed.CaseID, q.ID); code != 200 {
+		t.Fatalf("owner: want 200, got %d", code)
+	}
+
+	var got models.Quote
+	_ = db.First(&got, "id = ?", q.ID).Error
+	if got.Status != models.QuoteRejected {
+		t.Fatalf("want rejected, got %s", got.Status)
+	}
+	var hist int64
+	db.Model(&models.CaseHistory{}).Where("case_id = ? AND action = ?", seed.CaseID, "quote_rejected").Count(&hist)
+	if hist != 1 {
+		t.Fatalf("want 1 history row, got %d", hist)
+	}
+
+	// Repeat → 409
+	if code := rejectQuote(db, seed.ClientID, seed.CaseID, q.ID); code != 409 {
+		t.Fatalf("repeat: want 409, got %d", code)
+	}
+
+	// The lawyer still has a quote on the case (HasMyQuote stays true)
+	var mine int64
+	db.Model(&models.Quote{}).Where("case_id = ? AND lawyer_id = ?", seed.CaseID, seed.LawyerID).Count(&mine)
+	if mine != 1 {
+		t.Fatalf("rejected quote must remain visible to its lawyer, got %d", mine)
+	}
+
+	// Accepted quotes are immutable
+	other := uuid.New()
+	if err := db.Create(&models.User{ID: other, Email: "o+" + other.String() + "@test.local", Role: models.RoleLawyer}).Error; err != nil {
+		t.Fatal(err)
+	}
+	acc := models.Quote{CaseID: seed.CaseID, LawyerID: other, AmountCents: 7000, Days: 5, Status: models.QuoteAccepted}
+	if err := db.Create(&acc).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code := rejectQuote(db, seed.ClientID, seed.CaseID, acc.ID); code != 409 {
+		t.Fatalf("accepted: want 409, got %d", code)
+	}
+}
+
+// A quote the client has started paying for can't be rejected; after the
+// payment fails it can.
+func Test_RejectQuote_RefusedDuringCheckout(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	q := models.Quote{CaseID: seed.CaseID, LawyerID: seed.LawyerID, AmountCents: 5000, Days: 3, Status: models.QuoteProposed}
+	if err := db.Create(&q).Error; err != nil {
+		t.Fatal(err)
+	}
+	pay := models.Payment{CaseID: seed.CaseID, QuoteID: q.ID, ClientID: seed.ClientID, AmountCents: 5000, Status: models.PayInitiated}
+	if err := db.Create(&pay).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: auth.ErrorHandler})
+	app.Use(injectAuth(seed.ClientID, string(models.RoleClient)))
+	app.Post("/api/cases/:id/quotes/:quoteID/reject", NewHandler(db, nil, nil, nil).RejectByOwner)
+	resp, _ := app.Test(httptest.NewRequest("POST",
+		"/api/cases/"+seed.CaseID.String()+"/quotes/"+q.ID.String()+"/reject", nil))
+	var e models.ErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&e)
+	if resp.StatusCode != 409 || e.Code != apperr.QuoteInCheckout {
+		t.Fatalf("during checkout: want 409 %s, got %d %s", apperr.QuoteInCheckout, resp.StatusCode, e.Code)
+	}
+
+	db.Model(&models.Payment{}).Where("id = ?", pay.ID).Update("status", models.PayFailed)
+	if code := rejectQuote(db, seed.ClientID, seed.CaseID, q.ID); code != 200 {
+		t.Fatalf("after the payment failed: want 200, got %d", code)
+	}
+}
+
+/* ============================================================================
+   Tests — expiry
+   ============================================================================ */
+
+// New quotes get a default expiry; the sweep rejects only lapsed proposals.
+func Test_Quote_DefaultExpiry_AndSweep(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	app := newTestApp(NewHandler(db, nil, nil, nil), seed.LawyerID, string(models.RoleLawyer))
+
+	body := `{"case_id":"` + seed.CaseID.String() + `","amount_cents":5000,"days":3}`
+	req := httptest.NewRequest("POST", "/api/quotes", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if resp, _ := app.Test(req); resp.StatusCode != 201 {
+		t.Fatalf("upsert: want 201, got %d", resp.StatusCode)
+	}
+
+	var q models.Quote
+	if err := db.First(&q, "case_id = ? AND lawyer_id = ?", seed.CaseID, seed.LawyerID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if q.ExpiresAt == nil || q.ExpiresAt.Before(time.Now().Add(6*24*time.Hour)) {
+		t.Fatalf("want ~7 day default expiry, got %v", q.ExpiresAt)
+	}
+
+	// Nothing lapsed yet
+	if n, err := ExpireStale(db, time.Now()); err != nil || n != 0 {
+		t.Fatalf("sweep now: n=%d err=%v", n, err)
+	}
+	// Jump past expiry
+	if n, err := ExpireStale(db, q.ExpiresAt.Add(time.Minute)); err != nil || n != 1 {
+		t.Fatalf("sweep later: n=%d err=%v", n, err)
+	}
+	_ = db.First(&q, "id = ?", q.ID).Error
+	if q.Status != models.QuoteRejected {
+		t.Fatalf("want rejected after sweep, got %s", q.Status)
+	}
+}
+
+// A lapsed proposal with a checkout in flight is left for the payment.
+func Test_ExpireStale_SkipsQuoteInCheckout(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	past := time.Now().Add(-time.Hour)
+	q := models.Quote{CaseID: seed.CaseID, LawyerID: seed.LawyerID, AmountCents: 5000, Days: 3, Status: models.QuoteProposed, ExpiresAt: &past}
+	if err := db.Create(&q).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Payment{CaseID: seed.CaseID, QuoteID: q.ID, ClientID: seed.ClientID, AmountCents: 5000, Status: models.PayInitiated}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := ExpireStale(db, time.Now()); err != nil || n != 0 {
+		t.Fatalf("sweep: want 0, got %d (err=%v)", n, err)
+	}
+	_ = db.First(&q, "id = ?", q.ID).Error
+	if q.Status != models.QuoteProposed {
+		t.Fatalf("want still proposed, got %s", q.Status)
+	}
+}
+
+/* ============================================================================
+   Tests — owner list by case
+   ============================================================================ */
+
+// The owner can narrow the case's quotes to one status; total follows the filter.
+func Test_ListByCaseForOwner_StatusFilter(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	for i, st := range []models.QuoteStatus{models.QuoteProposed, models.QuoteProposed, models.QuoteRejected} {
+		if err := db.Create(&models.Quote{
+			CaseID: seed.CaseID, LawyerID: uuid.New(), AmountCents: 1000 + i, Days: 1,
+			Note: "call 0812 3456 7890", Status: st, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+		}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := fiber.New()
+	app.Use(injectAuth(seed.ClientID, string(models.RoleClient)))
+	app.Get("/api/cases/:id/quotes", NewHandler(db, nil, nil, nil).ListByCaseForOwner)
+	list := func(query string) (int, PageMyQuotes) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/cases/"+seed.CaseID.String()+"/quotes"+query, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out PageMyQuotes
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+
+	code, all := list("")
+	if code != 200 || all.Total != 3 {
+		t.Fatalf("unfiltered: want 200 total=3, got %d total=%d", code, all.Total)
+	}
+
+	code, rejected := list("?status=rejected&pageSize=10")
+	if code != 200 || rejected.Total != 1 || len(rejected.Items) != 1 || rejected.Items[0].Status != "rejected" {
+		t.Fatalf("rejected filter: got %d %+v", code, rejected)
+	}
+	if strings.Contains(rejected.Items[0].Note, "3456") {
+		t.Fatalf("open-case notes must stay redacted, got %q", rejected.Items[0].Note)
+	}
+
+	if code, _ := list("?status=bogus"); code != 400 {
+		t.Fatalf("invalid status: want 400, got %d", code)
+	}
+}
+
+// Admins read notes and pitches as written (moderation), on any case; the
+// owner's view of the same open case stays redacted.
+func Test_ListByCaseForOwner_AdminSeesRawNotes(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	if err := db.Create(&models.Quote{
+		CaseID: seed.CaseID, LawyerID: seed.LawyerID, AmountCents: 1000, Days: 1,
+		Note: "call 0812 3456 7890", Pitch: "mail me at abuse@example.com",
+		Status: models.QuoteProposed, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHandler(db, nil, nil, nil)
+	list := func(userID uuid.UUID, role string) (int, PageMyQuotes) {
+		t.Helper()
+		app := fiber.New()
+		app.Use(injectAuth(userID, role))
+		app.Get("/api/cases/:id/quotes", h.ListByCaseForOwner)
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/cases/"+seed.CaseID.String()+"/quotes", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out PageMyQuotes
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+
+	code, out := list(uuid.New(), string(models.RoleAdmin))
+	if code != 200 || len(out.Items) != 1 {
+		t.Fatalf("admin: want 200 with 1 quote, got %d %+v", code, out)
+	}
+	if out.Items[0].Note != "call 0812 3456 7890" || out.Items[0].Pitch != "mail me at abuse@example.com" {
+		t.Fatalf("admin should see raw note and pitch, got %q / %q", out.Items[0].Note, out.Items[0].Pitch)
+	}
+
+	code, out = list(seed.ClientID, string(models.RoleClient))
+	if code != 200 || len(out.Items) != 1 ||
+		strings.Contains(out.Items[0].Note, "3456") || strings.Contains(out.Items[0].Pitch, "abuse@") {
+		t.Fatalf("owner should still see redacted text, got %d %+v", code, out.Items)
+	}
+
+	if code, _ := list(seed.LawyerID, string(models.RoleLawyer)); code != 403 {
+		t.Fatalf("lawyer: want 403, got %d", code)
+	}
+}
+
+// The owner's quote list carries RFC3339 UTC timestamps in whole seconds,
+// the same shape as every other list.
+func Test_ListByCaseForOwner_TimesAreRFC3339UTC(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	now := time.Now().In(time.FixedZone("UTC-5", -5*3600))
+	expires := now.Add(7 * 24 * time.Hour)
+	if err := db.Create(&models.Quote{
+		CaseID: seed.CaseID, LawyerID: seed.LawyerID, AmountCents: 1000, Days: 1,
+		Status: models.QuoteProposed, CreatedAt: now, UpdatedAt: now, ExpiresAt: &expires,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Use(injectAuth(seed.ClientID, string(models.RoleClient)))
+	app.Get("/api/cases/:id/quotes", NewHandler(db, nil, nil, nil).ListByCaseForOwner)
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/cases/"+seed.CaseID.String()+"/quotes", nil))
+	if err != nil || resp.StatusCode != 200 {
+		t.Fatalf("want 200, got %v (err=%v)", resp.StatusCode, err)
+	}
+	var out struct {
+		Items []struct {
+			CreatedAt string `json:"created_at"`
+			UpdatedAt string `json:"updated_at"`
+			ExpiresAt string `json:"expires_at"`
+		} `json:"items"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	if len(out.Items) != 1 {
+		t.Fatalf("want 1 quote, got %d", len(out.Items))
+	}
+	it := out.Items[0]
+	if it.CreatedAt != apitime.Format(now) || it.UpdatedAt != apitime.Format(now) || it.ExpiresAt != apitime.Format(expires) {
+		t.Fatalf("want %s / %s / %s, got %s / %s / %s",
+			apitime.Format(now), apitime.Format(now), apitime.Format(expires), it.CreatedAt, it.UpdatedAt, it.ExpiresAt)
+	}
+}
+
+/* ============================================================================
+   Tests — quote comparison summary
+   ============================================================================ */
+
+// The summary aggregates live quotes per currency (rejected and expired ones
+// are left out) and is only for the case owner.
+func Test_SummaryForOwner_Aggregates(t *testing.T) {
+	t.Setenv("STRIPE_CURRENCY", "sgd")
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		seed := seedCase(t, tx, models.CaseOpen)
+		past := time.Now().Add(-time.Hour)
+		for _, q := range []models.Quote{
+			{AmountCents: 3000, Currency: "SGD", Days: 7, Status: models.QuoteProposed},
+			{AmountCents: 1000, Currency: "", Days: 3, Status: models.QuoteProposed}, // legacy → SGD
+			{AmountCents: 2000, Currency: "SGD", Days: 10, Status: models.QuoteProposed},
+			{AmountCents: 5000, Currency: "SGD", Days: 5, Status: models.QuoteProposed},
+			{AmountCents: 800, Currency: "EUR", Days: 4, Status: models.QuoteProposed},
+			{AmountCents: 1, Currency: "SGD", Days: 1, Status: models.QuoteRejected},
+			{AmountCents: 1, Currency: "SGD", Days: 1, Status: models.QuoteProposed, ExpiresAt: &past},
+		} {
+			q.CaseID, q.LawyerID = seed.CaseID, uuid.New()
+			if err := tx.Create(&q).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		summary := func(userID uuid.UUID) (int, QuoteSummary) {
+			app := fiber.New()
+			app.Use(injectAuth(userID, string(models.RoleClient)))
+			app.Get("/api/cases/:id/quotes/summary", NewHandler(tx, nil, nil, nil).SummaryForOwner)
+			resp, err := app.Test(httptest.NewRequest("GET", "/api/cases/"+seed.CaseID.String()+"/quotes/summary", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out QuoteSummary
+			_ = json.NewDecoder(resp.Body).Decode(&out)
+			return resp.StatusCode, out
+		}
+
+		code, got := summary(seed.ClientID)
+		if code != 200 {
+			t.Fatalf("owner: want 200, got %d", code)
+		}
+		if got.Count != 5 || got.MinDays == nil || *got.MinDays != 3 || got.MaxDays == nil || *got.MaxDays != 10 {
+			t.Fatalf("want count=5 days 3..10, got %+v", got)
+		}
+		want := []AmountSummary{
+			{Currency: "SGD", Count: 4, MinCents: 1000, MaxCents: 5000, MedianCents: 2500},
+			{Currency: "EUR", Count: 1, MinCents: 800, MaxCents: 800, MedianCents: 800},
+		}
+		if len(got.Amounts) != len(want) || got.Amounts[0] != want[0] || got.Amounts[1] != want[1] {
+			t.Fatalf("amounts: want %+v, got %+v", want, got.Amounts)
+		}
+
+		if code, _ := summary(uuid.New()); code != 403 {
+			t.Fatalf("non-owner: want 403, got %d", code)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — pitch
+   ============================================================================ */
+
+// The pitch is length-checked, redacted for the owner while OPEN, and shown in
+// full only for the accepted quote once ENGAGED.
+func Test_Pitch_VisibilityBeforeAndAfterEngagement(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+	const pitch = "Ten years of tenancy work. Reach me at pitch@law.com"
+
+	lawyer := newTestApp(NewHandler(db, nil, nil, nil), seed.LawyerID, string(models.RoleLawyer))
+	upsert := func(p string) int {
+		body := `{"case_id":"` + seed.CaseID.String() + `","amount_cents":5000,"days":5,"pitch":"` + p + `"}`
+		req := httptest.NewRequest("POST", "/api/quotes", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := lawyer.Test(req)
+		return resp.StatusCode
+	}
+	if code := upsert(strings.Repeat("x", 2001)); code != 400 {
+		t.Fatalf("long pitch: want 400, got %d", code)
+	}
+	if code := upsert(pitch); code != 201 {
+		t.Fatalf("upsert: want 201, got %d", code)
+	}
+	other := models.Quote{
+		CaseID: seed.CaseID, LawyerID: uuid.New(), AmountCents: 6000, Days: 5,
+		Pitch: "Call 0812 3456 7890", Status: models.QuoteProposed,
+	}
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	owner := fiber.New()
+	owner.Use(injectAuth(seed.ClientID, string(models.RoleClient)))
+	owner.Get("/api/cases/:id/quotes", NewHandler(db, nil, nil, nil).ListByCaseForOwner)
+	pitches := func() map[uuid.UUID]string {
+		resp, _ := owner.Test(httptest.NewRequest("GET", "/api/cases/"+seed.CaseID.String()+"/quotes", nil))
+		var out struct {
+			Items []caseQuoteItem `json:"items"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		m := map[uuid.UUID]string{}
+		for _, it := range out.Items {
+			m[it.LawyerID] = it.Pitch
+		}
+		return m
+	}
+
+	open := pitches()
+	if got := open[seed.LawyerID]; got == "" || strings.Contains(got, "pitch@law.com") {
+		t.Fatalf("open case: want redacted pitch, got %q", got)
+	}
+
+	// Engage on the first lawyer's quote
+	var q models.Quote
+	if err := db.First(&q, "case_id = ? AND lawyer_id = ?", seed.CaseID, seed.LawyerID).Error; err != nil {
+		t.Fatal(err)
+	}
+	db.Model(&q).Update("status", models.QuoteAccepted)
+	db.Model(&models.Case{}).Where("id = ?", seed.CaseID).Updates(map[string]any{
+		"status": models.CaseEngaged, "accepted_quote_id": q.ID, "accepted_lawyer_id": seed.LawyerID,
+	})
+
+	engaged := pitches()
+	if engaged[seed.LawyerID] != pitch {
+		t.Fatalf("accepted quote: want full pitch, got %q", engaged[seed.LawyerID])
+	}
+	if strings.Contains(engaged[other.LawyerID], "3456") {
+		t.Fatalf("other quote: pitch must stay redacted, got %q", engaged[other.LawyerID])
+	}
+}
+
+/* ============================================================================
+   Tests — lawyer dashboard stats
+   ============================================================================ */
+
+// Each figure counts only the authenticated lawyer's quotes and payments.
+func Test_Stats_AggregatesForLawyer(t *testing.T) {
+	t.Setenv("STRIPE_CURRENCY", "sgd")
+	db := openTestDB(t)
+	withTx(t, db, func(tx *gorm.DB) {
+		lawyer := uuid.New()
+		if err := tx.Create(&models.User{ID: lawyer, Email: fmt.Sprintf("l+%s@test.local", uuid.NewString()), Role: models.RoleLawyer}).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		// quote adds a quote on a fresh case; accepted ones engage the case
+		// (caseStatus) and get a payment with payStatus
+		quote := func(lawyerID uuid.UUID, st models.QuoteStatus, caseStatus models.CaseStatus, cents int, cur string, payStatus models.PayStatus) {
+			s := seedCase(t, tx, caseStatus)
+			q := models.Quote{
+				CaseID: s.CaseID, LawyerID: lawyerID, AmountCents: cents, Currency: cur, Days: 1,
+				Status: st, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+			}
+			if err := tx.Create(&q).Error; err != nil {
+				t.Fatal(err)
+			}
+			if st != models.QuoteAccepted {
+				return
+			}
+			tx.Model(&models.Case{}).Where("id = ?", s.CaseID).
+				Updates(map[string]any{"accepted_quote_id": q.ID, "accepted_lawyer_id": lawyerID})
+			if err := tx.Create(&models.Payment{
+				CaseID: s.CaseID, QuoteID: q.ID, ClientID: s.ClientID,
+				AmountCents: cents, Currency: cur, Status: payStatus, CreatedAt: time.Now(),
+			}).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		quote(lawyer, models.QuoteAccepted, models.CaseEngaged, 5000, "SGD", models.PayPaid)
+		quote(lawyer, models.QuoteAccepted, models.CaseClosed, 2000, "", models.PayPaid) // legacy → SGD
+		quote(lawyer, models.QuoteAccepted, models.CaseClosed, 700, "EUR", models.PayPaid)
+		quote(lawyer, models.QuoteProposed, models.CaseOpen, 100, "SGD", "")
+		quote(lawyer, models.QuoteRejected, models.CaseOpen, 100, "SGD", "")
+		quote(lawyer, models.QuoteRejected, models.CaseOpen, 100, "SGD", "")
+		// Someone else's win never counts
+		quote(uuid.New(), models.QuoteAccepted, models.CaseEngaged, 9999, "SGD", models.PayPaid)
+
+		app := fiber.New()
+		app.Use(injectAuth(lawyer, string(models.RoleLawyer)))
+		app.Get("/api/lawyers/me/stats", NewHandler(tx, nil, nil, nil).Stats)
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/lawyers/me/stats", nil))
+		if err != nil || resp.StatusCode != 200 {
+			t.Fatalf("want 200, got %v (err=%v)", resp, err)
+		}
+		var got LawyerStats
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Proposed != 1 || got.Accepted != 3 || got.Rejected != 2 {
+			t.Fatalf("quote counts: want 1/3/2, got %d/%d/%d", got.Proposed, got.Accepted, got.Rejected)
+		}
+		if got.EngagedCases != 1 {
+			t.Fatalf("engaged cases: want 1, got %d", got.EngagedCases)
+		}
+		if len(got.EarnedCents) != 2 || got.EarnedCents["SGD"] != 7000 || got.EarnedCents["EUR"] != 700 {
+			t.Fatalf("earned: want SGD 7000 + EUR 700, got %v", got.EarnedCents)
+		}
+		if got.WinRate != 0.6 {
+			t.Fatalf("win rate: want 0.6, got %v", got.WinRate)
+		}
+	})
+}
+
+/* ============================================================================
+   Tests — accepted quote
+   ============================================================================ */
+
+// getAccepted calls GET /cases/:id/quotes/accepted as the given user.
+func getAccepted(db *gorm.DB, userID uuid.UUID, role models.Role, caseID uuid.UUID) (int, QuoteDetail) {
+	app := fiber.New()
+	app.Use(injectAuth(userID, string(role)))
+	app.Get("/api/cases/:id/quotes/accepted", NewHandler(db, nil, nil, nil).GetAccepted)
+	resp, _ := app.Test(httptest.NewRequest("GET", "/api/cases/"+caseID.String()+"/quotes/accepted", nil))
+	var out QuoteDetail
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+// Engaged case: owner, accepted lawyer and their collaborators get the
+// accepted quote with its full note; admins get it redacted. Other lawyers get
+// the case detail's 404, other clients a 403.
+func Test_GetAccepted_EngagedCase(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseEngaged)
+
+	q := models.Quote{
+		CaseID: seed.CaseID, LawyerID: seed.LawyerID, AmountCents: 7000, Days: 5,
+		Note: "reach me at me@law.com", Status: models.QuoteAccepted,
+	}
+	if err := db.Create(&q).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&models.Case{}).Where("id = ?", seed.CaseID).Updates(map[string]any{
+		"accepted_quote_id":  q.ID,
+		"accepted_lawyer_id": seed.LawyerID,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, who := range []struct {
+		id   uuid.UUID
+		role models.Role
+	}{{seed.ClientID, models.RoleClient}, {seed.LawyerID, models.RoleLawyer}} {
+		code, out := getAccepted(db, who.id, who.role, seed.CaseID)
+		if code != 200 || out.ID != q.ID || out.Note != q.Note || out.AmountCents != 7000 {
+			t.Fatalf("%s: code=%d out=%+v", who.role, code, out)
+		}
+	}
+
+	code, out := getAccepted(db, uuid.New(), models.RoleAdmin, seed.CaseID)
+	if code != 200 || strings.Contains(out.Note, "me@law.com") {
+		t.Fatalf("admin: want 200 with redacted note, code=%d note=%q", code, out.Note)
+	}
+	if code, _ := getAccepted(db, uuid.New(), models.RoleLawyer, seed.CaseID); code != 404 {
+		t.Fatalf("other lawyer: want 404, got %d", code)
+	}
+	if code, _ := getAccepted(db, uuid.New(), models.RoleClient, seed.CaseID); code != 403 {
+		t.Fatalf("other client: want 403, got %d", code)
+	}
+
+	para := uuid.New()
+	if err := db.Create(&models.User{ID: para, Email: "p+" + para.String() + "@test.local", Role: models.RoleLawyer}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.CaseCollaborator{CaseID: seed.CaseID, UserID: para, GrantedBy: seed.LawyerID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, out := getAccepted(db, para, models.RoleLawyer, seed.CaseID); code != 200 || out.Note != q.Note {
+		t.Fatalf("collaborator: want 200 with full note, code=%d note=%q", code, out.Note)
+	}
+
+	// Soft-deleted: gone for the parties, still readable by admins
+	if err := db.Delete(&models.Case{}, "id = ?", seed.CaseID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := getAccepted(db, seed.ClientID, models.RoleClient, seed.CaseID); code != 404 {
+		t.Fatalf("deleted case, owner: want 404, got %d", code)
+	}
+	if code, _ := getAccepted(db, uuid.New(), models.RoleAdmin, seed.CaseID); code != 200 {
+		t.Fatalf("deleted case, admin: want 200, got %d", code)
+	}
+}
+
+// Open case has no accepted quote → 404 for its owner.
+func Test_GetAccepted_OpenCaseNotFound(t *testing.T) {
+	db := openTestDB(t)
+	seed := seedCaseNoTx(t, db, models.CaseOpen)
+
+	if code, _ := getAccepted(db, seed.ClientID, models.RoleClient, seed.CaseID); code != 404 {
+		t.Fatalf("open case: want 404, got %d", code)
+	}
+}
+
+/* ============================================================================
+   Tests — single accepted quote
+   ============================================================================ */
+
+// The partial unique index refuses a second ACCEPTED quote on the same case,
+// while accepted quotes on different cases are fine.
+func Test_SingleAcceptedQuote_Index(t *testing.T) {
+	db := openTestDB(t)
+	s := seedCaseNoTx(t, db, models.CaseOpen)
+	other := seedCaseNoTx(t, db, models.CaseOpen)
+
+	q1 := models.Quote{CaseID: s.CaseID, LawyerID: s.LawyerID, AmountCents: 1000, Days: 1, Status: models.QuoteAccepted}
+	q2 := models.Quote{CaseID: s.CaseID, LawyerID: other.LawyerID, AmountCents: 2000, Days: 2, Status: models.QuoteProposed}
+	q3 := models.Quote{CaseID: other.CaseID, LawyerID: other.LawyerID, AmountCents: 3000, Days: 3, Status: models.QuoteAccepted}
+	for _, q := range []*models.Quote{&q1, &q2, &q3} {
+		if err := db.Create(q).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := db.Model(&models.Quote{}).Where("id = ?", q2.ID).
+		Update("status", models.QuoteAccepted).Error
+	if !utils.IsUniqueViolation(err) {
+		t.Fatalf("want unique violation on second accepted quote, got %v", err)
+	}
+
+	// Other statuses are unaffected
+	if err := db.Model(&models.Quote{}).Where("id = ?", q2.ID).
+		Update("status", models.QuoteRejected).Error; err != nil {
+		t.Fatalf("reject should pass: %v", err)
 	}
 }
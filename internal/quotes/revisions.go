@@ -0,0 +1,122 @@
+package quotes
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/apitime"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/money"
+)
+
+// QuoteRevisionItem is one version of a quote, oldest first.
+type QuoteRevisionItem struct {
+	AmountCents int       `json:"amount_cents"`
+	Currency    string    `json:"currency"`
+	Days        int       `json:"days"`
+	Note        string    `json:"note"` // redacted for the client like the quote itself
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// backfillRevision records the version an update replaces when the quote
+// predates revisions, so its first tracked change still shows the old price.
+func backfillRevision(tx *gorm.DB, prev models.Quote, created bool) error {
+	if created {
+		return nil
+	}
+	var n int64
+	if err := tx.Model(&models.QuoteRevision{}).Where("quote_id = ?", prev.ID).Count(&n).Error; err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+	return tx.Create(&models.QuoteRevision{
+		QuoteID:     prev.ID,
+		AmountCents: prev.AmountCents,
+		Currency:    money.OrDefault(prev.Currency),
+		Days:        prev.Days,
+		Note:        prev.Note,
+		CreatedAt:   prev.UpdatedAt,
+	}).Error
+}
+
+/* ============================ Quote Revisions ============================= */
+
+// @Summary      Quote revisions
+// @Description  Every version of a quote (amount, currency, days, note), oldest first. Owning lawyer sees notes as written; the case owner sees them with the same redaction as the quote.
+// @Tags         quotes
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "quote id (uuid)"
+// @Success      200  {array}   QuoteRevisionItem
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /quotes/{id}/revisions [get]
+func (h *Handler) ListRevisions(c *fiber.Ctx) error {
+	userID := auth.MustUserID(c)
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid quote id")
+	}
+
+	var q models.Quote
+	if err := h.db.Select("id, case_id, lawyer_id").First(&q, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+
+	// Same access as GetByID: owning lawyer as-is, case owner redacted
+	redact := func(note string) string { return note }
+	if q.LawyerID.String() != userID {
+		var cs struct {
+			ClientID        uuid.UUID
+			Status          models.CaseStatus
+			AcceptedQuoteID uuid.UUID
+		}
+		if err := h.db.
+			Model(&models.Case{}).
+			Select("client_id, status, accepted_quote_id").
+			Where("id = ?", q.CaseID).
+			First(&cs).Error; err != nil {
+			return fiber.ErrInternalServerError
+		}
+		switch {
+		case auth.IsAdmin(c):
+			auth.LogAdminAccess(c)
+		case cs.ClientID.String() == userID:
+			redact = func(note string) string { return ownerNote(cs.Status, cs.AcceptedQuoteID, q.ID, note) }
+		default:
+			return fiber.ErrForbidden
+		}
+	}
+
+	var revs []models.QuoteRevision
+	if err := h.db.Where("quote_id = ?", q.ID).
+		Order("created_at ASC, id ASC").
+		Find(&revs).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	items := make([]QuoteRevisionItem, 0, len(revs))
+	for _, r := range revs {
+		items = append(items, QuoteRevisionItem{
+			AmountCents: r.AmountCents,
+			Currency:    money.OrDefault(r.Currency),
+			Days:        r.Days,
+			Note:        redact(r.Note),
+			CreatedAt:   apitime.Normalize(r.CreatedAt),
+		})
+	}
+	return c.JSON(items)
+}
@@ -0,0 +1,94 @@
+package quotes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/money"
+)
+
+// LawyerStats is the lawyer dashboard summary.
+type LawyerStats struct {
+	Proposed     int64 `json:"proposed"`
+	Accepted     int64 `json:"accepted"`
+	Rejected     int64 `json:"rejected"`
+	EngagedCases int64 `json:"engaged_cases"`
+	// Paid payments for accepted quotes, per currency (ISO-4217 → cents)
+	EarnedCents map[string]int64 `json:"earned_cents"`
+	// Accepted / (accepted + rejected); 0 until a quote has been decided
+	WinRate float64 `json:"win_rate"`
+}
+
+/* ========================= Lawyer: Dashboard Stats ======================== */
+
+// @Summary      My dashboard stats
+// @Description  Lawyer's quote counts by status, engaged cases, earnings per currency and win rate (aggregates only)
+// @Tags         quotes
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  LawyerStats
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /lawyers/me/stats [get]
+func (h *Handler) Stats(c *fiber.Ctx) error {
+	lawyerID := auth.MustUserID(c)
+	out := LawyerStats{EarnedCents: map[string]int64{}}
+
+	// Quotes by status (one grouped query)
+	var byStatus []struct {
+		Status models.QuoteStatus
+		N      int64
+	}
+	if err := h.db.Model(&models.Quote{}).
+		Select("status, COUNT(*) AS n").
+		Where("lawyer_id = ?", lawyerID).
+		Group("status").
+		Scan(&byStatus).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	for _, r := range byStatus {
+		switch r.Status {
+		case models.QuoteProposed:
+			out.Proposed = r.N
+		case models.QuoteAccepted:
+			out.Accepted = r.N
+		case models.QuoteRejected:
+			out.Rejected = r.N
+		}
+	}
+
+	// Cases currently engaged with this lawyer
+	if err := h.db.Model(&models.Case{}).
+		Where("accepted_lawyer_id = ? AND status = ?", lawyerID, models.CaseEngaged).
+		Count(&out.EngagedCases).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	// Earnings: paid payments on this lawyer's accepted quotes, per currency
+	var earned []struct {
+		Currency string
+		Cents    int64
+	}
+	if err := h.db.Model(&models.Payment{}).
+		Select("COALESCE(payments.currency, '') AS currency, SUM(payments.amount_cents) AS cents").
+		Joins("JOIN quotes ON quotes.id = payments.quote_id").
+		Where("quotes.lawyer_id = ? AND quotes.status = ? AND payments.status = ?",
+			lawyerID, models.QuoteAccepted, models.PayPaid).
+		Group("COALESCE(payments.currency, '')").
+		Scan(&earned).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	for _, r := range earned {
+		out.EarnedCents[money.OrDefault(r.Currency)] += r.Cents // legacy rows merge into the default
+	}
+
+	if decided := out.Accepted + out.Rejected; decided > 0 {
+		out.WinRate = float64(out.Accepted) / float64(decided)
+	}
+
+	// Per-user data: browsers may reuse it briefly, shared caches must not
+	c.Set(fiber.HeaderCacheControl, "private, max-age=60")
+	return c.JSON(out)
+}
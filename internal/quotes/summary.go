@@ -0,0 +1,99 @@
+package quotes
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/internal/auth"
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/money"
+)
+
+// QuoteSummary compares a case's live quotes (proposed and unexpired, or
+// accepted) at a glance. Aggregates only: no notes, pitches or lawyers.
+type QuoteSummary struct {
+	Count   int64 `json:"count"`
+	MinDays *int  `json:"min_days"` // nil when there are no live quotes
+	MaxDays *int  `json:"max_days"`
+	// Amounts can't be compared across currencies: one entry each
+	Amounts []AmountSummary `json:"amounts"`
+}
+
+// AmountSummary is the amount spread of the quotes in one currency.
+type AmountSummary struct {
+	Currency    string `json:"currency"`
+	Count       int64  `json:"count"`
+	MinCents    int64  `json:"min_cents"`
+	MaxCents    int64  `json:"max_cents"`
+	MedianCents int64  `json:"median_cents"` // rounded to the nearest cent
+}
+
+/* ====================== Client: Quote Comparison ========================== */
+
+// @Summary      Quote comparison summary (owner)
+// @Description  Client owner gets count, min/max days and min/max/median amount per currency over the case's live quotes (aggregates only)
+// @Tags         quotes
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string true "case id (uuid)"
+// @Success      200  {object}  QuoteSummary
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /cases/{id}/quotes/summary [get]
+func (h *Handler) SummaryForOwner(c *fiber.Ctx) error {
+	clientID := auth.MustUserID(c)
+	caseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid case id")
+	}
+
+	var cs models.Case
+	if err := h.db.Select("id, client_id").First(&cs, "id = ?", caseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrInternalServerError
+	}
+	if cs.ClientID.String() != clientID {
+		return fiber.ErrForbidden
+	}
+
+	live := h.db.Model(&models.Quote{}).
+		Where("case_id = ?", cs.ID).
+		Where("(status = ? OR (status = ? AND (expires_at IS NULL OR expires_at > ?)))",
+			models.QuoteAccepted, models.QuoteProposed, time.Now())
+
+	out := QuoteSummary{Amounts: []AmountSummary{}}
+	var days struct {
+		N       int64
+		MinDays *int
+		MaxDays *int
+	}
+	if err := live.Session(&gorm.Session{}).
+		Select("COUNT(*) AS n, MIN(days) AS min_days, MAX(days) AS max_days").
+		Scan(&days).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	out.Count, out.MinDays, out.MaxDays = days.N, days.MinDays, days.MaxDays
+
+	// Legacy rows without a currency count as the default one
+	if err := live.Session(&gorm.Session{}).
+		Select(`COALESCE(NULLIF(UPPER(currency), ''), ?) AS currency, COUNT(*) AS count,
+          MIN(amount_cents) AS min_cents, MAX(amount_cents) AS max_cents,
+          ROUND(percentile_cont(0.5) WITHIN GROUP (ORDER BY amount_cents))::bigint AS median_cents`,
+			money.DefaultCurrency()).
+		Group("1").
+		Order("count DESC, currency").
+		Scan(&out.Amounts).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(out)
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -41,6 +42,31 @@ func (s *Supabase) MakeObjectKey(caseID, filename string) string {
 	return path.Join("case", caseID, filename)
 }
 
+// Ping checks that the bucket is reachable with our credentials:
+// GET /storage/v1/bucket/{bucket}
+func (s *Supabase) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("%s/storage/v1/bucket/%s", s.baseURL, s.bucket)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("apikey", s.apiKey)
+	// See header note at the top of the file.
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("supabase bucket check: %s", res.Status)
+	}
+	return nil
+}
+
 // Upload sends a new object to: POST /storage/v1/object/{bucket}/{objectName}
 func (s *Supabase) Upload(key string, r io.Reader, contentType string, size int64) error {
 	url := fmt.Sprintf("%s/storage/v1/object/%s/%s", s.baseURL, s.bucket, key)
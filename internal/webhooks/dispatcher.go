@@ -0,0 +1,249 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+/* ================================ Events ================================= */
+
+// Event is the type of a case event sent to integrators.
+type Event string
+
+const (
+	EventCaseEngaged    Event = "case.engaged"
+	EventQuoteSubmitted Event = "quote.submitted"
+	EventPaymentPaid    Event = "payment.paid"
+)
+
+// Events lists every event type an endpoint can subscribe to.
+var Events = []Event{EventCaseEngaged, EventQuoteSubmitted, EventPaymentPaid}
+
+// Headers sent with every delivery.
+const (
+	SignatureHeader = "X-Webhook-Signature" // "sha256=" + hex HMAC-SHA256 of the raw body
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery" // delivery id; stable across retries
+)
+
+// Envelope is the JSON body POSTed to endpoints.
+type Envelope struct {
+	ID        uuid.UUID `json:"id"` // delivery id
+	Event     Event     `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// CaseEngaged is the data of a case.engaged event.
+type CaseEngaged struct {
+	CaseID    uuid.UUID `json:"case_id"`
+	Reference *string   `json:"reference,omitempty"`
+	ClientID  uuid.UUID `json:"client_id"`
+	LawyerID  uuid.UUID `json:"lawyer_id"`
+	QuoteID   uuid.UUID `json:"quote_id"`
+	EngagedAt time.Time `json:"engaged_at"`
+}
+
+// QuoteSubmitted is the data of a quote.submitted event (the note is never sent).
+type QuoteSubmitted struct {
+	QuoteID     uuid.UUID `json:"quote_id"`
+	CaseID      uuid.UUID `json:"case_id"`
+	LawyerID    uuid.UUID `json:"lawyer_id"`
+	AmountCents int       `json:"amount_cents"`
+	Currency    string    `json:"currency"`
+	Days        int       `json:"days"`
+}
+
+// PaymentPaid is the data of a payment.paid event.
+type PaymentPaid struct {
+	PaymentID   uuid.UUID `json:"payment_id"`
+	CaseID      uuid.UUID `json:"case_id"`
+	QuoteID     uuid.UUID `json:"quote_id"`
+	AmountCents int       `json:"amount_cents"`
+	Currency    string    `json:"currency"`
+	PaidAt      time.Time `json:"paid_at"`
+}
+
+/* ============================== Dispatcher =============================== */
+
+// Dispatcher delivers events to subscribed endpoints in the background with
+// retry/backoff, logging every delivery in webhook_deliveries.
+// A nil *Dispatcher is valid and sends nothing.
+type Dispatcher struct {
+	db          *gorm.DB
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration // first retry delay; doubled per attempt
+
+	wg sync.WaitGroup
+}
+
+// NewDispatcher builds a dispatcher from env.
+// Env: WEBHOOK_MAX_ATTEMPTS (default 5), WEBHOOK_BACKOFF (Go duration, default 2s),
+// WEBHOOK_TIMEOUT (per request, default 10s).
+func NewDispatcher(db *gorm.DB) *Dispatcher {
+	attempts := 5
+	if n, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS")); err == nil && n > 0 {
+		attempts = n
+	}
+	backoff := 2 * time.Second
+	if d, err := time.ParseDuration(os.Getenv("WEBHOOK_BACKOFF")); err == nil && d > 0 {
+		backoff = d
+	}
+	timeout := 10 * time.Second
+	if d, err := time.ParseDuration(os.Getenv("WEBHOOK_TIMEOUT")); err == nil && d > 0 {
+		timeout = d
+	}
+	return &Dispatcher{
+		db:          db,
+		client:      &http.Client{Timeout: timeout},
+		maxAttempts: attempts,
+		backoff:     backoff,
+	}
+}
+
+// Sign returns the signature header value for body: "sha256=<hex hmac>".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatch sends event to every active endpoint subscribed to it.
+// It returns immediately; delivery never blocks (or fails) the caller.
+func (d *Dispatcher) Dispatch(event Event, data any) {
+	if d == nil || d.db == nil {
+		return
+	}
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.dispatch(event, data)
+	}()
+}
+
+// Wait blocks until every in-flight delivery (including retries) finishes.
+func (d *Dispatcher) Wait() {
+	if d != nil {
+		d.wg.Wait()
+	}
+}
+
+func (d *Dispatcher) dispatch(event Event, data any) {
+	var endpoints []models.WebhookEndpoint
+	if err := d.db.Where("active = ?", true).Find(&endpoints).Error; err != nil {
+		log.Printf("webhooks: load endpoints for %s failed: %v", event, err)
+		return
+	}
+	for _, ep := range endpoints {
+		if !subscribed(ep.Events, event) {
+			continue
+		}
+		env := Envelope{ID: uuid.New(), Event: event, CreatedAt: time.Now().UTC(), Data: data}
+		body, err := json.Marshal(env)
+		if err != nil {
+			log.Printf("webhooks: encode %s failed: %v", event, err)
+			return
+		}
+		dl := models.WebhookDelivery{
+			ID:         env.ID,
+			EndpointID: ep.ID,
+			Event:      string(event),
+			Payload:    string(body),
+			Status:     models.DeliveryPending,
+		}
+		if err := d.db.Create(&dl).Error; err != nil {
+			log.Printf("webhooks: log delivery %s failed: %v", dl.ID, err)
+			continue
+		}
+		d.wg.Add(1)
+		go func(ep models.WebhookEndpoint, dl models.WebhookDelivery) {
+			defer d.wg.Done()
+			d.deliver(ep, dl, body)
+		}(ep, dl)
+	}
+}
+
+// deliver POSTs body until a 2xx or attempts run out, recording each try.
+func (d *Dispatcher) deliver(ep models.WebhookEndpoint, dl models.WebhookDelivery, body []byte) {
+	sig := Sign(ep.Secret, body)
+	delay := d.backoff
+
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		code, err := d.post(ep.URL, dl, sig, body)
+
+		upd := map[string]any{"attempts": attempt, "response_code": code, "last_error": ""}
+		ok := err == nil && code >= 200 && code < 300
+		switch {
+		case ok:
+			upd["status"] = models.DeliveryDelivered
+			upd["delivered_at"] = time.Now()
+		case err != nil:
+			upd["last_error"] = err.Error()
+		default:
+			upd["last_error"] = fmt.Sprintf("unexpected status %d", code)
+		}
+		if !ok && attempt == d.maxAttempts {
+			upd["status"] = models.DeliveryFailed
+		}
+		if err := d.db.Model(&models.WebhookDelivery{}).Where("id = ?", dl.ID).
+			Updates(upd).Error; err != nil {
+			log.Printf("webhooks: update delivery %s failed: %v", dl.ID, err)
+		}
+		if ok {
+			return
+		}
+		if attempt < d.maxAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	log.Printf("webhooks: delivery %s (%s) to %s failed after %d attempts", dl.ID, dl.Event, ep.URL, d.maxAttempts)
+}
+
+func (d *Dispatcher) post(url string, dl models.WebhookDelivery, sig string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, sig)
+	req.Header.Set(EventHeader, dl.Event)
+	req.Header.Set(DeliveryHeader, dl.ID.String())
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// subscribed reports whether a comma-separated event list contains event.
+func subscribed(list string, event Event) bool {
+	for _, e := range strings.Split(list, ",") {
+		if Event(strings.TrimSpace(e)) == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+	"github.com/aldoetobex/legal-mp-backend/pkg/validation"
+)
+
+/* =============================== DTOs ==================================== */
+
+type CreateEndpointRequest struct {
+	URL    string   `json:"url" validate:"required,url,startswith=https://|startswith=http://,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=case.engaged quote.submitted payment.paid"`
+}
+
+// EndpointItem never includes the secret (it is only returned on create).
+type EndpointItem struct {
+	ID        uuid.UUID `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateEndpointResponse struct {
+	EndpointItem
+	Secret string `json:"secret"` // shown once; used to verify X-Webhook-Signature
+}
+
+/* ============================== Handler ================================== */
+
+type Handler struct{ db *gorm.DB }
+
+func NewHandler(db *gorm.DB) *Handler { return &Handler{db: db} }
+
+func toItem(ep models.WebhookEndpoint) EndpointItem {
+	return EndpointItem{
+		ID:        ep.ID,
+		URL:       ep.URL,
+		Events:    strings.Split(ep.Events, ","),
+		Active:    ep.Active,
+		CreatedAt: ep.CreatedAt,
+	}
+}
+
+/* ================================ Create ================================= */
+
+// @Summary      Register a webhook endpoint
+// @Description  Admin only. Returns the signing secret once.
+// @Tags         admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body  CreateEndpointRequest  true  "endpoint"
+// @Success      201  {object}  CreateEndpointResponse
+// @Failure      400  {object}  models.ValidationErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Router       /admin/webhooks [post]
+func (h *Handler) Create(c *fiber.Ctx) error {
+	var in CreateEndpointRequest
+	if err := c.BodyParser(&in); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid json")
+	}
+	in.URL = strings.TrimSpace(in.URL)
+	if errs, _ := validation.Validate(in); errs != nil {
+		return validation.Respond(c, errs)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return fiber.ErrInternalServerError
+	}
+	ep := models.WebhookEndpoint{
+		URL:    in.URL,
+		Secret: hex.EncodeToString(buf),
+		Events: strings.Join(in.Events, ","),
+		Active: true,
+	}
+	if err := h.db.Create(&ep).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(CreateEndpointResponse{
+		EndpointItem: toItem(ep),
+		Secret:       ep.Secret,
+	})
+}
+
+/* ================================= List ================================== */
+
+// @Summary      List webhook endpoints
+// @Description  Admin only. Secrets are never listed.
+// @Tags         admin
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}  EndpointItem
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Router       /admin/webhooks [get]
+func (h *Handler) List(c *fiber.Ctx) error {
+	var eps []models.WebhookEndpoint
+	if err := h.db.Order("created_at DESC").Find(&eps).Error; err != nil {
+		return fiber.ErrInternalServerError
+	}
+	items := make([]EndpointItem, 0, len(eps))
+	for _, ep := range eps {
+		items = append(items, toItem(ep))
+	}
+	return c.JSON(items)
+}
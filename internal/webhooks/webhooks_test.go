@@ -0,0 +1,216 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/models"
+)
+
+/* ============================================================================
+   Helpers
+   ============================================================================ */
+
+// openTestDB connects to TEST_DATABASE_URL, migrates tables, and truncates them
+// after the test finishes.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	_ = godotenv.Load()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_URL is empty")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&models.WebhookEndpoint{}, &models.WebhookDelivery{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	t.Cleanup(func() {
+		sql := `
+TRUNCATE TABLE
+	webhook_deliveries,
+	webhook_endpoints
+RESTART IDENTITY CASCADE`
+		if err := db.Exec(sql).Error; err != nil {
+			t.Logf("truncate failed (ignored): %v", err)
+		}
+	})
+
+	return db
+}
+
+// received is one request captured by the fake integrator.
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// fakeReceiver records requests and answers with the given statuses in order
+// (the last one repeats).
+func fakeReceiver(t *testing.T, statuses ...int) (*httptest.Server, func() []received) {
+	t.Helper()
+	var (
+		mu  sync.Mutex
+		got []received
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		got = append(got, received{header: r.Header.Clone(), body: body})
+		code := statuses[min(len(got), len(statuses))-1]
+		mu.Unlock()
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []received {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]received(nil), got...)
+	}
+}
+
+func addEndpoint(t *testing.T, db *gorm.DB, url, secret, events string) models.WebhookEndpoint {
+	t.Helper()
+	ep := models.WebhookEndpoint{URL: url, Secret: secret, Events: events, Active: true}
+	if err := db.Create(&ep).Error; err != nil {
+		t.Fatal(err)
+	}
+	return ep
+}
+
+func testDispatcher(db *gorm.DB) *Dispatcher {
+	return &Dispatcher{db: db, client: &http.Client{Timeout: 2 * time.Second}, maxAttempts: 3, backoff: 10 * time.Millisecond}
+}
+
+/* ============================================================================
+   Tests — signing
+   ============================================================================ */
+
+// Sign is a plain hex HMAC-SHA256 of the raw body, prefixed with the scheme.
+func TestSign_HMACSHA256(t *testing.T) {
+	body := []byte(`{"event":"payment.paid"}`)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("s3cret", body); got != want {
+		t.Fatalf("want %s, got %s", want, got)
+	}
+	if Sign("other", body) == want {
+		t.Fatalf("different secrets must give different signatures")
+	}
+}
+
+/* ============================================================================
+   Tests — delivery
+   ============================================================================ */
+
+// payment.paid reaches a subscribed endpoint signed with its secret; an endpoint
+// subscribed to other events gets nothing. The delivery is logged.
+func Test_Dispatch_PaymentPaid_SignedPayload(t *testing.T) {
+	db := openTestDB(t)
+
+	srv, got := fakeReceiver(t, http.StatusOK)
+	ep := addEndpoint(t, db, srv.URL, "partner-secret", "case.engaged,payment.paid")
+	other, otherGot := fakeReceiver(t, http.StatusOK)
+	_ = addEndpoint(t, db, other.URL, "x", "quote.submitted")
+
+	data := PaymentPaid{
+		PaymentID:   uuid.New(),
+		CaseID:      uuid.New(),
+		QuoteID:     uuid.New(),
+		AmountCents: 150000,
+		Currency:    "SGD",
+		PaidAt:      time.Now().UTC().Truncate(time.Second),
+	}
+	d := testDispatcher(db)
+	d.Dispatch(EventPaymentPaid, data)
+	d.Wait()
+
+	reqs := got()
+	if len(reqs) != 1 {
+		t.Fatalf("want 1 request, got %d", len(reqs))
+	}
+	if n := len(otherGot()); n != 0 {
+		t.Fatalf("unsubscribed endpoint got %d requests", n)
+	}
+
+	r := reqs[0]
+	if sig := r.header.Get(SignatureHeader); sig != Sign("partner-secret", r.body) {
+		t.Fatalf("bad signature %q", sig)
+	}
+	if ev := r.header.Get(EventHeader); ev != string(EventPaymentPaid) {
+		t.Fatalf("event header: want payment.paid, got %q", ev)
+	}
+
+	var env struct {
+		ID    uuid.UUID   `json:"id"`
+		Event Event       `json:"event"`
+		Data  PaymentPaid `json:"data"`
+	}
+	if err := json.Unmarshal(r.body, &env); err != nil {
+		t.Fatal(err)
+	}
+	if env.Event != EventPaymentPaid || env.Data.PaymentID != data.PaymentID ||
+		env.Data.AmountCents != 150000 || env.Data.Currency != "SGD" || !env.Data.PaidAt.Equal(data.PaidAt) {
+		t.Fatalf("unexpected payload: %+v", env)
+	}
+	if r.header.Get(DeliveryHeader) != env.ID.String() {
+		t.Fatalf("delivery header should match envelope id")
+	}
+
+	var dl models.WebhookDelivery
+	if err := db.First(&dl, "id = ?", env.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if dl.EndpointID != ep.ID || dl.Status != models.DeliveryDelivered || dl.Attempts != 1 ||
+		dl.ResponseCode != 200 || dl.Payload != string(r.body) || dl.DeliveredAt == nil {
+		t.Fatalf("unexpected delivery log: %+v", dl)
+	}
+}
+
+// A failing endpoint is retried with the same delivery id until it succeeds.
+func Test_Dispatch_RetriesUntilSuccess(t *testing.T) {
+	db := openTestDB(t)
+
+	srv, got := fakeReceiver(t, http.StatusInternalServerError, http.StatusOK)
+	_ = addEndpoint(t, db, srv.URL, "k", "quote.submitted")
+
+	d := testDispatcher(db)
+	d.Dispatch(EventQuoteSubmitted, QuoteSubmitted{QuoteID: uuid.New(), CaseID: uuid.New()})
+	d.Wait()
+
+	reqs := got()
+	if len(reqs) != 2 {
+		t.Fatalf("want 2 attempts, got %d", len(reqs))
+	}
+	if reqs[0].header.Get(DeliveryHeader) != reqs[1].header.Get(DeliveryHeader) {
+		t.Fatalf("retries must reuse the delivery id")
+	}
+
+	var dl models.WebhookDelivery
+	if err := db.First(&dl).Error; err != nil {
+		t.Fatal(err)
+	}
+	if dl.Status != models.DeliveryDelivered || dl.Attempts != 2 {
+		t.Fatalf("want delivered after 2 attempts, got %s/%d", dl.Status, dl.Attempts)
+	}
+}
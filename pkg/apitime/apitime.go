@@ -0,0 +1,28 @@
+// Package apitime fixes how API responses carry timestamps: RFC3339 in UTC
+// with whole seconds, e.g. "2025-01-02T03:04:05Z".
+//
+// DTOs that keep a time.Time pass it through Normalize: once normalized,
+// encoding/json writes exactly the string Format returns, so string and
+// time.Time fields look the same on the wire.
+package apitime
+
+import "time"
+
+// Normalize converts t to UTC and drops sub-second precision.
+func Normalize(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
+
+// NormalizePtr is Normalize for optional times; nil stays nil.
+func NormalizePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	n := Normalize(*t)
+	return &n
+}
+
+// Format renders t as the API's RFC3339 UTC string.
+func Format(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,23 @@
+package apitime
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// A normalized time.Time marshals to exactly the Format string, whatever
+// zone and precision it started with.
+func TestNormalize_MarshalsLikeFormat(t *testing.T) {
+	in := time.Date(2025, 3, 4, 13, 14, 15, 987654321, time.FixedZone("UTC+8", 8*3600))
+	b, err := json.Marshal(Normalize(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"2025-03-04T05:14:15Z"`; string(b) != want || `"`+Format(in)+`"` != want {
+		t.Fatalf("want %s, got json=%s format=%s", want, b, Format(in))
+	}
+	if NormalizePtr(nil) != nil {
+		t.Fatal("nil should stay nil")
+	}
+}
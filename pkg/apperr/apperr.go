@@ -0,0 +1,90 @@
+package apperr
+
+import "github.com/gofiber/fiber/v2"
+
+// Stable machine-readable codes for domain failures. They go into
+// ErrorResponse.code so the frontend can tell two 409s apart; never rename one.
+const (
+	// Cases
+	CaseNotOpen         = "CASE_NOT_OPEN"
+	DuplicateCase       = "DUPLICATE_CASE"
+	CaseNotCancellable  = "CASE_NOT_CANCELLABLE"
+	CaseNotDeletable    = "CASE_NOT_DELETABLE"
+	CaseNotReopenable   = "CASE_NOT_REOPENABLE"
+	ReopenWindowExpired = "REOPEN_WINDOW_EXPIRED"
+	CaseNotEngaged      = "CASE_NOT_ENGAGED"
+	CaseNotPausable     = "CASE_NOT_PAUSABLE"
+	CaseNotPaused       = "CASE_NOT_PAUSED"
+	FilesLocked         = "FILES_LOCKED"
+	// A checkout on one of the case's quotes is in flight
+	CaseInCheckout = "CASE_IN_CHECKOUT"
+	// The case ran out its time on the marketplace (not the same as closed)
+	CaseExpired = "CASE_EXPIRED"
+
+	// Uploads
+	MultipartRequired = "MULTIPART_REQUIRED" // body isn't multipart/form-data
+	MultipartInvalid  = "MULTIPART_INVALID"  // multipart body couldn't be parsed
+	NoFilesProvided   = "NO_FILES_PROVIDED"  // form has no files under an accepted key
+	NoFilesStored     = "NO_FILES_STORED"    // every file in the batch was rejected
+
+	// Quotes
+	QuoteImmutable   = "QUOTE_IMMUTABLE"
+	QuoteNotProposed = "QUOTE_NOT_PROPOSED"
+	QuoteExpired     = "QUOTE_EXPIRED"
+	// DB safety net: another quote on the case is already accepted
+	QuoteAlreadyAccepted = "QUOTE_ALREADY_ACCEPTED"
+	// Quote can't change once the client has started checkout
+	QuoteInCheckout = "QUOTE_IN_CHECKOUT"
+
+	// Payments
+	QuoteAlreadyPaid     = "QUOTE_ALREADY_PAID"
+	AmountMismatch       = "AMOUNT_MISMATCH"
+	IdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	// Amount isn't a whole number of a zero-decimal currency (legacy quotes)
+	AmountNotChargeable = "AMOUNT_NOT_CHARGEABLE"
+	// Opt-in LAWYER_MAX_ENGAGED_CASES reached for the quote's lawyer
+	LawyerAtCapacity = "LAWYER_AT_CAPACITY"
+	// Checkout return_origin isn't in the FRONTEND_ORIGIN allowlist
+	ReturnOriginNotAllowed = "RETURN_ORIGIN_NOT_ALLOWED"
+
+	// Accounts
+	AccountLocked   = "ACCOUNT_LOCKED"
+	LawyerOnlyField = "LAWYER_ONLY_FIELD"
+	// Unverified email while REQUIRE_LAWYER_EMAIL_VERIFICATION is on
+	EmailNotVerified         = "EMAIL_NOT_VERIFIED"
+	EmailAlreadyVerified     = "EMAIL_ALREADY_VERIFIED"
+	InvalidVerificationToken = "INVALID_VERIFICATION_TOKEN"
+	// DELETE /me while engagements would lose their counterpart
+	AccountHasEngagements = "ACCOUNT_HAS_ENGAGEMENTS"
+)
+
+// Error is a domain error: an HTTP status and message (the wrapped
+// *fiber.Error, so Fiber and errors.As keep treating it as one) plus a
+// specific Code that auth.ErrorHandler puts in the response.
+type Error struct {
+	Code string
+	http *fiber.Error
+}
+
+func (e *Error) Error() string { return e.http.Message }
+func (e *Error) Unwrap() error { return e.http }
+
+// Status returns the HTTP status the error maps to.
+func (e *Error) Status() int { return e.http.Code }
+
+// New builds a domain error with an explicit status.
+func New(status int, code, message string) *Error {
+	return &Error{Code: code, http: fiber.NewError(status, message)}
+}
+
+// BadRequest is a 400 for a request the client has to fix.
+func BadRequest(code, message string) *Error { return New(fiber.StatusBadRequest, code, message) }
+
+// Conflict is a 409 for a state that doesn't allow the action.
+func Conflict(code, message string) *Error { return New(fiber.StatusConflict, code, message) }
+
+// Forbidden is a 403 for an action the caller may never take in this state.
+func Forbidden(code, message string) *Error { return New(fiber.StatusForbidden, code, message) }
+
+// Locked is a 423 for a temporarily locked resource.
+func Locked(code, message string) *Error { return New(fiber.StatusLocked, code, message) }
@@ -0,0 +1,63 @@
+// Package apptz is the app timezone (APP_TZ): the zone that local dates in
+// the API (YYYY-MM-DD filters, deadlines, windows like "last 7 days") and
+// human-facing dates are read in. API timestamps themselves stay UTC (see
+// apitime).
+package apptz
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Default is used when APP_TZ is unset.
+const Default = "Asia/Singapore"
+
+// current caches the resolved location; nil until first use.
+var current atomic.Pointer[time.Location]
+
+// Load resolves APP_TZ (or Default) from the tz database. An APP_TZ the
+// database doesn't know is an error; checkEnv uses this to fail at startup.
+func Load() (*time.Location, error) {
+	name := strings.TrimSpace(os.Getenv("APP_TZ"))
+	if name == "" {
+		return defaultLocation(), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("APP_TZ=%q is not a known timezone (want an IANA name like %s)", name, Default)
+	}
+	return loc, nil
+}
+
+// defaultLocation is Default, or a fixed UTC+8 when tzdata is not available.
+func defaultLocation() *time.Location {
+	if loc, err := time.LoadLocation(Default); err == nil {
+		return loc
+	}
+	return time.FixedZone("SGT", 8*60*60)
+}
+
+// Location returns the app timezone, resolved once and cached. The server
+// rejects a bad APP_TZ at startup, so falling back to Default here only
+// happens to code running without that check (and is logged).
+func Location() *time.Location {
+	if loc := current.Load(); loc != nil {
+		return loc
+	}
+	loc, err := Load()
+	if err != nil {
+		log.Printf("apptz: %v; using %s", err, Default)
+		loc = defaultLocation()
+	}
+	current.CompareAndSwap(nil, loc)
+	return current.Load()
+}
+
+// Set replaces the cached location (tests, or after re-reading APP_TZ).
+func Set(loc *time.Location) {
+	current.Store(loc)
+}
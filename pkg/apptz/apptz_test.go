@@ -0,0 +1,46 @@
+package apptz
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Unset means Default; a known zone loads; a typo is an error naming APP_TZ.
+func TestLoad(t *testing.T) {
+	t.Setenv("APP_TZ", "")
+	if loc, err := Load(); err != nil || (loc.String() != Default && loc.String() != "SGT") {
+		t.Fatalf("unset: want %s, got %v %v", Default, loc, err)
+	}
+
+	t.Setenv("APP_TZ", "Europe/Berlin")
+	if loc, err := Load(); err != nil || loc.String() != "Europe/Berlin" {
+		t.Fatalf("valid: got %v %v", loc, err)
+	}
+
+	t.Setenv("APP_TZ", "Asia/Singapur")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "APP_TZ") {
+		t.Fatalf("typo should be an error naming APP_TZ, got %v", err)
+	}
+}
+
+// Location resolves once and keeps the cached zone until Set replaces it.
+func TestLocation_Cached(t *testing.T) {
+	prev := current.Load()
+	t.Cleanup(func() { current.Store(prev) })
+	current.Store(nil)
+
+	t.Setenv("APP_TZ", "Europe/Berlin")
+	if got := Location().String(); got != "Europe/Berlin" {
+		t.Fatalf("want Europe/Berlin, got %s", got)
+	}
+	t.Setenv("APP_TZ", "America/New_York")
+	if got := Location().String(); got != "Europe/Berlin" {
+		t.Fatalf("location should be cached, got %s", got)
+	}
+
+	Set(time.UTC)
+	if Location() != time.UTC {
+		t.Fatalf("Set should replace the cached location")
+	}
+}
@@ -0,0 +1,130 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* ================================ Types ================================= */
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends a single email. Implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+/* ============================== Factories =============================== */
+
+// NewFromEnv returns an SMTP mailer when SMTP_HOST is set, otherwise a no-op.
+// Env: SMTP_HOST, SMTP_PORT (default 587), SMTP_USER, SMTP_PASS, SMTP_FROM.
+func NewFromEnv() Mailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return Noop{}
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = os.Getenv("SMTP_USER")
+	}
+	return &SMTP{
+		host: host,
+		port: port,
+		user: os.Getenv("SMTP_USER"),
+		pass: os.Getenv("SMTP_PASS"),
+		from: from,
+	}
+}
+
+// SendAsync sends in the background and only logs failures.
+// Email must never break (or slow down) the request that triggered it.
+func SendAsync(m Mailer, msg Message) {
+	if m == nil || strings.TrimSpace(msg.To) == "" {
+		return
+	}
+	go func() {
+		if err := m.Send(msg); err != nil {
+			log.Printf("mailer: send %q to %s failed: %v", msg.Subject, msg.To, err)
+		}
+	}()
+}
+
+/* ================================ SMTP ================================== */
+
+// SMTP sends mail through a standard SMTP relay (STARTTLS negotiated by net/smtp).
+type SMTP struct {
+	host string
+	port string
+	user string
+	pass string
+	from string
+}
+
+func (s *SMTP) Send(msg Message) error {
+	var a smtp.Auth
+	if s.user != "" {
+		a = smtp.PlainAuth("", s.user, s.pass, s.host)
+	}
+
+	raw := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.from, msg.To, msg.Subject, msg.Body,
+	)
+	return smtp.SendMail(s.host+":"+s.port, a, s.from, []string{msg.To}, []byte(raw))
+}
+
+/* ================================ No-op ================================= */
+
+// Noop discards every message (dev/tests without SMTP).
+type Noop struct{}
+
+func (Noop) Send(Message) error { return nil }
+
+/* =============================== Recorder =============================== */
+
+// Recorder keeps sent messages in memory so tests can assert on them.
+type Recorder struct {
+	mu   sync.Mutex
+	msgs []Message
+}
+
+func (r *Recorder) Send(msg Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.msgs = append(r.msgs, msg)
+	return nil
+}
+
+// Messages returns a copy of everything sent so far.
+func (r *Recorder) Messages() []Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Message(nil), r.msgs...)
+}
+
+// WaitFor polls until at least n messages were sent or the timeout passes
+// (SendAsync delivers from a goroutine).
+func (r *Recorder) WaitFor(n int, timeout time.Duration) []Message {
+	deadline := time.Now().Add(timeout)
+	for {
+		msgs := r.Messages()
+		if len(msgs) >= n || time.Now().After(deadline) {
+			return msgs
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"testing"
+	"time"
+)
+
+// Without SMTP_HOST the factory falls back to the no-op mailer.
+func TestNewFromEnv_NoopWithoutHost(t *testing.T) {
+	t.Setenv("SMTP_HOST", "")
+	if _, ok := NewFromEnv().(Noop); !ok {
+		t.Fatalf("want Noop when SMTP_HOST is empty")
+	}
+
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_USER", "bot@example.com")
+	m, ok := NewFromEnv().(*SMTP)
+	if !ok {
+		t.Fatalf("want *SMTP when SMTP_HOST is set")
+	}
+	if m.port != "587" || m.from != "bot@example.com" {
+		t.Fatalf("defaults not applied: port=%q from=%q", m.port, m.from)
+	}
+}
+
+// SendAsync delivers in the background and skips empty recipients / nil mailers.
+func TestSendAsync_DeliversAndSkipsEmpty(t *testing.T) {
+	rec := &Recorder{}
+	SendAsync(rec, Welcome("a@example.com", "Ann"))
+	SendAsync(rec, Welcome("", "Nobody"))
+	SendAsync(nil, Welcome("b@example.com", "Bob"))
+
+	rec.WaitFor(1, time.Second)
+	time.Sleep(20 * time.Millisecond) // give any stray sends a chance to land
+	msgs := rec.Messages()
+	if len(msgs) != 1 || msgs[0].To != "a@example.com" || msgs[0].Subject != Welcome("", "").Subject {
+		t.Fatalf("unexpected messages: %+v", msgs)
+	}
+}
@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/apptz"
+)
+
+// Welcome is sent right after signup.
+func Welcome(to, name string) Message {
+	return Message{
+		To:      to,
+		Subject: "Welcome to Legal Marketplace",
+		Body:    fmt.Sprintf("Hi %s,\n\nYour account is ready. You can sign in any time.\n", name),
+	}
+}
+
+// VerifyEmail carries the link that confirms the address.
+func VerifyEmail(to, name, link string, expires time.Time) Message {
+	return Message{
+		To:      to,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening this link (valid until %s):\n\n%s\n\nIf you didn't sign up, you can ignore this email.\n",
+			name, expires.In(apptz.Location()).Format("2006-01-02 15:04 MST"), link),
+	}
+}
+
+// NewQuote tells the case owner a lawyer submitted a quote.
+func NewQuote(to, caseTitle string) Message {
+	return Message{
+		To:      to,
+		Subject: "New quote on your case",
+		Body:    fmt.Sprintf("You received a new quote on \"%s\". Sign in to review it.\n", caseTitle),
+	}
+}
+
+// EngagedClient confirms payment and engagement to the client.
+func EngagedClient(to, caseTitle string) Message {
+	return Message{
+		To:      to,
+		Subject: "Payment received — your lawyer is engaged",
+		Body:    fmt.Sprintf("Your payment for \"%s\" was received and your lawyer has been engaged.\n", caseTitle),
+	}
+}
+
+// EngagedLawyer tells the winning lawyer their quote was accepted and paid.
+func EngagedLawyer(to, caseTitle string) Message {
+	return Message{
+		To:      to,
+		Subject: "Your quote was accepted",
+		Body:    fmt.Sprintf("Your quote for \"%s\" was accepted and paid. You now have access to the case files.\n", caseTitle),
+	}
+}
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+/*
+Metrics owns every Prometheus collector the service exposes, registered on a
+private registry (no global state, so tests can build their own).
+
+Handlers receive a *Metrics and call the business helpers below; a nil
+*Metrics is valid and records nothing, like a nil mailer sends nothing.
+*/
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	requests *prometheus.CounterVec   // http_requests_total{method,route,status}
+	latency  *prometheus.HistogramVec // http_request_duration_seconds{method,route}
+
+	quotesSubmitted  prometheus.Counter
+	checkoutsCreated *prometheus.CounterVec // {provider}
+	payments         *prometheus.CounterVec // {outcome="paid|failed"}
+}
+
+// New builds and registers all collectors.
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route pattern and status.",
+		}, []string{"method", "route", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method and route pattern.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		quotesSubmitted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "quotes_submitted_total",
+			Help: "Quotes created by lawyers (updates not counted).",
+		}),
+		checkoutsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "checkouts_created_total",
+			Help: "Checkout sessions handed to clients, by provider.",
+		}, []string{"provider"}),
+		payments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "payments_total",
+			Help: "Payment completions by outcome (failed = refused, e.g. amount mismatch).",
+		}, []string{"outcome"}),
+	}
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.latency, m.quotesSubmitted, m.checkoutsCreated, m.payments,
+	)
+	return m
+}
+
+/* ============================== HTTP ================================== */
+
+// Middleware records count and latency per matched route pattern (not the raw
+// path, to keep label cardinality bounded). Register it before AccessLog so
+// errors have already been rendered into the response status.
+func (m *Metrics) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				status = fe.Code
+			}
+		}
+		route := c.Route().Path
+		m.requests.WithLabelValues(c.Method(), route, strconv.Itoa(status)).Inc()
+		m.latency.WithLabelValues(c.Method(), route).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// Handler serves the Prometheus text exposition for this registry.
+func (m *Metrics) Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{}))
+}
+
+/* ============================ Business ================================ */
+
+// QuoteSubmitted counts a newly created quote.
+func (m *Metrics) QuoteSubmitted() {
+	if m != nil {
+		m.quotesSubmitted.Inc()
+	}
+}
+
+// CheckoutCreated counts a checkout handed to the client ("mock" or "stripe").
+func (m *Metrics) CheckoutCreated(provider string) {
+	if m != nil {
+		m.checkoutsCreated.WithLabelValues(provider).Inc()
+	}
+}
+
+// PaymentPaid counts a payment finalized as paid.
+func (m *Metrics) PaymentPaid() {
+	if m != nil {
+		m.payments.WithLabelValues("paid").Inc()
+	}
+}
+
+// PaymentFailed counts a completion we refused (e.g. amount mismatch).
+func (m *Metrics) PaymentFailed() {
+	if m != nil {
+		m.payments.WithLabelValues("failed").Inc()
+	}
+}
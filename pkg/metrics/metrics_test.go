@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// Requests are counted per route pattern and status, and show up on /metrics.
+func TestMiddleware_CountsByRouteAndStatus(t *testing.T) {
+	m := New()
+	app := fiber.New()
+	app.Use(m.Middleware())
+	app.Get("/metrics", m.Handler())
+	app.Get("/cases/:id", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Get("/boom", func(c *fiber.Ctx) error { return fiber.ErrConflict })
+
+	for _, path := range []string{"/cases/a", "/cases/b", "/boom"} {
+		if _, err := app.Test(httptest.NewRequest("GET", path, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("GET", "/cases/:id", "200")); got != 2 {
+		t.Fatalf("want 2 requests on /cases/:id, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("GET", "/boom", "409")); got != 1 {
+		t.Fatalf("want 1 conflict on /boom, got %v", got)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/metrics", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `http_requests_total{method="GET",route="/cases/:id",status="200"} 2`) {
+		t.Fatalf("exposition missing request counter:\n%s", body)
+	}
+}
+
+// Business helpers increment their counters and are no-ops on a nil *Metrics.
+func TestBusinessCounters(t *testing.T) {
+	var none *Metrics
+	none.QuoteSubmitted()
+	none.CheckoutCreated("mock")
+	none.PaymentPaid()
+	none.PaymentFailed()
+
+	m := New()
+	m.QuoteSubmitted()
+	m.CheckoutCreated("mock")
+	m.PaymentPaid()
+	m.PaymentPaid()
+	m.PaymentFailed()
+
+	if got := testutil.ToFloat64(m.quotesSubmitted); got != 1 {
+		t.Fatalf("quotes: want 1, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.checkoutsCreated.WithLabelValues("mock")); got != 1 {
+		t.Fatalf("checkouts: want 1, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.payments.WithLabelValues("paid")); got != 2 {
+		t.Fatalf("paid: want 2, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.payments.WithLabelValues("failed")); got != 1 {
+		t.Fatalf("failed: want 1, got %v", got)
+	}
+}
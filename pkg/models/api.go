@@ -13,4 +13,6 @@ type ErrorResponse struct {
 	Error   bool   `json:"error" example:"true"`               // always true for error cases
 	Message string `json:"message" example:"Forbidden"`        // human-readable error message
 	Code    string `json:"code,omitempty" example:"FORBIDDEN"` // machine-friendly error code
+	// Correlation id (also in the X-Request-ID header); quote it when reporting issues
+	RequestID string `json:"request_id,omitempty" example:"3f2b8c1e-7d4a-4e0b-9a51-2c6d8e9f0a1b"`
 }
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 /* =============================== Enums ================================== */
@@ -14,6 +15,7 @@ type Role string
 const (
 	RoleClient Role = "client"
 	RoleLawyer Role = "lawyer"
+	RoleAdmin  Role = "admin" // support staff; read access across cases
 )
 
 // CaseStatus defines lifecycle states for a case.
@@ -24,6 +26,17 @@ const (
 	CaseEngaged   CaseStatus = "engaged"
 	CaseClosed    CaseStatus = "closed"
 	CaseCancelled CaseStatus = "cancelled"
+	CasePaused    CaseStatus = "paused"  // owner hid it from the marketplace; quotes are kept
+	CaseExpired   CaseStatus = "expired" // reserved: no flow sets it yet, but quoting already refuses it
+)
+
+// Urgency is how pressing the client says their case is.
+type Urgency string
+
+const (
+	UrgencyLow    Urgency = "low"
+	UrgencyNormal Urgency = "normal"
+	UrgencyHigh   Urgency = "high"
 )
 
 // QuoteStatus defines lifecycle states for a quote.
@@ -44,9 +57,25 @@ const (
 	PayFailed    PayStatus = "failed"
 )
 
+// NotificationType identifies what an in-app notification is about.
+type NotificationType string
+
+const (
+	NotifyQuoteReceived NotificationType = "quote_received"
+)
+
+// DeliveryStatus defines lifecycle states for an outbound webhook delivery.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed" // retries exhausted
+)
+
 /* =============================== Entities =============================== */
 
-// User represents a client or lawyer.
+// User represents a client, lawyer, or admin.
 type User struct {
 	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	Email        string    `gorm:"uniqueIndex;not null"`
@@ -56,6 +85,25 @@ type User struct {
 	Jurisdiction string
 	BarNumber    string
 	CreatedAt    time.Time
+
+	// Optional contact details, shown only to the counterpart of an
+	// engaged/closed case
+	ContactPhone     string `gorm:"type:varchar(20)"` // E.164, e.g. +6591234567
+	PreferredContact string `gorm:"type:varchar(10)"` // email|phone; empty = no preference
+
+	// Login lockout state (see auth.Login)
+	FailedAttempts int `gorm:"not null;default:0"`
+	LockedUntil    *time.Time
+
+	// Email verification (see auth.VerifyEmail); nil = not verified yet.
+	// Only the SHA-256 of the emailed token is stored.
+	EmailVerifiedAt      *time.Time
+	EmailVerifyTokenHash string `gorm:"type:varchar(64);index"`
+	EmailVerifyExpiresAt *time.Time
+
+	// Set when the account is deleted (see account.DeleteMe); the row stays,
+	// anonymized, and its tokens stop working. Not a GORM soft delete.
+	DeletedAt *time.Time
 }
 
 // Case represents a legal case created by a client.
@@ -67,6 +115,11 @@ type Case struct {
 	Description string
 	Status      CaseStatus `gorm:"type:varchar(20);default:'open'"`
 	CreatedAt   time.Time
+	UpdatedAt   time.Time `gorm:"autoUpdateTime;index"` // bumped by every Updates/Update call
+
+	// Human-friendly reference for support and emails (e.g. LMP-2024-000123);
+	// nil on legacy rows. Routes keep using the UUID.
+	Reference *string `gorm:"type:varchar(32);uniqueIndex:ux_cases_reference"`
 
 	// Relations
 	Files  []CaseFile
@@ -76,6 +129,18 @@ type Case struct {
 	EngagedAt        *time.Time
 	AcceptedQuoteID  uuid.UUID
 	AcceptedLawyerID uuid.UUID
+
+	// Set when the client cancels; used for the reopen grace window
+	CancelledAt *time.Time
+
+	// Optional, set at creation so lawyers can prioritize. Deadline is a
+	// calendar date (stored at UTC midnight).
+	Deadline *time.Time `gorm:"type:date;index"`
+	Urgency  *Urgency   `gorm:"type:varchar(10)"`
+
+	// Soft delete: hidden from every default query; admins read it via Unscoped.
+	// Files, quotes and history rows are kept.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
 // CaseFile represents a file uploaded to a case.
@@ -90,6 +155,9 @@ type CaseFile struct {
 
 	// Relation back to case
 	Case Case `gorm:"foreignKey:CaseID;references:ID"`
+
+	// Optional client label, e.g. "signed contract"; empty when not provided
+	Description string `gorm:"type:varchar(200)"`
 }
 
 // Quote represents a lawyer’s proposal for a case.
@@ -98,35 +166,138 @@ type Quote struct {
 	CaseID      uuid.UUID `gorm:"type:uuid;not null;index:idx_case_lawyer,unique"`
 	LawyerID    uuid.UUID `gorm:"type:uuid;not null;index:idx_case_lawyer,unique"`
 	AmountCents int       `gorm:"not null"`
+	Currency    string    `gorm:"type:varchar(3)"` // ISO-4217, upper case; empty on legacy rows
 	Days        int       `gorm:"not null"`
 	Note        string
+	Pitch       string      // cover letter for the client; redacted like Note until accepted
 	Status      QuoteStatus `gorm:"type:varchar(20);default:'proposed'"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+
+	// Proposals lapse after this; nil for legacy quotes (never expire)
+	ExpiresAt *time.Time `gorm:"index"`
+}
+
+// QuoteRevision is one version of a quote as written by quotes.Upsert (the
+// first row is the original submission), so price changes leave a trace.
+type QuoteRevision struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	QuoteID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	AmountCents int       `gorm:"not null"`
+	Currency    string    `gorm:"type:varchar(3)"`
+	Days        int       `gorm:"not null"`
+	Note        string
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+// Expired reports whether the quote's validity window has passed.
+func (q *Quote) Expired(now time.Time) bool {
+	return q.ExpiresAt != nil && now.After(*q.ExpiresAt)
 }
 
 // Payment represents a payment attempt for a case’s accepted quote.
 type Payment struct {
 	ID                  uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	CaseID              uuid.UUID `gorm:"type:uuid;not null"`
-	QuoteID             uuid.UUID `gorm:"type:uuid;not null"`
-	ClientID            uuid.UUID `gorm:"type:uuid;not null"`
-	StripeSessionID     *string   `gorm:"uniqueIndex:ux_pay_session_filled"` // Stripe Checkout session (optional)
-	StripePaymentIntent *string   `gorm:"uniqueIndex:ux_pay_intent_filled"`  // Stripe PaymentIntent (optional)
-	AmountCents         int       `gorm:"not null"`                          // stored in cents to avoid float issues
+	QuoteID             uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:ux_pay_quote"` // one payment per quote
+	ClientID            uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:ux_pay_client_idem,priority:1"`
+	IdempotencyKey      *string   `gorm:"type:varchar(255);uniqueIndex:ux_pay_client_idem,priority:2"` // client's Idempotency-Key (optional)
+	StripeSessionID     *string   `gorm:"uniqueIndex:ux_pay_session_filled"`                           // Stripe Checkout session (optional)
+	StripePaymentIntent *string   `gorm:"uniqueIndex:ux_pay_intent_filled"`                            // Stripe PaymentIntent (optional)
+	AmountCents         int       `gorm:"not null"`                                                    // stored in cents to avoid float issues
+	Currency            string    `gorm:"type:varchar(3)"`                                             // copied from the quote (ISO-4217)
 	Status              PayStatus `gorm:"type:varchar(20);default:'initiated'"`
 	CreatedAt           time.Time `gorm:"not null;default:now()"`
 	UpdatedAt           time.Time `gorm:"not null;default:now()"`
+
+	// Set when the payment is finalized; nil on legacy rows
+	PaidAt *time.Time
+
+	// Stripe-hosted receipt page; nil for mock payments and legacy rows
+	ReceiptURL *string `gorm:"type:text"`
 }
 
+// ActorType says what kind of actor wrote a CaseHistory entry.
+type ActorType string
+
+const (
+	ActorUser   ActorType = "user"   // a client or admin acting through the API
+	ActorLawyer ActorType = "lawyer" // a lawyer acting on the case
+	ActorSystem ActorType = "system" // webhooks and background sweeps (ActorID is uuid.Nil)
+)
+
 // CaseHistory is an audit log entry for important case changes.
 type CaseHistory struct {
 	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	CaseID    uuid.UUID  `gorm:"type:uuid;not null;index"`
-	ActorID   uuid.UUID  `gorm:"type:uuid;not null;index"`  // who performed the action (client/lawyer/system)
+	ActorID   uuid.UUID  `gorm:"type:uuid;not null;index"` // who performed the action; uuid.Nil for the system
+	ActorType ActorType  `gorm:"type:varchar(10);not null;default:'user'"`
 	Action    string     `gorm:"type:varchar(50);not null"` // e.g. created, quote_submitted, accepted_quote, paid, cancelled, closed
 	OldStatus CaseStatus `gorm:"type:varchar(20)"`
 	NewStatus CaseStatus `gorm:"type:varchar(20)"`
 	Reason    string     `gorm:"type:text"` // optional explanation/comment
 	CreatedAt time.Time  `gorm:"autoCreateTime"`
 }
+
+// Message is a chat message between the client and the accepted lawyer of an
+// engaged (or closed) case. Bodies are stored as sent: no PII redaction.
+type Message struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CaseID    uuid.UUID `gorm:"type:uuid;not null;index:idx_msg_case_created,priority:1"`
+	SenderID  uuid.UUID `gorm:"type:uuid;not null"`
+	Body      string    `gorm:"type:text;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index:idx_msg_case_created,priority:2"`
+}
+
+// Notification is an in-app message for a single user (e.g. "new quote on your case").
+type Notification struct {
+	ID        uuid.UUID        `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID    uuid.UUID        `gorm:"type:uuid;not null;index"`
+	Type      NotificationType `gorm:"type:varchar(40);not null"`
+	CaseID    uuid.UUID        `gorm:"type:uuid;index"`
+	ReadAt    *time.Time       // nil while unread
+	CreatedAt time.Time        `gorm:"autoCreateTime"`
+}
+
+// SavedCase is a lawyer's bookmark on a marketplace case. Rows are kept when
+// the case leaves OPEN; reads only return cases that are still open.
+type SavedCase struct {
+	LawyerID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CaseID    uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// CaseCollaborator is read access to one engaged case granted by its
+// accepted lawyer to another lawyer account (e.g. a paralegal). Revoking
+// deletes the row.
+type CaseCollaborator struct {
+	CaseID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	GrantedBy uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// WebhookEndpoint is an integrator URL that receives signed case events.
+type WebhookEndpoint struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	URL       string    `gorm:"type:text;not null"`
+	Secret    string    `gorm:"type:text;not null"` // HMAC key for the signature header
+	Events    string    `gorm:"type:text;not null"` // comma-separated event types, e.g. "case.engaged,payment.paid"
+	Active    bool      `gorm:"not null;default:true"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// WebhookDelivery logs one event sent to one endpoint, across all retries.
+type WebhookDelivery struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primaryKey"` // also sent as the delivery id header
+	EndpointID   uuid.UUID      `gorm:"type:uuid;not null;index"`
+	Event        string         `gorm:"type:varchar(40);not null"`
+	Payload      string         `gorm:"type:text;not null"` // exact signed body
+	Status       DeliveryStatus `gorm:"type:varchar(20);default:'pending'"`
+	Attempts     int            `gorm:"not null;default:0"`
+	ResponseCode int            // last HTTP status; 0 when the request itself failed
+	LastError    string         `gorm:"type:text"`
+	DeliveredAt  *time.Time
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,50 @@
+package money
+
+import (
+	"os"
+	"strings"
+)
+
+// defaultAllowed are two-decimal currencies. Others (e.g. JPY) can be added
+// via CURRENCIES; see units.go for how their amounts are checked and charged.
+const defaultAllowed = "SGD,USD,EUR,GBP,AUD,MYR,HKD"
+
+// DefaultCurrency is the platform currency (STRIPE_CURRENCY, default USD),
+// used when a quote doesn't specify one and for legacy rows without a value.
+func DefaultCurrency() string {
+	if c := strings.ToUpper(strings.TrimSpace(os.Getenv("STRIPE_CURRENCY"))); c != "" {
+		return c
+	}
+	return "USD"
+}
+
+// Allowed reports whether code (case-insensitive ISO-4217) is accepted.
+// Env: CURRENCIES (comma-separated allowlist). The default currency is always allowed.
+func Allowed(code string) bool {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return false
+	}
+	if code == DefaultCurrency() {
+		return true
+	}
+	list := os.Getenv("CURRENCIES")
+	if list == "" {
+		list = defaultAllowed
+	}
+	for _, c := range strings.Split(list, ",") {
+		if strings.ToUpper(strings.TrimSpace(c)) == code {
+			return true
+		}
+	}
+	return false
+}
+
+// OrDefault normalizes a stored code to upper case, filling in the default
+// for rows written before currencies were tracked.
+func OrDefault(code string) string {
+	if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+		return code
+	}
+	return DefaultCurrency()
+}
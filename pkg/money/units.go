@@ -0,0 +1,51 @@
+package money
+
+// Amounts are stored as amount_cents: hundredths of the major unit in every
+// currency (¥1,500 is 150000). Stripe instead wants the currency's own
+// smallest unit, so the two only agree for two-decimal currencies.
+
+// zeroDecimal are the currencies Stripe charges in whole units.
+var zeroDecimal = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "JPY": true, "KMF": true,
+	"KRW": true, "MGA": true, "PYG": true, "RWF": true, "UGX": true, "VND": true,
+	"VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+// threeDecimal are the currencies whose smallest unit is a thousandth.
+var threeDecimal = map[string]bool{"BHD": true, "JOD": true, "KWD": true, "OMR": true, "TND": true}
+
+// MinorUnits is the number of decimals in the currency's smallest unit:
+// 0, 2 (the default) or 3.
+func MinorUnits(code string) int {
+	code = OrDefault(code)
+	switch {
+	case zeroDecimal[code]:
+		return 0
+	case threeDecimal[code]:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// Aligned reports whether cents is a positive amount the currency can
+// actually be charged: whole units for zero-decimal currencies.
+func Aligned(cents int64, code string) bool {
+	if cents <= 0 {
+		return false
+	}
+	return MinorUnits(code) > 0 || cents%100 == 0
+}
+
+// StripeUnitAmount converts cents to the smallest-unit amount Stripe expects
+// for the currency. Only meaningful for Aligned amounts.
+func StripeUnitAmount(cents int64, code string) int64 {
+	switch MinorUnits(code) {
+	case 0:
+		return cents / 100
+	case 3:
+		return cents * 10
+	default:
+		return cents
+	}
+}
@@ -0,0 +1,29 @@
+package money
+
+import "testing"
+
+// USD charges cents as-is; JPY charges whole yen, so only multiples of 100
+// cents are chargeable; KWD's smallest unit is a thousandth.
+func TestAlignedAndStripeUnitAmount(t *testing.T) {
+	for _, tc := range []struct {
+		code    string
+		cents   int64
+		aligned bool
+		unit    int64
+	}{
+		{"USD", 150050, true, 150050},
+		{"usd", 1, true, 1},
+		{"JPY", 150000, true, 1500},
+		{"JPY", 150050, false, 1500},
+		{"KWD", 1234, true, 12340},
+		{"USD", 0, false, 0},
+		{"JPY", -100, false, -1},
+	} {
+		if got := Aligned(tc.cents, tc.code); got != tc.aligned {
+			t.Errorf("Aligned(%d, %s) = %v, want %v", tc.cents, tc.code, got, tc.aligned)
+		}
+		if got := StripeUnitAmount(tc.cents, tc.code); got != tc.unit {
+			t.Errorf("StripeUnitAmount(%d, %s) = %d, want %d", tc.cents, tc.code, got, tc.unit)
+		}
+	}
+}
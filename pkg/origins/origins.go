@@ -0,0 +1,64 @@
+// Package origins is the frontend origin allowlist (FRONTEND_ORIGIN) shared
+// by CORS and by checkout return URLs.
+package origins
+
+import (
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Default is used when FRONTEND_ORIGIN is unset (developer-friendly).
+const Default = "http://localhost:3000,https://legal-mp-frontend.vercel.app"
+
+// FromEnv returns the FRONTEND_ORIGIN entries (or Default), blanks dropped.
+// Entries are "scheme://host[:port]", "https://*.example.com" for any
+// subdomain, or "*".
+func FromEnv() []string {
+	raw := os.Getenv("FRONTEND_ORIGIN")
+	if strings.TrimSpace(raw) == "" {
+		raw = Default
+	}
+	var out []string
+	for _, p := range strings.Split(raw, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Normalize parses a bare origin and returns it as "scheme://host[:port]" in
+// lower case; ok is false for anything else (paths, queries, userinfo...).
+func Normalize(origin string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return "", false
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), true
+}
+
+// Allowed reports whether origin is on the allowlist. Unlike CORS, a "*"
+// entry allows nothing here: redirect targets must be listed explicitly.
+func Allowed(allow []string, origin string) bool {
+	o, ok := Normalize(origin)
+	if !ok {
+		return false
+	}
+	for _, a := range allow {
+		a = strings.ToLower(strings.TrimSuffix(a, "/"))
+		if a == o {
+			return true
+		}
+		// https://*.example.com matches https://app.example.com (not the apex)
+		if scheme, rest, found := strings.Cut(a, "://*."); found {
+			prefix := scheme + "://"
+			if strings.HasPrefix(o, prefix) && strings.HasSuffix(o, "."+rest) &&
+				len(o) > len(prefix)+len(rest)+1 {
+				return true
+			}
+		}
+	}
+	return false
+}
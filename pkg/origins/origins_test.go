@@ -0,0 +1,43 @@
+package origins
+
+import "testing"
+
+// Exact entries and subdomain wildcards match; "*", look-alike hosts and
+// anything that isn't a bare origin don't.
+func TestAllowed(t *testing.T) {
+	allow := []string{"https://app.example.com", "https://*.partner.io", "http://localhost:3000/", "*"}
+	cases := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://app.example.com", true},
+		{"HTTPS://App.Example.com/", true},
+		{"https://portal.partner.io", true},
+		{"http://localhost:3000", true},
+		{"https://partner.io", false},
+		{"https://evilpartner.io", false},
+		{"http://portal.partner.io", false},
+		{"https://app.example.com.evil.com", false},
+		{"https://evil.com", false},
+		{"https://app.example.com/path", false},
+		{"https://user@app.example.com", false},
+		{"javascript:alert(1)", false},
+		{"", false},
+	}
+	for _, tc := range cases {
+		if got := Allowed(allow, tc.origin); got != tc.want {
+			t.Errorf("Allowed(%q) = %v, want %v", tc.origin, got, tc.want)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("FRONTEND_ORIGIN", "")
+	if got := FromEnv(); len(got) != 2 {
+		t.Fatalf("want the default origins, got %v", got)
+	}
+	t.Setenv("FRONTEND_ORIGIN", " https://a.com , ,https://b.com")
+	if got := FromEnv(); len(got) != 2 || got[0] != "https://a.com" || got[1] != "https://b.com" {
+		t.Fatalf("want trimmed entries without blanks, got %v", got)
+	}
+}
@@ -0,0 +1,95 @@
+package pagination
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Page size bounds for ?pageSize.
+const (
+	DefaultSize = 10
+	MaxSize     = 50
+)
+
+// Parse reads ?page and ?pageSize. A missing or bad page is 1; a size
+// outside 1..MaxSize falls back to DefaultSize.
+func Parse(c *fiber.Ctx) (page, size int) {
+	page, _ = strconv.Atoi(c.Query("page", "1"))
+	size, _ = strconv.Atoi(c.Query("pageSize", strconv.Itoa(DefaultSize)))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > MaxSize {
+		size = DefaultSize
+	}
+	return
+}
+
+// Page is the envelope every paged list returns.
+type Page[T any] struct {
+	Page     int   `json:"page"`
+	PageSize int   `json:"pageSize"`
+	Total    int64 `json:"total"`
+	Pages    int   `json:"pages"`
+	Links    Links `json:"links"`
+	Items    []T   `json:"items"` // always [] when empty
+}
+
+// New builds the envelope for one page of items out of total, including
+// the page count and next/prev links for the current request.
+func New[T any](c *fiber.Ctx, page, size int, total int64, items []T) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	pages := Pages(total, size)
+	return Page[T]{
+		Page:     page,
+		PageSize: size,
+		Total:    total,
+		Pages:    pages,
+		Links:    LinksFor(c, page, pages),
+		Items:    items,
+	}
+}
+
+// Links points at the neighbouring pages of a paged list. Each is the
+// request's own path and query with only ?page changed, or null at the
+// boundaries.
+type Links struct {
+	Next *string `json:"next"`
+	Prev *string `json:"prev"`
+}
+
+// Pages is the number of pages needed for total items at size per page.
+func Pages(total int64, size int) int {
+	if size < 1 {
+		return 0
+	}
+	return int((total + int64(size) - 1) / int64(size))
+}
+
+// LinksFor builds next/prev links for page out of pages. Other query params
+// (pageSize, filters, sort) are kept as sent. A page past the end links back
+// to the last page.
+func LinksFor(c *fiber.Ctx, page, pages int) Links {
+	var l Links
+	if page < pages {
+		l.Next = pageURL(c, page+1)
+	}
+	if prev := min(page-1, pages); prev >= 1 {
+		l.Prev = pageURL(c, prev)
+	}
+	return l
+}
+
+// pageURL is the current request URL (path + query) with ?page=n.
+func pageURL(c *fiber.Ctx, n int) *string {
+	args := fiber.AcquireArgs()
+	defer fiber.ReleaseArgs(args)
+	c.Request().URI().QueryArgs().CopyTo(args)
+	args.SetUint("page", n)
+
+	u := c.Path() + "?" + string(args.QueryString())
+	return &u
+}
@@ -0,0 +1,131 @@
+package pagination
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// links runs LinksFor for the given request URL and page count.
+func links(t *testing.T, target string, pages int) Links {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/api/cases/mine", func(c *fiber.Ctx) error {
+		page, _ := strconv.Atoi(c.Query("page", "1"))
+		return c.JSON(LinksFor(c, page, pages))
+	})
+	resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var l Links
+	if err := json.NewDecoder(resp.Body).Decode(&l); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func str(p *string) string {
+	if p == nil {
+		return "<nil>"
+	}
+	return *p
+}
+
+// First page has no prev, last page has no next; the middle has both, and
+// other query params survive.
+func TestLinksFor_FirstMiddleLast(t *testing.T) {
+	cases := []struct {
+		name, target string
+		pages        int
+		next, prev   string
+	}{
+		{"first", "/api/cases/mine?pageSize=5&sort=updated_desc", 3,
+			"/api/cases/mine?pageSize=5&sort=updated_desc&page=2", "<nil>"},
+		{"middle", "/api/cases/mine?page=2&pageSize=5&sort=updated_desc", 3,
+			"/api/cases/mine?page=3&pageSize=5&sort=updated_desc", "/api/cases/mine?page=1&pageSize=5&sort=updated_desc"},
+		{"last", "/api/cases/mine?page=3&pageSize=5", 3,
+			"<nil>", "/api/cases/mine?page=2&pageSize=5"},
+		{"only", "/api/cases/mine", 1, "<nil>", "<nil>"},
+		{"empty", "/api/cases/mine", 0, "<nil>", "<nil>"},
+		{"past end", "/api/cases/mine?page=9", 3, "<nil>", "/api/cases/mine?page=3"},
+	}
+	for _, tc := range cases {
+		l := links(t, tc.target, tc.pages)
+		if str(l.Next) != tc.next || str(l.Prev) != tc.prev {
+			t.Errorf("%s: got next=%s prev=%s, want next=%s prev=%s",
+				tc.name, str(l.Next), str(l.Prev), tc.next, tc.prev)
+		}
+	}
+}
+
+func TestPages(t *testing.T) {
+	for _, tc := range []struct {
+		total int64
+		size  int
+		want  int
+	}{{0, 10, 0}, {1, 10, 1}, {10, 10, 1}, {11, 10, 2}} {
+		if got := Pages(tc.total, tc.size); got != tc.want {
+			t.Errorf("Pages(%d, %d) = %d, want %d", tc.total, tc.size, got, tc.want)
+		}
+	}
+}
+
+// Missing, non-numeric and out-of-range values fall back to page 1 / DefaultSize.
+func TestParse_Clamp(t *testing.T) {
+	cases := []struct {
+		query      string
+		page, size int
+	}{
+		{"", 1, DefaultSize},
+		{"?page=3&pageSize=25", 3, 25},
+		{"?page=0&pageSize=0", 1, DefaultSize},
+		{"?page=-2&pageSize=-1", 1, DefaultSize},
+		{"?page=x&pageSize=y", 1, DefaultSize},
+		{"?pageSize=50", 1, MaxSize},
+		{"?pageSize=51", 1, DefaultSize},
+	}
+	for _, tc := range cases {
+		app := fiber.New()
+		var page, size int
+		app.Get("/l", func(c *fiber.Ctx) error {
+			page, size = Parse(c)
+			return nil
+		})
+		if _, err := app.Test(httptest.NewRequest("GET", "/l"+tc.query, nil)); err != nil {
+			t.Fatal(err)
+		}
+		if page != tc.page || size != tc.size {
+			t.Errorf("%q: got page=%d size=%d, want %d/%d", tc.query, page, size, tc.page, tc.size)
+		}
+	}
+}
+
+// New fills pages by ceil(total/size), links, and never returns null items.
+func TestNew_Envelope(t *testing.T) {
+	app := fiber.New()
+	app.Get("/l", func(c *fiber.Ctx) error {
+		return c.JSON(New[string](c, 2, 10, 21, nil))
+	})
+	resp, err := app.Test(httptest.NewRequest("GET", "/l?page=2&pageSize=10", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		t.Fatal(err)
+	}
+	if string(raw["items"]) != "[]" || string(raw["pages"]) != "3" || string(raw["total"]) != "21" {
+		t.Fatalf("unexpected envelope: items=%s pages=%s total=%s", raw["items"], raw["pages"], raw["total"])
+	}
+	var l Links
+	if err := json.Unmarshal(raw["links"], &l); err != nil {
+		t.Fatal(err)
+	}
+	if str(l.Next) != "/l?page=3&pageSize=10" || str(l.Prev) != "/l?page=1&pageSize=10" {
+		t.Fatalf("unexpected links: next=%s prev=%s", str(l.Next), str(l.Prev))
+	}
+}
@@ -1,6 +1,13 @@
 package sanitize
 
-import "regexp"
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
 
 /* ======================= Regex Definitions ======================= */
 
@@ -12,18 +19,122 @@ var reEmail = regexp.MustCompile(`(?i)[A-Z0-9._%+\-]+@[A-Z0-9.\-]+\.[A-Z]{2,}`)
 // Requires at least 9 digits total to reduce false positives.
 var rePhone = regexp.MustCompile(`\+?\d[\d\s\-\.$begin:math:text$$end:math:text$]{7,}\d`)
 
+/* ========================= Redaction Modes ======================== */
+
+// Mode selects how much of a detected email/phone survives redaction.
+type Mode int
+
+const (
+	// ModeFull replaces the whole match ("[redacted email]"); used in public views.
+	ModeFull Mode = iota
+	// ModePartial keeps context for support staff: the email domain
+	// ("****@gmail.com") and the last two phone digits ("+** **** **67").
+	ModePartial
+)
+
+// StaffMode is the mode for authorized support contexts (admin views).
+// Env: PII_STAFF_MASKING=partial enables ModePartial; anything else is ModeFull.
+func StaffMode() Mode {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("PII_STAFF_MASKING")), "partial") {
+		return ModePartial
+	}
+	return ModeFull
+}
+
 /* ======================= Public Functions ======================== */
 
 // RedactPII replaces emails and phone numbers in a string with "[redacted ...]".
 func RedactPII(s string) string {
+	return RedactPIIMode(s, ModeFull)
+}
+
+// RedactPIIMode redacts emails and phone numbers using the given mode.
+func RedactPIIMode(s string, mode Mode) string {
 	if s == "" {
 		return s
 	}
-	s = reEmail.ReplaceAllString(s, "[redacted email]")
-	s = rePhone.ReplaceAllString(s, "[redacted phone]")
+	if mode != ModePartial {
+		s = reEmail.ReplaceAllString(s, "[redacted email]")
+		s = rePhone.ReplaceAllString(s, "[redacted phone]")
+		return s
+	}
+
+	// Fixed-width mask so the local part's length isn't leaked
+	s = reEmail.ReplaceAllStringFunc(s, func(m string) string {
+		return "****" + m[strings.LastIndex(m, "@"):]
+	})
+	s = rePhone.ReplaceAllStringFunc(s, maskPhone)
 	return s
 }
 
+// maskPhone stars every digit except the last two, keeping separators.
+func maskPhone(m string) string {
+	b := []byte(m)
+	keep := 2
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < '0' || b[i] > '9' {
+			continue
+		}
+		if keep > 0 {
+			keep--
+			continue
+		}
+		b[i] = '*'
+	}
+	return string(b)
+}
+
+/* ========================== HTML Stripping ========================= */
+
+// dropContent are elements whose text is never user-visible content.
+var dropContent = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Object: true,
+	atom.Noscript: true, atom.Template: true, atom.Textarea: true,
+}
+
+// lineBreak are tags that stand for a line break in plain text.
+var lineBreak = map[atom.Atom]bool{
+	atom.Br: true, atom.P: true, atom.Div: true, atom.Li: true, atom.Tr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+}
+
+// StripHTML turns user input into plain text: every tag and attribute is
+// dropped, script/style-like elements lose their content too, and <br>/block
+// tags become newlines. Existing line breaks and entities are kept verbatim,
+// so nothing is unescaped into new markup. Used on case title/description
+// before storing; it is separate from PII redaction.
+func StripHTML(s string) string {
+	if !strings.ContainsAny(s, "<>") {
+		return s
+	}
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	skip := 0 // depth inside dropContent elements
+	for {
+		switch z.Next() {
+		case html.ErrorToken: // io.EOF; the reader itself can't fail
+			return strings.TrimSpace(b.String())
+		case html.TextToken:
+			if skip == 0 {
+				b.Write(z.Raw())
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			a := atom.Lookup(name)
+			if dropContent[a] && z.Token().Type == html.StartTagToken {
+				skip++
+			} else if lineBreak[a] && skip == 0 && b.Len() > 0 {
+				b.WriteByte('\n')
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if a := atom.Lookup(name); dropContent[a] && skip > 0 {
+				skip--
+			}
+		}
+	}
+}
+
 // Summary truncates a string to max characters and appends "…".
 // It tries to cut at the nearest space before the limit to avoid breaking words.
 func Summary(s string, max int) string {
@@ -0,0 +1,55 @@
+package sanitize
+
+import "testing"
+
+// Same input, both modes: full hides everything, partial keeps the email
+// domain and the last two phone digits.
+func TestRedactPIIMode(t *testing.T) {
+	in := "Email john.doe@gmail.com or call +65 9123 4567."
+
+	cases := []struct {
+		mode Mode
+		want string
+	}{
+		{ModeFull, "Email [redacted email] or call [redacted phone]."},
+		{ModePartial, "Email ****@gmail.com or call +** **** **67."},
+	}
+	for _, tc := range cases {
+		if got := RedactPIIMode(in, tc.mode); got != tc.want {
+			t.Errorf("mode %d:\n got  %q\n want %q", tc.mode, got, tc.want)
+		}
+	}
+
+	if RedactPII(in) != RedactPIIMode(in, ModeFull) {
+		t.Errorf("RedactPII should default to full redaction")
+	}
+}
+
+func TestStaffMode(t *testing.T) {
+	t.Setenv("PII_STAFF_MASKING", "")
+	if StaffMode() != ModeFull {
+		t.Errorf("unset: want full")
+	}
+	t.Setenv("PII_STAFF_MASKING", "Partial")
+	if StaffMode() != ModePartial {
+		t.Errorf("partial: want partial")
+	}
+}
+
+// Tags, attributes and script/style content go; text, entities and line
+// breaks stay.
+func TestStripHTML(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"Plain text, a < b > c", "Plain text, a < b > c"},
+		{"Hi<script>alert('x')</script> there", "Hi there"},
+		{`<img src=x onerror="alert(1)">Deposit`, "Deposit"},
+		{`<b onclick="x()">bold</b> &lt;i&gt;`, "bold &lt;i&gt;"},
+		{"line1\nline2<br/>line3<p>para</p>", "line1\nline2\nline3\npara"},
+		{"<style>p{}</style><SCRIPT>x()</SCRIPT>", ""},
+	}
+	for _, tc := range cases {
+		if got := StripHTML(tc.in); got != tc.want {
+			t.Errorf("StripHTML(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
@@ -2,20 +2,24 @@ package utils
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/aldoetobex/legal-mp-backend/pkg/models"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
 // LogCaseHistory inserts an audit record into case_histories.
-// Used to track important status changes and actions on a case.
+// Used to track important status changes and actions on a case; system
+// actions (webhooks, sweeps) pass uuid.Nil with models.ActorSystem.
 // Errors are ignored on purpose (best-effort logging).
 func LogCaseHistory(
 	ctx context.Context,
 	db *gorm.DB,
 	caseID, actorID uuid.UUID,
+	actorType models.ActorType,
 	action string,
 	oldS, newS models.CaseStatus,
 	reason string,
@@ -23,6 +27,7 @@ func LogCaseHistory(
 	_ = db.WithContext(ctx).Create(&models.CaseHistory{
 		CaseID:    caseID,
 		ActorID:   actorID,
+		ActorType: actorType,
 		Action:    action,
 		OldStatus: oldS,
 		NewStatus: newS,
@@ -30,3 +35,27 @@ func LogCaseHistory(
 		CreatedAt: time.Now(),
 	}).Error
 }
+
+// Notify inserts an in-app notification for a user.
+// Errors are ignored on purpose (best-effort, never blocks the main action).
+func Notify(
+	ctx context.Context,
+	db *gorm.DB,
+	userID uuid.UUID,
+	typ models.NotificationType,
+	caseID uuid.UUID,
+) {
+	_ = db.WithContext(ctx).Create(&models.Notification{
+		UserID:    userID,
+		Type:      typ,
+		CaseID:    caseID,
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+// IsUniqueViolation reports whether err is a Postgres unique-constraint
+// violation (SQLSTATE 23505).
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -10,3 +10,12 @@ func Respond(c *fiber.Ctx, errs map[string][]string) error {
 		"errors":  errs,
 	})
 }
+
+// RespondField writes a Laravel-style error for a single field with a custom
+// status (e.g. 409 for a taken email).
+func RespondField(c *fiber.Ctx, status int, field, msg string) error {
+	return c.Status(status).JSON(fiber.Map{
+		"message": "Validation failed",
+		"errors":  map[string][]string{field: {msg}},
+	})
+}
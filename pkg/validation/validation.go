@@ -2,11 +2,15 @@ package validation
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 	"regexp"
 	"strings"
+	"unicode"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/aldoetobex/legal-mp-backend/pkg/money"
 )
 
 var (
@@ -14,10 +18,20 @@ var (
 
 	// Bar number: 3–40 chars; allow letters, digits, space, dash, slash.
 	reBarNum = regexp.MustCompile(`^[A-Za-z0-9 /-]{3,40}$`)
-	// Jurisdiction: ISO-3166 alpha-2 (e.g., "SG").
-	reJurisdiction = regexp.MustCompile(`^[A-Z]{2}$`)
 )
 
+// NormalizeJurisdiction trims and upper-cases a jurisdiction code for storage.
+func NormalizeJurisdiction(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsJurisdiction reports whether code (any case) is an assigned
+// ISO-3166 alpha-2 country code, e.g. "SG" (not just two letters like "XX").
+func IsJurisdiction(code string) bool {
+	_, ok := iso3166Alpha2[NormalizeJurisdiction(code)]
+	return ok
+}
+
 func init() {
 	v = validator.New()
 
@@ -39,13 +53,23 @@ func init() {
 		return reBarNum.MatchString(val)
 	})
 
+	// Custom rule: ISO-4217 code from the configured allowlist (allows empty via `omitempty`).
+	_ = v.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return money.Allowed(fl.Field().String())
+	})
+
+	// Custom rule: password strength (see passwordPolicy).
+	_ = v.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return passwordPolicyFromEnv().ok(fl.Field().String())
+	})
+
 	// Custom rule: jurisdiction code (allows empty via `omitempty`).
 	_ = v.RegisterValidation("jurisdiction", func(fl validator.FieldLevel) bool {
-		val := strings.TrimSpace(strings.ToUpper(fl.Field().String()))
-		if val == "" {
+		val := fl.Field().String()
+		if strings.TrimSpace(val) == "" {
 			return true
 		}
-		return reJurisdiction.MatchString(val)
+		return IsJurisdiction(val)
 	})
 }
 
@@ -62,6 +86,13 @@ func Validate(s any) (map[string][]string, error) {
 		for _, e := range ve {
 			field := e.Field() // already mapped from json tag
 
+			// Fields tagged with label/unit read naturally,
+			// e.g. "Amount must be at least 1 cent"
+			if msg, ok := labeledMessage(s, e); ok {
+				out[field] = append(out[field], msg)
+				continue
+			}
+
 			switch e.Tag() {
 			case "required":
 				out[field] = append(out[field], "This field is required")
@@ -107,12 +138,46 @@ func Validate(s any) (map[string][]string, error) {
 			case "barnum":
 				out[field] = append(out[field], "Invalid bar number format")
 
+			case "currency":
+				out[field] = append(out[field], "Unsupported currency")
+
+			case "password":
+				out[field] = append(out[field], passwordPolicyFromEnv().message())
+
 			case "jurisdiction":
 				out[field] = append(out[field], "Invalid jurisdiction code (use ISO-3166 alpha-2, e.g., \"SG\")")
 
+			case "len":
+				if e.Kind() == reflect.String {
+					out[field] = append(out[field], fmt.Sprintf("Must be exactly %s characters", e.Param()))
+				} else {
+					out[field] = append(out[field], fmt.Sprintf("Must contain exactly %s items", e.Param()))
+				}
+
+			case "gt":
+				out[field] = append(out[field], fmt.Sprintf("Must be greater than %s", e.Param()))
+
+			case "lt":
+				out[field] = append(out[field], fmt.Sprintf("Must be less than %s", e.Param()))
+
+			case "gtefield":
+				out[field] = append(out[field], fmt.Sprintf("Must be greater than or equal to %s", jsonName(s, e.Param())))
+
+			case "ltefield":
+				out[field] = append(out[field], fmt.Sprintf("Must be less than or equal to %s", jsonName(s, e.Param())))
+
+			case "e164":
+				out[field] = append(out[field], "Invalid phone number (use international format, e.g. +6591234567)")
+
+			case "url", "http_url":
+				out[field] = append(out[field], "Invalid URL format")
+
+			case "datetime":
+				out[field] = append(out[field], fmt.Sprintf("Invalid date/time (expected format %s)", e.Param()))
+
 			default:
-				// Fallback to the original validation error string.
-				out[field] = append(out[field], e.Error())
+				// Never leak validator internals; the tag name is enough to debug.
+				out[field] = append(out[field], fmt.Sprintf("Invalid value (%s)", e.Tag()))
 			}
 		}
 		return out, nil
@@ -121,3 +186,130 @@ func Validate(s any) (map[string][]string, error) {
 	// No validation errors.
 	return nil, nil
 }
+
+/* ============================ Field labels ============================== */
+
+// structField finds the top-level struct field by Go name (DTOs are flat).
+func structField(s any, name string) (reflect.StructField, bool) {
+	t := reflect.TypeOf(s)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	return t.FieldByName(name)
+}
+
+// jsonName maps a Go field name (as used in *field params) to its JSON name.
+func jsonName(s any, name string) string {
+	if f, ok := structField(s, name); ok {
+		if j := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]; j != "" && j != "-" {
+			return j
+		}
+	}
+	return name
+}
+
+// labeledMessage builds messages for numeric fields that declare a human label
+// and unit, e.g. `label:"Amount" unit:"cent"`. Other fields use the generic text.
+func labeledMessage(s any, e validator.FieldError) (string, bool) {
+	f, ok := structField(s, e.StructField())
+	if !ok {
+		return "", false
+	}
+	label, unit := f.Tag.Get("label"), f.Tag.Get("unit")
+	if label == "" || unit == "" || e.Kind() == reflect.String {
+		return "", false
+	}
+
+	switch e.Tag() {
+	case "required":
+		return label + " is required", true
+	case "min", "gte":
+		return fmt.Sprintf("%s must be at least %s", label, quantity(e.Param(), unit)), true
+	case "max", "lte":
+		return fmt.Sprintf("%s must be at most %s", label, quantity(e.Param(), unit)), true
+	}
+	return "", false
+}
+
+// quantity formats "100000000" + "cent" as "100,000,000 cents".
+func quantity(n, unit string) string {
+	if n != "1" {
+		unit += "s"
+	}
+	var b strings.Builder
+	for i, r := range n {
+		if i > 0 && (len(n)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + " " + unit
+}
+
+/* =========================== Password policy ============================ */
+
+// bcrypt ignores everything past 72 bytes, so longer passwords are rejected
+// outright (the `max` tag counts characters, not bytes).
+const maxPasswordBytes = 72
+
+// passwordPolicy lists the character classes a password must contain.
+type passwordPolicy struct {
+	letter, digit, upper, symbol bool
+}
+
+// passwordPolicyFromEnv always requires a letter and a digit; uppercase and
+// symbol are opt-in via PASSWORD_REQUIRE_UPPER / PASSWORD_REQUIRE_SYMBOL=true.
+func passwordPolicyFromEnv() passwordPolicy {
+	return passwordPolicy{
+		letter: true,
+		digit:  true,
+		upper:  os.Getenv("PASSWORD_REQUIRE_UPPER") == "true",
+		symbol: os.Getenv("PASSWORD_REQUIRE_SYMBOL") == "true",
+	}
+}
+
+func (p passwordPolicy) ok(s string) bool {
+	if len(s) > maxPasswordBytes {
+		return false
+	}
+	var letter, digit, upper, symbol bool
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r):
+			letter = true
+			upper = upper || unicode.IsUpper(r)
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	return (!p.letter || letter) && (!p.digit || digit) &&
+		(!p.upper || upper) && (!p.symbol || symbol)
+}
+
+// message describes the active policy, e.g. "Must contain at least one letter
+// and one digit (max 72 bytes)".
+func (p passwordPolicy) message() string {
+	var parts []string
+	if p.letter {
+		parts = append(parts, "one letter")
+	}
+	if p.upper {
+		parts = append(parts, "one uppercase letter")
+	}
+	if p.digit {
+		parts = append(parts, "one digit")
+	}
+	if p.symbol {
+		parts = append(parts, "one symbol")
+	}
+	list := strings.Join(parts, ", ")
+	if i := strings.LastIndex(list, ", "); i >= 0 {
+		list = list[:i] + " and " + list[i+2:]
+	}
+	return fmt.Sprintf("Must contain at least %s (max %d bytes)", list, maxPasswordBytes)
+}
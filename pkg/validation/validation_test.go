@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+type pwInput struct {
+	Password string `json:"password" validate:"required,password"`
+}
+
+// Weak passwords are rejected with the policy message under the JSON field name.
+func TestPassword_WeakAndStrong(t *testing.T) {
+	weak := []string{"123456", "abcdef", "!!!!!!", strings.Repeat("a1", 37)} // last is 74 bytes
+	for _, pw := range weak {
+		errs, err := Validate(pwInput{Password: pw})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(errs["password"]) != 1 || errs["password"][0] != "Must contain at least one letter and one digit (max 72 bytes)" {
+			t.Fatalf("%q: want password error, got %v", pw, errs)
+		}
+	}
+
+	for _, pw := range []string{"secret1", "P4ssword", "kata sandi 9"} {
+		if errs, _ := Validate(pwInput{Password: pw}); errs != nil {
+			t.Fatalf("%q: want valid, got %v", pw, errs)
+		}
+	}
+}
+
+// Optional classes tighten the rule and show up in the message.
+func TestPassword_ConfigurableClasses(t *testing.T) {
+	t.Setenv("PASSWORD_REQUIRE_UPPER", "true")
+	t.Setenv("PASSWORD_REQUIRE_SYMBOL", "true")
+
+	errs, _ := Validate(pwInput{Password: "secret1"})
+	want := "Must contain at least one letter, one uppercase letter, one digit and one symbol (max 72 bytes)"
+	if len(errs["password"]) != 1 || errs["password"][0] != want {
+		t.Fatalf("want %q, got %v", want, errs)
+	}
+	if errs, _ := Validate(pwInput{Password: "Secret1!"}); errs != nil {
+		t.Fatalf("want valid, got %v", errs)
+	}
+}
+
+type mixedInput struct {
+	Code  string `json:"code" validate:"len=4"`
+	Start int    `json:"start"`
+	End   int    `json:"end" validate:"gtefield=Start"`
+	Tag   string `json:"tag" validate:"alpha"`
+}
+
+// Tags without a dedicated message never leak validator's internal error text.
+func TestValidate_FriendlyFallbacks(t *testing.T) {
+	errs, _ := Validate(mixedInput{Code: "abc", Start: 5, End: 1, Tag: "x1"})
+	want := map[string]string{
+		"code": "Must be exactly 4 characters",
+		"end":  "Must be greater than or equal to start",
+		"tag":  "Invalid value (alpha)",
+	}
+	for field, msg := range want {
+		if got := errs[field]; len(got) != 1 || got[0] != msg {
+			t.Fatalf("%s: want %q, got %v", field, msg, got)
+		}
+	}
+}
+
+type currencyInput struct {
+	Currency string `json:"currency" validate:"omitempty,currency"`
+}
+
+// Currencies outside the allowlist are rejected; case is ignored.
+func TestCurrency_Allowlist(t *testing.T) {
+	t.Setenv("CURRENCIES", "SGD,EUR")
+
+	for _, c := range []string{"", "eur", "SGD"} {
+		if errs, _ := Validate(currencyInput{Currency: c}); errs != nil {
+			t.Fatalf("%q: want valid, got %v", c, errs)
+		}
+	}
+	errs, _ := Validate(currencyInput{Currency: "JPY"})
+	if len(errs["currency"]) != 1 || errs["currency"][0] != "Unsupported currency" {
+		t.Fatalf("JPY: want currency error, got %v", errs)
+	}
+}
+
+type jurisdictionInput struct {
+	Jurisdiction string `json:"jurisdiction" validate:"omitempty,jurisdiction"`
+}
+
+// Only assigned ISO-3166 codes pass; well-formed fakes get the friendly message.
+func TestJurisdiction_ISO3166(t *testing.T) {
+	for _, j := range []string{"", "SG", "us", " gb "} {
+		if errs, _ := Validate(jurisdictionInput{Jurisdiction: j}); errs != nil {
+			t.Fatalf("%q: want valid, got %v", j, errs)
+		}
+	}
+	for _, j := range []string{"XX", "ZZ", "S1", "SGP"} {
+		errs, _ := Validate(jurisdictionInput{Jurisdiction: j})
+		if len(errs["jurisdiction"]) != 1 || !strings.HasPrefix(errs["jurisdiction"][0], "Invalid jurisdiction code") {
+			t.Fatalf("%q: want jurisdiction error, got %v", j, errs)
+		}
+	}
+}
+
+// Codes are stored trimmed and upper-case.
+func TestNormalizeJurisdiction(t *testing.T) {
+	if got := NormalizeJurisdiction(" sg "); got != "SG" {
+		t.Fatalf("want SG, got %q", got)
+	}
+}